@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/orchestrator"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// runAnnotate adds, changes or removes the note on a track of a saved state
+func runAnnotate(args []string) {
+	fs := flag.NewFlagSet("annotate", flag.ExitOnError)
+	applyOutput := registerOutputFlags(fs)
+	sptURL := fs.String("url", "", "SPT playlist URL of the saved state")
+	track := fs.String("track", "", "Track to annotate: Spotify track ID or position in the playlist (1-based)")
+	note := fs.String("note", "", "Note text, e.g. \"prefer live version\"; \"skip\" leaves the track out; empty removes the note")
+	fs.Usage = func() {
+		ui.Println("Usage: playlistporter annotate -url <spt-playlist-url> -track <id|position> -note <text>")
+		ui.Println("\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	applyOutput()
+
+	if *sptURL == "" || *track == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	orch := orchestrator.New(nil, false, "", 1, false)
+	if err := orch.AnnotateTrack(*sptURL, *track, *note); err != nil {
+		log.Fatalf("Failed to annotate track: %v", err)
+	}
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/auth"
+	"github.com/Verryx-02/PlaylistPorter/internal/config"
+	"github.com/Verryx-02/PlaylistPorter/internal/paths"
+	"github.com/Verryx-02/PlaylistPorter/internal/spt"
+	"github.com/Verryx-02/PlaylistPorter/internal/tubo"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// googleRevokeURL is Google's OAuth token revocation endpoint
+const googleRevokeURL = "https://oauth2.googleapis.com/revoke"
+
+// runAuth signs in to Spotify or YouTube and caches the token, so a later porting run
+// never stops halfway to wait for a browser
+func runAuth(args []string) {
+	if len(args) > 0 && args[0] == "revoke" {
+		runAuthRevoke(args[1:])
+		return
+	}
+
+	fs := flag.NewFlagSet("auth", flag.ExitOnError)
+	applyOutput := registerOutputFlags(fs)
+	configPath := fs.String("config", paths.Config(), "Path to configuration file")
+	account := fs.String("account", "", "With youtube, the account to sign in (default: tubo.account)")
+	force := fs.Bool("force", false, "Sign in again even if a saved authorization still works")
+	noBrowser := fs.Bool("no-browser", false, "Print the authorization URL and paste the resulting code or redirect URL into the terminal")
+	fs.Usage = func() {
+		ui.Println("Usage: playlistporter auth <spotify|youtube> [options]")
+		ui.Println("       playlistporter auth revoke <spotify|youtube> [-account name]")
+		ui.Println("\nSigns in and saves the token; porting runs then reuse it without asking.")
+		ui.Println("\nOptions:")
+		fs.PrintDefaults()
+	}
+
+	// The service comes first so options can follow it
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fs.Parse(args)
+		fs.Usage()
+		os.Exit(1)
+	}
+	service := strings.ToLower(args[0])
+	fs.Parse(args[1:])
+	applyOutput()
+
+	if service != "spotify" && service != "youtube" {
+		ui.Printf("Unknown service %q\n\n", service)
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	if err := config.CheckAccountName(*account); err != nil {
+		log.Fatalf("-account: %v", err)
+	}
+	if *noBrowser {
+		auth.SetNoBrowser(true)
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	switch service {
+	case "spotify":
+		err = authSpotify(cfg, *force)
+	case "youtube":
+		if *account != "" {
+			cfg.TUBO.Account = *account
+		}
+		err = authYouTube(cfg, *force)
+	}
+	if err != nil {
+		log.Fatalf("Sign-in failed: %v", err)
+	}
+}
+
+// authSpotify signs in the Spotify user; the app credentials alone need no sign-in
+func authSpotify(cfg *config.Config, force bool) error {
+	if force {
+		if err := auth.DeleteToken(spt.TokenCacheName); err != nil {
+			return err
+		}
+	}
+
+	ui.Printf("🔐 Signing in to Spotify...\n")
+	cfg.SPT.UserAuth = true
+	if _, err := spt.NewClient(&cfg.SPT); err != nil {
+		return err
+	}
+	ui.Printf("✅ Spotify authorization saved to %s\n", tokenLocation(spt.TokenCacheName))
+	ui.Printf("💡 It's used for Liked Songs, -all-playlists, -dest spotify and with spt.user_auth\n")
+	return nil
+}
+
+// authYouTube signs in the configured YouTube account and shows the channel it belongs to
+func authYouTube(cfg *config.Config, force bool) error {
+	name := tubo.TokenName(cfg.TUBO.Account)
+	if force {
+		if err := auth.DeleteToken(name); err != nil {
+			return err
+		}
+	}
+
+	ui.Printf("🔐 Signing in to YouTube...\n")
+	client, err := tubo.NewClient(&cfg.TUBO)
+	if err != nil {
+		return err
+	}
+	channel, err := client.ChannelTitle()
+	if err != nil {
+		return fmt.Errorf("checking the signed-in channel: %w", err)
+	}
+	ui.Printf("✅ YouTube authorization for %q saved to %s\n", channel, tokenLocation(name))
+	if cfg.TUBO.Account != "" {
+		ui.Printf("💡 Use it with -account %s\n", cfg.TUBO.Account)
+	}
+	return nil
+}
+
+// tokenLocation is the cache file of a token for messages
+func tokenLocation(name string) string {
+	path, err := auth.TokenPath(name)
+	if err != nil {
+		return "the token cache"
+	}
+	return path
+}
+
+// runAuthRevoke signs out: the saved token is revoked at the provider where it offers that,
+// and deleted locally
+func runAuthRevoke(args []string) {
+	fs := flag.NewFlagSet("auth revoke", flag.ExitOnError)
+	applyOutput := registerOutputFlags(fs)
+	configPath := fs.String("config", paths.Config(), "Path to configuration file, read for tubo.account")
+	account := fs.String("account", "", "With youtube, the account to sign out (default: tubo.account)")
+	fs.Usage = func() {
+		ui.Println("Usage: playlistporter auth revoke <spotify|youtube> [options]")
+		ui.Println("\nRevokes the saved authorization and deletes it; the next run signs in again.")
+		ui.Println("\nOptions:")
+		fs.PrintDefaults()
+	}
+
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fs.Parse(args)
+		fs.Usage()
+		os.Exit(1)
+	}
+	service := strings.ToLower(args[0])
+	fs.Parse(args[1:])
+	applyOutput()
+
+	if err := config.CheckAccountName(*account); err != nil {
+		log.Fatalf("-account: %v", err)
+	}
+
+	switch service {
+	case "spotify":
+		revokeSpotify()
+	case "youtube":
+		// The config only names the default account, so a missing one is no reason to stop
+		name := *account
+		if name == "" {
+			if cfg, err := config.Load(*configPath); err == nil {
+				name = cfg.TUBO.Account
+			}
+		}
+		revokeYouTube(name)
+	default:
+		ui.Printf("Unknown service %q\n\n", service)
+		fs.Usage()
+		os.Exit(1)
+	}
+}
+
+// revokeSpotify deletes the saved Spotify token. Spotify has no revocation endpoint, so the
+// grant itself can only be removed on the account page.
+func revokeSpotify() {
+	token, err := auth.LoadToken(spt.TokenCacheName)
+	if err != nil {
+		log.Fatalf("Failed to read the saved Spotify authorization: %v", err)
+	}
+	if token == nil {
+		ui.Printf("ℹ️  No saved Spotify authorization at %s\n", tokenLocation(spt.TokenCacheName))
+		return
+	}
+	if err := auth.DeleteToken(spt.TokenCacheName); err != nil {
+		log.Fatalf("Failed to delete the Spotify authorization: %v", err)
+	}
+	ui.Printf("🗑️  Deleted the saved Spotify authorization\n")
+	ui.Printf("💡 Spotify can't revoke tokens from an app; remove its access at https://www.spotify.com/account/apps/\n")
+}
+
+// revokeYouTube revokes the saved token of a YouTube account with Google and deletes it
+func revokeYouTube(account string) {
+	name := tubo.TokenName(account)
+	label := "YouTube"
+	if account != "" {
+		label = fmt.Sprintf("YouTube (account %s)", account)
+	}
+
+	err := auth.Revoke(name, googleRevokeURL)
+	switch {
+	case errors.Is(err, auth.ErrNoToken):
+		ui.Printf("ℹ️  No saved %s authorization at %s\n", label, tokenLocation(name))
+	case err != nil:
+		ui.Printf("❌ Google didn't confirm the revocation: %v\n", err)
+		ui.Printf("💡 The token is kept so you can retry; remove access at https://myaccount.google.com/permissions\n")
+		os.Exit(1)
+	default:
+		ui.Printf("🔒 Revoked the %s authorization with Google and deleted it\n", label)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/config"
+	"github.com/Verryx-02/PlaylistPorter/internal/orchestrator"
+	"github.com/Verryx-02/PlaylistPorter/internal/paths"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// runCollage builds a cover collage from the album arts of a saved playlist
+func runCollage(args []string) {
+	fs := flag.NewFlagSet("collage", flag.ExitOnError)
+	applyOutput := registerOutputFlags(fs)
+	sptURL := fs.String("url", "", "SPT playlist URL of the saved state")
+	configPath := fs.String("config", paths.Config(), "Path to configuration file")
+	out := fs.String("out", "", "Output JPEG path (default: covers/playlist_<id>.jpg)")
+	upload := fs.Bool("upload", false, "Also set the collage as the image of the YouTube playlist")
+	fs.Usage = func() {
+		ui.Println("Usage: playlistporter collage -url <spt-playlist-url> [-out cover.jpg] [-upload]")
+		ui.Println("\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	applyOutput()
+
+	if *sptURL == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	orch := orchestrator.New(cfg, false, "", 1, false)
+	if err := orch.MakeCollage(*sptURL, *out, *upload); err != nil {
+		log.Fatalf("Failed to build collage: %v", err)
+	}
+}
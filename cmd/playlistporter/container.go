@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/auth"
+	"github.com/Verryx-02/PlaylistPorter/internal/config"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+const (
+	defaultContainerDataDir = "/data"
+	containerConfigPath     = "configs/config.yaml" // Relative to the data directory
+)
+
+// setupContainer moves into the data directory so states, logs and the
+// optional config file all live under a single mount
+func setupContainer() (string, error) {
+	dataDir := os.Getenv("PLAYLISTPORTER_DATA_DIR")
+	if dataDir == "" {
+		dataDir = defaultContainerDataDir
+	}
+
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return "", fmt.Errorf("creating data directory: %w", err)
+	}
+	if err := os.Chdir(dataDir); err != nil {
+		return "", fmt.Errorf("entering data directory: %w", err)
+	}
+
+	// Keep OAuth tokens, states and logs on the data volume so they survive container restarts
+	for env, dir := range map[string]string{
+		"PLAYLISTPORTER_TOKEN_DIR":  "tokens",
+		"PLAYLISTPORTER_STATES_DIR": "states",
+		"PLAYLISTPORTER_LOGS_DIR":   "logs",
+	} {
+		if os.Getenv(env) == "" {
+			os.Setenv(env, filepath.Join(dataDir, dir))
+		}
+	}
+
+	return dataDir, nil
+}
+
+// loadContainerConfig uses the config file when present and the environment otherwise
+func loadContainerConfig(path string) (*config.Config, error) {
+	if _, err := os.Stat(path); err == nil {
+		return config.Load(path)
+	}
+	return config.LoadFromEnv()
+}
+
+// useStderrForPeople keeps stdout for the JSON log lines, so log collectors can parse every
+// line of it: progress, summaries and sign-in prompts go to stderr. It returns the original
+// stdout for the JSON writer.
+func useStderrForPeople() io.Writer {
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	ui.SetOutput(os.Stderr)
+	return stdout
+}
+
+// headlessSignIn makes sign-in work without a browser, since the local callback server of a
+// container can't be reached: YouTube uses the device code flow unless PLAYLISTPORTER_TUBO_DEVICE_CODE
+// turns it off, and every sign-in falls back to pasting the authorization code
+func headlessSignIn(cfg *config.Config) {
+	if _, set := cfg.EnvOverrides()["tubo.device_code"]; !set {
+		cfg.TUBO.DeviceCode = true
+	}
+	auth.SetNoBrowser(true)
+}
+
+// jsonLogWriter turns each log line into a JSON object on the underlying writer
+type jsonLogWriter struct {
+	out io.Writer
+}
+
+func (w *jsonLogWriter) Write(p []byte) (int, error) {
+	// The orchestrator prefixes each message with "[<run>] " or "[<run>-t<n>] "; the IDs
+	// become fields of every line of the message
+	text := string(bytes.TrimRight(p, "\n"))
+	run, trace := "", ""
+	if prefix, rest, ok := strings.Cut(text, "] "); ok && strings.HasPrefix(prefix, "[") && !strings.ContainsAny(prefix, " \n") {
+		run, _, _ = strings.Cut(prefix[1:], "-t")
+		if run != prefix[1:] {
+			trace = prefix[1:]
+		}
+		text = rest
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		msg := strings.TrimSpace(line)
+		if msg == "" {
+			continue
+		}
+
+		fields := map[string]string{
+			"time":  time.Now().Format(time.RFC3339),
+			"level": "info",
+			"msg":   msg,
+		}
+		if run != "" {
+			fields["run"] = run
+		}
+		if trace != "" {
+			fields["trace"] = trace
+		}
+		entry, err := json.Marshal(fields)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := w.out.Write(append(entry, '\n')); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/auth"
+	"github.com/Verryx-02/PlaylistPorter/internal/config"
+	"github.com/Verryx-02/PlaylistPorter/internal/paths"
+	"github.com/Verryx-02/PlaylistPorter/internal/spt"
+	"github.com/Verryx-02/PlaylistPorter/internal/tubo"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// doctorReport collects the outcome of the doctor checks
+type doctorReport struct {
+	failed, warned int
+}
+
+func (r *doctorReport) pass(check, detail string) {
+	ui.Printf("%s: %s\n", ui.Green("✅ "+check), detail)
+}
+
+func (r *doctorReport) warn(check, detail string) {
+	r.warned++
+	ui.Printf("⚠️  %s: %s\n", check, detail)
+}
+
+func (r *doctorReport) fail(check string, err error) {
+	r.failed++
+	ui.Printf("%s: %v\n", ui.Red("❌ "+check), err)
+}
+
+// runDoctor checks the configuration, credentials, sign-in callback and directories before
+// a run, without spending any quota
+func runDoctor(args []string) {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	applyOutput := registerOutputFlags(fs)
+	configPath := fs.String("config", paths.Config(), "Path to configuration file")
+	offline := fs.Bool("offline", false, "Skip the checks that contact Spotify and Google")
+	fs.Usage = func() {
+		ui.Println("Usage: playlistporter doctor [-config path] [-offline]")
+		ui.Println("\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	applyOutput()
+
+	ui.Printf("🩺 Checking the setup\n")
+	ui.Printf("====================\n\n")
+	report := &doctorReport{}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		report.fail("Config", err)
+	} else {
+		report.pass("Config", *configPath+" is valid")
+	}
+
+	if cfg != nil && !*offline {
+		checkSpotify(report, cfg)
+		checkYouTube(report, cfg)
+	}
+	if cfg != nil {
+		checkCallbacks(report, cfg)
+	}
+
+	tokenDir, err := auth.TokenDir()
+	if err != nil {
+		report.fail("Token directory", err)
+	}
+	for _, m := range paths.Legacy() {
+		report.warn("Legacy "+m.From, fmt.Sprintf("no longer used, %s is; move it with playlistporter migrate", m.To))
+	}
+	for _, dir := range []string{paths.States(), paths.Logs(), "exports", tokenDir} {
+		if dir == "" {
+			continue
+		}
+		if err := checkWritable(dir); err != nil {
+			report.fail("Directory "+dir, err)
+		} else {
+			report.pass("Directory "+dir, "writable")
+		}
+	}
+
+	ui.Println()
+	switch {
+	case report.failed > 0:
+		ui.Summaryf("%s\n", ui.Red(fmt.Sprintf("❌ %d checks failed, %d warnings; fix them before porting", report.failed, report.warned)))
+		os.Exit(1)
+	case report.warned > 0:
+		ui.Summaryf("⚠️  All checks passed with %d warnings\n", report.warned)
+	default:
+		ui.Summaryf("%s\n", ui.Green("✅ All checks passed, ready to port"))
+	}
+}
+
+// checkSpotify requests an app token with the Spotify credentials
+func checkSpotify(report *doctorReport, cfg *config.Config) {
+	if cfg.SPT.ClientSecret != "" {
+		if err := spt.CheckCredentials(&cfg.SPT); err != nil {
+			report.fail("Spotify credentials", err)
+		} else {
+			report.pass("Spotify credentials", "client credentials accepted")
+		}
+	}
+
+	if !cfg.SPT.UserAuth {
+		return
+	}
+	token, err := auth.LoadToken(spt.TokenCacheName)
+	switch {
+	case err != nil:
+		report.fail("Spotify sign-in", err)
+	case token == nil:
+		report.warn("Spotify sign-in", "not signed in yet; run playlistporter auth spotify")
+	default:
+		report.pass("Spotify sign-in", "saved authorization found")
+	}
+}
+
+// checkYouTube refreshes the saved YouTube authorization, which also confirms the client
+func checkYouTube(report *doctorReport, cfg *config.Config) {
+	check := "YouTube sign-in"
+	if cfg.TUBO.Account != "" {
+		check += " (account " + cfg.TUBO.Account + ")"
+	}
+
+	err := tubo.CheckAuthorization(&cfg.TUBO)
+	switch {
+	case errors.Is(err, auth.ErrNoToken):
+		report.warn(check, "not signed in yet, so the client can't be checked; run playlistporter auth youtube")
+	case err != nil:
+		report.fail(check, err)
+	default:
+		report.pass(check, "saved authorization refreshed, client ID and secret accepted")
+	}
+}
+
+// checkCallbacks makes sure the browser can reach the sign-in callback servers
+func checkCallbacks(report *doctorReport, cfg *config.Config) {
+	if cfg.TUBO.DeviceCode {
+		report.pass("YouTube callback", "not needed with tubo.device_code")
+	} else if uri, err := auth.CheckCallback(cfg.TUBO.RedirectURI, cfg.TUBO.CallbackPort); err != nil {
+		if cfg.TUBO.CallbackPort == auth.AutoPort {
+			report.fail("YouTube callback", err)
+		} else {
+			report.warn("YouTube callback", fmt.Sprintf("%v; sign-in will fall back to a free port", err))
+		}
+	} else {
+		report.pass("YouTube callback", uri+" is reachable")
+	}
+
+	if cfg.SPT.UserAuth {
+		// Spotify only redirects to the registered URI, so its port has to be free
+		if uri, err := auth.CheckCallback(spt.RedirectURI(&cfg.SPT), ""); err != nil {
+			report.fail("Spotify callback", err)
+		} else {
+			report.pass("Spotify callback", uri+" is reachable")
+		}
+	}
+}
+
+// checkWritable creates dir if needed and writes a file into it
+func checkWritable(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	probe, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return err
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
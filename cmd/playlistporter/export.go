@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/config"
+	"github.com/Verryx-02/PlaylistPorter/internal/export"
+	"github.com/Verryx-02/PlaylistPorter/internal/orchestrator"
+	"github.com/Verryx-02/PlaylistPorter/internal/paths"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// runExport writes a saved playlist with its matches to a standard playlist format
+func runExport(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	applyOutput := registerOutputFlags(fs)
+	sptURL := fs.String("url", "", "SPT playlist URL of the saved state")
+	configPath := fs.String("config", paths.Config(), "Path to configuration file")
+	format := fs.String("format", export.FormatM3U, "Export format: "+strings.Join(export.Formats, ", "))
+	out := fs.String("out", "", "Output path (default: exports/playlist_<id>.<format>)")
+	fs.Usage = func() {
+		ui.Println("Usage: playlistporter export -url <spt-playlist-url> [-format m3u|xspf|csv|json|html] [-out file]")
+		ui.Println("\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	applyOutput()
+
+	if *sptURL == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	orch := orchestrator.New(cfg, false, "", 1, false)
+	if err := orch.ExportPlaylist(*sptURL, *format, *out); err != nil {
+		log.Fatalf("Failed to export playlist: %v", err)
+	}
+}
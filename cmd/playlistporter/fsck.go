@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/paths"
+	"github.com/Verryx-02/PlaylistPorter/internal/state"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// runFsck validates every saved state and optionally repairs it
+func runFsck(args []string) {
+	fs := flag.NewFlagSet("fsck", flag.ExitOnError)
+	applyOutput := registerOutputFlags(fs)
+	repair := fs.Bool("repair", false, "Automatically repair inconsistent states, and keep hand edits that fail the checksum")
+	fs.Usage = func() {
+		ui.Println("Usage: playlistporter fsck [options]")
+		ui.Println("\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	applyOutput()
+
+	stateManager, err := state.NewManager(paths.States())
+	if err != nil {
+		log.Fatalf("Failed to open states directory: %v", err)
+	}
+
+	files, err := stateManager.ListStates()
+	if err != nil {
+		log.Fatalf("Failed to list states: %v", err)
+	}
+
+	ui.Printf("🩺 Checking %d saved states\n", len(files))
+	ui.Printf("==========================\n\n")
+
+	if stateManager.ReplayedJournal() {
+		ui.Printf("🔁 Completed a save that was interrupted, e.g. by a crash; the state and index are in step again\n\n")
+	}
+
+	broken := 0
+	for _, name := range files {
+		portingState, err := stateManager.LoadStateFile(name)
+
+		// A file that parses but fails its checksum was most likely edited by hand; -repair
+		// accepts the edit and stamps a new checksum
+		edited := false
+		if errors.Is(err, state.ErrChecksumMismatch) && *repair {
+			portingState, err = stateManager.LoadEditedStateFile(name)
+			edited = err == nil
+		}
+		if err != nil {
+			broken++
+			ui.Printf("%s\n", ui.Red("❌ "+name))
+			ui.Printf("   Cannot be read: %v\n", err)
+			if errors.Is(err, state.ErrChecksumMismatch) {
+				ui.Printf("   If you edited it by hand, run with -repair to keep the edit and update the checksum\n")
+			}
+			ui.Println()
+			continue
+		}
+
+		issues := portingState.Check()
+		if len(issues) == 0 && !edited {
+			ui.Printf("%s (%s)\n", ui.Green("✅ "+name), portingState.OriginalPlaylist.Name)
+			continue
+		}
+
+		ui.Printf("⚠️  %s (%s)\n", name, portingState.OriginalPlaylist.Name)
+		if edited {
+			ui.Printf("   • checksum doesn't match the content, e.g. after an edit by hand\n")
+		}
+		for _, issue := range issues {
+			ui.Printf("   • %s\n", issue)
+		}
+
+		if !*repair {
+			broken++
+			ui.Println()
+			continue
+		}
+
+		portingState.Repair()
+		if remaining := portingState.Check(); len(remaining) > 0 {
+			broken++
+			ui.Printf("   ❌ %d problems could not be repaired\n\n", len(remaining))
+			continue
+		}
+
+		if err := stateManager.SaveState(portingState); err != nil {
+			broken++
+			ui.Printf("   ❌ Failed to save repaired state: %v\n\n", err)
+			continue
+		}
+		ui.Printf("   🔧 Repaired\n\n")
+	}
+
+	if broken > 0 {
+		ui.Printf("\n%d states need attention", broken)
+		if !*repair {
+			ui.Printf(" (run with -repair to fix them)")
+		}
+		ui.Println()
+		os.Exit(1)
+	}
+
+	ui.Println("\nAll states are consistent.")
+}
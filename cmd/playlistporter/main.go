@@ -1,157 +1,662 @@
 package main
 
 import (
-	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
-	"playlistporter/internal/config"
-	"playlistporter/internal/orchestrator"
+	"github.com/Verryx-02/PlaylistPorter/internal/auth"
+	"github.com/Verryx-02/PlaylistPorter/internal/collate"
+	"github.com/Verryx-02/PlaylistPorter/internal/config"
+	"github.com/Verryx-02/PlaylistPorter/internal/localfile"
+	"github.com/Verryx-02/PlaylistPorter/internal/orchestrator"
+	"github.com/Verryx-02/PlaylistPorter/internal/paths"
+	"github.com/Verryx-02/PlaylistPorter/internal/quota"
+	"github.com/Verryx-02/PlaylistPorter/internal/state"
+	"github.com/Verryx-02/PlaylistPorter/internal/traffic"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
 )
 
 func main() {
-	var (
-		sptURL     = flag.String("url", "", "SPT playlist URL to port")
-		configPath = flag.String("config", "configs/config.yaml", "Path to configuration file")
-		verbose    = flag.Bool("v", false, "Verbose output")
-		logFile    = flag.String("log", "", "Log file path (optional). If empty, creates logs/porting_TIMESTAMP.log")
-		maxTracks  = flag.Int("max-tracks", 50, "Maximum number of tracks to process in this session (default: 50)")
-		showStates = flag.Bool("list-states", false, "List all saved porting states")
-		syncMode   = flag.Bool("sync", false, "Check for new tracks on completed playlists and sync them")
-	)
-	flag.Parse()
+	// Subcommands are handled before the porting flags
+	if len(os.Args) > 1 {
+		if !strings.HasPrefix(os.Args[1], "-") && !ownStatesDir[os.Args[1]] {
+			warnLegacyPaths()
+		}
+		switch os.Args[1] {
+		case "migrate":
+			runMigrate(os.Args[2:])
+			return
+		case "setup":
+			runSetup(os.Args[2:])
+			return
+		case "search":
+			runSearch(os.Args[2:])
+			return
+		case "fsck":
+			runFsck(os.Args[2:])
+			return
+		case "strategies":
+			runStrategies(os.Args[2:])
+			return
+		case "annotate":
+			runAnnotate(os.Args[2:])
+			return
+		case "collage":
+			runCollage(os.Args[2:])
+			return
+		case "export":
+			runExport(os.Args[2:])
+			return
+		case "outdated":
+			runOutdated(os.Args[2:])
+			return
+		case "tag":
+			runTag(os.Args[2:])
+			return
+		case "mappings":
+			runMappings(os.Args[2:])
+			return
+		case "auth":
+			runAuth(os.Args[2:])
+			return
+		case "warm-cache":
+			runWarmCache(os.Args[2:])
+			return
+		case "timeline":
+			runTimeline(os.Args[2:])
+			return
+		case "doctor":
+			runDoctor(os.Args[2:])
+			return
+		case "positions":
+			runPositions(os.Args[2:])
+			return
+		case "selftest", "-selftest":
+			runSelftest(os.Args[2:])
+			return
+		case "port", "sync":
+			runNamed(os.Args[1], os.Args[2:])
+			return
+		}
+	}
+
+	if err := runPorting(os.Args[1:], ""); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// ownStatesDir lists the subcommands that pick their own states directory or take the porting
+// flags, and so warn about legacy paths themselves, if at all
+var ownStatesDir = map[string]bool{
+	"migrate": true, "port": true, "sync": true, "selftest": true, "doctor": true,
+}
+
+// runPorting runs the porting options given in args; name selects an entry of the config's
+// playlists section in place of -url. Problems with the options end the program, the error
+// of the run itself is returned.
+func runPorting(args []string, name string) error {
+	opts := newRunOptions()
+	if err := opts.parse(args); err != nil {
+		log.Fatalf("Invalid option: %v", err)
+	}
+
+	// The config file supplies the options given neither on the command line nor in the
+	// environment; -list-states and -show-config also work without one
+	cfg, dataDir, cfgErr := loadRunConfig(opts)
+	if name != "" {
+		if cfgErr != nil {
+			log.Fatalf("Failed to load config: %v", cfgErr)
+		}
+		entry, err := cfg.Playlist(name)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if opts.hasTarget() {
+			log.Fatalf("%s names the playlist; don't combine it with -url, -file, -merge, -tag or -all-playlists", name)
+		}
+		opts.URL = entry.URL
+		opts.origins["url"] = "config playlists." + name + ".url"
+		if err := opts.applyConfig(entry.Defaults, "playlists."+name+".defaults"); err != nil {
+			log.Fatalf("Invalid config: %v", err)
+		}
+	}
+	if cfgErr == nil {
+		if err := opts.applyConfig(cfg.Defaults, "defaults"); err != nil {
+			log.Fatalf("Invalid config: %v", err)
+		}
+	}
+	opts.applyOutput()
+	paths.SetStates(opts.StatesDir)
+	paths.SetLogs(opts.LogsDir)
+	if !opts.Container {
+		warnLegacyPaths()
+	}
+
+	if opts.ShowConfig {
+		opts.printEffective(cfg)
+		if cfgErr != nil {
+			ui.Printf("⚠️  Config not loaded: %v\n\n", cfgErr)
+		}
+		if !opts.hasTarget() && !opts.ListStates {
+			return nil
+		}
+	}
 
 	// If listing states, do that and exit
-	if *showStates {
-		listSavedStates()
-		return
+	if opts.ListStates {
+		costs, ytmusic := quota.DefaultCosts, false
+		if cfg != nil {
+			costs, ytmusic = cfg.Quota.Costs(), cfg.TUBO.SearchBackend == config.SearchYTMusic
+		}
+		listSavedStates(opts.Tag, opts.MaxTracks, costs.PerTrack(ytmusic), costs.DailyLimit)
+		return nil
+	}
+
+	// A playlist file takes the place of the link
+	if opts.FilePath != "" {
+		if opts.URL != "" {
+			log.Fatalf("use either -url or -file, not both")
+		}
+		if !localfile.IsPlaylistFile(opts.FilePath) {
+			log.Fatalf("-file must be a .csv, .m3u, .m3u8 or .xspf file, or an iTunes Library.xml#Playlist")
+		}
+		opts.URL = opts.FilePath
+	}
+
+	if opts.AllPlaylists && (opts.URL != "" || opts.MergeURLs != "") {
+		log.Fatalf("-all-playlists can't be combined with -url, -file or -merge")
 	}
 
-	if *sptURL == "" {
-		fmt.Println("Usage: playlistporter -url <spt-playlist-url>")
-		fmt.Println("\nOptions:")
-		flag.PrintDefaults()
-		fmt.Println("\nExamples:")
-		fmt.Println("  # Process first 50 tracks (default)")
-		fmt.Println("  playlistporter -url https://open.spotify.com/playlist/...")
-		fmt.Println("")
-		fmt.Println("  # Process only 20 tracks (to save quota)")
-		fmt.Println("  playlistporter -url https://open.spotify.com/playlist/... -max-tracks 20")
-		fmt.Println("")
-		fmt.Println("  # Check for new tracks on a completed playlist")
-		fmt.Println("  playlistporter -url https://open.spotify.com/playlist/... -sync")
-		fmt.Println("")
-		fmt.Println("  # List all saved states")
-		fmt.Println("  playlistporter -list-states")
+	if opts.Tag != "" && (opts.URL != "" || opts.MergeURLs != "" || opts.AllPlaylists) {
+		log.Fatalf("-tag can't be combined with -url, -file, -merge or -all-playlists")
+	}
+
+	if opts.URL == "" && opts.MergeURLs == "" && !opts.AllPlaylists && opts.Tag == "" {
+		ui.Println("Usage: playlistporter -url <spt-playlist-url>")
+		ui.Println("\nOptions:")
+		opts.fs.PrintDefaults()
+		ui.Println("\nExamples:")
+		ui.Println("  # First run: create the API credentials and the config file step by step")
+		ui.Println("  playlistporter setup")
+		ui.Println("")
+		ui.Println("  # Process first 50 tracks (default)")
+		ui.Println("  playlistporter -url https://open.spotify.com/playlist/...")
+		ui.Println("")
+		ui.Println("  # Port an album, or check how a single track matches")
+		ui.Println("  playlistporter -url https://open.spotify.com/album/...")
+		ui.Println("  playlistporter -url https://open.spotify.com/track/...")
+		ui.Println("")
+		ui.Println("  # Port your Liked Songs (signs in to Spotify)")
+		ui.Println("  playlistporter -url liked")
+		ui.Println("")
+		ui.Println("  # Port every playlist in your library, 200 tracks per run in total")
+		ui.Println("  playlistporter -all-playlists -max-tracks 200")
+		ui.Println("")
+		ui.Println("  # Port a playlist exported with Exportify")
+		ui.Println("  playlistporter -file my_playlist.csv")
+		ui.Println("")
+		ui.Println("  # Port a playlist from an iTunes/Music.app library export")
+		ui.Println("  playlistporter -file \"Library.xml#Road Trip\"")
+		ui.Println("")
+		ui.Println("  # Process only 20 tracks (to save quota)")
+		ui.Println("  playlistporter -url https://open.spotify.com/playlist/... -max-tracks 20")
+		ui.Println("")
+		ui.Println("  # Check for new tracks on a completed playlist")
+		ui.Println("  playlistporter -url https://open.spotify.com/playlist/... -sync")
+		ui.Println("")
+		ui.Println("  # Port an editorial playlist once, or keep following it")
+		ui.Println("  playlistporter -url https://open.spotify.com/playlist/... -mode snapshot")
+		ui.Println("  playlistporter -url https://open.spotify.com/playlist/... -mode follow -sync")
+		ui.Println("")
+		ui.Println("  # Archive Discover Weekly every Monday (e.g. from cron)")
+		ui.Println("  playlistporter -url https://open.spotify.com/playlist/... -mode archive")
+		ui.Println("")
+		ui.Println("  # Work for at most 30 minutes")
+		ui.Println("  playlistporter -url https://open.spotify.com/playlist/... -max-duration 30m")
+		ui.Println("")
+		ui.Println("  # Unattended run printing only the session summary")
+		ui.Println("  playlistporter -url https://open.spotify.com/playlist/... -sync -quiet")
+		ui.Println("")
+		ui.Println("  # Spend today's quota on searching only, upload later")
+		ui.Println("  playlistporter -url https://open.spotify.com/playlist/... -phase match")
+		ui.Println("  playlistporter -url https://open.spotify.com/playlist/... -phase upload")
+		ui.Println("")
+		ui.Println("  # Merge several playlists into one YouTube playlist")
+		ui.Println("  playlistporter -merge https://open.spotify.com/playlist/A,https://open.spotify.com/playlist/B -merge-name \"Road Trip\"")
+		ui.Println("")
+		ui.Println("  # Check the matches against Odesli (song.link), 50 tracks per run")
+		ui.Println("  playlistporter -url https://open.spotify.com/playlist/... -crosscheck")
+		ui.Println("")
+		ui.Println("  # Rebuild a deleted YouTube playlist from stored matches")
+		ui.Println("  playlistporter -url https://open.spotify.com/playlist/... -recreate-target")
+		ui.Println("")
+		ui.Println("  # List all saved states")
+		ui.Println("  playlistporter -list-states")
+		ui.Println("")
+		ui.Println("  # Tag playlists, then sync all playlists with a tag")
+		ui.Println("  playlistporter tag -url https://open.spotify.com/playlist/... -add workout")
+		ui.Println("  playlistporter -tag workout -sync")
+		ui.Println("")
+		ui.Println("  # Search tracks across all saved states")
+		ui.Println("  playlistporter search \"daft punk\"")
+		ui.Println("")
+		ui.Println("  # Check saved states for inconsistencies and repair them")
+		ui.Println("  playlistporter fsck -repair")
+		ui.Println("")
+		ui.Println("  # Note that track 12 should be a live version, and leave track 13 out")
+		ui.Println("  playlistporter annotate -url https://open.spotify.com/playlist/... -track 12 -note \"prefer live version\"")
+		ui.Println("  playlistporter annotate -url https://open.spotify.com/playlist/... -track 13 -note skip")
+		ui.Println("")
+		ui.Println("  # Build a cover collage from the album arts and use it on YouTube")
+		ui.Println("  playlistporter collage -url https://open.spotify.com/playlist/... -upload")
+		ui.Println("")
+		ui.Println("  # Export the matched playlist for other players and tools")
+		ui.Println("  playlistporter export -url https://open.spotify.com/playlist/... -format xspf")
+		ui.Println("")
+		ui.Println("  # Replay the past sessions of a playlist on a timeline")
+		ui.Println("  playlistporter timeline -url https://open.spotify.com/playlist/...")
+		ui.Println("")
+		ui.Println("  # List tracks matched by older matching rules, then re-match them")
+		ui.Println("  playlistporter outdated -url https://open.spotify.com/playlist/...")
+		ui.Println("  playlistporter -url https://open.spotify.com/playlist/... -rematch-outdated")
+		ui.Println("")
+		ui.Println("  # Show which search strategies produce the matches")
+		ui.Println("  playlistporter strategies")
+		ui.Println("")
+		ui.Println("  # Use verified matches shared by someone who ported similar playlists")
+		ui.Println("  playlistporter mappings -import shared_mappings.csv")
+		ui.Println("")
+		ui.Println("  # Show which options and config values take effect, and where they come from")
+		ui.Println("  playlistporter -show-config -max-tracks 20")
+		ui.Println("")
+		ui.Println("  # Port a playlist named in the config, or sync a group of them")
+		ui.Println("  playlistporter port workout")
+		ui.Println("  playlistporter sync -group favorites")
+		ui.Println("")
+		ui.Println("  # Check the config, credentials and directories before spending quota")
+		ui.Println("  playlistporter doctor")
+		ui.Println("")
+		ui.Println("  # Port 3 tracks into a throwaway playlist, check it and clean up")
+		ui.Println("  playlistporter selftest -url https://open.spotify.com/playlist/...")
+		ui.Println("")
+		ui.Println("  # Sign in ahead of a long unattended run")
+		ui.Println("  playlistporter auth youtube")
+		ui.Println("")
+		ui.Println("  # Search tomorrow's batch overnight with a small quota budget")
+		ui.Println("  playlistporter warm-cache -units 1000")
 		os.Exit(1)
 	}
 
 	// Validate max tracks
-	if *maxTracks < 1 {
+	if opts.MaxTracks < 1 {
 		log.Fatalf("max-tracks must be at least 1")
 	}
 
-	// Setup logging
+	if opts.MaxDuration < 0 {
+		log.Fatalf("max-duration must not be negative")
+	}
+	if opts.Timeout < 0 {
+		log.Fatalf("timeout must not be negative")
+	}
+
+	// Validate phase
+	switch opts.Phase {
+	case orchestrator.PhaseAll, orchestrator.PhaseMatch, orchestrator.PhaseUpload:
+	default:
+		log.Fatalf("phase must be one of: all, match, upload")
+	}
+
+	// Validate destination
+	switch opts.Dest {
+	case "", orchestrator.DestYouTube, orchestrator.DestSoundCloud, orchestrator.DestSpotify, orchestrator.DestJellyfin, orchestrator.DestFolder:
+	default:
+		log.Fatalf("dest must be one of: youtube, soundcloud, spotify, jellyfin, folder")
+	}
+
+	if err := config.CheckAccountName(opts.Account); err != nil {
+		log.Fatalf("-account: %v", err)
+	}
+
+	// Validate failure policy
+	switch opts.OnFailure {
+	case orchestrator.FailureContinue, orchestrator.FailurePause, orchestrator.FailureAbort:
+	default:
+		log.Fatalf("on-failure must be one of: continue, pause, abort")
+	}
+
+	// Validate mode
+	switch opts.Mode {
+	case "", state.ModeSnapshot, state.ModeFollow, state.ModeArchive:
+	default:
+		log.Fatalf("mode must be one of: snapshot, follow, archive")
+	}
+
+	// Quiet runs keep the detailed log file in place of the terminal output
+	if opts.Quiet {
+		ui.SetQuiet(true)
+		opts.Verbose = true
+	}
+
+	var jsonOut io.Writer = os.Stdout
+	if opts.Container {
+		jsonOut = useStderrForPeople()
+		ui.Printf("📦 Container mode: data directory %s\n", dataDir)
+	}
+
+	// Setup logging (in container mode detailed logs go to stdout instead)
 	var logFilePath string
-	if *logFile != "" {
-		logFilePath = *logFile
-	} else {
+	if opts.LogFile != "" {
+		logFilePath = opts.LogFile
+	} else if !opts.Container {
 		// Create logs directory if it doesn't exist
-		if err := os.MkdirAll("logs", 0755); err != nil {
+		logsDir := paths.Logs()
+		if err := os.MkdirAll(logsDir, 0755); err != nil {
 			log.Fatalf("Failed to create logs directory: %v", err)
 		}
 
 		// Generate filename with timestamp
 		timestamp := time.Now().Format("20060102_150405")
-		logFilePath = fmt.Sprintf("logs/porting_%s.log", timestamp)
+		logFilePath = filepath.Join(logsDir, fmt.Sprintf("porting_%s.log", timestamp))
+		if name != "" {
+			logFilePath = filepath.Join(logsDir, fmt.Sprintf("porting_%s_%s.log", timestamp, name))
+		}
 	}
 
-	// Load configuration
-	cfg, err := config.Load(*configPath)
-	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+	if cfgErr != nil {
+		log.Fatalf("Failed to load config: %v", cfgErr)
+	}
+	if opts.Container {
+		headlessSignIn(cfg)
+	}
+	if opts.Split && len(cfg.Split) == 0 {
+		log.Fatalf("split mode needs at least one rule in the 'split' section of the config")
 	}
 
-	fmt.Printf("🎵 PlaylistPorter Starting\n")
-	fmt.Printf("===========================\n")
-	fmt.Printf("📋 Playlist URL: %s\n", *sptURL)
-	fmt.Printf("🔢 Max tracks per session: %d\n", *maxTracks)
-	if *syncMode {
-		fmt.Printf("🔄 Sync mode: ENABLED (checking for new tracks)\n")
+	ui.Printf("🎵 PlaylistPorter Starting\n")
+	ui.Printf("===========================\n")
+	var sourceURLs []string
+	if opts.MergeURLs != "" {
+		for _, u := range strings.Split(opts.MergeURLs, ",") {
+			if u = strings.TrimSpace(u); u != "" {
+				sourceURLs = append(sourceURLs, u)
+			}
+		}
+		if len(sourceURLs) < 2 {
+			log.Fatalf("merge needs at least two playlist URLs")
+		}
+		ui.Printf("🔀 Merging %d playlists\n", len(sourceURLs))
+	} else if opts.AllPlaylists {
+		ui.Printf("📚 Porting all playlists of your Spotify account\n")
+	} else if opts.Tag != "" {
+		ui.Printf("🏷️  Playlists tagged: %s\n", state.NormalizeTag(opts.Tag))
+	} else {
+		ui.Printf("📋 Playlist URL: %s\n", opts.URL)
+	}
+	ui.Printf("🔢 Max tracks per session: %d\n", opts.MaxTracks)
+	if opts.MaxDuration > 0 {
+		ui.Printf("⏱️  Time budget: %s\n", opts.MaxDuration)
+	}
+	if opts.Timeout > 0 {
+		ui.Printf("⏱️  Run timeout: %s\n", opts.Timeout)
 	}
-	if *verbose {
-		fmt.Printf("📝 Detailed logs: %s\n", logFilePath)
-		fmt.Printf("💡 Follow progress: tail -f %s\n", logFilePath)
+	if opts.Light {
+		traffic.SetLight(true)
+		ui.Printf("📶 Bandwidth-light mode: ENABLED\n")
 	}
-	fmt.Printf("⏳ Processing...\n\n")
+	if opts.NoBrowser {
+		auth.SetNoBrowser(true)
+		ui.Printf("🔑 Headless sign-in: paste authorization codes into the terminal\n")
+	}
+	if opts.Sync {
+		ui.Printf("🔄 Sync mode: ENABLED (checking for new tracks)\n")
+	}
+	if opts.Phase != orchestrator.PhaseAll {
+		ui.Printf("🧭 Phase: %s\n", opts.Phase)
+	}
+	if opts.Dest != "" && opts.Dest != orchestrator.DestYouTube {
+		ui.Printf("🎯 Destination: %s\n", opts.Dest)
+	}
+	if opts.Account != "" {
+		ui.Printf("👤 YouTube account: %s\n", opts.Account)
+	}
+	if opts.Verbose && logFilePath != "" {
+		ui.Printf("📝 Detailed logs: %s\n", logFilePath)
+		ui.Printf("💡 Follow progress: tail -f %s\n", logFilePath)
+	}
+	ui.Printf("⏳ Processing...\n\n")
 
 	// Show quota information
-	showQuotaInfo(*maxTracks)
+	showQuotaInfo(cfg, opts.MaxTracks)
 
 	// Initialize orchestrator with log file, max tracks, and sync mode
-	orch := orchestrator.New(cfg, *verbose, logFilePath, *maxTracks, *syncMode)
+	orch := orchestrator.New(cfg, opts.Verbose, logFilePath, opts.MaxTracks, opts.Sync)
+	if opts.Container && logFilePath == "" {
+		orch.SetLogWriter(&jsonLogWriter{out: jsonOut})
+	}
+	orch.SetPhase(opts.Phase)
+	orch.SetSplit(opts.Split)
+	orch.SetHoldOnRegression(opts.HoldRegress)
+	orch.SetMode(opts.Mode)
+	orch.SetArchiveWeekly(opts.Weekly)
+	orch.SetMaxDuration(opts.MaxDuration)
+	orch.SetTimeout(opts.Timeout)
+	orch.SetSessionNote(opts.SessionNote)
+	orch.SetStrictISRC(opts.StrictISRC)
+	orch.SetFailurePolicy(opts.OnFailure)
+	orch.SetDestination(opts.Dest)
+	orch.SetAccount(opts.Account)
+
+	// Merge several source playlists into one target
+	if len(sourceURLs) > 0 {
+		if err := orch.MergePlaylists(sourceURLs, opts.MergeName); err != nil {
+			return fmt.Errorf("Failed to merge playlists: %w", err)
+		}
+		return nil
+	}
+
+	// Port the whole Spotify library round-robin
+	if opts.AllPlaylists {
+		if err := orch.PortAllPlaylists(); err != nil {
+			return fmt.Errorf("Failed to port playlists: %w", err)
+		}
+		return nil
+	}
+
+	// Run every playlist with the tag
+	if opts.Tag != "" {
+		if err := orch.PortTagged(opts.Tag); err != nil {
+			return fmt.Errorf("Failed to port tagged playlists: %w", err)
+		}
+		return nil
+	}
+
+	// Cross-check matches with Odesli
+	if opts.CrossCheck {
+		if err := orch.CrossCheck(opts.URL); err != nil {
+			return fmt.Errorf("Failed to cross-check matches: %w", err)
+		}
+		return nil
+	}
+
+	// Replace matched videos that were deleted on YouTube
+	if opts.Verify {
+		if err := orch.VerifyPlaylist(opts.URL); err != nil {
+			return fmt.Errorf("Failed to verify playlist: %w", err)
+		}
+		return nil
+	}
+
+	// Retry tracks that failed in earlier sessions
+	if opts.Retry {
+		if err := orch.RetryFailed(opts.URL); err != nil {
+			return fmt.Errorf("Failed to retry failed tracks: %w", err)
+		}
+		return nil
+	}
+
+	// Re-match tracks whose results older matching rules produced
+	if opts.Rematch {
+		if err := orch.RematchOutdated(opts.URL); err != nil {
+			return fmt.Errorf("Failed to re-match outdated results: %w", err)
+		}
+		return nil
+	}
+
+	// Rebuild the target playlist from stored matches
+	if opts.Recreate {
+		if err := orch.RecreateTarget(opts.URL); err != nil {
+			return fmt.Errorf("Failed to recreate target playlist: %w", err)
+		}
+		return nil
+	}
 
 	// Execute playlist porting
-	if err := orch.PortPlaylist(*sptURL); err != nil {
-		log.Fatalf("Failed to port playlist: %v", err)
+	if err := orch.PortPlaylist(opts.URL); err != nil {
+		return fmt.Errorf("Failed to port playlist: %w", err)
+	}
+
+	if opts.Verbose && logFilePath != "" {
+		ui.Printf("\n📄 Full details saved to: %s\n", logFilePath)
+	}
+	return nil
+}
+
+// loadRunConfig loads the config file of a run. Container mode keeps everything under one
+// mount, the config file included, so it first moves into the data directory and returns it.
+func loadRunConfig(opts *runOptions) (*config.Config, string, error) {
+	if !opts.Container {
+		cfg, err := config.Load(opts.ConfigPath)
+		return cfg, "", err
 	}
 
-	if *verbose {
-		fmt.Printf("\n📄 Full details saved to: %s\n", logFilePath)
+	dataDir, err := setupContainer()
+	if err != nil {
+		log.Fatalf("Failed to set up container mode: %v", err)
+	}
+	if opts.origins["config"] == "" {
+		opts.ConfigPath = containerConfigPath
 	}
+	cfg, err := loadContainerConfig(opts.ConfigPath)
+	return cfg, dataDir, err
 }
 
-// showQuotaInfo displays information about YouTube API quota usage
-func showQuotaInfo(maxTracks int) {
-	fmt.Printf("\n📊 YouTube API Quota Information:\n")
-	fmt.Printf("==================================\n")
-	fmt.Printf("• Daily quota limit: 10,000 units\n")
-	fmt.Printf("• Search cost: ~100 units per track\n")
-	fmt.Printf("• Estimated usage: ~%d units for %d tracks\n", maxTracks*200, maxTracks)
-	fmt.Printf("• Quota resets: Pacific Time midnight\n\n")
+// showQuotaInfo displays information about YouTube API quota usage, from the quota section
+// of the config
+func showQuotaInfo(cfg *config.Config, maxTracks int) {
+	costs := cfg.Quota.Costs()
+	perTrack := costs.PerTrack(cfg.TUBO.SearchBackend == config.SearchYTMusic)
+	estimate := maxTracks * perTrack
+
+	ui.Printf("\n📊 YouTube API Quota Information:\n")
+	ui.Printf("==================================\n")
+	ui.Printf("• Daily quota limit: %s units\n", ui.FormatCount(costs.DailyLimit))
+	ui.Printf("• Search cost: %s units, insert cost: %s units\n", ui.FormatCount(costs.Search), ui.FormatCount(costs.Insert))
+	ui.Printf("• Estimated usage: ~%s units for %d tracks\n", ui.FormatCount(estimate), maxTracks)
+	if budget := cfg.Quota.SessionBudget; budget > 0 {
+		ui.Printf("• Session budget: %s units\n", ui.FormatCount(budget))
+	}
+	ui.Printf("• Quota resets: Pacific Time midnight, %s\n\n", quota.ResetMessage(time.Now()))
 
-	if maxTracks > 50 {
-		fmt.Printf("⚠️  Warning: Processing %d tracks may use significant quota!\n", maxTracks)
-		fmt.Printf("   Consider using -max-tracks 50 or less.\n\n")
+	if estimate > costs.DailyLimit {
+		ui.Printf("⚠️  Warning: Processing %d tracks may use more than the daily quota!\n", maxTracks)
+		ui.Printf("   Consider using -max-tracks %d or less.\n\n", costs.DailyLimit/perTrack)
 	}
 }
 
-// listSavedStates shows all saved porting states
-func listSavedStates() {
-	fmt.Printf("📂 Saved Porting States\n")
-	fmt.Printf("======================\n\n")
+// listSavedStates shows the saved porting states from the state index, with the sessions and
+// quota still needed to finish each playlist at dailyLimit units per day
+func listSavedStates(tag string, tracksPerSession, unitsPerTrack, dailyLimit int) {
+	ui.Printf("📂 Saved Porting States\n")
+	ui.Printf("======================\n\n")
 
-	// List all files in states directory
-	entries, err := os.ReadDir("states")
+	statesDir := paths.States()
+	if _, err := os.Stat(statesDir); os.IsNotExist(err) {
+		ui.Printf("No saved states found. The states directory %s doesn't exist yet.\n", statesDir)
+		ui.Println("States will be created when you start porting a playlist.")
+		return
+	}
+
+	stateManager, err := state.NewManager(statesDir)
 	if err != nil {
-		if os.IsNotExist(err) {
-			fmt.Println("No saved states found. The 'states' directory doesn't exist yet.")
-			fmt.Println("States will be created when you start porting a playlist.")
-			return
-		}
-		log.Fatalf("Error reading states directory: %v", err)
+		log.Fatalf("Error opening states directory: %v", err)
+	}
+	index, err := stateManager.LoadIndex()
+	if err != nil {
+		log.Fatalf("Error reading the state index: %v", err)
+	}
+	if tag != "" {
+		ui.Printf("🏷️  Tagged #%s\n\n", state.NormalizeTag(tag))
 	}
 
+	var entries []state.IndexEntry
+	for _, entry := range index.Playlists {
+		if tag == "" || entry.HasTag(tag) {
+			entries = append(entries, entry)
+		}
+	}
 	if len(entries) == 0 {
-		fmt.Println("No saved states found.")
+		ui.Println("No saved states found.")
 		return
 	}
+	sort.Slice(entries, func(i, j int) bool {
+		return collate.Less(entries[i].PlaylistName, entries[j].PlaylistName)
+	})
 
+	var total quota.Forecast
+	unfinished := 0
 	for _, entry := range entries {
-		if !entry.IsDir() && entry.Name() != ".gitkeep" {
-			info, err := entry.Info()
-			if err != nil {
-				continue
-			}
+		ui.Printf("📄 %s\n", entry.PlaylistName)
+		ui.Printf("   State file: %s", entry.StateFile)
+		if info, err := os.Stat(filepath.Join(statesDir, entry.StateFile)); err == nil {
+			ui.Printf(" (%s)", ui.FormatBytes(info.Size()))
+		}
+		ui.Printf("\n")
 
-			fmt.Printf("📄 %s\n", entry.Name())
-			fmt.Printf("   Last modified: %s\n", info.ModTime().Format("2006-01-02 15:04:05"))
-			fmt.Printf("   Size: %d bytes\n\n", info.Size())
+		percent := 0.0
+		if entry.TotalTracks > 0 {
+			percent = float64(entry.ProcessedTracks) / float64(entry.TotalTracks) * 100
+		}
+		ui.Printf("   Progress: %d/%d tracks (%.1f%%)", entry.ProcessedTracks, entry.TotalTracks, percent)
+		if entry.IsComplete {
+			ui.Printf(" ✅ COMPLETE")
 		}
+		ui.Printf("\n")
+
+		if !entry.IsComplete {
+			units := 0
+			if entry.Destination == orchestrator.DestYouTube {
+				units = unitsPerTrack
+			}
+			forecast := quota.NewForecast(entry.TotalTracks-entry.ProcessedTracks, tracksPerSession, units, dailyLimit)
+			total = total.Add(forecast)
+			unfinished++
+			ui.Printf("   To finish: %s\n", formatForecast(forecast))
+		}
+		ui.Printf("   Last updated: %s\n\n", entry.LastUpdatedAt.Format("2006-01-02 15:04:05"))
+	}
+
+	if total.Tracks > 0 {
+		ui.Printf("📊 To finish %d unfinished playlists: %s\n", unfinished, formatForecast(total))
+		ui.Printf("   Estimated at %d tracks per session (-max-tracks) and %s quota units per day\n\n",
+			tracksPerSession, ui.FormatCount(dailyLimit))
 	}
 
-	fmt.Println("💡 Tip: When you run the porter with the same playlist URL,")
-	fmt.Println("   it will automatically resume from where it left off.")
+	ui.Println("💡 Tip: When you run the porter with the same playlist URL,")
+	ui.Println("   it will automatically resume from where it left off.")
+}
+
+// formatForecast describes what finishing still takes, e.g.
+// "180 tracks, ~4 sessions, ~36,000 quota units (~4 days)"
+func formatForecast(f quota.Forecast) string {
+	text := fmt.Sprintf("%s tracks, ~%d sessions", ui.FormatCount(f.Tracks), f.Sessions)
+	if f.Units > 0 {
+		text += fmt.Sprintf(", ~%s quota units (~%d days)", ui.FormatCount(f.Units), f.Days)
+	}
+	return text
 }
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/orchestrator"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// runMappings imports shared mapping files of verified matches, or exports your own
+func runMappings(args []string) {
+	fs := flag.NewFlagSet("mappings", flag.ExitOnError)
+	applyOutput := registerOutputFlags(fs)
+	importPaths := fs.String("import", "", "Comma-separated mapping files to import (.csv or .json) with Spotify track IDs and YouTube video IDs")
+	replace := fs.Bool("replace", false, "Let the imported files replace mappings you already have for the same tracks")
+	exportPath := fs.String("export", "", "Write your high-confidence and confirmed YouTube matches to this mapping file (.csv or .json)")
+	minScore := fs.Float64("min-score", orchestrator.DefaultMappingMinScore, "With -export, lowest score of a match not confirmed by Odesli or a fingerprint")
+	tag := fs.String("tag", "", "With -export, only use playlists with this tag")
+	fs.Usage = func() {
+		ui.Println("Usage: playlistporter mappings -import <file.csv,...> [-replace]")
+		ui.Println("       playlistporter mappings -export <file.csv|file.json> [-min-score 0.9] [-tag tag]")
+		ui.Println("\nImported mappings are used instead of searching when porting to YouTube.")
+		ui.Println("\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	applyOutput()
+
+	if (*importPaths == "") == (*exportPath == "") {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *minScore < 0 || *minScore > 1 {
+		log.Fatalf("-min-score must be between 0 and 1")
+	}
+
+	orch := orchestrator.New(nil, false, "", 1, false)
+	if *exportPath != "" {
+		if err := orch.ExportMappings(*exportPath, *minScore, *tag); err != nil {
+			log.Fatalf("Failed to export mappings: %v", err)
+		}
+		return
+	}
+
+	var paths []string
+	for _, path := range strings.Split(*importPaths, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	if err := orch.ImportMappings(paths, *replace); err != nil {
+		log.Fatalf("Failed to import mappings: %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/paths"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// runMigrate moves the configs/config.yaml, states/ and logs/ of an older setup in the working
+// directory to the XDG locations (or to those set in the environment)
+func runMigrate(args []string) {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	applyOutput := registerOutputFlags(fs)
+	dryRun := fs.Bool("n", false, "Only list what would be moved")
+	fs.Usage = func() {
+		ui.Println("Usage: playlistporter migrate [-n]")
+		ui.Println("\nRun it in the directory that holds configs/, states/ and logs/ of an older setup.")
+		ui.Println("\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	applyOutput()
+
+	legacy := paths.Legacy()
+	if len(legacy) == 0 {
+		ui.Printf("✅ Nothing to move: no configs/config.yaml, states/ or logs/ here that isn't in use\n")
+		return
+	}
+	if *dryRun {
+		for _, m := range legacy {
+			ui.Printf("   %s → %s\n", m.From, m.To)
+		}
+		return
+	}
+
+	moved, err := paths.Migrate()
+	for _, m := range moved {
+		ui.Printf("📦 Moved %s → %s\n", m.From, m.To)
+	}
+	if err != nil {
+		log.Fatalf("Failed to migrate: %v", err)
+	}
+}
+
+// warnLegacyPaths points out files of an older setup in the working directory, which are no
+// longer read. It writes to stderr so output meant for files or pipes stays clean.
+func warnLegacyPaths() {
+	legacy := paths.Legacy()
+	if len(legacy) == 0 {
+		return
+	}
+	for _, m := range legacy {
+		fmt.Fprintf(os.Stderr, "⚠️  %s in the working directory is no longer used; %s is used instead\n", m.From, m.To)
+	}
+	fmt.Fprintf(os.Stderr, "💡 Move them with: playlistporter migrate\n\n")
+}
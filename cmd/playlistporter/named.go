@@ -0,0 +1,88 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strings"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// runNamed runs playlists named in the playlists section of the config. port ports the named
+// playlists; sync also checks them for new tracks and, without names, runs every named
+// playlist. -group selects the playlists of a group. The other options are those of a
+// porting run and apply to every playlist; a failing playlist doesn't stop the others.
+func runNamed(command string, args []string) {
+	var names []string
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		names = append(names, args[0])
+		args = args[1:]
+	}
+	group, args := takeGroupFlag(args)
+	if command == "sync" {
+		args = append([]string{"-sync"}, args...)
+	}
+
+	if group != "" || (command == "sync" && len(names) == 0) {
+		if len(names) > 0 {
+			log.Fatalf("use either playlist names or -group, not both")
+		}
+		opts := newRunOptions()
+		if err := opts.parse(args); err != nil {
+			log.Fatalf("Invalid option: %v", err)
+		}
+		cfg, _, err := loadRunConfig(opts)
+		if err != nil {
+			log.Fatalf("Failed to load config: %v", err)
+		}
+		names = cfg.PlaylistNames(group)
+		if len(names) == 0 && group != "" {
+			log.Fatalf("no playlist in the config belongs to group %q", group)
+		}
+	}
+
+	if len(names) == 0 {
+		ui.Println("Usage: playlistporter port <name>... [options]")
+		ui.Println("       playlistporter sync [<name>... | -group <group>] [options]")
+		ui.Println("\nNames and groups come from the playlists section of the config; the options are")
+		ui.Println("those of a porting run (see playlistporter -h) and apply to every playlist.")
+		os.Exit(1)
+	}
+
+	failed := 0
+	for i, name := range names {
+		if len(names) > 1 {
+			ui.Printf("\n━━━ %s (%d/%d) ━━━\n", name, i+1, len(names))
+		}
+		if err := runPorting(args, name); err != nil {
+			log.Printf("%s: %v", name, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		log.Fatalf("%d of %d playlists failed", failed, len(names))
+	}
+}
+
+// takeGroupFlag removes -group (or --group) and its value from args and returns the value
+func takeGroupFlag(args []string) (string, []string) {
+	group := ""
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "group" {
+			rest = append(rest, arg)
+			continue
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				log.Fatalf("-group needs a group name")
+			}
+			i++
+			value = args[i]
+		}
+		group = value
+	}
+	return group, rest
+}
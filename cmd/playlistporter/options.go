@@ -0,0 +1,281 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/config"
+	"github.com/Verryx-02/PlaylistPorter/internal/orchestrator"
+	"github.com/Verryx-02/PlaylistPorter/internal/paths"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// runOptions are the options of a porting run. Each option is resolved from its default, the
+// defaults section of the config file, a PLAYLISTPORTER_<NAME> environment variable and the
+// command line, each source overriding the ones before it.
+type runOptions struct {
+	URL          string
+	ConfigPath   string
+	Verbose      bool
+	LogFile      string
+	MaxTracks    int
+	ListStates   bool
+	ShowConfig   bool
+	Sync         bool
+	Recreate     bool
+	Container    bool
+	MergeURLs    string
+	MergeName    string
+	HoldRegress  bool
+	CrossCheck   bool
+	Verify       bool
+	Retry        bool
+	Rematch      bool
+	Split        bool
+	Phase        string
+	Quiet        bool
+	Mode         string
+	Weekly       bool
+	Dest         string
+	MaxDuration  time.Duration
+	SessionNote  string
+	Timeout      time.Duration
+	FilePath     string
+	AllPlaylists bool
+	Account      string
+	Tag          string
+	Light        bool
+	StrictISRC   bool
+	OnFailure    string
+	NoBrowser    bool
+	StatesDir    string
+	LogsDir      string
+
+	fs          *flag.FlagSet
+	applyOutput func()
+	origins     map[string]string // Flag name -> where its value came from
+}
+
+// Option names that name what to run rather than how; they are only read from the command
+// line. config and container are needed to find the config file, so they can't come from it.
+var (
+	commandLineOnly = map[string]bool{
+		"url": true, "file": true, "merge": true, "merge-name": true, "all-playlists": true, "tag": true,
+		"list-states": true, "show-config": true, "recreate-target": true, "crosscheck": true,
+		"verify": true, "retry-failed": true, "rematch-outdated": true, "session-note": true,
+		"mode": true, "archive-weekly": true,
+	}
+	notFromConfig = map[string]bool{"config": true, "container": true}
+
+	// Command-line options a named playlist may still set for itself
+	perPlaylist = map[string]bool{"mode": true, "archive-weekly": true}
+)
+
+// optionKeys are the config and environment names of options whose flag name is too short
+var optionKeys = map[string]string{"v": "verbose"}
+
+// newRunOptions defines the command-line options of a porting run
+func newRunOptions() *runOptions {
+	opts := &runOptions{origins: make(map[string]string)}
+	fs := flag.NewFlagSet("playlistporter", flag.ExitOnError)
+	fs.StringVar(&opts.URL, "url", "", "SPT (or TIDAL) playlist or album URL to port, a track URL to test its match, or \"liked\" for your Liked Songs")
+	fs.StringVar(&opts.ConfigPath, "config", paths.Config(), "Path to configuration file")
+	fs.BoolVar(&opts.Verbose, "v", false, "Verbose output")
+	fs.StringVar(&opts.LogFile, "log", "", "Log file path (optional). If empty, creates porting_TIMESTAMP.log in the logs directory")
+	fs.IntVar(&opts.MaxTracks, "max-tracks", 50, "Maximum number of tracks to process in this session (default: 50)")
+	fs.BoolVar(&opts.ListStates, "list-states", false, "List all saved porting states")
+	fs.BoolVar(&opts.ShowConfig, "show-config", false, "Print the effective options and config, and where each value came from (defaults, config file, environment or command line)")
+	fs.BoolVar(&opts.Sync, "sync", false, "Check for new tracks on completed playlists and sync them")
+	fs.BoolVar(&opts.Recreate, "recreate-target", false, "Build a new YouTube playlist from the stored matches without searching")
+	fs.BoolVar(&opts.Container, "container", false, "Container mode: data under PLAYLISTPORTER_DATA_DIR, config from environment, headless sign-in, JSON logs on stdout and other output on stderr")
+	fs.StringVar(&opts.MergeURLs, "merge", "", "Comma-separated SPT playlist URLs to merge into one YouTube playlist")
+	fs.StringVar(&opts.MergeName, "merge-name", "", "Name for the merged playlist (default: source names joined with +)")
+	fs.BoolVar(&opts.HoldRegress, "hold-on-regression", false, "In sync mode, don't upload a batch whose match rate is well below the playlist's average")
+	fs.BoolVar(&opts.CrossCheck, "crosscheck", false, "Ask Odesli (song.link) whether matched videos are the same recording as the source tracks and list mismatches")
+	fs.BoolVar(&opts.Verify, "verify", false, "Check matched videos still exist on YouTube and re-match deleted ones")
+	fs.BoolVar(&opts.Retry, "retry-failed", false, "Search again for tracks that failed to match (replaces placeholders in place)")
+	fs.BoolVar(&opts.Rematch, "rematch-outdated", false, "Search again for tracks matched by older matching rules (see the outdated command)")
+	fs.BoolVar(&opts.Split, "split", false, "Route matches into several YouTube playlists using the split rules in the config")
+	fs.StringVar(&opts.Phase, "phase", orchestrator.PhaseAll, "Workflow phase: all, match (search only) or upload (add stored matches)")
+	fs.BoolVar(&opts.Quiet, "quiet", false, "Only print the session summary (for cron jobs); detailed logs still go to the log file")
+	fs.StringVar(&opts.Mode, "mode", "", "Port mode stored in the state: snapshot (port once), follow (sync changes) or archive (accumulate a weekly playlist). Default: snapshot for Spotify editorial playlists, follow otherwise")
+	fs.BoolVar(&opts.Weekly, "archive-weekly", false, "In archive mode, create one YouTube playlist per week instead of a cumulative one")
+	fs.StringVar(&opts.Dest, "dest", "", "Destination service: youtube (default), soundcloud, jellyfin, folder, or spotify (default for YouTube playlist links)")
+	fs.DurationVar(&opts.MaxDuration, "max-duration", 0, "Stop starting new tracks after this much time, e.g. 30m (finishes the current track and saves progress)")
+	fs.StringVar(&opts.SessionNote, "session-note", "", "Note stored with this run's session and shown in the stateviewer session history, e.g. \"after matcher tweak\"")
+	fs.DurationVar(&opts.Timeout, "timeout", 0, "Hard limit for the whole run, e.g. 2h: aborts hanging API calls, saves progress and prints the summary (for cron jobs)")
+	fs.StringVar(&opts.FilePath, "file", "", "Port a playlist file instead of a link: CSV (e.g. from Exportify), M3U, XSPF, or Library.xml#Playlist from iTunes")
+	fs.BoolVar(&opts.AllPlaylists, "all-playlists", false, "Port every playlist in your Spotify library (signs in to Spotify), sharing -max-tracks between them round-robin")
+	fs.StringVar(&opts.Account, "account", "", "YouTube account to write with, e.g. alice; each account signs in once and keeps its own token (default: tubo.account)")
+	fs.StringVar(&opts.Tag, "tag", "", "Run on every saved playlist with this tag (e.g. -tag workout -sync), or filter -list-states")
+	fs.BoolVar(&opts.Light, "light", false, "Bandwidth-light mode for metered connections: request trimmed, compressed responses and skip candidate enrichment")
+	fs.BoolVar(&opts.StrictISRC, "strict-isrc", false, "Only keep YouTube matches tied to the track's ISRC, by Odesli or, with acoustid.enabled, by an audio fingerprint of one of the ISRC's MusicBrainz recordings; imported mappings are checked too, the rest stay unmatched and are listed for manual handling")
+	fs.StringVar(&opts.OnFailure, "on-failure", orchestrator.FailureContinue, "What a track that fails to match does to the batch: continue, pause (pick a video on the terminal) or abort")
+	fs.BoolVar(&opts.NoBrowser, "no-browser", false, "Sign in without a local browser: print the authorization URL and paste the resulting code or redirect URL into the terminal")
+	fs.StringVar(&opts.StatesDir, "states-dir", "", "Directory of saved states (default: $XDG_STATE_HOME/playlistporter/states)")
+	fs.StringVar(&opts.LogsDir, "logs-dir", "", "Directory of log files (default: $XDG_STATE_HOME/playlistporter/logs)")
+	opts.applyOutput = registerOutputFlags(fs)
+	opts.fs = fs
+	return opts
+}
+
+// optionKey returns the config key of an option, e.g. max_tracks for -max-tracks
+func optionKey(name string) string {
+	if key, ok := optionKeys[name]; ok {
+		return key
+	}
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// optionEnv returns the environment variable of an option, e.g. PLAYLISTPORTER_MAX_TRACKS
+func optionEnv(name string) string {
+	return "PLAYLISTPORTER_" + strings.ToUpper(optionKey(name))
+}
+
+// parse reads the command line, then fills the options not given there from the environment
+func (o *runOptions) parse(args []string) error {
+	o.fs.Parse(args)
+	o.fs.Visit(func(f *flag.Flag) {
+		o.origins[f.Name] = "flag -" + f.Name
+	})
+
+	var err error
+	o.fs.VisitAll(func(f *flag.Flag) {
+		if err != nil || commandLineOnly[f.Name] || o.origins[f.Name] != "" {
+			return
+		}
+		env := optionEnv(f.Name)
+		if value := os.Getenv(env); value != "" {
+			if setErr := o.fs.Set(f.Name, value); setErr != nil {
+				err = fmt.Errorf("%s: %w", env, setErr)
+				return
+			}
+			o.origins[f.Name] = "env " + env
+		}
+	})
+	return err
+}
+
+// applyConfig fills the options given neither on the command line, in the environment nor by
+// an earlier call from a defaults section of the config, e.g. "defaults" or
+// "playlists.workout.defaults"; the defaults of a named playlist may also set its mode
+func (o *runOptions) applyConfig(defaults map[string]string, section string) error {
+	byKey := make(map[string]*flag.Flag)
+	o.fs.VisitAll(func(f *flag.Flag) {
+		allowed := !commandLineOnly[f.Name] || (section != "defaults" && perPlaylist[f.Name])
+		if allowed && !notFromConfig[f.Name] {
+			byKey[optionKey(f.Name)] = f
+		}
+	})
+
+	keys := make([]string, 0, len(defaults))
+	for key := range defaults {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		f := byKey[key]
+		if f == nil {
+			return fmt.Errorf("%s.%s: not an option that can be set in the config", section, key)
+		}
+		if o.origins[f.Name] != "" {
+			continue
+		}
+		if err := o.fs.Set(f.Name, defaults[key]); err != nil {
+			return fmt.Errorf("%s.%s: %w", section, key, err)
+		}
+		o.origins[f.Name] = "config " + section + "." + key
+	}
+	return nil
+}
+
+// printEffective lists every option and the config values that matter for a run, each with
+// where its value came from; secrets are masked
+func (o *runOptions) printEffective(cfg *config.Config) {
+	ui.Printf("⚙️  Effective configuration\n")
+	ui.Printf("==========================\n")
+	ui.Printf("Config file: %s (%s)\n\n", o.ConfigPath, o.origin("config"))
+
+	ui.Printf("Options:\n")
+	o.fs.VisitAll(func(f *flag.Flag) {
+		value := f.Value.String()
+		if value == "" {
+			value = `""`
+		}
+		ui.Printf("  %-20s %-24s %s\n", optionKey(f.Name), value, o.origin(f.Name))
+	})
+
+	if cfg == nil {
+		return
+	}
+	env := cfg.EnvOverrides()
+	fromConfig := func(key string) string {
+		if variable, ok := env[key]; ok {
+			return "env " + variable
+		}
+		return "config"
+	}
+	costs := cfg.Quota.Costs()
+	searchBackend := cfg.TUBO.SearchBackend
+	if searchBackend == "" {
+		searchBackend = config.SearchDataAPI
+	}
+	values := []struct{ key, value, origin string }{
+		{"tubo.search_backend", searchBackend, fromConfig("tubo.search_backend")},
+		{"tubo.account", cfg.TUBO.Account, fromConfig("tubo.account")},
+		{"tubo.callback_port", cfg.TUBO.CallbackPort, fromConfig("tubo.callback_port")},
+		{"quota.daily_limit", fmt.Sprint(costs.DailyLimit), "config"},
+		{"quota.search_cost", fmt.Sprint(costs.Search), "config"},
+		{"quota.insert_cost", fmt.Sprint(costs.Insert), "config"},
+		{"quota.session_budget", fmt.Sprint(cfg.Quota.SessionBudget), "config"},
+	}
+	ui.Printf("\nConfig:\n")
+	for _, v := range values {
+		if v.value == "" || v.value == "0" {
+			v.value = "(not set)"
+		}
+		ui.Printf("  %-20s %-24s %s\n", v.key, v.value, v.origin)
+	}
+
+	if len(env) > 0 {
+		ui.Printf("\nSet from the environment:\n")
+		keys := make([]string, 0, len(env))
+		for key := range env {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			ui.Printf("  %-28s %s\n", key, env[key])
+		}
+	}
+
+	if secrets := cfg.SecretSources(); len(secrets) > 0 {
+		ui.Printf("\nSecrets read from files and commands:\n")
+		keys := make([]string, 0, len(secrets))
+		for key := range secrets {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			ui.Printf("  %-28s %s\n", key, secrets[key])
+		}
+	}
+	ui.Printf("\n")
+}
+
+// hasTarget reports whether the options name something to run
+func (o *runOptions) hasTarget() bool {
+	return o.URL != "" || o.FilePath != "" || o.MergeURLs != "" || o.AllPlaylists || o.Tag != ""
+}
+
+// origin describes where an option's value came from
+func (o *runOptions) origin(name string) string {
+	if origin := o.origins[name]; origin != "" {
+		return origin
+	}
+	return "default"
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/orchestrator"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// runOutdated lists the results of a saved playlist that older matching rules produced
+func runOutdated(args []string) {
+	fs := flag.NewFlagSet("outdated", flag.ExitOnError)
+	applyOutput := registerOutputFlags(fs)
+	sptURL := fs.String("url", "", "SPT playlist URL of the saved state")
+	fs.Usage = func() {
+		ui.Println("Usage: playlistporter outdated -url <spt-playlist-url>")
+		ui.Println("\nRe-match the listed tracks with: playlistporter -url <spt-playlist-url> -rematch-outdated")
+		ui.Println("\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	applyOutput()
+
+	if *sptURL == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	orch := orchestrator.New(nil, false, "", 1, false)
+	if err := orch.ListOutdated(*sptURL); err != nil {
+		log.Fatalf("Failed to list outdated results: %v", err)
+	}
+}
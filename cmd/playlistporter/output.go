@@ -0,0 +1,18 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// registerOutputFlags adds the output style flags to a flag set and returns a
+// function applying them once the flags are parsed
+func registerOutputFlags(fs *flag.FlagSet) func() {
+	plain := fs.Bool("plain", !ui.IsTerminal(os.Stdout), "Screen-reader friendly output: no emoji, progress animations or rulers (default when stdout is not a terminal)")
+
+	return func() {
+		ui.SetPlain(*plain)
+	}
+}
@@ -0,0 +1,6 @@
+//go:build preferredchannels
+
+package main
+
+// Compiled in with -tags preferredchannels
+import _ "github.com/Verryx-02/PlaylistPorter/plugins/preferredchannels"
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/config"
+	"github.com/Verryx-02/PlaylistPorter/internal/orchestrator"
+	"github.com/Verryx-02/PlaylistPorter/internal/paths"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// runPositions reads the position notes PlaylistPorter keeps in a YouTube playlist's
+// description, e.g. to rebuild mappings without the state files
+func runPositions(args []string) {
+	fs := flag.NewFlagSet("positions", flag.ExitOnError)
+	applyOutput := registerOutputFlags(fs)
+	ytURL := fs.String("url", "", "YouTube playlist URL with position notes in its description")
+	out := fs.String("out", "", "Write the source tracks and their videos to this mapping file (.csv or .json)")
+	configPath := fs.String("config", paths.Config(), "Path to configuration file")
+	account := fs.String("account", "", "YouTube account owning the playlist (default: tubo.account)")
+	fs.Usage = func() {
+		ui.Println("Usage: playlistporter positions -url <youtube-playlist-url> [-out mappings.csv]")
+		ui.Println("\nImport the written file with: playlistporter mappings -import mappings.csv")
+		ui.Println("\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	applyOutput()
+
+	if *ytURL == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	orch := orchestrator.New(cfg, false, "", 1, false)
+	if *account != "" {
+		orch.SetAccount(*account)
+	}
+	if err := orch.ReadPositionNotes(*ytURL, *out); err != nil {
+		log.Fatalf("Failed to read position notes: %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/paths"
+	"github.com/Verryx-02/PlaylistPorter/internal/state"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// runSearch searches tracks, matches and failures across all saved states
+func runSearch(args []string) {
+	fs := flag.NewFlagSet("search", flag.ExitOnError)
+	applyOutput := registerOutputFlags(fs)
+	rebuild := fs.Bool("rebuild-index", false, "Rebuild the search index from the state files first")
+	fs.Usage = func() {
+		ui.Println("Usage: playlistporter search [options] <query>")
+		ui.Println("\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	applyOutput()
+
+	query := strings.Join(fs.Args(), " ")
+	if strings.TrimSpace(query) == "" {
+		fs.Usage()
+		return
+	}
+
+	stateManager, err := state.NewManager(paths.States())
+	if err != nil {
+		log.Fatalf("Failed to open states directory: %v", err)
+	}
+
+	if *rebuild {
+		if _, err := stateManager.RebuildIndex(); err != nil {
+			log.Fatalf("Failed to rebuild index: %v", err)
+		}
+	}
+
+	hits, err := stateManager.Search(query)
+	if err != nil {
+		log.Fatalf("Search failed: %v", err)
+	}
+
+	ui.Printf("🔎 Search results for \"%s\"\n", query)
+	ui.Printf("==========================\n\n")
+
+	if len(hits) == 0 {
+		ui.Println("No matching tracks found.")
+		return
+	}
+
+	currentPlaylist := ""
+	for _, hit := range hits {
+		if hit.Playlist.SpotifyID != currentPlaylist {
+			currentPlaylist = hit.Playlist.SpotifyID
+			ui.Printf("📁 %s (%s)\n", hit.Playlist.PlaylistName, hit.Playlist.StateFile)
+		}
+
+		switch hit.Track.Status {
+		case state.TrackStatusMatched:
+			ui.Printf("   %s\n", ui.ForScore(hit.Track.Score, fmt.Sprintf("✅ %s - %s", hit.Track.Artist, hit.Track.Title)))
+			ui.Printf("      → \"%s\" (score: %.2f) https://www.youtube.com/watch?v=%s\n",
+				hit.Track.VideoTitle, hit.Track.Score, hit.Track.VideoID)
+		case state.TrackStatusFailed:
+			ui.Printf("   %s\n", ui.Red(fmt.Sprintf("❌ %s - %s", hit.Track.Artist, hit.Track.Title)))
+			if hit.Track.Error != "" {
+				ui.Printf("      Error: %s\n", hit.Track.Error)
+			}
+		default:
+			ui.Printf("   ⏳ %s - %s (not processed yet)\n", hit.Track.Artist, hit.Track.Title)
+		}
+		if hit.Track.Note != "" {
+			ui.Printf("      📝 %s\n", hit.Track.Note)
+		}
+	}
+
+	ui.Printf("\nFound %d tracks\n", len(hits))
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/config"
+	"github.com/Verryx-02/PlaylistPorter/internal/orchestrator"
+	"github.com/Verryx-02/PlaylistPorter/internal/paths"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// runSelftest ports a few tracks of a public playlist into a throwaway YouTube playlist and
+// checks the result, so a setup can be tried end to end before a big migration. States and
+// caches go to a temporary directory, leaving the real ones untouched.
+func runSelftest(args []string) {
+	fs := flag.NewFlagSet("selftest", flag.ExitOnError)
+	applyOutput := registerOutputFlags(fs)
+	sourceURL := fs.String("url", "", "Public Spotify, TIDAL or Amazon Music playlist to port the first tracks of")
+	configPath := fs.String("config", paths.Config(), "Path to configuration file, e.g. one with test credentials")
+	account := fs.String("account", "", "YouTube account to write with (default: tubo.account)")
+	keep := fs.Bool("keep", false, "Keep the throwaway YouTube playlist instead of deleting it")
+	verbose := fs.Bool("v", false, "Write a detailed log to the logs directory")
+	fs.Usage = func() {
+		ui.Println("Usage: playlistporter selftest -url <playlist-url> [-config path] [-account name] [-keep]")
+		ui.Printf("\nPorts the first %d tracks into a new private YouTube playlist, checks it and deletes it.\n", orchestrator.SelfTestTracks)
+		ui.Println("\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	applyOutput()
+
+	if *sourceURL == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if err := config.CheckAccountName(*account); err != nil {
+		log.Fatalf("-account: %v", err)
+	}
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	statesDir, err := os.MkdirTemp("", "playlistporter-selftest-")
+	if err != nil {
+		log.Fatalf("Failed to create a temporary states directory: %v", err)
+	}
+	defer os.RemoveAll(statesDir)
+	paths.SetStates(statesDir)
+
+	logPath := ""
+	if *verbose {
+		if err := os.MkdirAll(paths.Logs(), 0755); err == nil {
+			logPath = filepath.Join(paths.Logs(), fmt.Sprintf("selftest_%s.log", time.Now().Format("20060102_150405")))
+		}
+	}
+
+	// Searches and inserts of the tracks, creating and deleting the playlist and listing it
+	costs := cfg.Quota.Costs()
+	units := orchestrator.SelfTestTracks*costs.PerTrack(cfg.TUBO.SearchBackend == config.SearchYTMusic) + 2*50 + 1
+
+	ui.Printf("🧪 PlaylistPorter self-test\n")
+	ui.Printf("==========================\n")
+	ui.Printf("📊 Uses about %d of the %d daily quota units\n\n", units, costs.DailyLimit)
+	orch := orchestrator.New(cfg, *verbose, logPath, orchestrator.SelfTestTracks, false)
+	if *account != "" {
+		orch.SetAccount(*account)
+	}
+	if err := orch.SelfTest(*sourceURL, *keep); err != nil {
+		ui.Summaryf("\n%s\n", ui.Red(fmt.Sprintf("❌ Self-test failed: %v", err)))
+		if logPath != "" {
+			ui.Printf("📄 Details: %s\n", logPath)
+		}
+		os.RemoveAll(statesDir)
+		os.Exit(1)
+	}
+	ui.Summaryf("\n%s\n", ui.Green("✅ Self-test passed, the setup is ready to port"))
+}
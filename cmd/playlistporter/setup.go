@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/config"
+	"github.com/Verryx-02/PlaylistPorter/internal/paths"
+	"github.com/Verryx-02/PlaylistPorter/internal/spt"
+	"github.com/Verryx-02/PlaylistPorter/internal/tubo"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+const (
+	setupSpotifyRedirect = "http://127.0.0.1:8080/callback"
+	setupYouTubeRedirect = "http://localhost:8080/callback"
+)
+
+var (
+	spotifyClientIDPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+	googleClientIDPattern  = regexp.MustCompile(`^[0-9]+-[0-9a-z]+\.apps\.googleusercontent\.com$`)
+)
+
+// runSetup walks through creating the Spotify and Google API credentials, writes the
+// config file and checks that both sign-ins work
+func runSetup(args []string) {
+	fs := flag.NewFlagSet("setup", flag.ExitOnError)
+	applyOutput := registerOutputFlags(fs)
+	configPath := fs.String("config", paths.Config(), "Path of the configuration file to write")
+	force := fs.Bool("force", false, "Overwrite an existing configuration file")
+	skipVerify := fs.Bool("skip-verify", false, "Write the configuration without signing in to check it")
+	fs.Usage = func() {
+		ui.Println("Usage: playlistporter setup [-config path] [-force] [-skip-verify]")
+		ui.Println("\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	applyOutput()
+
+	if _, err := os.Stat(*configPath); err == nil && !*force {
+		log.Fatalf("%s already exists; pass -force to replace it", *configPath)
+	}
+
+	in := bufio.NewReader(os.Stdin)
+
+	ui.Printf("🧙 PlaylistPorter setup\n")
+	ui.Printf("======================\n")
+	ui.Printf("This creates %s with your own Spotify and YouTube API credentials.\n", *configPath)
+	ui.Printf("Both are free. Nothing here needs a credit card or a billing account.\n")
+
+	ui.Printf("\n━━━ Step 1 of 4: Spotify app ━━━\n")
+	ui.Printf("  1. Open https://developer.spotify.com/dashboard and log in\n")
+	ui.Printf("  2. Click \"Create app\". Any name and description will do\n")
+	ui.Printf("  3. Add the redirect URI %s and tick \"Web API\"\n", setupSpotifyRedirect)
+	ui.Printf("  4. Open the app's Settings and copy the Client ID and Client secret\n\n")
+	spotifyID := ask(in, "Spotify Client ID", func(v string) error {
+		if !spotifyClientIDPattern.MatchString(v) {
+			return errors.New("a Spotify Client ID is 32 characters of 0-9 and a-f")
+		}
+		return nil
+	})
+	spotifySecret := ask(in, "Spotify Client secret", func(v string) error {
+		if !spotifyClientIDPattern.MatchString(v) {
+			return errors.New("a Spotify Client secret is 32 characters of 0-9 and a-f (click \"View client secret\")")
+		}
+		return nil
+	})
+	userAuth := askYesNo(in, "Sign in with your Spotify account to port private playlists and Liked Songs?")
+
+	ui.Printf("\n━━━ Step 2 of 4: Google project for YouTube ━━━\n")
+	ui.Printf("  1. Create a project at https://console.cloud.google.com/projectcreate\n")
+	ui.Printf("     Don't link a billing account: PlaylistPorter only uses the free daily quota\n")
+	ui.Printf("     (10,000 units), and a project without billing can never be charged\n")
+	ui.Printf("  2. Enable the YouTube Data API v3:\n")
+	ui.Printf("     https://console.cloud.google.com/apis/library/youtube.googleapis.com\n")
+	ui.Printf("  3. Under \"OAuth consent screen\", choose External and add your Google account\n")
+	ui.Printf("     as a test user\n")
+	ui.Printf("  4. Under \"Credentials\", create an OAuth client ID of type \"Web application\"\n")
+	ui.Printf("     with the authorized redirect URI %s\n", setupYouTubeRedirect)
+	ui.Printf("  5. Copy the Client ID and Client secret it shows\n\n")
+	googleID := ask(in, "Google Client ID", func(v string) error {
+		if !googleClientIDPattern.MatchString(v) {
+			return errors.New("a Google Client ID ends in .apps.googleusercontent.com")
+		}
+		return nil
+	})
+	googleSecret := ask(in, "Google Client secret", func(v string) error {
+		if len(v) < 20 || strings.ContainsAny(v, " \t") {
+			return errors.New("that doesn't look like a Google Client secret (usually starts with GOCSPX-)")
+		}
+		return nil
+	})
+
+	ui.Printf("\n━━━ Step 3 of 4: Writing the configuration ━━━\n")
+	if err := writeSetupConfig(*configPath, spotifyID, spotifySecret, userAuth, googleID, googleSecret); err != nil {
+		log.Fatalf("Failed to write configuration: %v", err)
+	}
+	ui.Printf("💾 Saved %s (readable only by you)\n", *configPath)
+
+	if *skipVerify {
+		ui.Printf("\n⏭️  Skipped the sign-in check. Run playlistporter setup again if porting fails to sign in\n")
+		return
+	}
+
+	ui.Printf("\n━━━ Step 4 of 4: Checking both sign-ins ━━━\n")
+	if err := verifySetup(*configPath); err != nil {
+		ui.Printf("\n❌ %v\n", err)
+		ui.Printf("   Fix the problem above and run playlistporter setup -force again\n")
+		os.Exit(1)
+	}
+
+	ui.Printf("\n🎉 All set! Port your first playlist with:\n")
+	ui.Printf("   playlistporter -url https://open.spotify.com/playlist/...\n")
+}
+
+// ask prompts until check accepts the trimmed answer
+func ask(in *bufio.Reader, prompt string, check func(string) error) string {
+	for {
+		ui.Printf("%s: ", prompt)
+		answer, err := in.ReadString('\n')
+		if err != nil {
+			ui.Println()
+			log.Fatalf("setup cancelled")
+		}
+		answer = strings.TrimSpace(answer)
+		if err := check(answer); err != nil {
+			ui.Printf("   ⚠️  %v, please try again\n", err)
+			continue
+		}
+		return answer
+	}
+}
+
+// askYesNo asks a yes/no question, defaulting to no
+func askYesNo(in *bufio.Reader, question string) bool {
+	ui.Printf("%s [y/N]: ", question)
+	answer, err := in.ReadString('\n')
+	if err != nil {
+		ui.Println()
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// writeSetupConfig writes a minimal configuration with the given credentials
+func writeSetupConfig(path, spotifyID, spotifySecret string, userAuth bool, googleID, googleSecret string) error {
+	content := fmt.Sprintf(`# Written by playlistporter setup; see the README for all options
+spt:
+  client_id: %q
+  client_secret: %q
+  user_auth: %t
+  redirect_uri: %q
+
+tubo:
+  client_id: %q
+  client_secret: %q
+  redirect_uri: %q
+  scopes:
+    - "https://www.googleapis.com/auth/youtube"
+`, spotifyID, spotifySecret, userAuth, setupSpotifyRedirect, googleID, googleSecret, setupYouTubeRedirect)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating config directory: %w", err)
+	}
+	return os.WriteFile(path, []byte(content), 0600)
+}
+
+// verifySetup loads the written configuration and signs in to both services
+func verifySetup(path string) error {
+	cfg, err := config.Load(path)
+	if err != nil {
+		return err
+	}
+
+	ui.Printf("🔐 Signing in to Spotify...\n")
+	if _, err := spt.NewClient(&cfg.SPT); err != nil {
+		return fmt.Errorf("Spotify sign-in failed: %w\n   Check the Client ID and secret, and that the redirect URI is registered in the app", err)
+	}
+	ui.Printf("✅ Spotify works\n")
+
+	ui.Printf("🔐 Signing in to YouTube (a browser window will ask for permission)...\n")
+	tuboClient, err := tubo.NewClient(&cfg.TUBO)
+	if err != nil {
+		return fmt.Errorf("YouTube sign-in failed: %w\n   Check the Client ID and secret, the redirect URI, and that your account is a test user", err)
+	}
+	channel, err := tuboClient.ChannelTitle()
+	if err != nil {
+		var apiErr *tubo.APIError
+		if errors.As(err, &apiErr) && apiErr.Reason == "accessNotConfigured" {
+			return fmt.Errorf("the YouTube Data API v3 is not enabled for the Google project yet; enable it and wait a minute")
+		}
+		return fmt.Errorf("YouTube check failed: %w", err)
+	}
+	ui.Printf("✅ YouTube works, signed in as %q\n", channel)
+
+	return nil
+}
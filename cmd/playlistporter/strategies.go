@@ -0,0 +1,113 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/paths"
+	"github.com/Verryx-02/PlaylistPorter/internal/state"
+	"github.com/Verryx-02/PlaylistPorter/internal/tubo"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// strategyStats aggregates how one search strategy performed
+type strategyStats struct {
+	Attempts   int     // Tracks where the strategy's search was run
+	Wins       int     // Tracks where it produced the selected match
+	TotalScore float64 // Sum of winning scores, for the average
+}
+
+// runStrategies reports how often each search strategy wins across all saved states
+func runStrategies(args []string) {
+	fs := flag.NewFlagSet("strategies", flag.ExitOnError)
+	applyOutput := registerOutputFlags(fs)
+	fs.Usage = func() {
+		ui.Println("Usage: playlistporter strategies")
+		ui.Println("\nReports, per search strategy, how often it produced the winning match.")
+	}
+	fs.Parse(args)
+	applyOutput()
+
+	stateManager, err := state.NewManager(paths.States())
+	if err != nil {
+		log.Fatalf("Failed to open states directory: %v", err)
+	}
+
+	files, err := stateManager.ListStates()
+	if err != nil {
+		log.Fatalf("Failed to list states: %v", err)
+	}
+
+	names := tubo.StrategyNames()
+	stats := make(map[string]*strategyStats)
+	for _, name := range names {
+		stats[name] = &strategyStats{}
+	}
+
+	tracked, untracked, totalSearches := 0, 0, 0
+	for _, file := range files {
+		portingState, err := stateManager.LoadStateFile(file)
+		if err != nil {
+			ui.Printf("⚠️  Skipping %s: %v\n", file, err)
+			continue
+		}
+
+		for _, result := range portingState.MatchResults {
+			// Results from before strategies were recorded carry no search count
+			if result.SearchesUsed == 0 {
+				untracked++
+				continue
+			}
+			tracked++
+			totalSearches += result.SearchesUsed
+
+			for i, name := range names {
+				if result.SearchesUsed > i {
+					stats[name].Attempts++
+				}
+			}
+
+			if result.Matched && result.Strategy != "" {
+				s, ok := stats[result.Strategy]
+				if !ok {
+					// Strategy that has since been removed
+					s = &strategyStats{}
+					stats[result.Strategy] = s
+					names = append(names, result.Strategy)
+				}
+				s.Wins++
+				s.TotalScore += result.MatchScore
+			}
+		}
+	}
+
+	ui.Printf("📈 Search Strategy Report\n")
+	ui.Printf("=========================\n\n")
+
+	if tracked == 0 {
+		ui.Println("No strategy data recorded yet. Run a porting session to collect some.")
+		return
+	}
+
+	ui.Printf("Tracks searched: %d (%d search requests, ~%d quota units)\n\n",
+		tracked, totalSearches, totalSearches*100)
+
+	for i, name := range names {
+		s := stats[name]
+		ui.Printf("%d. %s\n", i+1, name)
+		ui.Printf("   Attempts: %d\n", s.Attempts)
+		if s.Attempts > 0 {
+			ui.Printf("   Wins: %d (%.1f%% of attempts)\n", s.Wins, float64(s.Wins)/float64(s.Attempts)*100)
+		} else {
+			ui.Printf("   Wins: %d\n", s.Wins)
+		}
+		if s.Wins > 0 {
+			ui.Printf("   Average winning score: %.2f\n", s.TotalScore/float64(s.Wins))
+		}
+		ui.Println()
+	}
+
+	if untracked > 0 {
+		ui.Printf("💡 %d results predate strategy tracking and are not counted.\n", untracked)
+	}
+}
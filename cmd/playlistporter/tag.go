@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/orchestrator"
+	"github.com/Verryx-02/PlaylistPorter/internal/state"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// runTag adds or removes tags on a saved state, or shows them
+func runTag(args []string) {
+	fs := flag.NewFlagSet("tag", flag.ExitOnError)
+	applyOutput := registerOutputFlags(fs)
+	sptURL := fs.String("url", "", "SPT playlist URL of the saved state")
+	add := fs.String("add", "", "Comma-separated tags to add, e.g. workout,running")
+	remove := fs.String("remove", "", "Comma-separated tags to remove")
+	fs.Usage = func() {
+		ui.Println("Usage: playlistporter tag -url <spt-playlist-url> [-add tags] [-remove tags]")
+		ui.Println("\nWithout -add or -remove the playlist's tags are shown.")
+		ui.Println("\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	applyOutput()
+
+	if *sptURL == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	orch := orchestrator.New(nil, false, "", 1, false)
+	if err := orch.TagPlaylist(*sptURL, state.ParseTags(*add), state.ParseTags(*remove)); err != nil {
+		log.Fatalf("Failed to tag playlist: %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/orchestrator"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// runTimeline writes the session history of a saved playlist as an HTML page
+func runTimeline(args []string) {
+	fs := flag.NewFlagSet("timeline", flag.ExitOnError)
+	applyOutput := registerOutputFlags(fs)
+	sptURL := fs.String("url", "", "SPT playlist URL of the saved state")
+	out := fs.String("out", "", "Output HTML path (default: exports/timeline_<id>.html)")
+	fs.Usage = func() {
+		ui.Println("Usage: playlistporter timeline -url <spt-playlist-url> [-out timeline.html]")
+		ui.Println("\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	applyOutput()
+
+	if *sptURL == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	orch := orchestrator.New(nil, false, "", 1, false)
+	if err := orch.ExportTimeline(*sptURL, *out); err != nil {
+		log.Fatalf("Failed to write timeline: %v", err)
+	}
+}
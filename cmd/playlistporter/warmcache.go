@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/config"
+	"github.com/Verryx-02/PlaylistPorter/internal/orchestrator"
+	"github.com/Verryx-02/PlaylistPorter/internal/paths"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// runWarmCache searches ahead for the next batch of saved playlists, e.g. overnight, so the
+// next session mostly uses cached searches
+func runWarmCache(args []string) {
+	fs := flag.NewFlagSet("warm-cache", flag.ExitOnError)
+	applyOutput := registerOutputFlags(fs)
+	sptURL := fs.String("url", "", "SPT playlist URL of the saved state to warm (default: every unfinished YouTube playlist)")
+	tag := fs.String("tag", "", "Only warm the saved playlists carrying this tag")
+	configPath := fs.String("config", paths.Config(), "Path to configuration file")
+	account := fs.String("account", "", "YouTube account to search with (default: tubo.account)")
+	units := fs.Int("units", 1000, "YouTube quota units to spend at most (ignored with the ytmusic search backend)")
+	maxTracks := fs.Int("max-tracks", 50, "Tracks to search ahead per playlist; match the -max-tracks of your sessions")
+	fs.Usage = func() {
+		ui.Println("Usage: playlistporter warm-cache [-url <spt-playlist-url> | -tag <tag>] [-units 1000] [-max-tracks 50]")
+		ui.Println("\nThe next session of each playlist uses the searches made here without spending quota.")
+		ui.Println("\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	applyOutput()
+
+	if *sptURL != "" && *tag != "" {
+		log.Fatal("Use either -url or -tag")
+	}
+	if *units <= 0 || *maxTracks <= 0 {
+		log.Fatal("-units and -max-tracks must be positive")
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	orch := orchestrator.New(cfg, false, "", *maxTracks, false)
+	if *account != "" {
+		orch.SetAccount(*account)
+	}
+	if err := orch.WarmCache(*sptURL, *tag, *units); err != nil {
+		log.Fatalf("Failed to warm the search cache: %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/badge"
+	"github.com/Verryx-02/PlaylistPorter/internal/paths"
+	"github.com/Verryx-02/PlaylistPorter/internal/state"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// writeBadges writes the status JSON and SVG badge of one state file, or of every saved
+// state (with the tag, if given)
+func writeBadges(dir, stateFile, tag string) {
+	var files []string
+	if stateFile != "" {
+		if !strings.Contains(stateFile, string(os.PathSeparator)) {
+			stateFile = filepath.Join(paths.States(), stateFile)
+		}
+		files = append(files, stateFile)
+	} else {
+		entries, err := os.ReadDir(paths.States())
+		if err != nil {
+			ui.Printf("Error reading states directory: %v\n", err)
+			return
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() && state.IsStateFile(entry.Name()) {
+				files = append(files, filepath.Join(paths.States(), entry.Name()))
+			}
+		}
+	}
+
+	written := 0
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			ui.Printf("Error reading %s: %v\n", path, err)
+			continue
+		}
+		var portingState state.PortingState
+		if err := json.Unmarshal(data, &portingState); err != nil {
+			ui.Printf("Error parsing %s: %v\n", path, err)
+			continue
+		}
+		if tag != "" && !portingState.HasTag(tag) {
+			continue
+		}
+
+		svgPath, err := badge.Write(dir, &portingState)
+		if err != nil {
+			ui.Printf("Error writing badge for %s: %v\n", portingState.OriginalPlaylist.Name, err)
+			continue
+		}
+		status := badge.StatusOf(&portingState)
+		ui.Printf("🏅 %s: %d%% ported → %s\n", portingState.OriginalPlaylist.Name, status.Percent, svgPath)
+		written++
+	}
+	ui.Printf("\n%d badge(s) written to %s\n", written, dir)
+}
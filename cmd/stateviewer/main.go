@@ -9,7 +9,12 @@ import (
 	"strings"
 	"time"
 
-	"playlistporter/internal/state"
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/paths"
+	"github.com/Verryx-02/PlaylistPorter/internal/quota"
+	"github.com/Verryx-02/PlaylistPorter/internal/state"
+	"github.com/Verryx-02/PlaylistPorter/internal/triage"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
 )
 
 func main() {
@@ -17,11 +22,25 @@ func main() {
 		stateFile = flag.String("file", "", "State file to view")
 		summary   = flag.Bool("summary", false, "Show summary of all states")
 		detailed  = flag.Bool("detailed", false, "Show detailed information")
+		tag       = flag.String("tag", "", "Only summarize playlists with this tag")
+		badgeDir  = flag.String("badge", "", "Write a status JSON and SVG badge per playlist into this directory")
+		statesDir = flag.String("states-dir", "", "Directory of saved states (default: $XDG_STATE_HOME/playlistporter/states)")
+		plain     = flag.Bool("plain", !ui.IsTerminal(os.Stdout), "Screen-reader friendly output: no emoji or rulers (default when stdout is not a terminal)")
 	)
 	flag.Parse()
+	ui.SetPlain(*plain)
+	paths.SetStates(*statesDir)
+	for _, m := range paths.Legacy() {
+		fmt.Fprintf(os.Stderr, "⚠️  %s in the working directory is no longer used; %s is used instead (move it with: playlistporter migrate)\n", m.From, m.To)
+	}
+
+	if *badgeDir != "" {
+		writeBadges(*badgeDir, *stateFile, *tag)
+		return
+	}
 
 	if *summary || (*stateFile == "" && !*summary) {
-		showAllStates()
+		showAllStates(*tag)
 		return
 	}
 
@@ -30,28 +49,39 @@ func main() {
 	}
 }
 
-// showAllStates displays a summary of all saved states
-func showAllStates() {
-	fmt.Printf("📊 PlaylistPorter State Summary\n")
-	fmt.Printf("================================\n\n")
+// showAllStates displays a summary of all saved states, or of those with the tag
+func showAllStates(tag string) {
+	ui.Printf("📊 PlaylistPorter State Summary\n")
+	ui.Printf("================================\n\n")
+	if tag != "" {
+		ui.Printf("🏷️  Tagged #%s\n\n", state.NormalizeTag(tag))
+	}
 
-	entries, err := os.ReadDir("states")
+	statesDir := paths.States()
+	entries, err := os.ReadDir(statesDir)
 	if err != nil {
 		if os.IsNotExist(err) {
-			fmt.Println("No states directory found.")
+			ui.Printf("No states directory found at %s.\n", statesDir)
 			return
 		}
-		fmt.Printf("Error reading states directory: %v\n", err)
+		ui.Printf("Error reading states directory: %v\n", err)
 		return
 	}
 
-	totalStates := 0
+	var (
+		totalStates   int
+		totalTracks   int
+		totalListen   time.Duration
+		totalSessions time.Duration
+		totalQuota    int
+		totalSize     int64
+	)
 	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+		if entry.IsDir() || !state.IsStateFile(entry.Name()) {
 			continue
 		}
 
-		stateFilePath := filepath.Join("states", entry.Name())
+		stateFilePath := filepath.Join(statesDir, entry.Name())
 		data, err := os.ReadFile(stateFilePath)
 		if err != nil {
 			continue
@@ -61,30 +91,61 @@ func showAllStates() {
 		if err := json.Unmarshal(data, &state); err != nil {
 			continue
 		}
+		if tag != "" && !state.HasTag(tag) {
+			continue
+		}
+
+		listening := state.GetTotalDuration()
+		sessionTime := state.GetTotalSessionTime()
+		quotaUsed := state.GetTotalQuotaUsed()
 
 		totalStates++
-		fmt.Printf("📁 %s\n", state.OriginalPlaylist.Name)
-		fmt.Printf("   Spotify ID: %s\n", state.SpotifyID)
-		fmt.Printf("   Progress: %s", state.GetProgress())
+		totalTracks += state.TotalTracks
+		totalListen += listening
+		totalSessions += sessionTime
+		totalQuota += quotaUsed
+		totalSize += int64(len(data))
+
+		ui.Printf("📁 %s\n", state.OriginalPlaylist.Name)
+		ui.Printf("   Spotify ID: %s\n", state.SpotifyID)
+		if len(state.Tags) > 0 {
+			ui.Printf("   Tags: %s\n", formatTags(state.Tags))
+		}
+		ui.Printf("   Progress: %s", state.GetProgress())
 		if state.IsComplete {
-			fmt.Printf(" ✅ COMPLETE")
+			ui.Printf(" ✅ COMPLETE")
+		}
+		if state.IsSourceUnavailable() {
+			ui.Printf(" 🚫 SOURCE UNAVAILABLE")
 		}
-		fmt.Printf("\n")
-		fmt.Printf("   Sessions: %d\n", len(state.Sessions))
-		fmt.Printf("   Last updated: %s\n", state.LastUpdatedAt.Format("2006-01-02 15:04"))
+		ui.Printf("\n")
+		if listening > 0 {
+			ui.Printf("   Listening time: %s\n", ui.FormatDuration(listening))
+		}
+		ui.Printf("   Sessions: %d (%s, ~%s quota units)\n", len(state.Sessions), ui.FormatDuration(sessionTime), ui.FormatCount(quotaUsed))
+		ui.Printf("   State file: %s\n", ui.FormatBytes(int64(len(data))))
+		ui.Printf("   Last updated: %s\n", state.LastUpdatedAt.Format("2006-01-02 15:04"))
 		if state.LastSyncCheck.Year() > 1 {
-			fmt.Printf("   Last sync check: %s\n", state.LastSyncCheck.Format("2006-01-02 15:04"))
+			ui.Printf("   Last sync check: %s\n", state.LastSyncCheck.Format("2006-01-02 15:04"))
 		}
 		if state.YouTubePlaylistID != "" {
-			fmt.Printf("   YouTube: https://www.youtube.com/playlist?list=%s\n", state.YouTubePlaylistID)
+			ui.Printf("   YouTube: https://www.youtube.com/playlist?list=%s\n", state.YouTubePlaylistID)
 		}
-		fmt.Printf("\n")
+		ui.Printf("\n")
 	}
 
-	if totalStates == 0 {
-		fmt.Println("No saved states found.")
+	if totalStates == 0 && tag != "" {
+		ui.Printf("No saved states tagged #%s.\n", state.NormalizeTag(tag))
+	} else if totalStates == 0 {
+		ui.Println("No saved states found.")
 	} else {
-		fmt.Printf("Total playlists being ported: %d\n", totalStates)
+		ui.Printf("📊 Totals\n")
+		ui.Printf("   Playlists being ported: %d\n", totalStates)
+		ui.Printf("   Tracks: %s\n", ui.FormatCount(totalTracks))
+		ui.Printf("   Listening time: %s total\n", ui.FormatDuration(totalListen))
+		ui.Printf("   Time spent porting: %s\n", ui.FormatDuration(totalSessions))
+		ui.Printf("   Est. quota used: ~%s units\n", ui.FormatCount(totalQuota))
+		ui.Printf("   State files: %s\n", ui.FormatBytes(totalSize))
 	}
 }
 
@@ -92,104 +153,182 @@ func showAllStates() {
 func showStateDetails(filename string, detailed bool) {
 	statePath := filename
 	if !strings.Contains(statePath, string(os.PathSeparator)) {
-		statePath = filepath.Join("states", filename)
+		statePath = filepath.Join(paths.States(), filename)
 	}
 
 	data, err := os.ReadFile(statePath)
 	if err != nil {
-		fmt.Printf("Error reading state file: %v\n", err)
+		ui.Printf("Error reading state file: %v\n", err)
 		return
 	}
 	if err != nil {
-		fmt.Printf("Error reading state file: %v\n", err)
+		ui.Printf("Error reading state file: %v\n", err)
 		return
 	}
 
 	var state state.PortingState
 	if err := json.Unmarshal(data, &state); err != nil {
-		fmt.Printf("Error parsing state file: %v\n", err)
+		ui.Printf("Error parsing state file: %v\n", err)
 		return
 	}
 
-	fmt.Printf("📋 Playlist: %s\n", state.OriginalPlaylist.Name)
-	fmt.Printf("========================================\n\n")
+	ui.Printf("📋 Playlist: %s\n", state.OriginalPlaylist.Name)
+	ui.Printf("========================================\n\n")
 
-	fmt.Printf("📊 Overall Progress\n")
-	fmt.Printf("------------------\n")
-	fmt.Printf("Spotify URL: %s\n", state.SpotifyURL)
-	fmt.Printf("Progress: %s", state.GetProgress())
+	ui.Printf("📊 Overall Progress\n")
+	ui.Printf("------------------\n")
+	ui.Printf("Spotify URL: %s\n", state.SpotifyURL)
+	if len(state.Tags) > 0 {
+		ui.Printf("Tags: %s\n", formatTags(state.Tags))
+	}
+	ui.Printf("Progress: %s", state.GetProgress())
 	if state.IsComplete {
-		fmt.Printf(" ✅ COMPLETE")
+		ui.Printf(" ✅ COMPLETE")
+	}
+	ui.Printf("\n")
+	ui.Printf("Mode: %s", state.GetMode())
+	if state.ArchiveWeekly {
+		ui.Printf(" (one playlist per week)")
+	}
+	ui.Printf("\n")
+	if state.IsSourceUnavailable() {
+		ui.Printf("Source: 🚫 unavailable on Spotify since %s\n", state.SourceUnavailableSince.Format("2006-01-02 15:04"))
+	}
+	if listening := state.GetTotalDuration(); listening > 0 {
+		ui.Printf("Listening time: %s total\n", ui.FormatDuration(listening))
+	}
+	ui.Printf("State file: %s\n", ui.FormatBytes(int64(len(data))))
+	ui.Printf("Created: %s\n", state.CreatedAt.Format("2006-01-02 15:04:05"))
+	ui.Printf("Last updated: %s\n", state.LastUpdatedAt.Format("2006-01-02 15:04:05"))
+
+	if state.IsMerged() {
+		ui.Printf("\n🔀 Merged Sources (%d)\n", len(state.Sources))
+		ui.Printf("------------------\n")
+		for _, source := range state.Sources {
+			count := 0
+			for _, ids := range state.TrackSources {
+				for _, id := range ids {
+					if id == source.SpotifyID {
+						count++
+					}
+				}
+			}
+			ui.Printf("%s: %d tracks (%s)\n", source.Name, count, source.SpotifyURL)
+		}
 	}
-	fmt.Printf("\n")
-	fmt.Printf("Created: %s\n", state.CreatedAt.Format("2006-01-02 15:04:05"))
-	fmt.Printf("Last updated: %s\n", state.LastUpdatedAt.Format("2006-01-02 15:04:05"))
 
 	if state.YouTubePlaylistID != "" {
-		fmt.Printf("\n📺 YouTube Playlist\n")
-		fmt.Printf("------------------\n")
-		fmt.Printf("Name: %s\n", state.YouTubePlaylistName)
-		fmt.Printf("URL: https://www.youtube.com/playlist?list=%s\n", state.YouTubePlaylistID)
+		ui.Printf("\n📺 YouTube Playlist\n")
+		ui.Printf("------------------\n")
+		ui.Printf("Name: %s\n", state.YouTubePlaylistName)
+		ui.Printf("URL: https://www.youtube.com/playlist?list=%s\n", state.YouTubePlaylistID)
 	}
 
 	// Session history
-	fmt.Printf("\n📅 Session History (%d sessions)\n", len(state.Sessions))
-	fmt.Printf("------------------\n")
+	ui.Printf("\n📅 Session History (%d sessions)\n", len(state.Sessions))
+	ui.Printf("------------------\n")
 	for i, session := range state.Sessions {
 		duration := session.EndTime.Sub(session.StartTime)
-		fmt.Printf("Session %d: %s\n", i+1, session.StartTime.Format("2006-01-02 15:04"))
-		fmt.Printf("  Duration: %s\n", duration.Round(time.Second))
-		fmt.Printf("  Tracks processed: %d\n", session.TracksProcessed)
-		fmt.Printf("  Tracks matched: %d (%.1f%%)\n",
+		ui.Printf("Session %d: %s\n", i+1, session.StartTime.Format("2006-01-02 15:04"))
+		if session.Note != "" {
+			ui.Printf("  Note: %s\n", session.Note)
+		}
+		ui.Printf("  Duration: %s\n", ui.FormatDuration(duration))
+		ui.Printf("  Tracks processed: %d\n", session.TracksProcessed)
+		ui.Printf("  Tracks matched: %d (%.1f%%)\n",
 			session.TracksMatched,
 			float64(session.TracksMatched)/float64(session.TracksProcessed)*100)
-		fmt.Printf("  Est. quota used: ~%d units\n", session.QuotaUsed)
+		ui.Printf("  Est. quota used: ~%s units\n", ui.FormatCount(session.QuotaUsed))
 	}
 
 	// Match statistics
 	successful := 0
 	failed := 0
+	lowConfidence := 0
+	mismatched := 0
 	var failedTracks []string
+	var failedResults []models.MatchResult
 
 	for _, result := range state.MatchResults {
 		if result.Matched {
 			successful++
+			if result.MatchScore < ui.LowConfidenceScore {
+				lowConfidence++
+			}
+			if result.CrossCheck == models.CrossCheckMismatch {
+				mismatched++
+			}
 		} else {
 			failed++
 			failedTracks = append(failedTracks,
 				fmt.Sprintf("%s - %s", result.OriginalTrack.Artist, result.OriginalTrack.Title))
+			failedResults = append(failedResults, result)
 		}
 	}
 
-	fmt.Printf("\n📈 Match Statistics\n")
-	fmt.Printf("------------------\n")
-	fmt.Printf("Successful matches: %d\n", successful)
-	fmt.Printf("Failed matches: %d\n", failed)
+	ui.Printf("\n📈 Match Statistics\n")
+	ui.Printf("------------------\n")
+	ui.Printf("%s\n", ui.Green(fmt.Sprintf("Successful matches: %d", successful)))
+	if lowConfidence > 0 {
+		ui.Printf("%s\n", ui.Yellow(fmt.Sprintf("Low-confidence matches: %d (score below %.2f)", lowConfidence, ui.LowConfidenceScore)))
+	}
+	if mismatched > 0 {
+		ui.Printf("%s\n", ui.Yellow(fmt.Sprintf("Odesli cross-check mismatches: %d", mismatched)))
+	}
+	ui.Printf("%s\n", ui.Red(fmt.Sprintf("Failed matches: %d", failed)))
 	if state.ProcessedTracks > 0 {
-		fmt.Printf("Success rate: %.1f%%\n", float64(successful)/float64(state.ProcessedTracks)*100)
+		ui.Printf("Success rate: %.1f%%\n", float64(successful)/float64(state.ProcessedTracks)*100)
+	}
+	ui.Printf("Total estimated quota used: ~%s units\n", ui.FormatCount(state.GetTotalQuotaUsed()))
+	ui.Printf("Total time spent porting: %s\n", ui.FormatDuration(state.GetTotalSessionTime()))
+
+	if len(state.Substitutions) > 0 {
+		ui.Printf("Videos replaced after removal: %d\n", len(state.Substitutions))
 	}
-	fmt.Printf("Total estimated quota used: ~%d units\n", state.GetTotalQuotaUsed())
 
 	// Show failed tracks if requested
 	if detailed && len(failedTracks) > 0 {
-		fmt.Printf("\n❌ Failed Tracks (%d)\n", len(failedTracks))
-		fmt.Printf("------------------\n")
+		ui.Printf("\n❌ Failed Tracks (%d)\n", len(failedTracks))
+		ui.Printf("------------------\n")
 		for i, track := range failedTracks {
-			fmt.Printf("%d. %s\n", i+1, track)
+			ui.Printf("%d. %s\n", i+1, ui.Red(track))
+			if failedResults[i].Error != "" {
+				ui.Printf("   Reason: %s\n", failedResults[i].Error)
+			}
+			for _, suggestion := range triage.Suggest(failedResults[i], state.GetDestination()) {
+				ui.Printf("   💡 %s\n", suggestion)
+			}
 			if i >= 20 && len(failedTracks) > 25 {
-				fmt.Printf("... and %d more\n", len(failedTracks)-20)
+				ui.Printf("... and %d more\n", len(failedTracks)-20)
 				break
 			}
 		}
 	}
 
+	// Track notes
+	if detailed && len(state.TrackNotes) > 0 {
+		ui.Printf("\n📝 Track Notes (%d)\n", len(state.TrackNotes))
+		ui.Printf("------------------\n")
+		for i, track := range state.OriginalPlaylist.Tracks {
+			if note := state.GetNote(track.ID); note != "" {
+				ui.Printf("%d. %s - %s: %s\n", i+1, track.Artist, track.Title, note)
+			}
+		}
+	}
+
 	// Next steps
 	if !state.IsComplete {
 		remaining := state.TotalTracks - state.ProcessedTracks
-		fmt.Printf("\n💡 Next Steps\n")
-		fmt.Printf("------------------\n")
-		fmt.Printf("Tracks remaining: %d\n", remaining)
-		fmt.Printf("Sessions needed: ~%d (at 50 tracks/session)\n", (remaining+49)/50)
-		fmt.Printf("Run the same command tomorrow to continue from track %d\n", state.ProcessedTracks+1)
+		ui.Printf("\n💡 Next Steps\n")
+		ui.Printf("------------------\n")
+		ui.Printf("Tracks remaining: %d\n", remaining)
+		ui.Printf("Sessions needed: ~%d (at 50 tracks/session)\n", (remaining+49)/50)
+		ui.Printf("Quota resets: %s\n", quota.ResetMessage(time.Now()))
+		ui.Printf("Run the same command after the reset to continue from track %d\n", state.ProcessedTracks+1)
 	}
 }
+
+// formatTags renders tags as "#workout #running"; the views shadow the state package with their state variable
+func formatTags(tags []string) string {
+	return state.FormatTags(tags)
+}
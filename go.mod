@@ -1,9 +1,10 @@
-module playlistporter
+module github.com/Verryx-02/PlaylistPorter
 
 go 1.21
 
 require (
 	golang.org/x/oauth2 v0.15.0
+	golang.org/x/text v0.14.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
@@ -0,0 +1,212 @@
+// Package acoustid verifies candidate videos by their audio: a short sample is downloaded with
+// a configurable command, fingerprinted with Chromaprint's fpcalc and identified by AcoustID
+package acoustid
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/config"
+	"github.com/Verryx-02/PlaylistPorter/internal/traffic"
+)
+
+const (
+	lookupURL      = "https://api.acoustid.org/v2/lookup"
+	requestTimeout = 20 * time.Second
+
+	// AcoustID allows three requests per second
+	requestInterval = 350 * time.Millisecond
+
+	// commandTimeout bounds downloading and fingerprinting one sample
+	commandTimeout = 2 * time.Minute
+
+	// confidentScore is the AcoustID result score from which an identification
+	// that doesn't match the ISRC rejects the candidate
+	confidentScore = 0.8
+)
+
+// DefaultSampleCommand downloads the first minute of a video's audio with yt-dlp (needs ffmpeg)
+var DefaultSampleCommand = []string{
+	"yt-dlp", "--quiet", "--no-playlist", "-f", "bestaudio",
+	"--download-sections", "*0:00-1:00", "-o", "{dir}/sample.%(ext)s", "{url}",
+}
+
+// Verdicts of a verification
+const (
+	Verified = "verified" // The sample is a recording with the source ISRC
+	Rejected = "rejected" // AcoustID confidently identifies the sample as another recording
+	Unknown  = "unknown"  // AcoustID doesn't know the sample; the match is kept as it is
+)
+
+// Verifier checks candidate videos against the recordings of the source track's ISRC
+type Verifier struct {
+	apiKey        string
+	sampleCommand []string
+	fpcalc        string
+	httpClient    *http.Client
+	lastRequest   time.Time
+}
+
+// NewVerifier checks that the sample command and fpcalc can be found
+func NewVerifier(cfg *config.AcoustIDConfig) (*Verifier, error) {
+	if cfg.APIKey == "" {
+		return nil, errors.New("acoustid.api_key is required (register an application at https://acoustid.org/new-application)")
+	}
+
+	v := &Verifier{
+		apiKey:        cfg.APIKey,
+		sampleCommand: cfg.SampleCommand,
+		fpcalc:        cfg.FpcalcPath,
+		httpClient:    traffic.NewClient(requestTimeout),
+	}
+	if len(v.sampleCommand) == 0 {
+		v.sampleCommand = DefaultSampleCommand
+	}
+	if v.fpcalc == "" {
+		v.fpcalc = "fpcalc"
+	}
+
+	if _, err := exec.LookPath(v.sampleCommand[0]); err != nil {
+		return nil, fmt.Errorf("sample command %s not found: %w", v.sampleCommand[0], err)
+	}
+	if _, err := exec.LookPath(v.fpcalc); err != nil {
+		return nil, fmt.Errorf("fpcalc not found (install Chromaprint or set acoustid.fpcalc_path): %w", err)
+	}
+	return v, nil
+}
+
+// Verify fingerprints a sample of the video at videoURL and compares AcoustID's identification
+// with recordingIDs, the MusicBrainz recordings carrying the source ISRC. trackDuration is the
+// length of the whole source track, which AcoustID uses to narrow the lookup.
+func (v *Verifier) Verify(videoURL string, trackDuration time.Duration, recordingIDs []string) (string, error) {
+	dir, err := os.MkdirTemp("", "playlistporter-sample-")
+	if err != nil {
+		return "", fmt.Errorf("creating sample directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	sample, err := v.downloadSample(videoURL, dir)
+	if err != nil {
+		return "", err
+	}
+
+	fingerprint, err := v.fingerprint(sample)
+	if err != nil {
+		return "", err
+	}
+
+	results, err := v.lookup(fingerprint, trackDuration)
+	if err != nil {
+		return "", err
+	}
+
+	wanted := make(map[string]bool, len(recordingIDs))
+	for _, id := range recordingIDs {
+		wanted[id] = true
+	}
+
+	verdict := Unknown
+	for _, result := range results {
+		for _, recording := range result.Recordings {
+			if wanted[recording.ID] {
+				return Verified, nil
+			}
+		}
+		if result.Score >= confidentScore && len(result.Recordings) > 0 {
+			verdict = Rejected
+		}
+	}
+	return verdict, nil
+}
+
+// downloadSample runs the sample command and returns the file it wrote into dir
+func (v *Verifier) downloadSample(videoURL, dir string) (string, error) {
+	args := make([]string, len(v.sampleCommand))
+	for i, arg := range v.sampleCommand {
+		arg = strings.ReplaceAll(arg, "{url}", videoURL)
+		args[i] = strings.ReplaceAll(arg, "{dir}", dir)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	if output, err := exec.CommandContext(ctx, args[0], args[1:]...).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("downloading sample: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	if err != nil || len(files) == 0 {
+		return "", fmt.Errorf("the sample command wrote no file into {dir}")
+	}
+	return files[0], nil
+}
+
+// fingerprint computes the Chromaprint fingerprint of an audio file
+func (v *Verifier) fingerprint(path string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, v.fpcalc, "-json", path).Output()
+	if err != nil {
+		return "", fmt.Errorf("fingerprinting sample: %w", err)
+	}
+
+	var result struct {
+		Fingerprint string `json:"fingerprint"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil || result.Fingerprint == "" {
+		return "", fmt.Errorf("fpcalc returned no fingerprint")
+	}
+	return result.Fingerprint, nil
+}
+
+// lookup identifies a fingerprint with AcoustID
+func (v *Verifier) lookup(fingerprint string, duration time.Duration) ([]lookupResult, error) {
+	if wait := requestInterval - time.Since(v.lastRequest); wait > 0 {
+		time.Sleep(wait)
+	}
+	v.lastRequest = time.Now()
+
+	form := url.Values{}
+	form.Set("client", v.apiKey)
+	form.Set("meta", "recordingids")
+	form.Set("duration", fmt.Sprint(int(duration.Seconds())))
+	form.Set("fingerprint", fingerprint)
+
+	resp, err := v.httpClient.PostForm(lookupURL, form)
+	if err != nil {
+		return nil, fmt.Errorf("querying AcoustID: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var response lookupResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("decoding AcoustID response: %w", err)
+	}
+	if response.Status != "ok" {
+		return nil, fmt.Errorf("AcoustID lookup failed: %s", response.Error.Message)
+	}
+	return response.Results, nil
+}
+
+type lookupResponse struct {
+	Status  string         `json:"status"`
+	Results []lookupResult `json:"results"`
+	Error   struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type lookupResult struct {
+	Score      float64 `json:"score"`
+	Recordings []struct {
+		ID string `json:"id"`
+	} `json:"recordings"`
+}
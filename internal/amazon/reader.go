@@ -0,0 +1,205 @@
+// Package amazon reads Amazon Music shared playlists by scraping their public page
+package amazon
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/traffic"
+)
+
+// IDPrefix marks Amazon Music playlist IDs in states
+const IDPrefix = "amazon-"
+
+const (
+	playlistURL  = "https://music.amazon.com/%s/%s"
+	maxPageBytes = 10 * 1024 * 1024
+	userAgent    = "Mozilla/5.0 (compatible; PlaylistPorter)"
+)
+
+// ErrPlaylistUnavailable is returned when the shared page can't be read
+var ErrPlaylistUnavailable = errors.New("Amazon Music playlist is private, deleted or not available")
+
+// playlistURLPattern matches music.amazon.<tld>/playlists/ID and /user-playlists/ID
+var playlistURLPattern = regexp.MustCompile(`(?i)^(?:https?://)?music\.amazon\.[a-z.]+/(playlists|user-playlists)/([0-9A-Za-z]+)`)
+
+// jsonLDPattern finds the structured data blocks embedded in the page
+var jsonLDPattern = regexp.MustCompile(`(?is)<script[^>]+type="application/ld\+json"[^>]*>(.*?)</script>`)
+
+// IsAmazonURL reports whether a link points to Amazon Music
+func IsAmazonURL(link string) bool {
+	return strings.Contains(strings.ToLower(link), "music.amazon.")
+}
+
+// IsAmazonID reports whether a state ID belongs to an Amazon Music playlist
+func IsAmazonID(id string) bool {
+	return strings.HasPrefix(id, IDPrefix)
+}
+
+// PlaylistIDFromURL extracts the prefixed playlist ID from a shared playlist link.
+// User playlists keep a "u-" marker so the page can be found again.
+func PlaylistIDFromURL(link string) (string, error) {
+	parts := playlistURLPattern.FindStringSubmatch(strings.TrimSpace(link))
+	if parts == nil {
+		return "", fmt.Errorf("not an Amazon Music playlist link (expected https://music.amazon.com/user-playlists/...)")
+	}
+	if strings.EqualFold(parts[1], "user-playlists") {
+		return IDPrefix + "u-" + parts[2], nil
+	}
+	return IDPrefix + parts[2], nil
+}
+
+// Reader fetches shared playlist pages
+type Reader struct {
+	httpClient *http.Client
+}
+
+// NewReader creates a new Amazon Music reader; no credentials are needed
+func NewReader() *Reader {
+	return &Reader{httpClient: traffic.NewClient(30 * time.Second)}
+}
+
+// GetPlaylist reads the artist/title pairs of a shared playlist from its page's structured data.
+// Amazon Music doesn't publish ISRCs there, so matching relies on titles and durations.
+func (r *Reader) GetPlaylist(id string) (*models.Playlist, error) {
+	page, err := r.fetchPage(id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, block := range jsonLDPattern.FindAllStringSubmatch(page, -1) {
+		var data musicPlaylist
+		if json.Unmarshal([]byte(block[1]), &data) != nil || data.Type != "MusicPlaylist" {
+			continue
+		}
+
+		playlist := &models.Playlist{
+			ID:          id,
+			Name:        html.UnescapeString(data.Name),
+			Description: html.UnescapeString(data.Description),
+			IsPublic:    true,
+		}
+		seen := make(map[string]int)
+		for _, recording := range data.Tracks {
+			title := html.UnescapeString(recording.Name)
+			artist := html.UnescapeString(recording.ByArtist.Name)
+			playlist.Tracks = append(playlist.Tracks, models.Track{
+				ID:       trackID(artist, title, seen),
+				Title:    title,
+				Artist:   artist,
+				Album:    html.UnescapeString(recording.InAlbum.Name),
+				Duration: parseISODuration(recording.Duration),
+			})
+		}
+		playlist.TotalTracks = len(playlist.Tracks)
+
+		if len(playlist.Tracks) == 0 {
+			return nil, fmt.Errorf("the Amazon Music page lists no tracks")
+		}
+		return playlist, nil
+	}
+
+	return nil, fmt.Errorf("no track list found on the Amazon Music page (the page layout may have changed)")
+}
+
+// trackID derives a stable ID from artist and title, since the page has no track IDs;
+// repeats of the same song get a counter so every entry stays unique
+func trackID(artist, title string, seen map[string]int) string {
+	sum := sha1.Sum([]byte(strings.ToLower(artist + "\x00" + title)))
+	id := IDPrefix + hex.EncodeToString(sum[:8])
+
+	seen[id]++
+	if n := seen[id]; n > 1 {
+		id = fmt.Sprintf("%s-%d", id, n)
+	}
+	return id
+}
+
+// fetchPage downloads the shared playlist page
+func (r *Reader) fetchPage(id string) (string, error) {
+	kind, pageID := "playlists", strings.TrimPrefix(id, IDPrefix)
+	if strings.HasPrefix(pageID, "u-") {
+		kind, pageID = "user-playlists", pageID[2:]
+	}
+
+	req, err := http.NewRequest("GET", fmt.Sprintf(playlistURL, kind, pageID), nil)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusForbidden:
+		return "", fmt.Errorf("%w (status %d)", ErrPlaylistUnavailable, resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("page request failed with status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPageBytes))
+	if err != nil {
+		return "", fmt.Errorf("reading page: %w", err)
+	}
+	return string(body), nil
+}
+
+// isoDurationPattern matches ISO 8601 durations such as PT3M45S
+var isoDurationPattern = regexp.MustCompile(`^PT(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?$`)
+
+// parseISODuration converts an ISO 8601 duration, returning 0 if it can't be parsed
+func parseISODuration(value string) time.Duration {
+	parts := isoDurationPattern.FindStringSubmatch(value)
+	if parts == nil {
+		return 0
+	}
+
+	units := []time.Duration{time.Hour, time.Minute, time.Second}
+	var total time.Duration
+	for i, unit := range units {
+		if parts[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(parts[i+1])
+		if err != nil {
+			return 0
+		}
+		total += time.Duration(n) * unit
+	}
+	return total
+}
+
+// schema.org structured data embedded in the page
+
+type musicPlaylist struct {
+	Type        string           `json:"@type"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Tracks      []musicRecording `json:"track"`
+}
+
+type musicRecording struct {
+	Name     string `json:"name"`
+	Duration string `json:"duration"`
+	ByArtist struct {
+		Name string `json:"name"`
+	} `json:"byArtist"`
+	InAlbum struct {
+		Name string `json:"name"`
+	} `json:"inAlbum"`
+}
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+var noBrowser atomic.Bool
+
+// SetNoBrowser enables the headless sign-in: the authorization code is pasted into the
+// terminal instead of being received by the local callback server
+func SetNoBrowser(enabled bool) {
+	noBrowser.Store(enabled)
+}
+
+// NoBrowser reports whether the headless sign-in is on
+func NoBrowser() bool {
+	return noBrowser.Load()
+}
+
+// PromptForCode prints the authorization URL and reads the code, or the whole redirect URL,
+// that the user pastes into the terminal after signing in on another device. A pasted URL
+// must carry state, the nonce of the authorization URL.
+func PromptForCode(authURL, redirectURI, state string) (string, error) {
+	fmt.Printf("Open this URL in a browser on any device and allow access:\n\n%s\n\n", authURL)
+	fmt.Printf("The browser is then sent to %s, which won't load on that device.\n", redirectURI)
+	fmt.Println("Copy the full address from the browser's address bar (or just its code parameter) and paste it here.")
+
+	in := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Print("Code or redirect URL: ")
+		line, err := in.ReadString('\n')
+		if input := strings.TrimSpace(line); input != "" {
+			return ParseCode(input, state)
+		}
+		if err != nil {
+			return "", fmt.Errorf("reading authorization code: %w", err)
+		}
+	}
+}
+
+// ParseCode extracts the authorization code from a pasted redirect URL, or returns the input
+// as is when it is a bare code. A URL whose state parameter isn't state (when set) is refused;
+// a bare code has nothing to check.
+func ParseCode(input, state string) (string, error) {
+	input = strings.TrimSpace(input)
+	if !strings.Contains(input, "code=") && !strings.Contains(input, "error=") {
+		if strings.ContainsAny(input, " ?") {
+			return "", fmt.Errorf("%q doesn't look like an authorization code", input)
+		}
+		return input, nil
+	}
+
+	query := input
+	if i := strings.Index(input, "?"); i >= 0 {
+		query = input[i+1:]
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return "", fmt.Errorf("parsing redirect URL: %w", err)
+	}
+	if state != "" && values.Get("state") != state {
+		return "", fmt.Errorf("the redirect URL belongs to another sign-in attempt (state parameter doesn't match), use the URL printed above")
+	}
+	if errorMsg := values.Get("error"); errorMsg != "" {
+		return "", fmt.Errorf("authorization error: %s", errorMsg)
+	}
+	code := values.Get("code")
+	if code == "" {
+		return "", fmt.Errorf("no authorization code in the redirect URL")
+	}
+	return code, nil
+}
@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/traffic"
+)
+
+// ErrNoToken is returned when there is no saved token to revoke
+var ErrNoToken = errors.New("no saved authorization")
+
+// Revoke invalidates a saved token at the provider's RFC 7009 revocation endpoint and then
+// deletes it from the cache. The refresh token is revoked when there is one, which also ends
+// the access tokens issued with it. A token the provider no longer knows counts as revoked.
+func Revoke(name, endpoint string) error {
+	token, err := LoadToken(name)
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		return ErrNoToken
+	}
+
+	value := token.RefreshToken
+	if value == "" {
+		value = token.AccessToken
+	}
+
+	client := traffic.NewClient(30 * time.Second)
+	resp, err := client.Post(endpoint, "application/x-www-form-urlencoded",
+		strings.NewReader(url.Values{"token": {value}}.Encode()))
+	if err != nil {
+		return fmt.Errorf("revoking token: %w", err)
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	resp.Body.Close()
+
+	// Google answers 400 invalid_token for tokens that expired or were revoked already
+	alreadyGone := resp.StatusCode == http.StatusBadRequest && strings.Contains(string(body), "invalid_token")
+	if resp.StatusCode != http.StatusOK && !alreadyGone {
+		return fmt.Errorf("revoking token: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	return DeleteToken(name)
+}
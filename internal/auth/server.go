@@ -1,27 +1,119 @@
 package auth
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"net/url"
 	"time"
 )
 
-// StartHTTPServer starts a local HTTP server for OAuth callback
-func StartHTTPServer(port string, codeChan chan string, errChan chan error) {
-	// Setup HTTP handler
-	http.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
-		fmt.Printf("Received callback request: %s\n", r.URL.String())
-
-		code := r.URL.Query().Get("code")
-		if code == "" {
-			errorMsg := r.URL.Query().Get("error")
-			if errorMsg != "" {
-				http.Error(w, fmt.Sprintf("Authorization error: %s", errorMsg), http.StatusBadRequest)
-				errChan <- fmt.Errorf("authorization error: %s", errorMsg)
-				return
-			}
-			http.Error(w, "No authorization code received", http.StatusBadRequest)
-			errChan <- fmt.Errorf("no authorization code received")
+// AutoPort as callback port lets the system pick a free port
+const AutoPort = "auto"
+
+// ListenCallback binds the port of the OAuth callback server: port, or the port of redirectURI
+// when empty. With fallback, a free port is taken when that one is in use. It returns the
+// listener and redirectURI rewritten to the bound port.
+func ListenCallback(redirectURI, port string, fallback bool) (net.Listener, string, error) {
+	if port == "" {
+		port = CallbackPort(redirectURI)
+	}
+	if port == AutoPort {
+		port = "0"
+	}
+
+	listener, err := net.Listen("tcp", ":"+port)
+	if err != nil && fallback && port != "0" {
+		fmt.Printf("Port %s is in use, using a free port for the sign-in callback instead\n", port)
+		listener, err = net.Listen("tcp", ":0")
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("starting the sign-in callback server on port %s: %w", port, err)
+	}
+
+	bound := fmt.Sprint(listener.Addr().(*net.TCPAddr).Port)
+	return listener, WithPort(redirectURI, bound), nil
+}
+
+// WithPort rewrites the port of a redirect URI; unparsable URIs are returned unchanged
+func WithPort(redirectURI, port string) string {
+	u, err := url.Parse(redirectURI)
+	if err != nil || u.Host == "" {
+		return redirectURI
+	}
+	u.Host = net.JoinHostPort(u.Hostname(), port)
+	return u.String()
+}
+
+// CallbackTimeout is how long a sign-in waits for the browser to come back
+const CallbackTimeout = 5 * time.Minute
+
+// NewState returns a random nonce for the OAuth state parameter. The callback has to bring it
+// back, which tells the real redirect from a forged one (CSRF).
+func NewState() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// CallbackServer receives the authorization code of one sign-in on its own http.Server and
+// mux, so several sign-ins can follow each other in one process
+type CallbackServer struct {
+	server *http.Server
+	state  string
+	result chan callbackResult
+}
+
+type callbackResult struct {
+	code string
+	err  error
+}
+
+// StartCallbackServer serves the OAuth callback on listener in the background. Only callbacks
+// carrying state are accepted; the first one ends the sign-in and later ones are turned away.
+func StartCallbackServer(listener net.Listener, state string) *CallbackServer {
+	port := fmt.Sprint(listener.Addr().(*net.TCPAddr).Port)
+	s := &CallbackServer{
+		state:  state,
+		result: make(chan callbackResult, 1),
+	}
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		// Without the nonce this may be a forged redirect, or one from an older sign-in attempt;
+		// either way it must not end this one
+		if subtle.ConstantTimeCompare([]byte(query.Get("state")), []byte(s.state)) != 1 {
+			fmt.Println("Ignored a sign-in callback with the wrong state parameter; waiting for the right one")
+			http.Error(w, "This sign-in link is out of date or not from PlaylistPorter. Use the URL shown in the terminal.", http.StatusBadRequest)
+			return
+		}
+
+		var result callbackResult
+		switch {
+		case query.Get("code") != "":
+			result.code = query.Get("code")
+		case query.Get("error") != "":
+			result.err = fmt.Errorf("authorization error: %s", query.Get("error"))
+		default:
+			result.err = fmt.Errorf("no authorization code received")
+		}
+
+		select {
+		case s.result <- result:
+		default:
+			http.Error(w, "This sign-in is already complete. You can close this window.", http.StatusConflict)
+			return
+		}
+
+		if result.err != nil {
+			http.Error(w, "Sign-in failed: "+result.err.Error(), http.StatusBadRequest)
 			return
 		}
 
@@ -71,14 +163,10 @@ func StartHTTPServer(port string, codeChan chan string, errChan chan error) {
 			</body>
 			</html>
 		`))
-
-		// Send code to channel
-		fmt.Printf("Sending authorization code to channel\n")
-		codeChan <- code
 	})
 
 	// Add a simple root handler for debugging
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == "/" {
 			w.WriteHeader(http.StatusOK)
 			w.Write([]byte(`
@@ -88,7 +176,7 @@ func StartHTTPServer(port string, codeChan chan string, errChan chan error) {
 				<body>
 					<h1>🎵 PlaylistPorter OAuth Server</h1>
 					<p>This server is running and waiting for OAuth callbacks.</p>
-					<p>Callback endpoint: <code>http://localhost:8080/callback</code></p>
+					<p>Callback endpoint: <code>http://localhost:` + port + `/callback</code></p>
 					<p>Status: <span style="color: green;">Ready</span></p>
 				</body>
 				</html>
@@ -99,8 +187,8 @@ func StartHTTPServer(port string, codeChan chan string, errChan chan error) {
 	})
 
 	// Setup HTTP server (NOT HTTPS!)
-	server := &http.Server{
-		Addr:         ":" + port,
+	s.server = &http.Server{
+		Handler:      mux,
 		ReadTimeout:  30 * time.Second,
 		WriteTimeout: 30 * time.Second,
 	}
@@ -108,7 +196,74 @@ func StartHTTPServer(port string, codeChan chan string, errChan chan error) {
 	fmt.Printf("Starting HTTP server on http://localhost:%s\n", port)
 	fmt.Printf("Callback URL: http://localhost:%s/callback\n", port)
 
-	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		errChan <- fmt.Errorf("HTTP server error: %w", err)
+	go func() {
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			select {
+			case s.result <- callbackResult{err: fmt.Errorf("HTTP server error: %w", err)}:
+			default:
+			}
+		}
+	}()
+
+	return s
+}
+
+// Wait returns the authorization code once the browser has been redirected back, then shuts
+// the server down
+func (s *CallbackServer) Wait(timeout time.Duration) (string, error) {
+	defer s.Shutdown()
+
+	select {
+	case result := <-s.result:
+		return result.code, result.err
+	case <-time.After(timeout):
+		return "", fmt.Errorf("authentication timeout - no response received within %s", timeout)
+	}
+}
+
+// Shutdown stops the server, letting the success page finish loading first
+func (s *CallbackServer) Shutdown() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.server.Shutdown(ctx); err != nil {
+		s.server.Close()
+	}
+}
+
+// CallbackPort returns the port of a loopback redirect URI, defaulting to 8080
+func CallbackPort(redirectURI string) string {
+	u, err := url.Parse(redirectURI)
+	if err != nil || u.Port() == "" {
+		return "8080"
+	}
+	return u.Port()
+}
+
+// CheckCallback makes sure the browser can come back from a sign-in: it binds the callback
+// port like a sign-in would (without falling back to a free port) and requests the redirect
+// URI, which has to reach this process. It returns the redirect URI that was checked.
+func CheckCallback(redirectURI, port string) (string, error) {
+	listener, bound, err := ListenCallback(redirectURI, port, false)
+	if err != nil {
+		return redirectURI, err
+	}
+
+	probe := NewState()
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, probe)
+	})}
+	go server.Serve(listener)
+	defer server.Close()
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(bound)
+	if err != nil {
+		return bound, fmt.Errorf("requesting %s: %w", bound, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if string(body) != probe {
+		return bound, fmt.Errorf("%s is answered by something else than the sign-in callback server", bound)
 	}
+	return bound, nil
 }
@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+// TokenDir returns the directory holding cached OAuth tokens: PLAYLISTPORTER_TOKEN_DIR
+// if set, otherwise ~/.playlistporter/tokens
+func TokenDir() (string, error) {
+	if dir := os.Getenv("PLAYLISTPORTER_TOKEN_DIR"); dir != "" {
+		return dir, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("finding home directory: %w", err)
+	}
+	return filepath.Join(home, ".playlistporter", "tokens"), nil
+}
+
+// TokenPath returns the cache file of a named token, e.g. "youtube"
+func TokenPath(name string) (string, error) {
+	dir, err := TokenDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name+".json"), nil
+}
+
+// LoadToken reads a cached token; it returns nil without error when none is cached
+func LoadToken(name string) (*oauth2.Token, error) {
+	path, err := TokenPath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading token cache: %w", err)
+	}
+
+	var token oauth2.Token
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("parsing token cache %s: %w", path, err)
+	}
+	return &token, nil
+}
+
+// SaveToken writes a token to the cache, readable only by the current user
+func SaveToken(name string, token *oauth2.Token) error {
+	path, err := TokenPath(name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("creating token directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(token, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling token: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("writing token cache: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("renaming token cache: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteToken removes a cached token; a missing token is not an error
+func DeleteToken(name string) error {
+	path, err := TokenPath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing token cache: %w", err)
+	}
+	return nil
+}
+
+// savingTokenSource writes every newly refreshed token back to the cache
+type savingTokenSource struct {
+	name string
+	base oauth2.TokenSource
+
+	mu   sync.Mutex
+	last string // Access token last written
+}
+
+// NewSavingTokenSource wraps a token source so refreshed tokens are cached under name
+func NewSavingTokenSource(name string, base oauth2.TokenSource) oauth2.TokenSource {
+	return &savingTokenSource{name: name, base: base}
+}
+
+// Token returns a valid token, refreshing and caching it when needed
+func (s *savingTokenSource) Token() (*oauth2.Token, error) {
+	token, err := s.base.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if token.AccessToken != s.last {
+		// A failed write only costs a refresh on the next run
+		if err := SaveToken(s.name, token); err == nil {
+			s.last = token.AccessToken
+		}
+	}
+
+	return token, nil
+}
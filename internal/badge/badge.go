@@ -0,0 +1,138 @@
+// Package badge writes a small migration status artifact for a playlist, a JSON summary
+// and an SVG badge, meant to be published e.g. on a personal site
+package badge
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/state"
+)
+
+// Status is the JSON summary of a playlist's migration
+type Status struct {
+	Name        string    `json:"name"`
+	PlaylistID  string    `json:"playlist_id"`
+	Destination string    `json:"destination"`
+	TotalTracks int       `json:"total_tracks"`
+	Processed   int       `json:"processed"`
+	Matched     int       `json:"matched"`
+	Percent     int       `json:"percent_ported"` // Matched tracks out of all tracks
+	Complete    bool      `json:"complete"`
+	LastSync    time.Time `json:"last_sync"` // Last run that changed the state
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// StatusOf summarizes a state
+func StatusOf(s *state.PortingState) Status {
+	status := Status{
+		Name:        s.OriginalPlaylist.Name,
+		PlaylistID:  s.SpotifyID,
+		Destination: s.GetDestination(),
+		TotalTracks: s.TotalTracks,
+		Processed:   s.ProcessedTracks,
+		Complete:    s.IsComplete,
+		LastSync:    s.LastUpdatedAt,
+		GeneratedAt: time.Now(),
+	}
+	if s.LastSyncCheck.After(status.LastSync) {
+		status.LastSync = s.LastSyncCheck
+	}
+	for _, result := range s.MatchResults {
+		if result.Matched {
+			status.Matched++
+		}
+	}
+	if s.TotalTracks > 0 {
+		status.Percent = status.Matched * 100 / s.TotalTracks
+	}
+	return status
+}
+
+// Write writes <dir>/<playlist ID>.json and .svg and returns the SVG's path
+func Write(dir string, s *state.PortingState) (string, error) {
+	status := StatusOf(s)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("creating badge directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encoding badge status: %w", err)
+	}
+	base := filepath.Join(dir, s.SpotifyID)
+	if err := os.WriteFile(base+".json", append(data, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("writing badge status: %w", err)
+	}
+	if err := os.WriteFile(base+".svg", []byte(SVG(status)), 0644); err != nil {
+		return "", fmt.Errorf("writing badge: %w", err)
+	}
+	return base + ".svg", nil
+}
+
+// SVG renders a flat two-part badge: the playlist name, then "87% ported · synced Oct 16"
+func SVG(status Status) string {
+	label := status.Name
+	if len([]rune(label)) > 32 {
+		label = string([]rune(label)[:31]) + "…"
+	}
+	message := fmt.Sprintf("%d%% ported", status.Percent)
+	if status.Complete && status.Matched == status.TotalTracks {
+		message = "fully ported"
+	}
+	if !status.LastSync.IsZero() {
+		message += " · synced " + status.LastSync.Format("Jan 2, 2006")
+	}
+
+	color := "#e05d44" // Red below 50%
+	switch {
+	case status.Percent >= 90:
+		color = "#4c1"
+	case status.Percent >= 75:
+		color = "#97ca00"
+	case status.Percent >= 50:
+		color = "#dfb317"
+	}
+
+	labelWidth := textWidth(label)
+	messageWidth := textWidth(message)
+	width := labelWidth + messageWidth
+
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">
+  <title>%s: %s</title>
+  <linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>
+  <clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>
+  <g clip-path="url(#r)">
+    <rect width="%d" height="20" fill="#555"/>
+    <rect x="%d" width="%d" height="20" fill="%s"/>
+    <rect width="%d" height="20" fill="url(#s)"/>
+  </g>
+  <g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+    <text x="%d" y="14">%s</text>
+    <text x="%d" y="14">%s</text>
+  </g>
+</svg>
+`,
+		width, esc(label), esc(message),
+		esc(label), esc(message),
+		width,
+		labelWidth,
+		labelWidth, messageWidth, color,
+		width,
+		labelWidth/2, esc(label),
+		labelWidth+messageWidth/2, esc(message))
+}
+
+// textWidth estimates the width of 11px Verdana text plus padding
+func textWidth(text string) int {
+	return len([]rune(text))*7 + 10
+}
+
+// esc escapes text for SVG
+func esc(text string) string {
+	return html.EscapeString(text)
+}
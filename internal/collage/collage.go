@@ -0,0 +1,121 @@
+// Package collage builds a cover image from a grid of album arts
+package collage
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	_ "image/png" // Some album arts are PNG
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/traffic"
+)
+
+const (
+	// TileSize is the edge length of one album art in the collage, in pixels
+	TileSize = 320
+
+	maxGrid     = 4               // At most 4x4 album arts
+	maxArtBytes = 5 * 1024 * 1024 // Album arts larger than this are skipped
+	jpegQuality = 90
+)
+
+// GridSize returns the number of tiles per row for a number of distinct album arts
+func GridSize(arts int) int {
+	for n := maxGrid; n > 1; n-- {
+		if arts >= n*n {
+			return n
+		}
+	}
+	return 1
+}
+
+// Build downloads album arts and arranges the first ones into a square grid.
+// Duplicate URLs are used once; arts that fail to download are skipped.
+func Build(urls []string) (image.Image, error) {
+	var distinct []string
+	seen := make(map[string]bool)
+	for _, u := range urls {
+		if u != "" && !seen[u] {
+			seen[u] = true
+			distinct = append(distinct, u)
+		}
+	}
+	if len(distinct) == 0 {
+		return nil, fmt.Errorf("no album art available")
+	}
+
+	client := traffic.NewClient(15 * time.Second)
+
+	// Download until the largest grid the arts can fill is complete
+	grid := GridSize(len(distinct))
+	var arts []image.Image
+	for _, u := range distinct {
+		if len(arts) == grid*grid {
+			break
+		}
+		art, err := fetchImage(client, u)
+		if err != nil {
+			continue
+		}
+		arts = append(arts, art)
+	}
+	if len(arts) == 0 {
+		return nil, fmt.Errorf("none of the %d album arts could be downloaded", len(distinct))
+	}
+
+	// Shrink the grid when downloads failed
+	grid = GridSize(len(arts))
+	canvas := image.NewRGBA(image.Rect(0, 0, grid*TileSize, grid*TileSize))
+	draw.Draw(canvas, canvas.Bounds(), &image.Uniform{color.Black}, image.Point{}, draw.Src)
+
+	for i := 0; i < grid*grid; i++ {
+		tile := image.Rect(0, 0, TileSize, TileSize).Add(image.Pt((i%grid)*TileSize, (i/grid)*TileSize))
+		drawScaled(canvas, tile, arts[i])
+	}
+
+	return canvas, nil
+}
+
+// Encode writes the collage as a JPEG
+func Encode(w io.Writer, img image.Image) error {
+	return jpeg.Encode(w, img, &jpeg.Options{Quality: jpegQuality})
+}
+
+// fetchImage downloads and decodes one album art
+func fetchImage(client *http.Client, url string) (image.Image, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading %s: status %d", url, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxArtBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
+
+// drawScaled draws src into dst's rect with nearest-neighbour scaling
+func drawScaled(dst *image.RGBA, rect image.Rectangle, src image.Image) {
+	sb := src.Bounds()
+	for y := 0; y < rect.Dy(); y++ {
+		sy := sb.Min.Y + y*sb.Dy()/rect.Dy()
+		for x := 0; x < rect.Dx(); x++ {
+			sx := sb.Min.X + x*sb.Dx()/rect.Dx()
+			dst.Set(rect.Min.X+x, rect.Min.Y+y, src.At(sx, sy))
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// Package collate compares and sorts titles, artists and playlist names the way people read
+// them, in any script: case and accents are ignored first ("Beyoncé" sorts and matches like
+// "beyonce", "Ёлка" like "елка") and only break ties. Ordering follows the Unicode collation
+// algorithm of golang.org/x/text/collate with its root (language-neutral) tailoring.
+package collate
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	// primary ignores case and accents; full breaks the ties primary leaves. Collators keep
+	// internal buffers, so mu guards both.
+	mu      sync.Mutex
+	primary = collate.New(language.Und, collate.IgnoreCase, collate.IgnoreDiacritics, collate.IgnoreWidth)
+	full    = collate.New(language.Und)
+
+	folder = cases.Fold()
+)
+
+// letters spells the letters canonical decomposition doesn't split with their base letters,
+// so keys agree with the collator, which treats them as accented forms
+var letters = map[rune]string{
+	'æ': "ae", 'ð': "d", 'đ': "d", 'ħ': "h", 'ı': "i", 'ĳ': "ij", 'ł': "l", 'ŀ': "l",
+	'ø': "o", 'œ': "oe", 'þ': "th", 'ŧ': "t",
+}
+
+// Key returns the primary key of s for matching: case folded, without accents or other
+// combining marks, compatibility forms (full-width letters, ligatures) spelled out and runs
+// of whitespace collapsed. Strings with equal keys are the same for matching.
+func Key(s string) string {
+	stripped, _, err := transform.String(transform.Chain(
+		norm.NFKD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), s)
+	if err != nil {
+		stripped = s
+	}
+	folded := folder.String(stripped)
+
+	var b strings.Builder
+	b.Grow(len(folded))
+	for i, word := range strings.Fields(folded) {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		for _, r := range word {
+			if spelled, ok := letters[r]; ok {
+				b.WriteString(spelled)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	return b.String()
+}
+
+// Compare orders a and b ignoring case and accents first, then by the full collation order,
+// which puts lowercase and unaccented forms first; identical text compares equal
+func Compare(a, b string) int {
+	a, b = strings.Join(strings.Fields(a), " "), strings.Join(strings.Fields(b), " ")
+
+	mu.Lock()
+	c := primary.CompareString(a, b)
+	if c == 0 {
+		c = full.CompareString(a, b)
+	}
+	mu.Unlock()
+	if c != 0 {
+		return c
+	}
+	return strings.Compare(a, b) // Text the collator ranks equal, e.g. other normalization forms
+}
+
+// Less reports whether a sorts before b
+func Less(a, b string) bool {
+	return Compare(a, b) < 0
+}
+
+// Equal reports whether a and b differ at most in case, accents and spacing
+func Equal(a, b string) bool {
+	return Key(a) == Key(b)
+}
+
+// Contains reports whether substr occurs in s, ignoring case, accents and spacing
+func Contains(s, substr string) bool {
+	return strings.Contains(Key(s), Key(substr))
+}
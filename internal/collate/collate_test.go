@@ -0,0 +1,102 @@
+package collate
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Beyoncé", "beyonce"},
+		{"  Sigur   Rós ", "sigur ros"},
+		{"Beyoncé", "beyonce"}, // Decomposed accent
+		{"Motörhead", "motorhead"},
+		{"Røyksopp", "royksopp"},
+		{"Łona", "lona"},
+		{"Straße", "strasse"},
+		{"Ёлка", "елка"},
+		{"Άλκηστις Πρωτοψάλτη", "αλκηστισ πρωτοψαλτη"}, // Final sigma folds to σ
+		{"Mỹ Tâm", "my tam"},
+		{"ＹＯＡＳＯＢＩ", "yoasobi"}, // Full-width letters
+		{"夜に駆ける", "夜に駆ける"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Key(tt.in); got != tt.want {
+			t.Errorf("Key(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"beyonce", "Beyoncé", -1}, // Ties go to lowercase and unaccented
+		{"Beyoncé", "beyonce", 1},
+		{"Beyoncé", "Beyoncé", 0},
+		{"Sigur  Rós", "Sigur Rós", 0},
+		{"Émilie", "Eric", -1}, // Accents don't outweigh letters
+		{"zebra", "Ängel", 1},
+		{"Елка", "Ёлка", -1},
+		{"Ёлка", "Жуки", -1}, // Ё sorts with Е, not after Я as by code point
+		{"Ωmega", "alpha", 1},
+	}
+	for _, tt := range tests {
+		if got := sign(Compare(tt.a, tt.b)); got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSortAcrossScripts(t *testing.T) {
+	names := []string{"Zaz", "Ёлка", "Émilie Simon", "abba", "Жуки", "Alizée", "Елена", "Ábba"}
+	sort.Slice(names, func(i, j int) bool { return Less(names[i], names[j]) })
+	want := []string{"abba", "Ábba", "Alizée", "Émilie Simon", "Zaz", "Елена", "Ёлка", "Жуки"}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("sorted %q, want %q", names, want)
+		}
+	}
+}
+
+func TestContains(t *testing.T) {
+	tests := []struct {
+		s, substr string
+		want      bool
+	}{
+		{"Beyoncé feat. Jay-Z", "beyonce", true},
+		{"Sigur Rós", "ROS", true},
+		{"Ёлка - Прованс", "елка", true},
+		{"Mỹ Tâm", "my  tam", true},
+		{"Daft Punk", "punks", false},
+		{"Anything", "", true},
+	}
+	for _, tt := range tests {
+		if got := Contains(tt.s, tt.substr); got != tt.want {
+			t.Errorf("Contains(%q, %q) = %v, want %v", tt.s, tt.substr, got, tt.want)
+		}
+	}
+}
+
+func TestEqual(t *testing.T) {
+	if !Equal("Beyoncé", "BEYONCE") {
+		t.Error("Equal(Beyoncé, BEYONCE) = false")
+	}
+	if Equal("Beyoncé", "Beyonc") {
+		t.Error("Equal(Beyoncé, Beyonc) = true")
+	}
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
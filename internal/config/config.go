@@ -3,14 +3,43 @@ package config
 import (
 	"fmt"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/quota"
 )
 
 // Config holds all application configuration
 type Config struct {
-	SPT  SPTConfig  `yaml:"spt"`
-	TUBO TUBOConfig `yaml:"tubo"`
+	SPT   SPTConfig   `yaml:"spt"`
+	TUBO  TUBOConfig  `yaml:"tubo"`
+	Tidal TidalConfig `yaml:"tidal"`
+
+	SoundCloud  SoundCloudConfig  `yaml:"soundcloud"`
+	Jellyfin    JellyfinConfig    `yaml:"jellyfin"`
+	MusicFolder MusicFolderConfig `yaml:"music_folder"`
+	MusicBrainz MusicBrainzConfig `yaml:"musicbrainz"`
+	Matching    MatchingConfig    `yaml:"matching"`
+	Odesli      OdesliConfig      `yaml:"odesli"`
+	AcoustID    AcoustIDConfig    `yaml:"acoustid"`
+	Split       []SplitRule       `yaml:"split"`
+	OnComplete  []CompletionHook  `yaml:"on_complete"`
+	Courtesy    CourtesyConfig    `yaml:"courtesy"`
+	Quota       QuotaConfig       `yaml:"quota"`
+	BadgeDir    string            `yaml:"badge_dir"` // Status JSON and SVG badges refreshed after each run
+
+	// Values for command-line options not given on the command line or in the environment,
+	// keyed by option name, e.g. max_tracks: 30
+	Defaults map[string]string `yaml:"defaults"`
+
+	// Playlists with friendly names, for `playlistporter port <name>` and `sync -group <group>`
+	Playlists map[string]PlaylistEntry `yaml:"playlists"`
+
+	fromEnv    map[string]string // Config key -> environment variable that set it
+	secretFrom map[string]string // Secret config key -> file or command it was read from
 }
 
 // SPTConfig holds SPT-specific configuration
@@ -19,6 +48,14 @@ type SPTConfig struct {
 	ClientSecret string   `yaml:"client_secret"`
 	RedirectURI  string   `yaml:"redirect_uri"`
 	Scopes       []string `yaml:"scopes"`
+
+	// Read client_secret from a file, or from the output of a command such as pass, instead
+	ClientSecretFile string   `yaml:"client_secret_file"`
+	ClientSecretCmd  []string `yaml:"client_secret_cmd"`
+
+	// Sign in with your Spotify account (Authorization Code + PKCE) to read private
+	// playlists and Liked Songs; client_secret is not needed in this mode
+	UserAuth bool `yaml:"user_auth"`
 }
 
 // TUBOConfig holds TUBO Music-specific configuration
@@ -27,6 +64,198 @@ type TUBOConfig struct {
 	ClientSecret string   `yaml:"client_secret"`
 	RedirectURI  string   `yaml:"redirect_uri"`
 	Scopes       []string `yaml:"scopes"`
+
+	// Read client_secret from a file, or from the output of a command such as pass, instead
+	ClientSecretFile string   `yaml:"client_secret_file"`
+	ClientSecretCmd  []string `yaml:"client_secret_cmd"`
+
+	// Delay between playlist inserts (default 100ms when unset, 0 for none) plus a random
+	// jitter up to the given value
+	MutationDelayMS  *int `yaml:"mutation_delay_ms"`
+	MutationJitterMS int  `yaml:"mutation_jitter_ms"`
+
+	// Video inserted in place of failed matches so positions stay aligned with Spotify (optional)
+	PlaceholderVideoID string `yaml:"placeholder_video_id"`
+
+	// Fetch duration and region restrictions of search candidates to improve scoring
+	// (one videos.list call per search, 1 quota unit)
+	EnrichCandidates bool   `yaml:"enrich_candidates"`
+	RegionCode       string `yaml:"region_code"` // ISO 3166-1 alpha-2, e.g. "US"; penalizes videos blocked there
+
+	// Minimum match score depending on the Spotify popularity of the track (optional)
+	Popularity *PopularityPolicy `yaml:"popularity_policy"`
+
+	// Where tracks are searched: "data_api" (default, 100 quota units per search) or "ytmusic"
+	// (the YouTube Music web API, no quota); playlists are always written with the Data API
+	SearchBackend string `yaml:"search_backend"`
+
+	// YouTube account playlists are written with, e.g. "alice"; each account keeps its own
+	// saved sign-in (optional, the default account when empty)
+	Account string `yaml:"account"`
+
+	// Sign in with the device authorization grant: a short code is entered at google.com/device
+	// on any device, no local callback server (needs a "TVs and Limited Input devices" client)
+	DeviceCode bool `yaml:"device_code"`
+
+	// Port of the local sign-in callback server: a number, or "auto" for any free port
+	// (optional, the port of redirect_uri when empty); a port in use falls back to a free one
+	CallbackPort string `yaml:"callback_port"`
+
+	// Keep a block in the playlist description listing the source track of every position,
+	// for rebuilding a state or checking the playlist without the state files (optional)
+	PositionNotes bool `yaml:"position_notes"`
+}
+
+// Search backends for tubo.search_backend
+const (
+	SearchDataAPI = "data_api"
+	SearchYTMusic = "ytmusic"
+)
+
+// PopularityPolicy requires closer matches for popular tracks, whose official upload
+// surely exists, and accepts looser ones for obscure tracks, where a fan upload may be all there is
+type PopularityPolicy struct {
+	PopularAbove    int     `yaml:"popular_above"`     // Popularity from which a track counts as popular (e.g. 70)
+	PopularMinScore float64 `yaml:"popular_min_score"` // Minimum score for popular tracks (e.g. 0.65)
+	ObscureBelow    int     `yaml:"obscure_below"`     // Popularity below which a track counts as obscure (e.g. 20)
+	ObscureMinScore float64 `yaml:"obscure_min_score"` // Minimum score for obscure tracks (e.g. 0.4)
+}
+
+// TidalConfig holds the TIDAL API credentials, needed only to port TIDAL playlists
+type TidalConfig struct {
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret"`
+	CountryCode  string `yaml:"country_code"` // Catalog country, e.g. "US" (default)
+
+	ClientSecretFile string   `yaml:"client_secret_file"`
+	ClientSecretCmd  []string `yaml:"client_secret_cmd"`
+}
+
+// SoundCloudConfig holds the SoundCloud app credentials, needed only for -dest soundcloud
+type SoundCloudConfig struct {
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret"`
+	RedirectURI  string `yaml:"redirect_uri"` // Default http://127.0.0.1:8080/callback
+
+	ClientSecretFile string   `yaml:"client_secret_file"`
+	ClientSecretCmd  []string `yaml:"client_secret_cmd"`
+}
+
+// JellyfinConfig holds the Jellyfin server access, needed only for -dest jellyfin
+type JellyfinConfig struct {
+	ServerURL string `yaml:"server_url"` // e.g. http://192.168.1.10:8096
+	APIKey    string `yaml:"api_key"`    // Dashboard > API Keys
+	User      string `yaml:"user"`       // User whose library is searched and who owns the playlists
+
+	APIKeyFile string   `yaml:"api_key_file"`
+	APIKeyCmd  []string `yaml:"api_key_cmd"`
+}
+
+// MusicFolderConfig names the folder of audio files used by -dest folder
+type MusicFolderConfig struct {
+	Path        string `yaml:"path"`         // Scanned recursively for tagged audio files
+	PlaylistDir string `yaml:"playlist_dir"` // Where the .m3u8 files are written (default: path)
+}
+
+// MusicBrainzConfig enables looking up each track's ISRC on MusicBrainz before searching,
+// adding the canonical title and artist and their aliases as extra search strategies
+type MusicBrainzConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Contact string `yaml:"contact"` // Email address or URL sent in the User-Agent, required by MusicBrainz
+}
+
+// OdesliConfig holds the optional Odesli (song.link) API key used by -crosscheck;
+// without one, checks are limited to 10 per minute
+type OdesliConfig struct {
+	APIKey     string   `yaml:"api_key"`
+	APIKeyFile string   `yaml:"api_key_file"`
+	APIKeyCmd  []string `yaml:"api_key_cmd"`
+}
+
+// AcoustIDConfig enables verifying low-confidence matches by their audio fingerprint.
+// The source ISRC is resolved through MusicBrainz, so musicbrainz.contact is needed too.
+type AcoustIDConfig struct {
+	Enabled       bool     `yaml:"enabled"`
+	APIKey        string   `yaml:"api_key"`        // AcoustID application key
+	SampleCommand []string `yaml:"sample_command"` // Downloads audio into {dir} from {url}; default uses yt-dlp
+	FpcalcPath    string   `yaml:"fpcalc_path"`    // Chromaprint's fpcalc (default: fpcalc on the PATH)
+
+	APIKeyFile string   `yaml:"api_key_file"`
+	APIKeyCmd  []string `yaml:"api_key_cmd"`
+}
+
+// CourtesyConfig shares one Google Cloud project's daily quota between friends through a
+// ledger every run reserves its budget in; courtesy mode is on when Ledger is set
+type CourtesyConfig struct {
+	Ledger     string `yaml:"ledger"`      // JSON file on a shared drive, or http(s) URL accepting GET and PUT
+	User       string `yaml:"user"`        // Name recorded in the ledger (default: the login name)
+	DailyLimit int    `yaml:"daily_limit"` // Quota of the project (default: quota.daily_limit)
+	UserLimit  int    `yaml:"user_limit"`  // Most one user may spend per day (default: no cap)
+}
+
+// QuotaConfig sets the YouTube Data API quota figures used for accounting, forecasts and
+// warnings, for projects with a raised quota or different costs; zero values keep the defaults
+type QuotaConfig struct {
+	DailyLimit    int `yaml:"daily_limit"`    // Units per day of the project (default 10000)
+	SearchCost    int `yaml:"search_cost"`    // Units per search (default 100)
+	InsertCost    int `yaml:"insert_cost"`    // Units per playlist insert (default 50)
+	SessionBudget int `yaml:"session_budget"` // Most units one session may spend (default: no cap)
+}
+
+// Costs returns the configured quota figures with unset fields replaced by the defaults
+func (q QuotaConfig) Costs() quota.Costs {
+	costs := quota.DefaultCosts
+	if q.DailyLimit > 0 {
+		costs.DailyLimit = q.DailyLimit
+	}
+	if q.SearchCost > 0 {
+		costs.Search = q.SearchCost
+	}
+	if q.InsertCost > 0 {
+		costs.Insert = q.InsertCost
+	}
+	return costs
+}
+
+// Completion hook actions
+const (
+	HookSetPublic   = "set_public"
+	HookSetUnlisted = "set_unlisted"
+	HookSetPrivate  = "set_private"
+	HookWebhook     = "webhook"
+	HookReport      = "report"
+	HookCommand     = "command"
+)
+
+// CompletionHook is an action run once when a playlist has been ported completely.
+// Path and Command accept the placeholders {id}, {name}, {url} and {source}.
+type CompletionHook struct {
+	Action  string   `yaml:"action"`  // One of the Hook* actions
+	URL     string   `yaml:"url"`     // webhook: endpoint receiving a JSON summary
+	Path    string   `yaml:"path"`    // report: export file, the format follows the extension
+	Command []string `yaml:"command"` // command: program and arguments
+}
+
+// SplitRule routes matched tracks into a separate YouTube playlist (split mode).
+// All conditions set on a rule must hold; the first matching rule wins.
+type SplitRule struct {
+	Name      string `yaml:"name"`
+	Artist    string `yaml:"artist"`    // Case-insensitive substring of the track artist
+	Decade    int    `yaml:"decade"`    // e.g. 1980 for releases from 1980 to 1989
+	Explicit  *bool  `yaml:"explicit"`  // Match only explicit (true) or clean (false) tracks
+	Positions string `yaml:"positions"` // Source playlist positions, e.g. "1-100"
+}
+
+// PositionRange parses the rule's positions into an inclusive 1-based range
+func (r SplitRule) PositionRange() (int, int, error) {
+	var from, to int
+	if _, err := fmt.Sscanf(r.Positions, "%d-%d", &from, &to); err != nil {
+		return 0, 0, fmt.Errorf("positions must look like 1-100: %w", err)
+	}
+	if from < 1 || to < from {
+		return 0, 0, fmt.Errorf("invalid positions range %s", r.Positions)
+	}
+	return from, to, nil
 }
 
 // Load reads configuration from file
@@ -41,6 +270,32 @@ func Load(path string) (*Config, error) {
 		return nil, fmt.Errorf("parsing config file: %w", err)
 	}
 
+	cfg.applyEnv()
+
+	if err := cfg.resolveSecrets(); err != nil {
+		return nil, fmt.Errorf("reading secrets: %w", err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return &cfg, nil
+}
+
+// LoadFromEnv builds the configuration entirely from PLAYLISTPORTER_* environment variables
+func LoadFromEnv() (*Config, error) {
+	cfg := Config{
+		TUBO: TUBOConfig{
+			RedirectURI: "http://localhost:8080/callback",
+			Scopes:      []string{"https://www.googleapis.com/auth/youtube"},
+		},
+	}
+
+	cfg.applyEnv()
+
+	if err := cfg.resolveSecrets(); err != nil {
+		return nil, fmt.Errorf("reading secrets: %w", err)
+	}
 	if err := cfg.validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
@@ -48,19 +303,188 @@ func Load(path string) (*Config, error) {
 	return &cfg, nil
 }
 
+// applyEnv overrides configuration values with PLAYLISTPORTER_* environment variables
+func (c *Config) applyEnv() {
+	c.setFromEnv(&c.SPT.ClientID, "spt.client_id", "PLAYLISTPORTER_SPT_CLIENT_ID")
+	c.setFromEnv(&c.SPT.ClientSecret, "spt.client_secret", "PLAYLISTPORTER_SPT_CLIENT_SECRET")
+	c.setFromEnv(&c.SPT.RedirectURI, "spt.redirect_uri", "PLAYLISTPORTER_SPT_REDIRECT_URI")
+	c.setFromEnv(&c.TUBO.ClientID, "tubo.client_id", "PLAYLISTPORTER_TUBO_CLIENT_ID")
+	c.setFromEnv(&c.TUBO.ClientSecret, "tubo.client_secret", "PLAYLISTPORTER_TUBO_CLIENT_SECRET")
+	c.setFromEnv(&c.TUBO.RedirectURI, "tubo.redirect_uri", "PLAYLISTPORTER_TUBO_REDIRECT_URI")
+	c.setFromEnv(&c.TUBO.CallbackPort, "tubo.callback_port", "PLAYLISTPORTER_TUBO_CALLBACK_PORT")
+	c.setFromEnv(&c.Tidal.ClientID, "tidal.client_id", "PLAYLISTPORTER_TIDAL_CLIENT_ID")
+	c.setFromEnv(&c.Tidal.ClientSecret, "tidal.client_secret", "PLAYLISTPORTER_TIDAL_CLIENT_SECRET")
+	c.setFromEnv(&c.SoundCloud.ClientID, "soundcloud.client_id", "PLAYLISTPORTER_SOUNDCLOUD_CLIENT_ID")
+	c.setFromEnv(&c.SoundCloud.ClientSecret, "soundcloud.client_secret", "PLAYLISTPORTER_SOUNDCLOUD_CLIENT_SECRET")
+	c.setFromEnv(&c.Jellyfin.ServerURL, "jellyfin.server_url", "PLAYLISTPORTER_JELLYFIN_SERVER_URL")
+	c.setFromEnv(&c.Jellyfin.APIKey, "jellyfin.api_key", "PLAYLISTPORTER_JELLYFIN_API_KEY")
+	c.setFromEnv(&c.Jellyfin.User, "jellyfin.user", "PLAYLISTPORTER_JELLYFIN_USER")
+	c.setFromEnv(&c.MusicFolder.Path, "music_folder.path", "PLAYLISTPORTER_MUSIC_FOLDER")
+	c.setFromEnv(&c.Courtesy.Ledger, "courtesy.ledger", "PLAYLISTPORTER_COURTESY_LEDGER")
+	c.setFromEnv(&c.Courtesy.User, "courtesy.user", "PLAYLISTPORTER_COURTESY_USER")
+
+	c.setFromEnv(&c.TUBO.SearchBackend, "tubo.search_backend", "PLAYLISTPORTER_TUBO_SEARCH_BACKEND")
+	c.setFromEnv(&c.TUBO.Account, "tubo.account", "PLAYLISTPORTER_TUBO_ACCOUNT")
+	c.setFromEnv(&c.MusicBrainz.Contact, "musicbrainz.contact", "PLAYLISTPORTER_MUSICBRAINZ_CONTACT")
+	c.setFromEnv(&c.Odesli.APIKey, "odesli.api_key", "PLAYLISTPORTER_ODESLI_API_KEY")
+	c.setFromEnv(&c.AcoustID.APIKey, "acoustid.api_key", "PLAYLISTPORTER_ACOUSTID_API_KEY")
+
+	if userAuth := os.Getenv("PLAYLISTPORTER_SPT_USER_AUTH"); userAuth != "" {
+		c.SPT.UserAuth = userAuth == "true" || userAuth == "1"
+		c.recordEnv("spt.user_auth", "PLAYLISTPORTER_SPT_USER_AUTH")
+	}
+
+	if deviceCode := os.Getenv("PLAYLISTPORTER_TUBO_DEVICE_CODE"); deviceCode != "" {
+		c.TUBO.DeviceCode = deviceCode == "true" || deviceCode == "1"
+		c.recordEnv("tubo.device_code", "PLAYLISTPORTER_TUBO_DEVICE_CODE")
+	}
+
+	if scopes := os.Getenv("PLAYLISTPORTER_TUBO_SCOPES"); scopes != "" {
+		c.TUBO.Scopes = strings.Fields(strings.ReplaceAll(scopes, ",", " "))
+		c.recordEnv("tubo.scopes", "PLAYLISTPORTER_TUBO_SCOPES")
+	}
+
+	// Secret files, e.g. Docker secrets: PLAYLISTPORTER_SPT_CLIENT_SECRET_FILE
+	for _, secret := range c.secrets() {
+		c.setFromEnv(secret.file, secret.key+"_file", secret.env+"_FILE")
+	}
+}
+
+// EnvOverrides returns the config keys set from the environment, e.g. "tubo.account", with
+// the variable that set each
+func (c *Config) EnvOverrides() map[string]string {
+	return c.fromEnv
+}
+
+// recordEnv notes that an environment variable set a config key
+func (c *Config) recordEnv(key, env string) {
+	if c.fromEnv == nil {
+		c.fromEnv = make(map[string]string)
+	}
+	c.fromEnv[key] = env
+}
+
+// accountPattern limits account names to characters that are safe in token file names
+var accountPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// CheckAccountName validates a YouTube account name; empty selects the default account
+func CheckAccountName(name string) error {
+	if name != "" && !accountPattern.MatchString(name) {
+		return fmt.Errorf("account name %q may only contain letters, digits, - and _", name)
+	}
+	return nil
+}
+
+// setFromEnv sets target, the config key, to the value of an environment variable when it is
+// non-empty
+func (c *Config) setFromEnv(target *string, key, env string) {
+	if value := os.Getenv(env); value != "" {
+		*target = value
+		c.recordEnv(key, env)
+	}
+}
+
 // validate checks if configuration is valid
 func (c *Config) validate() error {
 	if c.SPT.ClientID == "" {
 		return fmt.Errorf("spt.client_id is required")
 	}
-	if c.SPT.ClientSecret == "" {
-		return fmt.Errorf("spt.client_secret is required")
+	if c.SPT.ClientSecret == "" && !c.SPT.UserAuth {
+		return fmt.Errorf("spt.client_secret (or client_secret_file or client_secret_cmd) is required")
 	}
 	if c.TUBO.ClientID == "" {
 		return fmt.Errorf("tubo.client_id is required")
 	}
 	if c.TUBO.ClientSecret == "" {
-		return fmt.Errorf("tubo.client_secret is required")
+		return fmt.Errorf("tubo.client_secret (or client_secret_file or client_secret_cmd) is required")
+	}
+
+	if p := c.TUBO.Popularity; p != nil {
+		if p.PopularMinScore < 0 || p.PopularMinScore > 1 || p.ObscureMinScore < 0 || p.ObscureMinScore > 1 {
+			return fmt.Errorf("tubo.popularity_policy scores must be between 0 and 1")
+		}
+		if p.PopularAbove > 0 && p.ObscureBelow > p.PopularAbove {
+			return fmt.Errorf("tubo.popularity_policy.obscure_below must not exceed popular_above")
+		}
+	}
+
+	switch c.TUBO.SearchBackend {
+	case "", SearchDataAPI, SearchYTMusic:
+	default:
+		return fmt.Errorf("tubo.search_backend must be %q or %q", SearchDataAPI, SearchYTMusic)
+	}
+
+	if err := CheckAccountName(c.TUBO.Account); err != nil {
+		return fmt.Errorf("tubo.account: %w", err)
+	}
+
+	if port := c.TUBO.CallbackPort; port != "" && port != "auto" {
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			return fmt.Errorf("tubo.callback_port must be a port number or \"auto\", got %q", port)
+		}
+	}
+
+	if q := c.Quota; q.DailyLimit < 0 || q.SearchCost < 0 || q.InsertCost < 0 || q.SessionBudget < 0 {
+		return fmt.Errorf("quota values must not be negative")
+	}
+
+	if err := c.Matching.validate(); err != nil {
+		return err
+	}
+
+	if err := c.validatePlaylists(); err != nil {
+		return err
+	}
+
+	if c.MusicBrainz.Enabled && c.MusicBrainz.Contact == "" {
+		return fmt.Errorf("musicbrainz.contact is required when musicbrainz.enabled is set")
+	}
+	for i, hook := range c.OnComplete {
+		if err := hook.validate(); err != nil {
+			return fmt.Errorf("on_complete[%d]: %w", i, err)
+		}
+	}
+	if c.AcoustID.Enabled && c.MusicBrainz.Contact == "" {
+		return fmt.Errorf("musicbrainz.contact is required when acoustid.enabled is set (ISRCs are resolved through MusicBrainz)")
+	}
+
+	names := make(map[string]bool)
+	for i, rule := range c.Split {
+		if rule.Name == "" {
+			return fmt.Errorf("split[%d].name is required", i)
+		}
+		if names[rule.Name] {
+			return fmt.Errorf("split rule name %q is used twice", rule.Name)
+		}
+		names[rule.Name] = true
+
+		if rule.Positions != "" {
+			if _, _, err := rule.PositionRange(); err != nil {
+				return fmt.Errorf("split[%d]: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validate checks that a hook names a known action and has what the action needs
+func (h CompletionHook) validate() error {
+	switch h.Action {
+	case HookSetPublic, HookSetUnlisted, HookSetPrivate:
+	case HookWebhook:
+		if h.URL == "" {
+			return fmt.Errorf("webhook needs a url")
+		}
+	case HookReport:
+		if h.Path == "" {
+			return fmt.Errorf("report needs a path")
+		}
+	case HookCommand:
+		if len(h.Command) == 0 {
+			return fmt.Errorf("command needs a command")
+		}
+	default:
+		return fmt.Errorf("unknown action %q (use set_public, set_unlisted, set_private, webhook, report or command)", h.Action)
 	}
 	return nil
 }
@@ -0,0 +1,99 @@
+package config
+
+import "fmt"
+
+// MatchingConfig tunes how search candidates are scored against the source track, trading
+// accuracy for recall. Unset fields keep the built-in values; see MatchingParams.
+type MatchingConfig struct {
+	MinScore     float64 `yaml:"min_score"`     // Lowest score accepted as a match (default 0.5)
+	GoodScore    float64 `yaml:"good_score"`    // Score that stops trying further searches (default 0.75)
+	TitleWeight  float64 `yaml:"title_weight"`  // Share of the title similarity in the score (default 0.7)
+	ArtistWeight float64 `yaml:"artist_weight"` // Share of the artist similarity (default 0.3)
+
+	// Bonus and penalties added to the Data API score; a pointer so 0 can turn one off
+	OfficialBonus *float64 `yaml:"official_bonus"` // Official videos and channels (default 0.05)
+	CoverPenalty  *float64 `yaml:"cover_penalty"`  // Covers of a track that isn't one (default 0.10)
+	LivePenalty   *float64 `yaml:"live_penalty"`   // Live versions of a studio track (default 0.10)
+	RemixPenalty  *float64 `yaml:"remix_penalty"`  // Remixes of a track that isn't one (default 0.10)
+}
+
+// MatchingParams are the effective matching parameters
+type MatchingParams struct {
+	MinScore      float64
+	GoodScore     float64
+	TitleWeight   float64
+	ArtistWeight  float64
+	OfficialBonus float64
+	CoverPenalty  float64
+	LivePenalty   float64
+	RemixPenalty  float64
+}
+
+// DefaultMatching are the parameters PlaylistPorter matches with unless configured otherwise
+var DefaultMatching = MatchingParams{
+	MinScore:      0.5,
+	GoodScore:     0.75,
+	TitleWeight:   0.7,
+	ArtistWeight:  0.3,
+	OfficialBonus: 0.05,
+	CoverPenalty:  0.10,
+	LivePenalty:   0.10,
+	RemixPenalty:  0.10,
+}
+
+// Params returns the configured parameters with unset fields replaced by the defaults
+func (m MatchingConfig) Params() MatchingParams {
+	params := DefaultMatching
+	if m.MinScore != 0 {
+		params.MinScore = m.MinScore
+	}
+	if m.GoodScore != 0 {
+		params.GoodScore = m.GoodScore
+	}
+	if m.TitleWeight != 0 || m.ArtistWeight != 0 {
+		params.TitleWeight = m.TitleWeight
+		params.ArtistWeight = m.ArtistWeight
+	}
+	if m.OfficialBonus != nil {
+		params.OfficialBonus = *m.OfficialBonus
+	}
+	if m.CoverPenalty != nil {
+		params.CoverPenalty = *m.CoverPenalty
+	}
+	if m.LivePenalty != nil {
+		params.LivePenalty = *m.LivePenalty
+	}
+	if m.RemixPenalty != nil {
+		params.RemixPenalty = *m.RemixPenalty
+	}
+	return params
+}
+
+// validate checks that the parameters stay within the score range
+func (m MatchingConfig) validate() error {
+	params := m.Params()
+	for _, field := range []struct {
+		name  string
+		value float64
+	}{
+		{"min_score", params.MinScore},
+		{"good_score", params.GoodScore},
+		{"title_weight", params.TitleWeight},
+		{"artist_weight", params.ArtistWeight},
+		{"official_bonus", params.OfficialBonus},
+		{"cover_penalty", params.CoverPenalty},
+		{"live_penalty", params.LivePenalty},
+		{"remix_penalty", params.RemixPenalty},
+	} {
+		if field.value < 0 || field.value > 1 {
+			return fmt.Errorf("matching.%s must be between 0 and 1, got %g", field.name, field.value)
+		}
+	}
+	if sum := params.TitleWeight + params.ArtistWeight; sum < 0.99 || sum > 1.01 {
+		return fmt.Errorf("matching.title_weight and artist_weight must add up to 1, got %g", sum)
+	}
+	if params.GoodScore < params.MinScore {
+		return fmt.Errorf("matching.good_score (%g) must not be below min_score (%g)", params.GoodScore, params.MinScore)
+	}
+	return nil
+}
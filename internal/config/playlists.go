@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PlaylistEntry gives a playlist a friendly name, e.g. "workout", for
+// `playlistporter port workout` and `playlistporter sync -group favorites`
+type PlaylistEntry struct {
+	URL    string   `yaml:"url"`    // Playlist link or playlist file, as for -url or -file
+	Groups []string `yaml:"groups"` // Groups the playlist belongs to, e.g. ["favorites"]
+
+	// Option values for this playlist, overriding the config's defaults section,
+	// e.g. max_tracks: 20 or mode: snapshot
+	Defaults map[string]string `yaml:"defaults"`
+}
+
+// Playlist returns the named playlist entry
+func (c *Config) Playlist(name string) (PlaylistEntry, error) {
+	entry, ok := c.Playlists[name]
+	if !ok {
+		if len(c.Playlists) == 0 {
+			return PlaylistEntry{}, fmt.Errorf("no playlist is named %q; name playlists in the playlists section of the config", name)
+		}
+		return PlaylistEntry{}, fmt.Errorf("no playlist is named %q (known: %s)", name, strings.Join(c.PlaylistNames(""), ", "))
+	}
+	return entry, nil
+}
+
+// PlaylistNames returns the names of the playlists in a group, or of all playlists when
+// group is empty, in alphabetical order
+func (c *Config) PlaylistNames(group string) []string {
+	var names []string
+	for name, entry := range c.Playlists {
+		if group == "" || entry.InGroup(group) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// InGroup reports whether the playlist belongs to a group; group names ignore case
+func (e PlaylistEntry) InGroup(group string) bool {
+	for _, g := range e.Groups {
+		if strings.EqualFold(g, group) {
+			return true
+		}
+	}
+	return false
+}
+
+// validatePlaylists checks that every named playlist has a usable name and a URL
+func (c *Config) validatePlaylists() error {
+	for _, name := range c.PlaylistNames("") {
+		entry := c.Playlists[name]
+		if !accountPattern.MatchString(name) {
+			return fmt.Errorf("playlists: name %q may only contain letters, digits, - and _", name)
+		}
+		if strings.TrimSpace(entry.URL) == "" {
+			return fmt.Errorf("playlists.%s.url is required", name)
+		}
+		for _, group := range entry.Groups {
+			if !accountPattern.MatchString(group) {
+				return fmt.Errorf("playlists.%s: group %q may only contain letters, digits, - and _", name, group)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// secretTimeout bounds a secret command, leaving time to unlock a password manager
+const secretTimeout = 2 * time.Minute
+
+// secret is a config value that may also be read from a file (<key>_file) or from what a
+// command prints (<key>_cmd), so it doesn't have to be written into the YAML
+type secret struct {
+	key   string // Config key, e.g. "spt.client_secret"
+	env   string // Environment variable of the value, e.g. PLAYLISTPORTER_SPT_CLIENT_SECRET
+	value *string
+	file  *string
+	cmd   *[]string
+}
+
+// secrets lists the config values that may come from a file or a command
+func (c *Config) secrets() []secret {
+	return []secret{
+		{"spt.client_secret", "PLAYLISTPORTER_SPT_CLIENT_SECRET", &c.SPT.ClientSecret, &c.SPT.ClientSecretFile, &c.SPT.ClientSecretCmd},
+		{"tubo.client_secret", "PLAYLISTPORTER_TUBO_CLIENT_SECRET", &c.TUBO.ClientSecret, &c.TUBO.ClientSecretFile, &c.TUBO.ClientSecretCmd},
+		{"tidal.client_secret", "PLAYLISTPORTER_TIDAL_CLIENT_SECRET", &c.Tidal.ClientSecret, &c.Tidal.ClientSecretFile, &c.Tidal.ClientSecretCmd},
+		{"soundcloud.client_secret", "PLAYLISTPORTER_SOUNDCLOUD_CLIENT_SECRET", &c.SoundCloud.ClientSecret, &c.SoundCloud.ClientSecretFile, &c.SoundCloud.ClientSecretCmd},
+		{"jellyfin.api_key", "PLAYLISTPORTER_JELLYFIN_API_KEY", &c.Jellyfin.APIKey, &c.Jellyfin.APIKeyFile, &c.Jellyfin.APIKeyCmd},
+		{"odesli.api_key", "PLAYLISTPORTER_ODESLI_API_KEY", &c.Odesli.APIKey, &c.Odesli.APIKeyFile, &c.Odesli.APIKeyCmd},
+		{"acoustid.api_key", "PLAYLISTPORTER_ACOUSTID_API_KEY", &c.AcoustID.APIKey, &c.AcoustID.APIKeyFile, &c.AcoustID.APIKeyCmd},
+	}
+}
+
+// resolveSecrets fills the secrets given as a file or a command. The environment wins over
+// the config file, as for other values; within either, a secret may be set in only one way.
+func (c *Config) resolveSecrets() error {
+	for _, s := range c.secrets() {
+		if _, ok := c.fromEnv[s.key]; ok {
+			continue
+		}
+		if _, ok := c.fromEnv[s.key+"_file"]; ok {
+			// A file named in the environment replaces the value of the config file
+			*s.value, *s.cmd = "", nil
+		}
+		file, cmd := *s.file, *s.cmd
+		if file == "" && len(cmd) == 0 {
+			continue
+		}
+		if *s.value != "" || (file != "" && len(cmd) > 0) {
+			return fmt.Errorf("set only one of %s, %s_file and %s_cmd", s.key, s.key, s.key)
+		}
+
+		var (
+			value string
+			from  string
+			err   error
+		)
+		if file != "" {
+			value, err = readSecretFile(file)
+			from = "file " + file
+		} else {
+			value, err = runSecretCommand(cmd)
+			from = "command " + cmd[0]
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", s.key, err)
+		}
+		if value == "" {
+			return fmt.Errorf("%s: %s gave an empty value", s.key, from)
+		}
+		*s.value = value
+
+		if c.secretFrom == nil {
+			c.secretFrom = make(map[string]string)
+		}
+		c.secretFrom[s.key] = from
+	}
+	return nil
+}
+
+// SecretSources returns the secrets read from a file or a command, e.g. "tubo.client_secret",
+// with the file or command each came from
+func (c *Config) SecretSources() map[string]string {
+	return c.secretFrom
+}
+
+// readSecretFile reads a secret from a file; a leading ~/ means the home directory
+func readSecretFile(path string) (string, error) {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading secret file: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// runSecretCommand runs a program such as `pass show spotify/client-secret` or `op read ...`
+// and returns the first line it prints. The terminal stays attached to its input and error
+// output, so it can ask for a passphrase.
+func runSecretCommand(command []string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), secretTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return "", fmt.Errorf("%s gave no secret within %s", command[0], secretTimeout)
+	}
+	if err != nil {
+		return "", fmt.Errorf("running %s: %w", command[0], err)
+	}
+	first, _, _ := strings.Cut(string(output), "\n")
+	return strings.TrimSpace(first), nil
+}
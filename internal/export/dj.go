@@ -0,0 +1,180 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+)
+
+// ErrNoLocalFiles is returned for Serato crates of playlists without local file matches
+var ErrNoLocalFiles = errors.New("Serato crates can only list files on disk; port the playlist with -dest folder first")
+
+// localPath returns the file behind a match link, "" for streaming links
+func localPath(matchURL string) string {
+	if strings.HasPrefix(matchURL, "file://") {
+		if u, err := url.Parse(matchURL); err == nil {
+			return filepath.FromSlash(u.Path)
+		}
+	}
+	if filepath.IsAbs(matchURL) {
+		return matchURL
+	}
+	return ""
+}
+
+type rekordboxDoc struct {
+	XMLName    xml.Name         `xml:"DJ_PLAYLISTS"`
+	Version    string           `xml:"Version,attr"`
+	Product    rekordboxProduct `xml:"PRODUCT"`
+	Collection struct {
+		Entries int              `xml:"Entries,attr"`
+		Tracks  []rekordboxTrack `xml:"TRACK"`
+	} `xml:"COLLECTION"`
+	Root rekordboxNode `xml:"PLAYLISTS>NODE"`
+}
+
+type rekordboxProduct struct {
+	Name    string `xml:"Name,attr"`
+	Version string `xml:"Version,attr"`
+	Company string `xml:"Company,attr"`
+}
+
+type rekordboxTrack struct {
+	TrackID   int    `xml:"TrackID,attr"`
+	Name      string `xml:"Name,attr"`
+	Artist    string `xml:"Artist,attr"`
+	Album     string `xml:"Album,attr,omitempty"`
+	TotalTime int    `xml:"TotalTime,attr,omitempty"` // Seconds
+	Year      int    `xml:"Year,attr,omitempty"`
+	Comments  string `xml:"Comments,attr,omitempty"`
+	Location  string `xml:"Location,attr,omitempty"`
+}
+
+type rekordboxNode struct {
+	Type    int              `xml:"Type,attr"` // 0 folder, 1 playlist
+	Name    string           `xml:"Name,attr"`
+	Count   int              `xml:"Count,attr,omitempty"`
+	KeyType *int             `xml:"KeyType,attr"`
+	Entries *int             `xml:"Entries,attr"`
+	Nodes   []rekordboxNode  `xml:"NODE"`
+	Tracks  []rekordboxEntry `xml:"TRACK"`
+}
+
+type rekordboxEntry struct {
+	Key int `xml:"Key,attr"`
+}
+
+// writeRekordbox writes a rekordbox collection XML (File > Import Collection) holding the
+// matched tracks and one playlist of them. Files on disk get a Location rekordbox can load;
+// streaming matches carry their link in the comments.
+func writeRekordbox(w io.Writer, playlist *Playlist) error {
+	doc := rekordboxDoc{
+		Version: "1.0.0",
+		Product: rekordboxProduct{Name: "PlaylistPorter", Version: "1.0", Company: "PlaylistPorter"},
+	}
+
+	keyType := 0
+	list := rekordboxNode{Type: 1, Name: playlist.Name, KeyType: &keyType}
+	for _, entry := range playlist.Entries {
+		if !entry.Matched {
+			continue
+		}
+		id := len(doc.Collection.Tracks) + 1
+		track := rekordboxTrack{
+			TrackID:   id,
+			Name:      entry.Title,
+			Artist:    entry.Artist,
+			Album:     entry.Album,
+			TotalTime: entry.Duration,
+			Year:      entry.Year,
+		}
+		if path := localPath(entry.MatchURL); path != "" {
+			location := url.URL{Scheme: "file", Host: "localhost", Path: filepath.ToSlash(path)}
+			if !strings.HasPrefix(location.Path, "/") {
+				location.Path = "/" + location.Path // Windows drive letters
+			}
+			track.Location = location.String()
+		} else {
+			track.Comments = entry.MatchURL
+		}
+		doc.Collection.Tracks = append(doc.Collection.Tracks, track)
+		list.Tracks = append(list.Tracks, rekordboxEntry{Key: id})
+	}
+	doc.Collection.Entries = len(doc.Collection.Tracks)
+	entries := len(list.Tracks)
+	list.Entries = &entries
+	doc.Root = rekordboxNode{Type: 0, Name: "ROOT", Count: 1, Nodes: []rekordboxNode{list}}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("encoding rekordbox XML: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// seratoColumns are the crate columns shown in Serato, with the length and year
+var seratoColumns = []string{"song", "artist", "album", "length", "year"}
+
+// writeSerato writes a Serato crate (_Serato_/Subcrates/<name>.crate) of the matched files.
+// Crates only list file paths; Serato reads the title, length and year from the files.
+func writeSerato(w io.Writer, playlist *Playlist) error {
+	var b bytes.Buffer
+	writeSeratoField(&b, "vrsn", seratoText("1.0/Serato ScratchLive Crate"))
+	for _, column := range seratoColumns {
+		var def bytes.Buffer
+		writeSeratoField(&def, "tvcn", seratoText(column))
+		writeSeratoField(&def, "tvcw", seratoText("0"))
+		writeSeratoField(&b, "ovct", def.Bytes())
+	}
+
+	tracks := 0
+	for _, entry := range playlist.Entries {
+		path := localPath(entry.MatchURL)
+		if !entry.Matched || path == "" {
+			continue
+		}
+		// Paths are relative to the root of the drive the crate belongs to
+		path = strings.TrimPrefix(path, filepath.VolumeName(path))
+		path = strings.TrimLeft(filepath.ToSlash(path), "/")
+
+		var track bytes.Buffer
+		writeSeratoField(&track, "ptrk", seratoText(path))
+		writeSeratoField(&b, "otrk", track.Bytes())
+		tracks++
+	}
+	if tracks == 0 {
+		return ErrNoLocalFiles
+	}
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// writeSeratoField appends a tag, its big-endian length and its payload
+func writeSeratoField(b *bytes.Buffer, tag string, payload []byte) {
+	b.WriteString(tag)
+	binary.Write(b, binary.BigEndian, uint32(len(payload)))
+	b.Write(payload)
+}
+
+// seratoText encodes a string as UTF-16BE
+func seratoText(s string) []byte {
+	units := utf16.Encode([]rune(s))
+	out := make([]byte, 2*len(units))
+	for i, unit := range units {
+		binary.BigEndian.PutUint16(out[2*i:], unit)
+	}
+	return out
+}
@@ -0,0 +1,245 @@
+// Package export writes ported playlists to standard playlist formats so other players and tools can read them
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Supported formats
+const (
+	FormatM3U  = "m3u"
+	FormatXSPF = "xspf"
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+	FormatHTML = "html"
+
+	FormatRekordbox = "rekordbox" // Collection XML for rekordbox
+	FormatSerato    = "serato"    // Serato crate, local files only
+)
+
+// Formats lists the supported formats in the order shown in help texts
+var Formats = []string{FormatM3U, FormatXSPF, FormatCSV, FormatJSON, FormatHTML, FormatRekordbox, FormatSerato}
+
+// extensions are the file extensions of formats not named after theirs
+var extensions = map[string]string{FormatRekordbox: ".xml", FormatSerato: ".crate"}
+
+// Entry is one track of the exported playlist: the source metadata and its match
+type Entry struct {
+	Position  int    `json:"position"`
+	Title     string `json:"title"`
+	Artist    string `json:"artist"`
+	Album     string `json:"album,omitempty"`
+	Duration  int    `json:"duration_seconds,omitempty"` // Seconds
+	Year      int    `json:"year,omitempty"`
+	ISRC      string `json:"isrc,omitempty"`
+	SourceID  string `json:"source_id"`
+	SourceURL string `json:"source_url,omitempty"` // Link to the track on the source service
+
+	Matched     bool    `json:"matched"`
+	MatchURL    string  `json:"match_url,omitempty"`
+	MatchTitle  string  `json:"match_title,omitempty"`
+	MatchArtist string  `json:"match_artist,omitempty"`
+	Score       float64 `json:"score"`
+	Strategy    string  `json:"strategy,omitempty"`
+	CrossCheck  string  `json:"cross_check,omitempty"`      // Odesli outcome: confirmed, mismatch or unknown
+	OdesliURL   string  `json:"odesli_match_url,omitempty"` // Video Odesli links instead, for mismatches
+	Note        string  `json:"note,omitempty"`             // User annotation
+	AlbumArtURL string  `json:"album_art_url,omitempty"`
+}
+
+// Playlist is the exported playlist
+type Playlist struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	SourceURL   string  `json:"source_url,omitempty"`
+	Destination string  `json:"destination"`
+	PlaylistURL string  `json:"playlist_url,omitempty"`
+	Entries     []Entry `json:"tracks"`
+}
+
+// IsFormat reports whether format is one of the supported formats
+func IsFormat(format string) bool {
+	for _, f := range Formats {
+		if f == strings.ToLower(format) {
+			return true
+		}
+	}
+	return false
+}
+
+// Extension returns the file extension of a format
+func Extension(format string) string {
+	if ext, ok := extensions[format]; ok {
+		return ext
+	}
+	return "." + format
+}
+
+// FormatForExtension returns the format written to files with an extension, "" when none is
+func FormatForExtension(ext string) string {
+	ext = strings.ToLower(ext)
+	for _, format := range Formats {
+		if Extension(format) == ext {
+			return format
+		}
+	}
+	return ""
+}
+
+// Write encodes the playlist in the given format
+func Write(w io.Writer, format string, playlist *Playlist) error {
+	switch strings.ToLower(format) {
+	case FormatM3U:
+		return writeM3U(w, playlist)
+	case FormatXSPF:
+		return writeXSPF(w, playlist)
+	case FormatCSV:
+		return writeCSV(w, playlist)
+	case FormatJSON:
+		return writeJSON(w, playlist)
+	case FormatHTML:
+		return writeHTML(w, playlist)
+	case FormatRekordbox:
+		return writeRekordbox(w, playlist)
+	case FormatSerato:
+		return writeSerato(w, playlist)
+	}
+	return fmt.Errorf("unknown export format %q (use %s)", format, strings.Join(Formats, ", "))
+}
+
+// writeM3U writes an extended M3U; tracks without a match are kept as comments
+func writeM3U(w io.Writer, playlist *Playlist) error {
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n")
+	fmt.Fprintf(&b, "#PLAYLIST:%s\n", playlist.Name)
+	for _, entry := range playlist.Entries {
+		duration := entry.Duration
+		if duration == 0 {
+			duration = -1 // Unknown length
+		}
+		if !entry.Matched {
+			fmt.Fprintf(&b, "# Not matched: %s - %s\n", entry.Artist, entry.Title)
+			continue
+		}
+		fmt.Fprintf(&b, "#EXTINF:%d,%s - %s\n", duration, entry.Artist, entry.Title)
+		if entry.Album != "" {
+			fmt.Fprintf(&b, "#EXTALB:%s\n", entry.Album)
+		}
+		fmt.Fprintf(&b, "%s\n", entry.MatchURL)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type xspfPlaylist struct {
+	XMLName    xml.Name    `xml:"playlist"`
+	Version    string      `xml:"version,attr"`
+	Namespace  string      `xml:"xmlns,attr"`
+	Title      string      `xml:"title"`
+	Annotation string      `xml:"annotation,omitempty"`
+	Info       string      `xml:"info,omitempty"`
+	Tracks     []xspfTrack `xml:"trackList>track"`
+}
+
+type xspfTrack struct {
+	Location   string `xml:"location,omitempty"`
+	Identifier string `xml:"identifier,omitempty"`
+	Title      string `xml:"title"`
+	Creator    string `xml:"creator"`
+	Album      string `xml:"album,omitempty"`
+	TrackNum   int    `xml:"trackNum"`
+	Duration   int64  `xml:"duration,omitempty"` // Milliseconds
+	Image      string `xml:"image,omitempty"`
+	Annotation string `xml:"annotation,omitempty"`
+	Info       string `xml:"info,omitempty"` // Link to the source track
+}
+
+// writeXSPF writes an XSPF playlist; unmatched tracks have no location so players skip them
+func writeXSPF(w io.Writer, playlist *Playlist) error {
+	doc := xspfPlaylist{
+		Version:    "1",
+		Namespace:  "http://xspf.org/ns/0/",
+		Title:      playlist.Name,
+		Annotation: playlist.Description,
+		Info:       playlist.PlaylistURL,
+	}
+	for _, entry := range playlist.Entries {
+		track := xspfTrack{
+			Location: entry.MatchURL,
+			Title:    entry.Title,
+			Creator:  entry.Artist,
+			Album:    entry.Album,
+			TrackNum: entry.Position,
+			Duration: int64(entry.Duration) * 1000,
+			Image:    entry.AlbumArtURL,
+			Info:     entry.SourceURL,
+		}
+		if entry.ISRC != "" {
+			track.Identifier = "isrc:" + entry.ISRC
+		}
+		if entry.Matched {
+			track.Annotation = fmt.Sprintf("Match score %.2f", entry.Score)
+		} else {
+			track.Annotation = "Not matched"
+		}
+		if entry.Note != "" {
+			track.Annotation += "; note: " + entry.Note
+		}
+		doc.Tracks = append(doc.Tracks, track)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("encoding XSPF: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// writeCSV writes one row per track, matched or not
+func writeCSV(w io.Writer, playlist *Playlist) error {
+	writer := csv.NewWriter(w)
+	header := []string{"position", "title", "artist", "album", "duration_seconds", "isrc", "source_id",
+		"source_url", "matched", "match_url", "match_title", "match_artist", "score", "strategy", "cross_check", "odesli_match_url", "note"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, entry := range playlist.Entries {
+		score := ""
+		if entry.Matched {
+			score = strconv.FormatFloat(entry.Score, 'f', 3, 64)
+		}
+		row := []string{
+			strconv.Itoa(entry.Position), entry.Title, entry.Artist, entry.Album,
+			strconv.Itoa(entry.Duration), entry.ISRC, entry.SourceID,
+			entry.SourceURL, strconv.FormatBool(entry.Matched), entry.MatchURL, entry.MatchTitle, entry.MatchArtist,
+			score, entry.Strategy, entry.CrossCheck, entry.OdesliURL, entry.Note,
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeJSON writes the playlist as indented JSON
+func writeJSON(w io.Writer, playlist *Playlist) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(playlist); err != nil {
+		return fmt.Errorf("encoding JSON: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package export
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"score": func(score float64) string { return fmt.Sprintf("%.2f", score) },
+	"low":   func(score float64) bool { return score < ui.LowConfidenceScore },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Name}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { padding: 0.35em 0.6em; border-bottom: 1px solid #ddd; text-align: left; vertical-align: top; }
+tr.failed td { background: #fdecea; }
+tr.low td { background: #fff8e1; }
+tr.mismatch td { background: #ede7f6; }
+td.num { text-align: right; color: #666; }
+.note { color: #666; font-style: italic; }
+</style>
+</head>
+<body>
+<h1>{{.Name}}</h1>
+<p>
+{{- if .SourceURL}}Source: <a href="{{.SourceURL}}">{{.SourceURL}}</a><br>{{end}}
+{{- if .PlaylistURL}}Ported to {{.Destination}}: <a href="{{.PlaylistURL}}">{{.PlaylistURL}}</a>{{end}}
+</p>
+<table>
+<thead><tr><th>#</th><th>Source track</th><th>Match</th><th>Score</th><th>Strategy</th></tr></thead>
+<tbody>
+{{- range .Entries}}
+<tr{{if not .Matched}} class="failed"{{else if eq .CrossCheck "mismatch"}} class="mismatch"{{else if low .Score}} class="low"{{end}}>
+<td class="num">{{.Position}}</td>
+<td>{{if .SourceURL}}<a href="{{.SourceURL}}">{{.Artist}} - {{.Title}}</a>{{else}}{{.Artist}} - {{.Title}}{{end}}
+{{- if .Note}}<br><span class="note">{{.Note}}</span>{{end}}</td>
+<td>{{if .Matched}}<a href="{{.MatchURL}}">{{.MatchArtist}} - {{.MatchTitle}}</a>{{else}}Not matched{{end}}
+{{- if .OdesliURL}}<br><span class="note">Odesli links <a href="{{.OdesliURL}}">another video</a></span>
+{{- else if eq .CrossCheck "confirmed"}}<br><span class="note">Confirmed by Odesli</span>{{end}}</td>
+<td class="num">{{if .Matched}}{{score .Score}}{{end}}</td>
+<td>{{.Strategy}}</td>
+</tr>
+{{- end}}
+</tbody>
+</table>
+</body>
+</html>
+`))
+
+// writeHTML writes a report for checking matches by hand: each source track links to the
+// source service next to its match, and failed, cross-check mismatched and low-confidence
+// matches are highlighted
+func writeHTML(w io.Writer, playlist *Playlist) error {
+	if err := htmlReport.Execute(w, playlist); err != nil {
+		return fmt.Errorf("rendering HTML: %w", err)
+	}
+	return nil
+}
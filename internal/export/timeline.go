@@ -0,0 +1,134 @@
+package export
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+)
+
+// Timeline is the session history of a playlist, replayed as an HTML page
+type Timeline struct {
+	Name       string
+	SourceURL  string
+	Sessions   []TimelineSession
+	MaxPerMin  float64 // Highest tracks/minute of any session, which scales the rate bars
+	TotalQuota int
+}
+
+// TimelineSession is one session on the timeline
+type TimelineSession struct {
+	Start     time.Time
+	Duration  time.Duration
+	Processed int
+	Matched   int
+	Quota     int  // Units of the searches the tracks recorded, or the estimate for older sessions
+	Estimated bool // Quota is the saved rough estimate
+	Note      string
+	RunID     string
+
+	Tracks   []TimelineTrack   // In processing order, empty for sessions saved before run IDs
+	Failures []TimelineFailure // Failures grouped by reason, most frequent first
+}
+
+// TimelineTrack is one track of a session's strip
+type TimelineTrack struct {
+	Label  string // "Artist - Title", shown on hover
+	Status string // "matched", "low" or "failed"
+	Reason string // Why a failed track failed
+}
+
+// TimelineFailure counts the failures of a session with the same reason
+type TimelineFailure struct {
+	Reason string
+	Count  int
+}
+
+// PerMinute returns the tracks processed per minute of the session
+func (s TimelineSession) PerMinute() float64 {
+	if s.Duration < time.Second {
+		return 0
+	}
+	return float64(s.Processed) / s.Duration.Minutes()
+}
+
+// Failed returns how many tracks of the session didn't match
+func (s TimelineSession) Failed() int {
+	return s.Processed - s.Matched
+}
+
+var timelineReport = template.Must(template.New("timeline").Funcs(template.FuncMap{
+	"when":  func(t time.Time) string { return t.Format("2006-01-02 15:04") },
+	"since": func(d time.Duration) string { return d.Round(time.Second).String() },
+	"rate":  func(rate float64) string { return fmt.Sprintf("%.1f", rate) },
+	"width": func(rate, max float64) string {
+		if max <= 0 {
+			return "0"
+		}
+		return fmt.Sprintf("%.0f", rate/max*100)
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Name}}: sessions</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em; }
+.session { border-bottom: 1px solid #ddd; padding: 0.8em 0; }
+.head { display: flex; gap: 1.5em; flex-wrap: wrap; align-items: baseline; }
+.head b { min-width: 10em; }
+.muted { color: #666; }
+.rate { background: #eee; height: 0.6em; width: 20em; margin: 0.4em 0; }
+.rate div { background: #42a5f5; height: 100%; }
+.strip { display: flex; flex-wrap: wrap; gap: 2px; margin: 0.4em 0; }
+.strip span { width: 0.7em; height: 1.2em; display: inline-block; }
+.matched { background: #66bb6a; }
+.low { background: #ffca28; }
+.failed { background: #ef5350; }
+ul { margin: 0.2em 0; }
+</style>
+</head>
+<body>
+<h1>{{.Name}}</h1>
+<p class="muted">
+{{- if .SourceURL}}Source: <a href="{{.SourceURL}}">{{.SourceURL}}</a><br>{{end}}
+{{len .Sessions}} sessions, ~{{.TotalQuota}} quota units.
+Each square is a track in the order it was processed: <span class="matched">&nbsp;&nbsp;</span> matched,
+<span class="low">&nbsp;&nbsp;</span> low confidence, <span class="failed">&nbsp;&nbsp;</span> failed.
+</p>
+{{- range .Sessions}}
+<div class="session">
+<div class="head">
+<b>{{when .Start}}</b>
+<span>{{since .Duration}}</span>
+<span>{{.Processed}} tracks, {{.Matched}} matched, {{.Failed}} failed</span>
+<span>{{rate .PerMinute}} tracks/min</span>
+<span>~{{.Quota}} units{{if .Estimated}} (estimate){{end}}</span>
+{{- if .RunID}}<span class="muted">run {{.RunID}}</span>{{end}}
+</div>
+{{- if .Note}}<div class="muted">{{.Note}}</div>{{end}}
+<div class="rate"><div style="width: {{width .PerMinute $.MaxPerMin}}%"></div></div>
+{{- if .Tracks}}
+<div class="strip">
+{{- range .Tracks}}<span class="{{.Status}}" title="{{.Label}}{{if .Reason}}: {{.Reason}}{{end}}"></span>{{end}}
+</div>
+{{- end}}
+{{- if .Failures}}
+<ul>
+{{- range .Failures}}<li>{{.Count}} × {{.Reason}}</li>{{end}}
+</ul>
+{{- end}}
+</div>
+{{- end}}
+</body>
+</html>
+`))
+
+// WriteTimeline writes the session history of a playlist as an HTML page: when each session
+// ran, how fast it went, what it spent, and where in the session its failures fell
+func WriteTimeline(w io.Writer, timeline *Timeline) error {
+	if err := timelineReport.Execute(w, timeline); err != nil {
+		return fmt.Errorf("rendering timeline: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+// Package jellyfin builds ported playlists from the music library of a Jellyfin server
+package jellyfin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/config"
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/processor"
+	"github.com/Verryx-02/PlaylistPorter/internal/traffic"
+)
+
+const (
+	requestTimeout = 30 * time.Second
+
+	searchLimit  = 25
+	minScore     = 0.5  // Lowest score accepted as a match
+	goodScore    = 0.85 // Score after which no further query is tried
+	durationSlop = 5.0  // Seconds of difference still counted as the same recording
+
+	ticksPerSecond = 10_000_000 // Jellyfin durations are in 100 ns ticks
+)
+
+// Client represents a Jellyfin API client acting for one user of the server
+type Client struct {
+	config     *config.JellyfinConfig
+	serverURL  string
+	httpClient *http.Client
+	processor  *processor.Processor
+	userID     string
+}
+
+// NewClient connects to the Jellyfin server and looks up the user whose library is searched
+func NewClient(cfg *config.JellyfinConfig) (*Client, error) {
+	if cfg.ServerURL == "" || cfg.APIKey == "" || cfg.User == "" {
+		return nil, fmt.Errorf("jellyfin.server_url, jellyfin.api_key and jellyfin.user are required for -dest jellyfin")
+	}
+
+	client := &Client{
+		config:     cfg,
+		serverURL:  strings.TrimRight(cfg.ServerURL, "/"),
+		httpClient: traffic.NewClient(requestTimeout),
+		processor:  processor.New(),
+	}
+
+	userID, err := client.findUser(cfg.User)
+	if err != nil {
+		return nil, err
+	}
+	client.userID = userID
+	return client, nil
+}
+
+// findUser resolves a user name to its ID; API keys act for the whole server
+func (c *Client) findUser(name string) (string, error) {
+	var users []jellyfinUser
+	if err := c.makeRequest("GET", "/Users", nil, &users); err != nil {
+		return "", fmt.Errorf("listing Jellyfin users: %w", err)
+	}
+	for _, user := range users {
+		if strings.EqualFold(user.Name, name) {
+			return user.ID, nil
+		}
+	}
+	return "", fmt.Errorf("Jellyfin user %q not found on %s", name, c.serverURL)
+}
+
+// SearchTrackOutcome looks for a track in the user's music library. Jellyfin searches names
+// only, so the title is searched and the results are scored by artist and duration too.
+// SearchesUsed stays 0 because no YouTube quota is spent.
+func (c *Client) SearchTrackOutcome(track models.Track) (*models.SearchOutcome, error) {
+	original := track
+	c.processor.NormalizeTrack(&original)
+
+	// The cleaned-up title finds "Song - Remastered 2011" filed as "Song"
+	queries := []string{track.Title}
+	if original.NormalizedTitle != "" && !strings.EqualFold(original.NormalizedTitle, track.Title) {
+		queries = append([]string{original.NormalizedTitle}, queries...)
+	}
+
+	outcome := &models.SearchOutcome{}
+	var best *models.Track
+	bestScore := 0.0
+	for _, query := range queries {
+		items, err := c.searchAudio(query)
+		if err != nil {
+			return nil, err
+		}
+
+		scoreStart := time.Now()
+		for _, item := range items {
+			candidate := item.toTrack()
+			c.processor.NormalizeTrack(&candidate)
+
+			score := c.processor.CalculateMatchScore(original, candidate)
+			if original.Duration > 0 && candidate.Duration > 0 {
+				diff := (original.Duration - candidate.Duration).Seconds()
+				if diff < 0 {
+					diff = -diff
+				}
+				if diff <= durationSlop {
+					score += 0.1
+				}
+			}
+			if score > 1 {
+				score = 1
+			}
+
+			if score > bestScore {
+				bestScore = score
+				matched := candidate
+				best = &matched
+			}
+		}
+		outcome.ScoreTime += time.Since(scoreStart)
+
+		if bestScore >= goodScore {
+			break
+		}
+	}
+
+	if best != nil && bestScore >= minScore {
+		outcome.Track = best
+		outcome.Score = bestScore
+		outcome.Strategy = "jellyfin"
+	}
+	return outcome, nil
+}
+
+// searchAudio searches the audio items of the user's libraries by name
+func (c *Client) searchAudio(term string) ([]jellyfinItem, error) {
+	query := url.Values{}
+	query.Set("userId", c.userID)
+	query.Set("searchTerm", term)
+	query.Set("includeItemTypes", "Audio")
+	query.Set("recursive", "true")
+	query.Set("limit", fmt.Sprint(searchLimit))
+
+	var result jellyfinItems
+	if err := c.makeRequest("GET", "/Items?"+query.Encode(), nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Items, nil
+}
+
+// CreatePlaylist creates an empty audio playlist owned by the user. Jellyfin playlists have
+// no description field in the create call, so the description is not stored.
+func (c *Client) CreatePlaylist(name, description string) (*models.Playlist, error) {
+	request := map[string]interface{}{
+		"Name":      name,
+		"UserId":    c.userID,
+		"MediaType": "Audio",
+		"Ids":       []string{},
+	}
+
+	var response struct {
+		ID string `json:"Id"`
+	}
+	if err := c.makeRequest("POST", "/Playlists", request, &response); err != nil {
+		return nil, err
+	}
+
+	return &models.Playlist{
+		ID:          response.ID,
+		Name:        name,
+		Description: description,
+	}, nil
+}
+
+// AddTracksToPlaylist appends library items to a playlist. Jellyfin doesn't return the
+// playlist entry IDs, so the item IDs double as item IDs.
+func (c *Client) AddTracksToPlaylist(playlistID string, trackIDs []string) ([]string, error) {
+	query := url.Values{}
+	query.Set("ids", strings.Join(trackIDs, ","))
+	query.Set("userId", c.userID)
+
+	path := fmt.Sprintf("/Playlists/%s/Items?%s", url.PathEscape(playlistID), query.Encode())
+	if err := c.makeRequest("POST", path, nil, nil); err != nil {
+		return nil, err
+	}
+	return trackIDs, nil
+}
+
+// PlaylistURL links to a playlist in the Jellyfin web client
+func (c *Client) PlaylistURL(playlistID string) string {
+	return fmt.Sprintf("%s/web/#/details?id=%s", c.serverURL, playlistID)
+}
+
+// makeRequest performs an HTTP request to the Jellyfin API
+func (c *Client) makeRequest(method, path string, body interface{}, result interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("marshaling request body: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.serverURL+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf(
+		`MediaBrowser Client="PlaylistPorter", Device="PlaylistPorter", DeviceId="playlistporter", Version="1.0", Token="%s"`,
+		c.config.APIKey))
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("Jellyfin rejected the API key (create one under Dashboard > API Keys)")
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, respBody)
+	}
+
+	if result != nil {
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+			return fmt.Errorf("decoding response: %w", err)
+		}
+	}
+	return nil
+}
+
+// Jellyfin API response structures
+
+type jellyfinUser struct {
+	ID   string `json:"Id"`
+	Name string `json:"Name"`
+}
+
+type jellyfinItems struct {
+	Items []jellyfinItem `json:"Items"`
+}
+
+type jellyfinItem struct {
+	ID             string   `json:"Id"`
+	Name           string   `json:"Name"`
+	Album          string   `json:"Album"`
+	AlbumArtist    string   `json:"AlbumArtist"`
+	Artists        []string `json:"Artists"`
+	RunTimeTicks   int64    `json:"RunTimeTicks"`
+	ProductionYear int      `json:"ProductionYear"`
+}
+
+// toTrack converts a library item to the shared model, preferring the track artist
+func (i jellyfinItem) toTrack() models.Track {
+	track := models.Track{
+		ID:          i.ID,
+		Title:       i.Name,
+		Album:       i.Album,
+		Duration:    time.Duration(i.RunTimeTicks * int64(time.Second) / ticksPerSecond),
+		ReleaseYear: i.ProductionYear,
+	}
+	track.SetArtists(i.Artists, i.AlbumArtist)
+	return track
+}
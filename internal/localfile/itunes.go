@@ -0,0 +1,236 @@
+package localfile
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+)
+
+// librarySeparator separates an iTunes library file from the playlist to port, as in
+// "Library.xml#Road Trip"
+const librarySeparator = "#"
+
+// SplitLibraryPath splits "Library.xml#Playlist" into the library file and the playlist name.
+// ok is false for anything that isn't an .xml file.
+func SplitLibraryPath(source string) (path, playlist string, ok bool) {
+	path, playlist, _ = strings.Cut(source, librarySeparator)
+	if strings.Contains(path, "://") || !strings.EqualFold(filepath.Ext(path), ".xml") {
+		return "", "", false
+	}
+	return path, strings.TrimSpace(playlist), true
+}
+
+// readITunesPlaylist reads one playlist of an iTunes or Music.app library export
+// (File > Library > Export Library), which is an XML property list
+func readITunesPlaylist(path, name string) (*models.Playlist, error) {
+	library, err := readITunesLibrary(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		example := "Playlist"
+		if len(library.playlists) > 0 {
+			example = library.playlists[0].name
+		}
+		return nil, fmt.Errorf("name the playlist to port, e.g. \"%s#%s\"; the library has: %s",
+			path, example, library.playlistNames())
+	}
+
+	for _, entry := range library.playlists {
+		if !strings.EqualFold(entry.name, name) {
+			continue
+		}
+
+		playlist := &models.Playlist{Name: entry.name, Description: entry.description}
+		seen := make(map[string]int)
+		for _, id := range entry.trackIDs {
+			track, ok := library.tracks[id]
+			if !ok || track.Title == "" {
+				continue
+			}
+			track.ID = trackID(track.Artist, track.Title, seen)
+			playlist.Tracks = append(playlist.Tracks, track)
+		}
+		return playlist, nil
+	}
+	return nil, fmt.Errorf("playlist %q not found; the library has: %s", name, library.playlistNames())
+}
+
+// itunesLibrary holds the tracks and user playlists of a library export
+type itunesLibrary struct {
+	tracks    map[string]models.Track // Library track ID -> track
+	playlists []itunesPlaylist
+}
+
+type itunesPlaylist struct {
+	name        string
+	description string
+	trackIDs    []string
+}
+
+// playlistNames lists the quoted playlist names in library order
+func (l *itunesLibrary) playlistNames() string {
+	names := make([]string, 0, len(l.playlists))
+	for _, playlist := range l.playlists {
+		names = append(names, fmt.Sprintf("%q", playlist.name))
+	}
+	return strings.Join(names, ", ")
+}
+
+// readITunesLibrary decodes a library export, keeping the user's own playlists: the library
+// itself, built-in lists such as Music or Podcasts, and folders are left out
+func readITunesLibrary(path string) (*itunesLibrary, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	root, err := decodePlist(xml.NewDecoder(file))
+	if err != nil {
+		return nil, fmt.Errorf("parsing iTunes library: %w", err)
+	}
+	doc, ok := root.(map[string]interface{})
+	if !ok || doc["Tracks"] == nil {
+		return nil, fmt.Errorf("%s is not an iTunes library export", path)
+	}
+
+	library := &itunesLibrary{tracks: make(map[string]models.Track)}
+	tracks, _ := doc["Tracks"].(map[string]interface{})
+	for id, value := range tracks {
+		fields, _ := value.(map[string]interface{})
+		track := models.Track{
+			Title:       plistString(fields, "Name"),
+			Album:       plistString(fields, "Album"),
+			Duration:    time.Duration(plistInt(fields, "Total Time")) * time.Millisecond,
+			ReleaseYear: int(plistInt(fields, "Year")),
+			Explicit:    fields["Explicit"] == true,
+		}
+		var artists []string
+		if artist := plistString(fields, "Artist"); artist != "" {
+			artists = append(artists, artist)
+		}
+		track.SetArtists(artists, plistString(fields, "Album Artist"))
+		library.tracks[id] = track
+	}
+
+	playlists, _ := doc["Playlists"].([]interface{})
+	for _, value := range playlists {
+		fields, _ := value.(map[string]interface{})
+		if fields["Master"] == true || fields["Folder"] == true || fields["Distinguished Kind"] != nil {
+			continue
+		}
+		playlist := itunesPlaylist{
+			name:        plistString(fields, "Name"),
+			description: plistString(fields, "Description"),
+		}
+		items, _ := fields["Playlist Items"].([]interface{})
+		for _, item := range items {
+			itemFields, _ := item.(map[string]interface{})
+			playlist.trackIDs = append(playlist.trackIDs, strconv.FormatInt(plistInt(itemFields, "Track ID"), 10))
+		}
+		library.playlists = append(library.playlists, playlist)
+	}
+	return library, nil
+}
+
+// decodePlist decodes the first value of a property list into maps, slices, strings,
+// int64s and bools
+func decodePlist(decoder *xml.Decoder) (interface{}, error) {
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local != "plist" {
+			return decodePlistValue(decoder, start)
+		}
+	}
+}
+
+// decodePlistValue decodes the element opened by start
+func decodePlistValue(decoder *xml.Decoder, start xml.StartElement) (interface{}, error) {
+	switch start.Name.Local {
+	case "dict":
+		dict := make(map[string]interface{})
+		key := ""
+		for {
+			token, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			switch t := token.(type) {
+			case xml.StartElement:
+				if t.Name.Local == "key" {
+					if err := decoder.DecodeElement(&key, &t); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				value, err := decodePlistValue(decoder, t)
+				if err != nil {
+					return nil, err
+				}
+				dict[key] = value
+			case xml.EndElement:
+				return dict, nil
+			}
+		}
+	case "array":
+		var array []interface{}
+		for {
+			token, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			switch t := token.(type) {
+			case xml.StartElement:
+				value, err := decodePlistValue(decoder, t)
+				if err != nil {
+					return nil, err
+				}
+				array = append(array, value)
+			case xml.EndElement:
+				return array, nil
+			}
+		}
+	case "true", "false":
+		if err := decoder.Skip(); err != nil {
+			return nil, err
+		}
+		return start.Name.Local == "true", nil
+	case "integer":
+		var text string
+		if err := decoder.DecodeElement(&text, &start); err != nil {
+			return nil, err
+		}
+		n, _ := strconv.ParseInt(strings.TrimSpace(text), 10, 64)
+		return n, nil
+	default: // string, date, real, data
+		var text string
+		if err := decoder.DecodeElement(&text, &start); err != nil && err != io.EOF {
+			return nil, err
+		}
+		return text, nil
+	}
+}
+
+// plistString returns a string field of a dict, "" when missing
+func plistString(fields map[string]interface{}, key string) string {
+	s, _ := fields[key].(string)
+	return strings.TrimSpace(s)
+}
+
+// plistInt returns an integer field of a dict, 0 when missing
+func plistInt(fields map[string]interface{}, key string) int64 {
+	n, _ := fields[key].(int64)
+	return n
+}
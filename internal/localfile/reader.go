@@ -0,0 +1,387 @@
+// Package localfile reads playlists from CSV, M3U and XSPF files, e.g. Exportify exports,
+// and from iTunes/Music.app library exports
+package localfile
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+)
+
+// IDPrefix marks playlists read from files in states
+const IDPrefix = "file-"
+
+// extensions are the supported file types
+var extensions = map[string]bool{".csv": true, ".m3u": true, ".m3u8": true, ".xspf": true}
+
+// IsPlaylistFile reports whether a source argument names a playlist file rather than a link
+func IsPlaylistFile(path string) bool {
+	if strings.Contains(path, "://") {
+		return false
+	}
+	if _, _, ok := SplitLibraryPath(path); ok {
+		return true
+	}
+	return extensions[strings.ToLower(filepath.Ext(path))]
+}
+
+// IsLocalID reports whether a state ID belongs to a playlist file
+func IsLocalID(id string) bool {
+	return strings.HasPrefix(id, IDPrefix)
+}
+
+// Reader reads playlist files; the IDs it hands out are derived from the absolute path,
+// so the same file resumes the same state
+type Reader struct {
+	paths            map[string]string // Playlist ID -> file path
+	libraryPlaylists map[string]string // Playlist ID -> playlist name, for iTunes libraries
+}
+
+// NewReader creates a playlist file reader
+func NewReader() *Reader {
+	return &Reader{paths: make(map[string]string), libraryPlaylists: make(map[string]string)}
+}
+
+// PlaylistIDFromPath returns the ID of a playlist file and remembers its path. For iTunes
+// libraries ("Library.xml#Playlist") the ID covers the playlist name too.
+func (r *Reader) PlaylistIDFromPath(path string) (string, error) {
+	libraryPlaylist := ""
+	if libraryPath, name, ok := SplitLibraryPath(path); ok {
+		path, libraryPlaylist = libraryPath, name
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("resolving %s: %w", path, err)
+	}
+	if _, err := os.Stat(abs); err != nil {
+		return "", fmt.Errorf("reading playlist file: %w", err)
+	}
+
+	key := abs
+	if libraryPlaylist != "" {
+		key += librarySeparator + strings.ToLower(libraryPlaylist)
+	}
+	sum := sha1.Sum([]byte(key))
+	id := IDPrefix + hex.EncodeToString(sum[:8])
+	r.paths[id] = abs
+	if _, _, ok := SplitLibraryPath(abs); ok {
+		r.libraryPlaylists[id] = libraryPlaylist
+	}
+	return id, nil
+}
+
+// GetPlaylist reads the tracks of a playlist file registered with PlaylistIDFromPath
+func (r *Reader) GetPlaylist(id string) (*models.Playlist, error) {
+	path, ok := r.paths[id]
+	if !ok {
+		return nil, fmt.Errorf("this playlist was read from a file; pass the file again with -file")
+	}
+
+	var (
+		playlist *models.Playlist
+		err      error
+	)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".xml":
+		playlist, err = readITunesPlaylist(path, r.libraryPlaylists[id])
+	case ".csv":
+		playlist, err = readCSV(path)
+	case ".m3u", ".m3u8":
+		playlist, err = readM3U(path)
+	case ".xspf":
+		playlist, err = readXSPF(path)
+	default:
+		return nil, fmt.Errorf("unsupported playlist file %s (use .csv, .m3u, .m3u8, .xspf or an iTunes Library.xml)", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", filepath.Base(path), err)
+	}
+
+	if playlist.Name == "" {
+		playlist.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	playlist.ID = id
+	playlist.TotalTracks = len(playlist.Tracks)
+	if playlist.TotalTracks == 0 {
+		return nil, fmt.Errorf("no tracks found in %s", filepath.Base(path))
+	}
+	return playlist, nil
+}
+
+// csvColumns maps known header names, including Exportify's, to track fields
+var csvColumns = map[string]string{
+	"track name": "title", "title": "title", "name": "title", "track": "title", "song": "title",
+	"artist name(s)": "artist", "artist": "artist", "artists": "artist", "artist name": "artist",
+	"album name": "album", "album": "album",
+	"album artist name(s)": "album_artist", "album artist": "album_artist", "album_artist": "album_artist",
+	"duration (ms)": "duration_ms", "duration_ms": "duration_ms", "duration": "duration",
+	"isrc":      "isrc",
+	"track uri": "uri", "spotify id": "uri", "uri": "uri",
+	"artist uri(s)": "artist_ids", "artist ids": "artist_ids",
+	"release date": "release_date", "popularity": "popularity", "explicit": "explicit",
+}
+
+// readCSV reads a CSV file with a header row, or headerless artist,title,album rows
+func readCSV(path string) (*models.Playlist, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// Spreadsheet exports often start with a byte order mark, which would break the first quoted cell
+	buffered := bufio.NewReader(file)
+	if bom, err := buffered.Peek(3); err == nil && string(bom) == "\ufeff" {
+		buffered.Discard(3)
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return &models.Playlist{}, nil
+	}
+
+	columns := make(map[string]int)
+	for i, cell := range rows[0] {
+		name := strings.ToLower(strings.TrimSpace(cell))
+		if field, ok := csvColumns[name]; ok {
+			if _, seen := columns[field]; !seen {
+				columns[field] = i
+			}
+		}
+	}
+	if _, hasTitle := columns["title"]; hasTitle {
+		rows = rows[1:]
+	} else {
+		columns = map[string]int{"artist": 0, "title": 1, "album": 2}
+	}
+
+	get := func(row []string, field string) string {
+		if i, ok := columns[field]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	playlist := &models.Playlist{}
+	seen := make(map[string]int)
+	for _, row := range rows {
+		track := models.Track{
+			Title: get(row, "title"),
+			Album: get(row, "album"),
+			ISRC:  get(row, "isrc"),
+		}
+		if track.Title == "" {
+			continue
+		}
+		albumArtists := splitArtists(get(row, "album_artist"), "")
+		albumArtist := ""
+		if len(albumArtists) > 0 {
+			albumArtist = albumArtists[0]
+		}
+		track.SetArtists(splitArtists(get(row, "artist"), get(row, "artist_ids")), albumArtist)
+		if ms, err := strconv.Atoi(get(row, "duration_ms")); err == nil {
+			track.Duration = time.Duration(ms) * time.Millisecond
+		} else if d, ok := parseClock(get(row, "duration")); ok {
+			track.Duration = d
+		}
+		if date := get(row, "release_date"); len(date) >= 4 {
+			track.ReleaseYear, _ = strconv.Atoi(date[:4])
+		}
+		if popularity, err := strconv.Atoi(get(row, "popularity")); err == nil {
+			track.Popularity = &popularity
+		}
+		track.Explicit = strings.EqualFold(get(row, "explicit"), "true")
+
+		track.ID = spotifyTrackID(get(row, "uri"))
+		if track.ID == "" {
+			track.ID = trackID(track.Artist, track.Title, seen)
+		} else {
+			track.SourceURL = "https://open.spotify.com/track/" + track.ID
+		}
+		playlist.Tracks = append(playlist.Tracks, track)
+	}
+	return playlist, nil
+}
+
+// splitArtists splits the credited artists, main artist first. Exportify joins several
+// artists with commas, which only split safely when the artist IDs column confirms how many
+// there are.
+func splitArtists(artists, artistIDs string) []string {
+	if artists == "" {
+		return nil
+	}
+	if strings.Contains(artists, ";") {
+		return strings.Split(artists, ";")
+	}
+	if ids := strings.Split(artistIDs, ","); len(ids) > 1 && strings.Count(artists, ",") == len(ids)-1 {
+		return strings.Split(artists, ",")
+	}
+	return []string{artists}
+}
+
+// spotifyTrackID extracts the ID from spotify:track:ID and open.spotify.com/track/ID values
+func spotifyTrackID(uri string) string {
+	switch {
+	case strings.HasPrefix(uri, "spotify:track:"):
+		return strings.TrimPrefix(uri, "spotify:track:")
+	case strings.Contains(uri, "open.spotify.com/track/"):
+		id := uri[strings.Index(uri, "/track/")+len("/track/"):]
+		id, _, _ = strings.Cut(id, "?")
+		return id
+	}
+	return ""
+}
+
+// readM3U reads an extended M3U playlist; entries without #EXTINF are named after their file
+func readM3U(path string) (*models.Playlist, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	playlist := &models.Playlist{}
+	seen := make(map[string]int)
+	var pending models.Track
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		switch {
+		case line == "" || line == "#EXTM3U":
+			continue
+		case strings.HasPrefix(line, "#PLAYLIST:"):
+			playlist.Name = strings.TrimSpace(strings.TrimPrefix(line, "#PLAYLIST:"))
+		case strings.HasPrefix(line, "#EXTINF:"):
+			seconds, info, _ := strings.Cut(strings.TrimPrefix(line, "#EXTINF:"), ",")
+			// Attributes such as tvg-id="..." may follow the duration
+			seconds, _, _ = strings.Cut(seconds, " ")
+			pending.Artist, pending.Title = splitArtistTitle(info)
+			if s, err := strconv.Atoi(seconds); err == nil && s > 0 {
+				pending.Duration = time.Duration(s) * time.Second
+			}
+		case strings.HasPrefix(line, "#EXTALB:"):
+			pending.Album = strings.TrimSpace(strings.TrimPrefix(line, "#EXTALB:"))
+		case strings.HasPrefix(line, "#EXTART:"):
+			pending.Artist = strings.TrimSpace(strings.TrimPrefix(line, "#EXTART:"))
+		case strings.HasPrefix(line, "#"):
+			continue
+		default:
+			if pending.Title == "" {
+				base := filepath.Base(strings.ReplaceAll(line, "\\", "/"))
+				pending.Artist, pending.Title = splitArtistTitle(strings.TrimSuffix(base, filepath.Ext(base)))
+			}
+			if pending.Title != "" {
+				pending.ID = trackID(pending.Artist, pending.Title, seen)
+				playlist.Tracks = append(playlist.Tracks, pending)
+			}
+			pending = models.Track{}
+		}
+	}
+	return playlist, scanner.Err()
+}
+
+// readXSPF reads an XSPF (XML Shareable Playlist Format) file
+func readXSPF(path string) (*models.Playlist, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc xspfPlaylist
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing XSPF: %w", err)
+	}
+
+	playlist := &models.Playlist{Name: strings.TrimSpace(doc.Title), Description: strings.TrimSpace(doc.Annotation)}
+	seen := make(map[string]int)
+	for _, entry := range doc.Tracks {
+		track := models.Track{
+			Title:    strings.TrimSpace(entry.Title),
+			Artist:   strings.TrimSpace(entry.Creator),
+			Album:    strings.TrimSpace(entry.Album),
+			Duration: time.Duration(entry.Duration) * time.Millisecond,
+		}
+		if track.Title == "" {
+			continue
+		}
+		for _, identifier := range entry.Identifiers {
+			if id := spotifyTrackID(strings.TrimSpace(identifier)); id != "" {
+				track.ID = id
+			}
+		}
+		if track.ID == "" {
+			track.ID = trackID(track.Artist, track.Title, seen)
+		}
+		playlist.Tracks = append(playlist.Tracks, track)
+	}
+	return playlist, nil
+}
+
+// splitArtistTitle splits "Artist - Title"; without a separator the whole text is the title
+func splitArtistTitle(text string) (string, string) {
+	if artist, title, found := strings.Cut(text, " - "); found {
+		return strings.TrimSpace(artist), strings.TrimSpace(title)
+	}
+	return "", strings.TrimSpace(text)
+}
+
+// parseClock converts "3:45" or a number of seconds
+func parseClock(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	var total int
+	for _, part := range strings.Split(value, ":") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return 0, false
+		}
+		total = total*60 + n
+	}
+	return time.Duration(total) * time.Second, true
+}
+
+// trackID derives a stable ID from artist and title for files without Spotify IDs;
+// repeats of the same song get a counter so every entry stays unique
+func trackID(artist, title string, seen map[string]int) string {
+	sum := sha1.Sum([]byte(strings.ToLower(artist + "\x00" + title)))
+	id := IDPrefix + hex.EncodeToString(sum[:8])
+
+	seen[id]++
+	if n := seen[id]; n > 1 {
+		id = fmt.Sprintf("%s-%d", id, n)
+	}
+	return id
+}
+
+// XSPF structures (only the fields used)
+
+type xspfPlaylist struct {
+	Title      string      `xml:"title"`
+	Annotation string      `xml:"annotation"`
+	Tracks     []xspfTrack `xml:"trackList>track"`
+}
+
+type xspfTrack struct {
+	Title       string   `xml:"title"`
+	Creator     string   `xml:"creator"`
+	Album       string   `xml:"album"`
+	Duration    int64    `xml:"duration"` // Milliseconds
+	Identifiers []string `xml:"identifier"`
+}
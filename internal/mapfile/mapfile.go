@@ -0,0 +1,238 @@
+// Package mapfile reads and writes the track mapping files users share with each other:
+// verified matches from Spotify track IDs to YouTube video IDs, in CSV or JSON
+package mapfile
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/collate"
+)
+
+// Entry maps one Spotify track to the YouTube video it was matched with; Artist and Title
+// describe the Spotify track for people reading the file
+type Entry struct {
+	TrackID string `json:"spotify_track_id"`
+	VideoID string `json:"youtube_video_id"`
+	Artist  string `json:"artist,omitempty"`
+	Title   string `json:"title,omitempty"`
+}
+
+// File is the JSON form of a mapping file
+type File struct {
+	Mappings []Entry `json:"mappings"`
+}
+
+// CSV columns; only the two IDs are required and the order is free
+const (
+	columnTrackID = "spotify_track_id"
+	columnVideoID = "youtube_video_id"
+	columnArtist  = "artist"
+	columnTitle   = "title"
+)
+
+var (
+	trackIDPattern = regexp.MustCompile(`^[A-Za-z0-9]{22}$`)
+	videoIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+)
+
+// Read loads a mapping file (.csv or .json). It returns the valid entries and how many were
+// left out because an ID is malformed.
+func Read(path string) ([]Entry, int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("opening mapping file: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		entries, err = readCSV(f)
+	case ".json":
+		entries, err = readJSON(f)
+	default:
+		return nil, 0, fmt.Errorf("unsupported mapping file %s, use .csv or .json", filepath.Base(path))
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	valid := entries[:0]
+	for _, entry := range entries {
+		entry.TrackID = strings.TrimSpace(entry.TrackID)
+		entry.VideoID = strings.TrimSpace(entry.VideoID)
+		if Valid(entry) {
+			valid = append(valid, entry)
+		}
+	}
+	return valid, len(entries) - len(valid), nil
+}
+
+// Valid reports whether an entry holds a Spotify track ID and a YouTube video ID
+func Valid(entry Entry) bool {
+	return trackIDPattern.MatchString(entry.TrackID) && videoIDPattern.MatchString(entry.VideoID)
+}
+
+// readCSV reads a mapping file with a header row naming the columns
+func readCSV(r io.Reader) ([]Entry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{columnTrackID, columnVideoID} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV header has no %s column", required)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+
+	var entries []Entry
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading CSV: %w", err)
+		}
+		entries = append(entries, Entry{
+			TrackID: field(record, columnTrackID),
+			VideoID: field(record, columnVideoID),
+			Artist:  field(record, columnArtist),
+			Title:   field(record, columnTitle),
+		})
+	}
+	return entries, nil
+}
+
+// readJSON reads a {"mappings": [...]} file
+func readJSON(r io.Reader) ([]Entry, error) {
+	var file File
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("parsing JSON mapping file: %w", err)
+	}
+	return file.Mappings, nil
+}
+
+// Write saves entries as a mapping file, CSV or JSON by the extension of path, sorted by
+// artist and title so shared files diff cleanly
+func Write(path string, entries []Entry) error {
+	sorted := append([]Entry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if c := collate.Compare(sorted[i].Artist, sorted[j].Artist); c != 0 {
+			return c < 0
+		}
+		return collate.Less(sorted[i].Title, sorted[j].Title)
+	})
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating mapping file: %w", err)
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		err = writeCSV(f, sorted)
+	case ".json":
+		encoder := json.NewEncoder(f)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(File{Mappings: sorted})
+	default:
+		return fmt.Errorf("unsupported mapping file %s, use .csv or .json", filepath.Base(path))
+	}
+	if err != nil {
+		return fmt.Errorf("writing mapping file: %w", err)
+	}
+	return f.Close()
+}
+
+// writeCSV writes the entries with a header row
+func writeCSV(w io.Writer, entries []Entry) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{columnTrackID, columnVideoID, columnArtist, columnTitle})
+	for _, entry := range entries {
+		writer.Write([]string{entry.TrackID, entry.VideoID, entry.Artist, entry.Title})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// Merge combines the entries of several files. When files map a track to different videos,
+// the video most files agree on wins, and the earlier file breaks a tie. It returns the
+// merged entries and how many tracks had such a conflict.
+func Merge(files [][]Entry) ([]Entry, int) {
+	type candidate struct {
+		entry Entry
+		votes int
+	}
+	byTrack := make(map[string][]*candidate)
+	var order []string
+
+	for _, entries := range files {
+		seen := make(map[string]bool) // A file votes once per track
+		for _, entry := range entries {
+			if seen[entry.TrackID] {
+				continue
+			}
+			seen[entry.TrackID] = true
+
+			candidates, known := byTrack[entry.TrackID]
+			if !known {
+				order = append(order, entry.TrackID)
+			}
+			found := false
+			for _, c := range candidates {
+				if c.entry.VideoID == entry.VideoID {
+					c.votes++
+					found = true
+					break
+				}
+			}
+			if !found {
+				byTrack[entry.TrackID] = append(candidates, &candidate{entry: entry, votes: 1})
+			}
+		}
+	}
+
+	merged := make([]Entry, 0, len(order))
+	conflicts := 0
+	for _, trackID := range order {
+		candidates := byTrack[trackID]
+		if len(candidates) > 1 {
+			conflicts++
+		}
+		best := candidates[0]
+		for _, c := range candidates[1:] {
+			if c.votes > best.votes {
+				best = c
+			}
+		}
+		merged = append(merged, best.entry)
+	}
+	return merged, conflicts
+}
@@ -0,0 +1,87 @@
+package models
+
+import "strings"
+
+// variousArtists are the placeholder names compilations use as their album artist, lowercase
+var variousArtists = map[string]bool{
+	"various artists":          true,
+	"various":                  true,
+	"va":                       true,
+	"v.a.":                     true,
+	"v/a":                      true,
+	"varios artistas":          true,
+	"vários artistas":          true,
+	"verschiedene interpreten": true,
+	"artistes divers":          true,
+	"artistes variés":          true,
+	"artisti vari":             true,
+	"diverse artiesten":        true,
+	"diverse artister":         true,
+}
+
+// IsVariousArtists reports whether an artist name is a compilation's "Various Artists"
+// placeholder rather than a real artist
+func IsVariousArtists(name string) bool {
+	return variousArtists[strings.ToLower(strings.TrimSpace(name))]
+}
+
+// SetArtists records the credited artists of a track and the artist of its album. Artist
+// becomes the first credited artist that isn't a "Various Artists" placeholder, then the
+// album artist, then the placeholder itself when nothing better is known.
+func (t *Track) SetArtists(artists []string, albumArtist string) {
+	t.AlbumArtist = strings.TrimSpace(albumArtist)
+	t.Artists = nil
+	seen := make(map[string]bool)
+	for _, artist := range artists {
+		artist = strings.TrimSpace(artist)
+		if artist == "" || seen[strings.ToLower(artist)] {
+			continue
+		}
+		seen[strings.ToLower(artist)] = true
+		t.Artists = append(t.Artists, artist)
+	}
+
+	t.Artist = ""
+	for _, artist := range append(append([]string{}, t.Artists...), t.AlbumArtist) {
+		if artist != "" && !IsVariousArtists(artist) {
+			t.Artist = artist
+			return
+		}
+	}
+	if len(t.Artists) > 0 {
+		t.Artist = t.Artists[0]
+	} else {
+		t.Artist = t.AlbumArtist
+	}
+}
+
+// IsCompilation reports whether the track comes from a "Various Artists" compilation
+func (t Track) IsCompilation() bool {
+	return IsVariousArtists(t.AlbumArtist) || IsVariousArtists(t.Artist)
+}
+
+// KnownArtist reports whether Artist names the real artist, not a placeholder or nothing
+func (t Track) KnownArtist() bool {
+	return t.Artist != "" && !IsVariousArtists(t.Artist)
+}
+
+// OtherArtists returns the credited artists besides Artist, leaving out placeholders
+func (t Track) OtherArtists() []string {
+	var others []string
+	for _, artist := range t.Artists {
+		if !strings.EqualFold(artist, t.Artist) && !IsVariousArtists(artist) {
+			others = append(others, artist)
+		}
+	}
+	return others
+}
+
+// Alternatives returns the spellings a match may be scored against besides the track's own:
+// its variants, then the title under each of the other credited artists
+func (t Track) Alternatives() []TrackVariant {
+	alternatives := append([]TrackVariant{}, t.Variants...)
+	for _, artist := range t.OtherArtists() {
+		alternatives = append(alternatives, TrackVariant{Title: t.Title, Artist: artist})
+	}
+	return alternatives
+}
@@ -6,17 +6,46 @@ import "time"
 type Track struct {
 	ID          string        `json:"id"`
 	Title       string        `json:"title"`
-	Artist      string        `json:"artist"`
+	Artist      string        `json:"artist"` // Main artist, the first credited one that is a real artist
 	Album       string        `json:"album"`
 	Duration    time.Duration `json:"duration"`
 	ReleaseYear int           `json:"release_year,omitempty"`
 	ISRC        string        `json:"isrc,omitempty"` // International Standard Recording Code
+	Explicit    bool          `json:"explicit,omitempty"`
+	AlbumArtURL string        `json:"album_art_url,omitempty"`
+	Popularity  *int          `json:"popularity,omitempty"` // Spotify popularity 0-100, nil when unknown
+	SourceURL   string        `json:"source_url,omitempty"` // Canonical link to the track on the source service
+
+	// Every credited artist of the track, and the artist of its album ("Various Artists" on
+	// compilations); set with SetArtists, empty for sources that only know one artist
+	Artists     []string `json:"artists,omitempty"`
+	AlbumArtist string   `json:"album_artist,omitempty"`
+
+	// Other spellings of the track, e.g. the canonical MusicBrainz title or an artist alias;
+	// searched and scored as alternatives to Title and Artist
+	Variants []TrackVariant `json:"variants,omitempty"`
 
 	// Normalized versions for better matching
 	NormalizedTitle  string `json:"normalized_title"`
 	NormalizedArtist string `json:"normalized_artist"`
 }
 
+// TrackVariant is an alternative title and artist of a track
+type TrackVariant struct {
+	Title  string `json:"title"`
+	Artist string `json:"artist"`
+}
+
+// WithVariant returns a copy of the track under the variant's title and artist
+func (t Track) WithVariant(v TrackVariant) Track {
+	t.Title = v.Title
+	t.Artist = v.Artist
+	t.NormalizedTitle = ""
+	t.NormalizedArtist = ""
+	t.Variants = nil
+	return t
+}
+
 // Playlist represents a music playlist
 type Playlist struct {
 	ID          string  `json:"id"`
@@ -26,6 +55,8 @@ type Playlist struct {
 	TotalTracks int     `json:"total_tracks"`
 	IsPublic    bool    `json:"is_public"`
 	OwnerID     string  `json:"owner_id,omitempty"`
+
+	SkippedEpisodes int `json:"skipped_episodes,omitempty"` // Podcast episodes left out of Tracks
 }
 
 // MatchResult represents the result of matching a track
@@ -35,6 +66,37 @@ type MatchResult struct {
 	MatchScore    float64 `json:"match_score"` // 0.0 to 1.0
 	Matched       bool    `json:"matched"`
 	Error         string  `json:"error,omitempty"`
+	Strategy      string  `json:"strategy,omitempty"`      // Search strategy that produced the match
+	SearchesUsed  int     `json:"searches_used,omitempty"` // Search requests spent on this track
+	Trace         string  `json:"trace,omitempty"`         // ID of the search in the run's log, "<run>-t<n>"
+
+	// Versions of the rules that produced the result (0 for results saved before versions were recorded)
+	NormalizerVersion int `json:"normalizer_version,omitempty"`
+	MatcherVersion    int `json:"matcher_version,omitempty"`
+
+	// Cross-platform check of the match against Odesli (song.link), see CrossCheck* constants
+	CrossCheck      string `json:"cross_check,omitempty"`
+	CrossCheckVideo string `json:"cross_check_video,omitempty"` // Video Odesli links instead, for mismatches
+
+	// Audio fingerprint check of a low-confidence match ("verified" or "rejected", "" when not decided)
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// Cross-check outcomes stored in MatchResult.CrossCheck ("" is not checked yet)
+const (
+	CrossCheckConfirmed = "confirmed" // Odesli links the source track to the matched video
+	CrossCheckMismatch  = "mismatch"  // Odesli links the source track to a different video
+	CrossCheckUnknown   = "unknown"   // Odesli knows no video for the source track
+)
+
+// SearchOutcome describes the result of searching for a track on the destination service
+type SearchOutcome struct {
+	Track        *Track // nil when no candidate reached the threshold
+	Score        float64
+	Strategy     string // Strategy that produced the winning candidate
+	SearchesUsed int    // Search requests made (100 quota units each on YouTube)
+
+	ScoreTime time.Duration // Part of the search spent scoring candidates
 }
 
 // PortingResult represents the final result of the porting operation
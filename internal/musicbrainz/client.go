@@ -0,0 +1,259 @@
+// Package musicbrainz looks up recordings by ISRC on MusicBrainz to find the canonical
+// title and artist of a track plus their aliases, which are searched as alternatives
+package musicbrainz
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/traffic"
+)
+
+const (
+	apiURL         = "https://musicbrainz.org/ws/2"
+	requestTimeout = 15 * time.Second
+
+	// MusicBrainz allows one request per second per client
+	requestInterval = time.Second
+
+	// maxVariants bounds the alternatives kept per track; each one can cost a search
+	maxVariants = 3
+)
+
+// Recording is the canonical metadata of a recording with its alternative names
+type Recording struct {
+	IDs           []string `json:"ids,omitempty"` // MusicBrainz IDs of every recording with the ISRC
+	Title         string   `json:"title"`
+	Artist        string   `json:"artist"`
+	TitleAliases  []string `json:"title_aliases,omitempty"`
+	ArtistAliases []string `json:"artist_aliases,omitempty"`
+}
+
+// Client queries the MusicBrainz web service, caching results on disk by ISRC
+type Client struct {
+	httpClient  *http.Client
+	userAgent   string
+	cachePath   string
+	lastRequest time.Time
+
+	mu    sync.Mutex
+	cache map[string]*Recording // nil entries mark ISRCs MusicBrainz doesn't know
+	dirty bool
+}
+
+// NewClient creates a client. MusicBrainz asks every application to identify itself with a
+// contact (an email address or URL). The cache file is created on the first Save.
+func NewClient(contact, cachePath string) (*Client, error) {
+	if contact == "" {
+		return nil, errors.New("musicbrainz.contact is required (an email address or URL MusicBrainz can reach you at)")
+	}
+
+	c := &Client{
+		httpClient: traffic.NewClient(requestTimeout),
+		userAgent:  fmt.Sprintf("PlaylistPorter/1.0 ( %s )", contact),
+		cachePath:  cachePath,
+		cache:      make(map[string]*Recording),
+	}
+
+	data, err := os.ReadFile(cachePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading MusicBrainz cache: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &c.cache); err != nil {
+			// A damaged cache only costs new lookups
+			c.cache = make(map[string]*Recording)
+		}
+	}
+
+	return c, nil
+}
+
+// Lookup returns the recording with the given ISRC, or nil when MusicBrainz doesn't know it
+func (c *Client) Lookup(isrc string) (*Recording, error) {
+	isrc = strings.ToUpper(strings.TrimSpace(isrc))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Entries cached before recording IDs were kept are looked up again
+	if recording, ok := c.cache[isrc]; ok && (recording == nil || len(recording.IDs) > 0) {
+		return recording, nil
+	}
+
+	recording, err := c.fetch(isrc)
+	if err != nil {
+		return nil, err
+	}
+
+	c.cache[isrc] = recording
+	c.dirty = true
+	return recording, nil
+}
+
+// Save writes the cache if new lookups were made
+func (c *Client) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.dirty {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(c.cache, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling MusicBrainz cache: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.cachePath), 0755); err != nil {
+		return fmt.Errorf("creating cache directory: %w", err)
+	}
+	if err := os.WriteFile(c.cachePath, data, 0644); err != nil {
+		return fmt.Errorf("writing MusicBrainz cache: %w", err)
+	}
+
+	c.dirty = false
+	return nil
+}
+
+// fetch queries the ISRC endpoint, waiting as long as the rate limit requires
+func (c *Client) fetch(isrc string) (*Recording, error) {
+	if wait := requestInterval - time.Since(c.lastRequest); wait > 0 {
+		time.Sleep(wait)
+	}
+	c.lastRequest = time.Now()
+
+	requestURL := fmt.Sprintf("%s/isrc/%s?inc=artist-credits+aliases&fmt=json", apiURL, url.PathEscape(isrc))
+	req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("looking up ISRC %s: %w", isrc, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, nil
+	case http.StatusServiceUnavailable:
+		return nil, fmt.Errorf("MusicBrainz rate limit hit looking up ISRC %s", isrc)
+	default:
+		return nil, fmt.Errorf("MusicBrainz returned %s for ISRC %s", resp.Status, isrc)
+	}
+
+	var result isrcResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decoding MusicBrainz response: %w", err)
+	}
+	if len(result.Recordings) == 0 {
+		return nil, nil
+	}
+
+	// The first recording is the one MusicBrainz lists for the ISRC; further ones are
+	// usually duplicates awaiting a merge
+	recording := result.Recordings[0].toRecording()
+	for _, r := range result.Recordings {
+		recording.IDs = append(recording.IDs, r.ID)
+	}
+	return recording, nil
+}
+
+// Variants returns the spellings of the recording that differ from the track's own,
+// canonical name first, at most maxVariants of them
+func (r *Recording) Variants(track models.Track) []models.TrackVariant {
+	if r == nil {
+		return nil
+	}
+
+	seen := map[string]bool{variantKey(track.Title, track.Artist): true}
+	var variants []models.TrackVariant
+	add := func(title, artist string) {
+		key := variantKey(title, artist)
+		if title == "" || artist == "" || seen[key] || len(variants) >= maxVariants {
+			return
+		}
+		seen[key] = true
+		variants = append(variants, models.TrackVariant{Title: title, Artist: artist})
+	}
+
+	add(r.Title, r.Artist)
+	for _, alias := range r.TitleAliases {
+		add(alias, r.Artist)
+	}
+	for _, alias := range r.ArtistAliases {
+		add(r.Title, alias)
+	}
+	return variants
+}
+
+// variantKey compares spellings ignoring case and surrounding space
+func variantKey(title, artist string) string {
+	return strings.ToLower(strings.TrimSpace(title)) + "\x00" + strings.ToLower(strings.TrimSpace(artist))
+}
+
+type isrcResponse struct {
+	Recordings []mbRecording `json:"recordings"`
+}
+
+type mbRecording struct {
+	ID           string         `json:"id"`
+	Title        string         `json:"title"`
+	Aliases      []mbAlias      `json:"aliases"`
+	ArtistCredit []artistCredit `json:"artist-credit"`
+}
+
+type mbAlias struct {
+	Name string `json:"name"`
+}
+
+type artistCredit struct {
+	Name       string `json:"name"`
+	JoinPhrase string `json:"joinphrase"`
+	Artist     struct {
+		Name    string    `json:"name"`
+		Aliases []mbAlias `json:"aliases"`
+	} `json:"artist"`
+}
+
+// toRecording joins the artist credit as MusicBrainz displays it, e.g. "Artist feat. Guest"
+func (m mbRecording) toRecording() *Recording {
+	recording := &Recording{Title: m.Title}
+
+	var artist strings.Builder
+	for _, credit := range m.ArtistCredit {
+		artist.WriteString(credit.Name)
+		artist.WriteString(credit.JoinPhrase)
+	}
+	recording.Artist = strings.TrimSpace(artist.String())
+
+	for _, alias := range m.Aliases {
+		if alias.Name != "" && alias.Name != m.Title {
+			recording.TitleAliases = append(recording.TitleAliases, alias.Name)
+		}
+	}
+
+	// Artist aliases only make sense for single-artist credits
+	if len(m.ArtistCredit) == 1 {
+		for _, alias := range m.ArtistCredit[0].Artist.Aliases {
+			if alias.Name != "" && alias.Name != recording.Artist {
+				recording.ArtistAliases = append(recording.ArtistAliases, alias.Name)
+			}
+		}
+	}
+
+	return recording
+}
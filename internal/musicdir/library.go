@@ -0,0 +1,269 @@
+// Package musicdir builds ported playlists from a folder of audio files: tracks are matched
+// against the files' tags and the playlist is written as an .m3u8 file
+package musicdir
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/config"
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/processor"
+)
+
+const minScore = 0.6 // Lowest score accepted as a match
+
+// audioExtensions are the files indexed; formats without a tag reader are matched by file name
+var audioExtensions = map[string]bool{
+	".mp3": true, ".flac": true, ".ogg": true, ".oga": true, ".opus": true,
+	".m4a": true, ".aac": true, ".wav": true, ".aiff": true, ".wma": true,
+}
+
+// Library is an index of the audio files in a folder. Track IDs are absolute file paths,
+// playlist IDs are the paths of the written .m3u8 files.
+type Library struct {
+	root        string
+	playlistDir string
+	processor   *processor.Processor
+	tracks      []models.Track
+	byISRC      map[string]int
+	byWord      map[string][]int // Word of the normalized title -> tracks
+	entries     map[string]models.Track
+}
+
+// Open scans the music folder and reads the tags of every audio file in it
+func Open(cfg *config.MusicFolderConfig) (*Library, error) {
+	if cfg.Path == "" {
+		return nil, fmt.Errorf("music_folder.path is required for -dest folder")
+	}
+	root, err := filepath.Abs(cfg.Path)
+	if err != nil {
+		return nil, fmt.Errorf("resolving %s: %w", cfg.Path, err)
+	}
+	playlistDir := cfg.PlaylistDir
+	if playlistDir == "" {
+		playlistDir = root
+	}
+	if playlistDir, err = filepath.Abs(playlistDir); err != nil {
+		return nil, fmt.Errorf("resolving %s: %w", cfg.PlaylistDir, err)
+	}
+
+	library := &Library{
+		root:        root,
+		playlistDir: playlistDir,
+		processor:   processor.New(),
+		byISRC:      make(map[string]int),
+		byWord:      make(map[string][]int),
+		entries:     make(map[string]models.Track),
+	}
+
+	err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !audioExtensions[strings.ToLower(filepath.Ext(path))] {
+			return nil
+		}
+		library.add(path)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scanning %s: %w", root, err)
+	}
+	if len(library.tracks) == 0 {
+		return nil, fmt.Errorf("no audio files found in %s", root)
+	}
+	return library, nil
+}
+
+// add indexes one audio file
+func (l *Library) add(path string) {
+	t := readTags(path)
+	if t.Artist == "" {
+		// Artist/Album/track layout
+		if rel, err := filepath.Rel(l.root, path); err == nil {
+			if parts := strings.Split(filepath.ToSlash(rel), "/"); len(parts) >= 3 {
+				t.Artist = parts[len(parts)-3]
+			}
+		}
+	}
+
+	track := models.Track{
+		ID:        path,
+		Title:     t.Title,
+		Artist:    t.Artist,
+		Album:     t.Album,
+		ISRC:      t.ISRC,
+		Duration:  t.Duration,
+		SourceURL: "file://" + filepath.ToSlash(path),
+	}
+	l.processor.NormalizeTrack(&track)
+
+	index := len(l.tracks)
+	l.tracks = append(l.tracks, track)
+	if track.ISRC != "" {
+		l.byISRC[track.ISRC] = index
+	}
+	for _, word := range uniqueWords(track.NormalizedTitle) {
+		l.byWord[word] = append(l.byWord[word], index)
+	}
+}
+
+// Len returns the number of indexed files
+func (l *Library) Len() int {
+	return len(l.tracks)
+}
+
+// SearchTrackOutcome matches a track against the indexed files: by ISRC when the tags carry
+// one, otherwise by scoring every file sharing a title word. SearchesUsed stays 0.
+func (l *Library) SearchTrackOutcome(track models.Track) (*models.SearchOutcome, error) {
+	outcome := &models.SearchOutcome{}
+	scoreStart := time.Now()
+	defer func() { outcome.ScoreTime = time.Since(scoreStart) }()
+
+	if i, ok := l.byISRC[strings.ToUpper(track.ISRC)]; ok && track.ISRC != "" {
+		matched := l.tracks[i]
+		outcome.Track = &matched
+		outcome.Score = 1
+		outcome.Strategy = "isrc"
+		return outcome, nil
+	}
+
+	candidates := make(map[int]bool)
+	originals := []models.Track{track}
+	for _, variant := range track.Variants {
+		originals = append(originals, track.WithVariant(variant))
+	}
+	for i := range originals {
+		l.processor.NormalizeTrack(&originals[i])
+		for _, word := range uniqueWords(originals[i].NormalizedTitle) {
+			for _, index := range l.byWord[word] {
+				candidates[index] = true
+			}
+		}
+	}
+
+	bestScore := 0.0
+	best := -1
+	for index := range candidates {
+		for _, original := range originals {
+			if score := l.processor.CalculateMatchScore(original, l.tracks[index]); score > bestScore {
+				bestScore = score
+				best = index
+			}
+		}
+	}
+
+	if best >= 0 && bestScore >= minScore {
+		matched := l.tracks[best]
+		outcome.Track = &matched
+		outcome.Score = bestScore
+		outcome.Strategy = "tags"
+	}
+	return outcome, nil
+}
+
+// CreatePlaylist writes an empty .m3u8 file named after the playlist. An existing file of
+// the same name is never overwritten; a number is added instead.
+func (l *Library) CreatePlaylist(name, description string) (*models.Playlist, error) {
+	if err := os.MkdirAll(l.playlistDir, 0755); err != nil {
+		return nil, fmt.Errorf("creating playlist directory: %w", err)
+	}
+
+	base := sanitizeFileName(name)
+	path := filepath.Join(l.playlistDir, base+".m3u8")
+	for n := 2; ; n++ {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			break
+		}
+		path = filepath.Join(l.playlistDir, fmt.Sprintf("%s (%d).m3u8", base, n))
+	}
+
+	header := fmt.Sprintf("#EXTM3U\n#PLAYLIST:%s\n", strings.ReplaceAll(name, "\n", " "))
+	if err := os.WriteFile(path, []byte(header), 0644); err != nil {
+		return nil, fmt.Errorf("writing playlist: %w", err)
+	}
+
+	return &models.Playlist{
+		ID:          path,
+		Name:        name,
+		Description: description,
+	}, nil
+}
+
+// AddTracksToPlaylist appends files to an .m3u8 playlist, relative to the playlist's folder
+// so the folder can be moved or shared as a whole
+func (l *Library) AddTracksToPlaylist(playlistID string, trackIDs []string) ([]string, error) {
+	f, err := os.OpenFile(playlistID, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("opening playlist: %w", err)
+	}
+	defer f.Close()
+
+	var b strings.Builder
+	for _, id := range trackIDs {
+		track := l.lookup(id)
+		fmt.Fprintf(&b, "#EXTINF:%d,%s - %s\n", int(track.Duration.Seconds()), track.Artist, track.Title)
+
+		entry := id
+		if rel, err := filepath.Rel(filepath.Dir(playlistID), id); err == nil {
+			entry = filepath.ToSlash(rel)
+		}
+		b.WriteString(entry + "\n")
+	}
+
+	if _, err := f.WriteString(b.String()); err != nil {
+		return nil, fmt.Errorf("writing playlist: %w", err)
+	}
+	return trackIDs, f.Sync()
+}
+
+// lookup returns the indexed track of a file, or just its path for files added since the scan
+func (l *Library) lookup(path string) models.Track {
+	if len(l.entries) == 0 {
+		for _, track := range l.tracks {
+			l.entries[track.ID] = track
+		}
+	}
+	if track, ok := l.entries[path]; ok {
+		return track
+	}
+	return models.Track{ID: path, Title: filepath.Base(path)}
+}
+
+// PlaylistURL links to the written playlist file
+func (l *Library) PlaylistURL(playlistID string) string {
+	return "file://" + filepath.ToSlash(playlistID)
+}
+
+// uniqueWords splits a normalized title into its distinct words
+func uniqueWords(title string) []string {
+	seen := make(map[string]bool)
+	var words []string
+	for _, word := range strings.Fields(title) {
+		if !seen[word] {
+			seen[word] = true
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// sanitizeFileName replaces characters that aren't allowed in file names
+func sanitizeFileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) || r < 32 {
+			return '_'
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "playlist"
+	}
+	return name
+}
@@ -0,0 +1,315 @@
+package musicdir
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// tags are the fields read from an audio file
+type tags struct {
+	Title    string
+	Artist   string
+	Album    string
+	ISRC     string
+	Duration time.Duration
+}
+
+// maxTagSize bounds how much of a file is read looking for tags
+const maxTagSize = 4 << 20
+
+// readTags reads the tags of an MP3 (ID3v2, ID3v1), FLAC or Ogg Vorbis/Opus file. Files
+// without readable tags fall back to an "Artist - Title" file name.
+func readTags(path string) tags {
+	var t tags
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".mp3":
+		t = readMP3(path)
+	case ".flac":
+		t = readFLAC(path)
+	case ".ogg", ".oga", ".opus":
+		t = readOgg(path)
+	}
+
+	if t.Title == "" {
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		if artist, title, ok := strings.Cut(name, " - "); ok {
+			t.Artist, t.Title = strings.TrimSpace(artist), strings.TrimSpace(title)
+		} else {
+			t.Title = name
+		}
+		// "01 Title" track-number prefixes
+		if fields := strings.SplitN(t.Title, " ", 2); len(fields) == 2 {
+			if _, err := strconv.Atoi(strings.TrimRight(fields[0], ".")); err == nil {
+				t.Title = fields[1]
+			}
+		}
+	}
+	return t
+}
+
+// readMP3 reads ID3v2.3/2.4 text frames, falling back to an ID3v1 trailer
+func readMP3(path string) tags {
+	var t tags
+	f, err := os.Open(path)
+	if err != nil {
+		return t
+	}
+	defer f.Close()
+
+	header := make([]byte, 10)
+	if _, err := io.ReadFull(f, header); err == nil && string(header[:3]) == "ID3" {
+		version := header[3]
+		size := syncsafe(header[6:10])
+		if size <= maxTagSize && version >= 3 {
+			body := make([]byte, size)
+			if _, err := io.ReadFull(f, body); err == nil {
+				parseID3v2Frames(body, version, &t)
+			}
+		}
+	}
+
+	if t.Title == "" {
+		if info, err := f.Stat(); err == nil && info.Size() >= 128 {
+			trailer := make([]byte, 128)
+			if _, err := f.ReadAt(trailer, info.Size()-128); err == nil && string(trailer[:3]) == "TAG" {
+				t.Title = trimLatin1(trailer[3:33])
+				t.Artist = trimLatin1(trailer[33:63])
+				t.Album = trimLatin1(trailer[63:93])
+			}
+		}
+	}
+	return t
+}
+
+// parseID3v2Frames walks the frames of an ID3v2.3 or 2.4 tag body
+func parseID3v2Frames(body []byte, version byte, t *tags) {
+	for len(body) >= 10 && body[0] != 0 {
+		id := string(body[:4])
+		var size int
+		if version == 4 {
+			size = syncsafe(body[4:8])
+		} else {
+			size = int(binary.BigEndian.Uint32(body[4:8]))
+		}
+		if size <= 0 || size > len(body)-10 {
+			return
+		}
+		frame := body[10 : 10+size]
+		body = body[10+size:]
+
+		switch id {
+		case "TIT2":
+			t.Title = decodeID3Text(frame)
+		case "TPE1":
+			t.Artist = decodeID3Text(frame)
+		case "TALB":
+			t.Album = decodeID3Text(frame)
+		case "TSRC":
+			t.ISRC = strings.ToUpper(decodeID3Text(frame))
+		case "TLEN":
+			if ms, err := strconv.Atoi(decodeID3Text(frame)); err == nil {
+				t.Duration = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+}
+
+// decodeID3Text decodes a text frame; multiple values keep only the first
+func decodeID3Text(frame []byte) string {
+	if len(frame) < 2 {
+		return ""
+	}
+	encoding, data := frame[0], frame[1:]
+
+	var text string
+	switch encoding {
+	case 1, 2: // UTF-16 with BOM, UTF-16BE
+		order := binary.ByteOrder(binary.BigEndian)
+		if encoding == 1 && len(data) >= 2 {
+			if data[0] == 0xFF && data[1] == 0xFE {
+				order = binary.LittleEndian
+			}
+			if (data[0] == 0xFF && data[1] == 0xFE) || (data[0] == 0xFE && data[1] == 0xFF) {
+				data = data[2:]
+			}
+		}
+		units := make([]uint16, 0, len(data)/2)
+		for i := 0; i+1 < len(data); i += 2 {
+			unit := order.Uint16(data[i:])
+			if unit == 0 {
+				break
+			}
+			units = append(units, unit)
+		}
+		text = string(utf16.Decode(units))
+	case 3: // UTF-8
+		text, _, _ = strings.Cut(string(data), "\x00")
+	default: // ISO-8859-1
+		text = trimLatin1(data)
+	}
+	return strings.TrimSpace(text)
+}
+
+// readFLAC reads the Vorbis comments and the stream length of a FLAC file
+func readFLAC(path string) tags {
+	var t tags
+	f, err := os.Open(path)
+	if err != nil {
+		return t
+	}
+	defer f.Close()
+
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(f, magic); err != nil || string(magic) != "fLaC" {
+		return t
+	}
+
+	for {
+		header := make([]byte, 4)
+		if _, err := io.ReadFull(f, header); err != nil {
+			return t
+		}
+		last := header[0]&0x80 != 0
+		blockType := header[0] & 0x7F
+		size := int(header[1])<<16 | int(header[2])<<8 | int(header[3])
+		if size > maxTagSize {
+			return t
+		}
+		block := make([]byte, size)
+		if _, err := io.ReadFull(f, block); err != nil {
+			return t
+		}
+
+		switch blockType {
+		case 0: // STREAMINFO
+			if len(block) >= 18 {
+				sampleRate := int64(block[10])<<12 | int64(block[11])<<4 | int64(block[12])>>4
+				samples := int64(block[13]&0x0F)<<32 | int64(binary.BigEndian.Uint32(block[14:18]))
+				if sampleRate > 0 {
+					t.Duration = time.Duration(samples * int64(time.Second) / sampleRate)
+				}
+			}
+		case 4: // VORBIS_COMMENT
+			parseVorbisComments(block, &t)
+		}
+		if last {
+			return t
+		}
+	}
+}
+
+// readOgg finds the Vorbis or Opus comment header near the start of an Ogg file.
+// Comments longer than the first pages (e.g. embedded cover art first) are not read.
+func readOgg(path string) tags {
+	var t tags
+	f, err := os.Open(path)
+	if err != nil {
+		return t
+	}
+	defer f.Close()
+
+	data := make([]byte, 64<<10)
+	n, _ := io.ReadFull(f, data)
+	data = data[:n]
+
+	for _, marker := range [][]byte{[]byte("\x03vorbis"), []byte("OpusTags")} {
+		if i := bytes.Index(data, marker); i >= 0 {
+			parseVorbisComments(stripOggPageHeaders(data[i+len(marker):]), &t)
+			break
+		}
+	}
+	return t
+}
+
+// stripOggPageHeaders removes page headers that split a comment packet across pages
+func stripOggPageHeaders(data []byte) []byte {
+	for {
+		i := bytes.Index(data, []byte("OggS"))
+		if i < 0 || i+27 > len(data) {
+			return data
+		}
+		segments := int(data[i+26])
+		end := i + 27 + segments
+		if end > len(data) {
+			return data[:i]
+		}
+		data = append(data[:i:i], data[end:]...)
+	}
+}
+
+// parseVorbisComments reads TITLE, ARTIST, ALBUM and ISRC from a Vorbis comment block
+func parseVorbisComments(block []byte, t *tags) {
+	read := func() ([]byte, error) {
+		if len(block) < 4 {
+			return nil, errors.New("short block")
+		}
+		n := int(binary.LittleEndian.Uint32(block))
+		if n < 0 || n > len(block)-4 {
+			return nil, errors.New("short block")
+		}
+		value := block[4 : 4+n]
+		block = block[4+n:]
+		return value, nil
+	}
+
+	if _, err := read(); err != nil { // Vendor string
+		return
+	}
+	if len(block) < 4 {
+		return
+	}
+	count := int(binary.LittleEndian.Uint32(block))
+	block = block[4:]
+
+	for i := 0; i < count; i++ {
+		comment, err := read()
+		if err != nil {
+			return
+		}
+		key, value, ok := strings.Cut(string(comment), "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToUpper(key) {
+		case "TITLE":
+			setOnce(&t.Title, value)
+		case "ARTIST":
+			setOnce(&t.Artist, value)
+		case "ALBUM":
+			setOnce(&t.Album, value)
+		case "ISRC":
+			setOnce(&t.ISRC, strings.ToUpper(value))
+		}
+	}
+}
+
+// setOnce keeps the first of repeated comments
+func setOnce(field *string, value string) {
+	if *field == "" {
+		*field = value
+	}
+}
+
+// syncsafe decodes a 28-bit ID3v2 syncsafe integer
+func syncsafe(b []byte) int {
+	return int(b[0]&0x7F)<<21 | int(b[1]&0x7F)<<14 | int(b[2]&0x7F)<<7 | int(b[3]&0x7F)
+}
+
+// trimLatin1 converts a NUL-padded ISO-8859-1 field to a string
+func trimLatin1(b []byte) string {
+	b, _, _ = bytes.Cut(b, []byte{0})
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+	return strings.TrimSpace(string(runes))
+}
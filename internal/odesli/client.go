@@ -0,0 +1,120 @@
+// Package odesli asks the Odesli (song.link) API which YouTube videos belong to the same
+// recording as a track on another platform, to cross-check matches
+package odesli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/traffic"
+)
+
+const (
+	apiURL         = "https://api.song.link/v1-alpha.1/links"
+	requestTimeout = 20 * time.Second
+
+	// Without an API key Odesli allows 10 requests per minute
+	anonymousInterval = 6 * time.Second
+)
+
+// ErrRateLimited is returned when Odesli asks to slow down; checks can resume on the next run
+var ErrRateLimited = errors.New("Odesli rate limit reached")
+
+// Client queries the Odesli links API
+type Client struct {
+	httpClient  *http.Client
+	apiKey      string
+	interval    time.Duration
+	lastRequest time.Time
+}
+
+// NewClient creates a client; the API key is optional and lifts the anonymous rate limit
+func NewClient(apiKey string) *Client {
+	interval := anonymousInterval
+	if apiKey != "" {
+		interval = 0
+	}
+	return &Client{
+		httpClient: traffic.NewClient(requestTimeout),
+		apiKey:     apiKey,
+		interval:   interval,
+	}
+}
+
+// YouTubeVideos returns the IDs of the YouTube and YouTube Music videos Odesli links to the
+// track at trackURL, e.g. a Spotify track link. An empty result means Odesli knows no video.
+func (c *Client) YouTubeVideos(trackURL string) ([]string, error) {
+	if wait := c.interval - time.Since(c.lastRequest); wait > 0 {
+		time.Sleep(wait)
+	}
+	c.lastRequest = time.Now()
+
+	query := url.Values{}
+	query.Set("url", trackURL)
+	if c.apiKey != "" {
+		query.Set("key", c.apiKey)
+	}
+
+	resp, err := c.httpClient.Get(apiURL + "?" + query.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("querying Odesli: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return nil, ErrRateLimited
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusBadRequest:
+		return nil, nil // Not a link Odesli can resolve
+	case resp.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("Odesli returned status %d: %s", resp.StatusCode, body)
+	}
+
+	var result linksResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decoding Odesli response: %w", err)
+	}
+
+	var videos []string
+	seen := make(map[string]bool)
+	for _, platform := range []string{"youtube", "youtubeMusic"} {
+		link, ok := result.LinksByPlatform[platform]
+		if !ok {
+			continue
+		}
+		id := videoID(link)
+		if id != "" && !seen[id] {
+			seen[id] = true
+			videos = append(videos, id)
+		}
+	}
+	return videos, nil
+}
+
+// videoID takes the video ID from an entity ID such as YOUTUBE_VIDEO::dQw4w9WgXcQ,
+// falling back to the v parameter of the link
+func videoID(link platformLink) string {
+	if _, id, found := strings.Cut(link.EntityUniqueID, "::"); found {
+		return id
+	}
+	if parsed, err := url.Parse(link.URL); err == nil {
+		return parsed.Query().Get("v")
+	}
+	return ""
+}
+
+type linksResponse struct {
+	LinksByPlatform map[string]platformLink `json:"linksByPlatform"`
+}
+
+type platformLink struct {
+	URL            string `json:"url"`
+	EntityUniqueID string `json:"entityUniqueId"`
+}
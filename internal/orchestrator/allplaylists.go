@@ -0,0 +1,159 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/spt"
+	"github.com/Verryx-02/PlaylistPorter/internal/state"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// roundRobinSlice is how many tracks each playlist gets per round in -all-playlists mode
+const roundRobinSlice = 10
+
+// accountPlaylist is one playlist of the account being ported in -all-playlists mode
+type accountPlaylist struct {
+	playlist models.Playlist
+	state    *state.PortingState
+	isNew    bool
+	done     bool
+}
+
+// PortAllPlaylists ports every playlist in the signed-in user's Spotify library, one state each.
+// maxTracks is shared by all of them: playlists take turns processing up to roundRobinSlice
+// tracks until the budget is used up or nothing is left, so every playlist makes progress.
+func (o *Orchestrator) PortAllPlaylists() error {
+	defer o.Close()
+
+	if o.destinationName() == DestSpotify {
+		return fmt.Errorf("-all-playlists ports Spotify playlists; pick another -dest")
+	}
+
+	// The library can only be listed with the user's account
+	o.cfg.SPT.UserAuth = true
+	if err := o.initializeClients(); err != nil {
+		return fmt.Errorf("initializing clients: %w", err)
+	}
+	if o.sptClient == nil {
+		if err := o.initializeSpt(); err != nil {
+			return err
+		}
+	}
+
+	ui.Printf("📚 Listing your Spotify playlists...\n")
+	library, err := o.sptClient.GetUserPlaylists()
+	if err != nil {
+		return err
+	}
+
+	var playlists []*accountPlaylist
+	for _, playlist := range library {
+		if playlist.TotalTracks == 0 {
+			o.writeToLog("Skipping empty playlist \"%s\"", playlist.Name)
+			continue
+		}
+		playlists = append(playlists, &accountPlaylist{playlist: playlist})
+	}
+	ui.Printf("📚 Found %d playlists with tracks (%d in the library)\n", len(playlists), len(library))
+	o.writeToLog("All playlists: %d with tracks, %d in the library", len(playlists), len(library))
+
+	budget := o.maxTracks
+	for round := 1; budget > 0 && !o.outOfTime(); round++ {
+		progressed := false
+		for _, entry := range playlists {
+			if entry.done || budget == 0 || o.outOfTime() {
+				continue
+			}
+
+			slice := roundRobinSlice
+			if slice > budget {
+				slice = budget
+			}
+			used, err := o.portAccountPlaylist(entry, round, slice)
+			if err != nil {
+				ui.Printf("⚠️  \"%s\": %v\n", entry.playlist.Name, err)
+				o.writeToLog("❌ Playlist %s failed: %v", entry.playlist.ID, err)
+				entry.done = true
+				continue
+			}
+
+			budget -= used
+			if used > 0 {
+				progressed = true
+			}
+			// A playlist is finished for this run once it has nothing left to process
+			if used < slice {
+				entry.done = true
+			}
+		}
+		if !progressed {
+			break
+		}
+	}
+
+	o.reportAllPlaylists(playlists, o.maxTracks-budget)
+	return nil
+}
+
+// portAccountPlaylist runs one session of at most limit tracks on a playlist of the account,
+// loading or creating its state on the first turn, and returns how many tracks were processed
+func (o *Orchestrator) portAccountPlaylist(entry *accountPlaylist, round, limit int) (int, error) {
+	ui.Printf("\n━━━ [round %d] %s ━━━\n", round, entry.playlist.Name)
+
+	if entry.state == nil {
+		sptURL := "https://open.spotify.com/playlist/" + entry.playlist.ID
+		portingState, isNew, err := o.loadOrCreateState(sptURL, entry.playlist.ID)
+		if errors.Is(err, spt.ErrPlaylistUnavailable) {
+			ui.Printf("🚫 Not available, skipped\n")
+			entry.done = true
+			return 0, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		entry.state, entry.isNew = portingState, isNew
+	}
+
+	// Complete playlists only take a turn to pick up new tracks when syncing
+	if entry.state.IsComplete && !o.syncMode && !entry.state.IsArchive() {
+		ui.Printf("✅ Already complete\n")
+		entry.done = true
+		return 0, nil
+	}
+
+	before := len(entry.state.MatchResults)
+	maxTracks := o.maxTracks
+	o.maxTracks = limit
+	o.stageTimes = nil
+	err := o.runSession(entry.state, entry.isNew)
+	o.maxTracks = maxTracks
+	entry.isNew = false
+
+	// Sync only checks a playlist once per run
+	if entry.state.IsComplete {
+		entry.done = true
+	}
+	return len(entry.state.MatchResults) - before, err
+}
+
+// reportAllPlaylists prints where every playlist of the account stands after the run
+func (o *Orchestrator) reportAllPlaylists(playlists []*accountPlaylist, used int) {
+	ui.Summaryf("\n📚 All playlists: %d tracks processed this run\n", used)
+	pending := 0
+	for _, entry := range playlists {
+		if entry.state == nil {
+			pending++
+			continue
+		}
+		status := "⏳"
+		if entry.state.IsComplete {
+			status = "✅"
+		}
+		ui.Summaryf("   %s %s: %s\n", status, entry.playlist.Name, entry.state.GetProgress())
+	}
+	if pending > 0 {
+		ui.Summaryf("   %d playlists not started yet, run again to continue\n", pending)
+	}
+}
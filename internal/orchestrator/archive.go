@@ -0,0 +1,76 @@
+package orchestrator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/state"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// manageArchivePlaylists adds matches to the cumulative archive playlist, or to the
+// playlist of the week they were fetched in when the state archives weekly
+func (o *Orchestrator) manageArchivePlaylists(portingState *state.PortingState, newResults []models.MatchResult) error {
+	newResults = o.dedupArchiveResults(portingState, newResults)
+
+	if !portingState.ArchiveWeekly {
+		playlistName := fmt.Sprintf("%s Archive (Ported from Spotify)", portingState.OriginalPlaylist.Name)
+		return o.addToPlaylist(portingState, &portingState.YouTubePlaylistID, &portingState.YouTubePlaylistName,
+			playlistName, newResults)
+	}
+
+	// Group results by week, keeping playlist order inside each week
+	var weekOrder []string
+	groups := make(map[string][]models.MatchResult)
+	for _, result := range newResults {
+		week, ok := portingState.TrackTargets[result.OriginalTrack.ID]
+		if !ok {
+			week = state.ArchiveWeekTarget(time.Now())
+			portingState.AssignTarget(result.OriginalTrack.ID, week)
+		}
+
+		if _, exists := groups[week]; !exists {
+			weekOrder = append(weekOrder, week)
+		}
+		groups[week] = append(groups[week], result)
+	}
+
+	for _, week := range weekOrder {
+		o.writeToLog("Archive week \"%s\": %d tracks", week, len(groups[week]))
+		info := portingState.GetOrAddTarget(week)
+		playlistName := fmt.Sprintf("%s - %s (Ported from Spotify)", portingState.OriginalPlaylist.Name, week)
+		if err := o.addToPlaylist(portingState, &info.YouTubePlaylistID, &info.YouTubePlaylistName,
+			playlistName, groups[week]); err != nil {
+			return fmt.Errorf("archive %s: %w", week, err)
+		}
+	}
+
+	return nil
+}
+
+// dedupArchiveResults drops matches whose video is already in the archive, e.g. a track that
+// reappears in Discover Weekly under another Spotify ID (single vs album release)
+func (o *Orchestrator) dedupArchiveResults(portingState *state.PortingState, results []models.MatchResult) []models.MatchResult {
+	archived := portingState.ArchivedVideoIDs()
+
+	kept := make([]models.MatchResult, 0, len(results))
+	skipped := 0
+	for _, result := range results {
+		if result.Matched && result.MatchedTrack != nil {
+			if archived[result.MatchedTrack.ID] {
+				o.writeToLog("Already archived: \"%s\" (%s)", result.OriginalTrack.Title, result.MatchedTrack.ID)
+				skipped++
+				continue
+			}
+			archived[result.MatchedTrack.ID] = true
+		}
+		kept = append(kept, result)
+	}
+
+	if skipped > 0 {
+		ui.Printf("🗄️  %d tracks are already in the archive, not adding them again\n", skipped)
+	}
+
+	return kept
+}
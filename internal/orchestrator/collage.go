@@ -0,0 +1,137 @@
+package orchestrator
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/collage"
+	"github.com/Verryx-02/PlaylistPorter/internal/spt"
+	"github.com/Verryx-02/PlaylistPorter/internal/state"
+	"github.com/Verryx-02/PlaylistPorter/internal/tubo"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// MakeCollage builds a cover image from the album arts of a saved playlist, writes it to
+// outPath (default covers/playlist_ID.jpg) and optionally sets it as the YouTube playlist image
+func (o *Orchestrator) MakeCollage(sptURL, outPath string, upload bool) error {
+	defer o.Close()
+
+	playlistID, err := o.extractPlaylistID(sptURL)
+	if err != nil {
+		return fmt.Errorf("extracting playlist ID: %w", err)
+	}
+
+	stateManager, err := state.NewManager(o.statesPath())
+	if err != nil {
+		return fmt.Errorf("creating state manager: %w", err)
+	}
+
+	portingState, err := stateManager.LoadState(playlistID)
+	if err != nil {
+		return fmt.Errorf("loading state: %w", err)
+	}
+	if portingState == nil {
+		return fmt.Errorf("no saved state for playlist %s, port it first", playlistID)
+	}
+
+	// States saved before album arts were recorded are filled in from Spotify once
+	if !hasAlbumArt(portingState) {
+		ui.Printf("🎨 Fetching album arts from Spotify...\n")
+		if err := o.backfillAlbumArt(portingState); err != nil {
+			return err
+		}
+		if err := stateManager.SaveState(portingState); err != nil {
+			return fmt.Errorf("saving state: %w", err)
+		}
+	}
+
+	var urls []string
+	for _, track := range portingState.OriginalPlaylist.Tracks {
+		urls = append(urls, track.AlbumArtURL)
+	}
+
+	img, err := collage.Build(urls)
+	if err != nil {
+		return fmt.Errorf("building collage: %w", err)
+	}
+
+	var data bytes.Buffer
+	if err := collage.Encode(&data, img); err != nil {
+		return fmt.Errorf("encoding collage: %w", err)
+	}
+
+	if outPath == "" {
+		outPath = filepath.Join("covers", fmt.Sprintf("playlist_%s.jpg", playlistID))
+	}
+	if err := os.MkdirAll(filepath.Dir(outPath), 0755); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+	if err := os.WriteFile(outPath, data.Bytes(), 0644); err != nil {
+		return fmt.Errorf("writing collage: %w", err)
+	}
+
+	size := img.Bounds().Dx()
+	ui.Printf("🖼️  Cover collage for \"%s\" (%dx%d, %d album arts) written to %s\n",
+		portingState.OriginalPlaylist.Name, size, size, (size/collage.TileSize)*(size/collage.TileSize), outPath)
+
+	if !upload {
+		return nil
+	}
+	if portingState.GetDestination() != DestYouTube {
+		return fmt.Errorf("playlist images can only be uploaded to YouTube")
+	}
+	if portingState.YouTubePlaylistID == "" {
+		return fmt.Errorf("the playlist has no YouTube playlist yet, nothing to upload to")
+	}
+
+	// Sign in with the account that owns the playlist
+	o.cfg.TUBO.Account = portingState.YouTubeAccount
+	tuboClient, err := tubo.NewClient(&o.cfg.TUBO)
+	if err != nil {
+		return fmt.Errorf("creating TUBO client: %w", err)
+	}
+	if err := tuboClient.SetPlaylistThumbnail(portingState.YouTubePlaylistID, data.Bytes()); err != nil {
+		return fmt.Errorf("uploading playlist image: %w", err)
+	}
+	ui.Printf("✅ Set as the image of https://www.youtube.com/playlist?list=%s\n", portingState.YouTubePlaylistID)
+
+	return nil
+}
+
+// hasAlbumArt reports whether any track of the state has an album art URL
+func hasAlbumArt(portingState *state.PortingState) bool {
+	for _, track := range portingState.OriginalPlaylist.Tracks {
+		if track.AlbumArtURL != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// backfillAlbumArt copies album art URLs from the current Spotify playlist into the state's tracks
+func (o *Orchestrator) backfillAlbumArt(portingState *state.PortingState) error {
+	sptClient, err := spt.NewClient(&o.cfg.SPT)
+	if err != nil {
+		return fmt.Errorf("creating SPT client: %w", err)
+	}
+
+	playlist, err := sptClient.GetPlaylist(portingState.SpotifyID)
+	if err != nil {
+		return fmt.Errorf("fetching SPT playlist: %w", err)
+	}
+
+	arts := make(map[string]string)
+	for _, track := range playlist.Tracks {
+		arts[track.ID] = track.AlbumArtURL
+	}
+
+	tracks := portingState.OriginalPlaylist.Tracks
+	for i := range tracks {
+		if tracks[i].AlbumArtURL == "" {
+			tracks[i].AlbumArtURL = arts[tracks[i].ID]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package orchestrator
+
+import (
+	"os/user"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/config"
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/quota"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// openLedger connects to the shared quota ledger when courtesy mode is configured
+func (o *Orchestrator) openLedger() {
+	cfg := o.cfg.Courtesy
+	if cfg.Ledger == "" || o.destinationName() != DestYouTube || o.customDest != nil {
+		return
+	}
+
+	name := cfg.User
+	if name == "" {
+		if current, err := user.Current(); err == nil {
+			name = current.Username
+		}
+	}
+	dailyLimit := cfg.DailyLimit
+	if dailyLimit <= 0 {
+		dailyLimit = o.costs().DailyLimit
+	}
+	o.ledger = quota.OpenLedger(cfg.Ledger, name, dailyLimit, cfg.UserLimit)
+	o.writeToLog("✅ Courtesy mode: sharing quota through %s as %s", cfg.Ledger, name)
+}
+
+// costs returns the quota figures of the config's quota section
+func (o *Orchestrator) costs() quota.Costs {
+	return o.cfg.Quota.Costs()
+}
+
+// trackCost is the quota reserved for one track
+func (o *Orchestrator) trackCost() int {
+	return o.costs().PerTrack(o.cfg.TUBO.SearchBackend == config.SearchYTMusic)
+}
+
+// reserveQuota reserves the budget of a batch in the shared ledger and returns how many of
+// the tracks it covers. Without courtesy mode every track is allowed.
+func (o *Orchestrator) reserveQuota(tracks int) (*quota.Reservation, int, error) {
+	if o.ledger == nil || tracks == 0 {
+		return nil, tracks, nil
+	}
+
+	reservation, err := o.ledger.Reserve(tracks * o.trackCost())
+	if err != nil {
+		return nil, 0, err
+	}
+	allowed := reservation.Units / o.trackCost()
+	o.writeToLog("Courtesy mode: reserved %d units for %d of %d tracks", reservation.Units, allowed, tracks)
+
+	if allowed == 0 {
+		o.ledger.Release(reservation, 0)
+		o.reportSharedQuota()
+		return nil, 0, nil
+	}
+	if allowed < tracks {
+		ui.Printf("🤝 Shared quota: %d units left for you today, processing %d of %d tracks\n",
+			reservation.Units, allowed, tracks)
+	}
+	return reservation, allowed, nil
+}
+
+// releaseQuota returns the unused part of a reservation, counting the searches made and
+// an insert per match
+func (o *Orchestrator) releaseQuota(reservation *quota.Reservation, results []models.MatchResult) {
+	if reservation == nil {
+		return
+	}
+
+	costs := o.costs()
+	used := 0
+	for _, result := range results {
+		used += result.SearchesUsed * costs.Search
+		if result.Matched && o.phase != PhaseMatch {
+			used += costs.Insert
+		}
+	}
+	if err := o.ledger.Release(reservation, used); err != nil {
+		ui.Printf("⚠️  Could not release unused quota in the shared ledger: %v\n", err)
+		return
+	}
+	o.writeToLog("Courtesy mode: used %d of %d reserved units", used, reservation.Units)
+}
+
+// reportSharedQuota explains that the shared budget is used up and who used it
+func (o *Orchestrator) reportSharedQuota() {
+	ui.Summaryf("🤝 The shared quota has no budget left for you today\n")
+	if usage, remaining, err := o.ledger.Usage(); err == nil {
+		for _, entry := range usage {
+			ui.Summaryf("   %s: ~%s units\n", entry.User, ui.FormatCount(entry.Units))
+		}
+		ui.Summaryf("   Left for the project: ~%s units\n", ui.FormatCount(remaining))
+	}
+	ui.Summaryf("📅 Try again after the quota resets: %s\n", quota.ResetMessage(time.Now()))
+}
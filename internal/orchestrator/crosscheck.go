@@ -0,0 +1,150 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/odesli"
+	"github.com/Verryx-02/PlaylistPorter/internal/state"
+	"github.com/Verryx-02/PlaylistPorter/internal/tubo"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// CrossCheck asks Odesli (song.link) whether each matched YouTube video belongs to the same
+// recording as the source track, at most maxTracks per run. Outcomes are stored in the state,
+// so later runs continue with the tracks not checked yet; mismatches are listed for review.
+func (o *Orchestrator) CrossCheck(sptURL string) error {
+	defer o.Close()
+
+	playlistID, err := o.extractPlaylistID(sptURL)
+	if err != nil {
+		return fmt.Errorf("extracting playlist ID: %w", err)
+	}
+
+	// Cross-checking only reads the state and queries Odesli, no sign-in needed
+	stateManager, err := state.NewManager(o.statesPath())
+	if err != nil {
+		return fmt.Errorf("creating state manager: %w", err)
+	}
+
+	portingState, err := stateManager.LoadState(playlistID)
+	if err != nil {
+		return fmt.Errorf("loading state: %w", err)
+	}
+	if portingState == nil {
+		return fmt.Errorf("no saved state for playlist %s, port it first", playlistID)
+	}
+	if portingState.GetDestination() != DestYouTube {
+		return fmt.Errorf("-crosscheck checks YouTube matches; this playlist is ported to %s", portingState.GetDestination())
+	}
+
+	var pending []int
+	unlinked := 0
+	for i, result := range portingState.MatchResults {
+		if !result.Matched || result.MatchedTrack == nil || result.CrossCheck != "" {
+			continue
+		}
+		if sourceTrackURL(result.OriginalTrack) == "" {
+			unlinked++
+			continue
+		}
+		pending = append(pending, i)
+	}
+	if unlinked > 0 {
+		ui.Printf("ℹ️  %d matched tracks have no source link Odesli could resolve, skipped\n", unlinked)
+	}
+	if len(pending) == 0 {
+		ui.Printf("✅ Every matched track of \"%s\" has been cross-checked\n", portingState.OriginalPlaylist.Name)
+		o.reportCrossCheck(portingState)
+		return nil
+	}
+
+	if len(pending) > o.maxTracks {
+		pending = pending[:o.maxTracks]
+	}
+	ui.Printf("🔗 Cross-checking %d matches of \"%s\" with Odesli\n", len(pending), portingState.OriginalPlaylist.Name)
+	if o.cfg.Odesli.APIKey == "" {
+		ui.Printf("   Without odesli.api_key this takes about 6 seconds per track\n")
+	}
+
+	client := odesli.NewClient(o.cfg.Odesli.APIKey)
+	checked := 0
+	for n, i := range pending {
+		if o.outOfTime() {
+			break
+		}
+		result := &portingState.MatchResults[i]
+		ui.Printf("\r🔗 Cross-checking: %d/%d - %s", n+1, len(pending),
+			truncateString(fmt.Sprintf("%s - %s", result.OriginalTrack.Artist, result.OriginalTrack.Title), 40))
+
+		videos, err := client.YouTubeVideos(sourceTrackURL(result.OriginalTrack))
+		if errors.Is(err, odesli.ErrRateLimited) {
+			ui.Printf("\n⏸️  Odesli rate limit reached, run again later to continue\n")
+			break
+		}
+		if err != nil {
+			o.writeToLog("⚠️  Odesli lookup for %s failed: %v", result.OriginalTrack.ID, err)
+			continue
+		}
+
+		applyCrossCheck(result, videos)
+		o.writeToLog("Cross-check %s: %s", result.OriginalTrack.ID, result.CrossCheck)
+		checked++
+	}
+	ui.Printf("\r🔗 Cross-checked %d matches                                          \n", checked)
+
+	if checked > 0 {
+		if err := stateManager.SaveState(portingState); err != nil {
+			return fmt.Errorf("saving state: %w", err)
+		}
+	}
+
+	o.reportCrossCheck(portingState)
+	return nil
+}
+
+// applyCrossCheck records how the matched video compares with the videos Odesli links
+func applyCrossCheck(result *models.MatchResult, videos []string) {
+	result.CrossCheckVideo = ""
+	if len(videos) == 0 {
+		result.CrossCheck = models.CrossCheckUnknown
+		return
+	}
+	for _, video := range videos {
+		if video == result.MatchedTrack.ID {
+			result.CrossCheck = models.CrossCheckConfirmed
+			return
+		}
+	}
+	result.CrossCheck = models.CrossCheckMismatch
+	result.CrossCheckVideo = videos[0]
+}
+
+// reportCrossCheck summarizes the stored cross-check outcomes and lists the mismatches
+func (o *Orchestrator) reportCrossCheck(portingState *state.PortingState) {
+	counts := make(map[string]int)
+	var mismatches []models.MatchResult
+	for _, result := range portingState.MatchResults {
+		if result.CrossCheck == "" {
+			continue
+		}
+		counts[result.CrossCheck]++
+		if result.CrossCheck == models.CrossCheckMismatch {
+			mismatches = append(mismatches, result)
+		}
+	}
+
+	ui.Summaryf("\n🔗 Cross-check: %d confirmed, %d mismatched, %d unknown to Odesli\n",
+		counts[models.CrossCheckConfirmed], counts[models.CrossCheckMismatch], counts[models.CrossCheckUnknown])
+	for _, result := range mismatches {
+		ui.Summaryf("   ⚠️  %s - %s%s\n", result.OriginalTrack.Artist, result.OriginalTrack.Title,
+			noteSuffix(portingState, result.OriginalTrack.ID))
+		ui.Summaryf("      matched: %s (score %.2f)\n", tubo.VideoURL(result.MatchedTrack.ID), result.MatchScore)
+		ui.Summaryf("      Odesli:  %s\n", tubo.VideoURL(result.CrossCheckVideo))
+	}
+	if len(mismatches) > 0 {
+		ui.Summaryf("\n💡 A mismatch is often just another upload of the same song. Compare both links, or review\n")
+		ui.Summaryf("   all matches in the HTML report: playlistporter export -url ... -format html\n")
+	}
+}
@@ -0,0 +1,217 @@
+package orchestrator
+
+import (
+	"fmt"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/config"
+	"github.com/Verryx-02/PlaylistPorter/internal/jellyfin"
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/musicdir"
+	"github.com/Verryx-02/PlaylistPorter/internal/soundcloud"
+	"github.com/Verryx-02/PlaylistPorter/internal/state"
+	"github.com/Verryx-02/PlaylistPorter/internal/tubo"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+	"github.com/Verryx-02/PlaylistPorter/internal/ytmusic"
+)
+
+// Destination services for ported playlists
+const (
+	DestYouTube    = "youtube"
+	DestSoundCloud = "soundcloud"
+	DestSpotify    = "spotify" // Reverse porting, from a YouTube playlist
+	DestJellyfin   = "jellyfin"
+	DestFolder     = "folder" // .m3u8 of matching files in a local music folder
+)
+
+// SetDestination selects the service playlists are ported to (default YouTube)
+func (o *Orchestrator) SetDestination(name string) {
+	o.destName = name
+	o.writeToLog("Destination: %s", o.destinationName())
+}
+
+// destinationName returns the selected destination, defaulting to YouTube
+func (o *Orchestrator) destinationName() string {
+	if o.destName == "" {
+		return DestYouTube
+	}
+	return o.destName
+}
+
+// destinationLabel names the destination in messages
+func (o *Orchestrator) destinationLabel() string {
+	switch o.destinationName() {
+	case DestSoundCloud:
+		return "SoundCloud"
+	case DestSpotify:
+		return "Spotify"
+	case DestJellyfin:
+		return "Jellyfin"
+	case DestFolder:
+		return "music folder"
+	case DestYouTube:
+		return "YouTube"
+	}
+	return o.destinationName() // Custom destination
+}
+
+// matchDirection picks Spotify as the destination of YouTube playlist links
+// and rejects combinations that would port a playlist to its own service
+func (o *Orchestrator) matchDirection(sourceURL string) error {
+	if !tubo.IsYouTubeURL(sourceURL) {
+		if o.destName == DestSpotify {
+			return fmt.Errorf("-dest spotify ports YouTube playlists; pass a https://www.youtube.com/playlist?list=... link")
+		}
+		return nil
+	}
+
+	switch o.destName {
+	case "":
+		o.destName = DestSpotify
+		o.writeToLog("YouTube source, destination: %s", DestSpotify)
+	case DestSpotify:
+	default:
+		return fmt.Errorf("YouTube playlists can only be ported to Spotify (-dest spotify)")
+	}
+	return nil
+}
+
+// initializeDestination creates the client for the selected destination
+func (o *Orchestrator) initializeDestination() error {
+	if o.customDest != nil {
+		o.dest = o.customDest
+		o.cfg.TUBO.PlaceholderVideoID = ""
+		return nil
+	}
+
+	if o.destinationName() == DestSpotify {
+		// The Spotify client was signed in with the user's account by initializeClients
+		o.dest = o.sptClient
+		o.cfg.TUBO.PlaceholderVideoID = ""
+		return nil
+	}
+
+	if o.destinationName() == DestSoundCloud {
+		client, err := soundcloud.NewClient(&o.cfg.SoundCloud)
+		if err != nil {
+			return fmt.Errorf("creating SoundCloud client: %w", err)
+		}
+		o.dest = client
+		o.writeToLog("✅ SoundCloud client initialized")
+
+		// Placeholders are YouTube videos
+		o.cfg.TUBO.PlaceholderVideoID = ""
+		return nil
+	}
+
+	if o.destinationName() == DestJellyfin {
+		client, err := jellyfin.NewClient(&o.cfg.Jellyfin)
+		if err != nil {
+			return fmt.Errorf("creating Jellyfin client: %w", err)
+		}
+		o.dest = client
+		o.writeToLog("✅ Jellyfin client initialized")
+
+		// Placeholders are YouTube videos
+		o.cfg.TUBO.PlaceholderVideoID = ""
+		return nil
+	}
+
+	if o.destinationName() == DestFolder {
+		ui.Printf("📂 Scanning %s...\n", o.cfg.MusicFolder.Path)
+		library, err := musicdir.Open(&o.cfg.MusicFolder)
+		if err != nil {
+			return fmt.Errorf("opening music folder: %w", err)
+		}
+		o.dest = library
+		o.writeToLog("✅ Music folder indexed: %d files", library.Len())
+
+		// Placeholders are YouTube videos
+		o.cfg.TUBO.PlaceholderVideoID = ""
+		return nil
+	}
+
+	if err := o.initializeTubo(); err != nil {
+		return err
+	}
+	o.dest = o.tuboClient
+
+	if o.cfg.TUBO.SearchBackend == config.SearchYTMusic {
+		search := ytmusic.NewClient(&o.cfg.TUBO)
+		if o.logger != nil {
+			search.SetLogger(o.logger)
+		}
+		search.SetMatching(o.cfg.Matching.Params())
+		o.dest = ytmusicDestination{Client: o.tuboClient, search: search}
+		o.writeToLog("✅ Searching with YouTube Music (no Data API quota)")
+	}
+	return nil
+}
+
+// ytmusicDestination writes playlists with the YouTube Data API but searches YouTube Music
+type ytmusicDestination struct {
+	*tubo.Client
+	search *ytmusic.Client
+}
+
+// SearchTrackOutcome searches YouTube Music instead of the Data API
+func (d ytmusicDestination) SearchTrackOutcome(track models.Track) (*models.SearchOutcome, error) {
+	return d.search.SearchTrackOutcome(track)
+}
+
+// requireYouTube fails for features that only exist for YouTube playlists
+func (o *Orchestrator) requireYouTube(feature string) error {
+	if o.destinationName() != DestYouTube || o.customDest != nil {
+		return fmt.Errorf("%s is only available for YouTube playlists", feature)
+	}
+	return nil
+}
+
+// checkDestination makes sure a state is ported to the destination selected for this run
+func (o *Orchestrator) checkDestination(portingState *state.PortingState) error {
+	if portingState.GetDestination() != o.destinationName() {
+		return fmt.Errorf("this playlist is being ported to %s; pass -dest %s to continue it",
+			portingState.GetDestination(), portingState.GetDestination())
+	}
+	if portingState.GetDestination() == DestYouTube && portingState.YouTubeAccount != o.cfg.TUBO.Account {
+		if portingState.YouTubeAccount == "" {
+			return fmt.Errorf("this playlist belongs to the default YouTube account; run it without -account")
+		}
+		return fmt.Errorf("this playlist belongs to YouTube account %q; pass -account %s to continue it",
+			portingState.YouTubeAccount, portingState.YouTubeAccount)
+	}
+	return nil
+}
+
+// SetAccount selects the YouTube account playlists are written with; empty keeps the configured one
+func (o *Orchestrator) SetAccount(name string) {
+	if name != "" {
+		o.cfg.TUBO.Account = name
+	}
+	if o.cfg.TUBO.Account != "" {
+		o.writeToLog("YouTube account: %s", o.cfg.TUBO.Account)
+	}
+}
+
+// stateAccount is the YouTube account recorded in new states
+func (o *Orchestrator) stateAccount() string {
+	if o.destinationName() != DestYouTube {
+		return ""
+	}
+	return o.cfg.TUBO.Account
+}
+
+// stateDestination is the value recorded in new states ("" keeps YouTube states unchanged)
+func (o *Orchestrator) stateDestination() string {
+	if o.destinationName() == DestYouTube {
+		return ""
+	}
+	return o.destinationName()
+}
+
+// playlistURL links to a destination playlist
+func (o *Orchestrator) playlistURL(playlistID string) string {
+	if o.dest != nil {
+		return o.dest.PlaylistURL(playlistID)
+	}
+	return fmt.Sprintf("https://www.youtube.com/playlist?list=%s", playlistID)
+}
@@ -0,0 +1,176 @@
+package orchestrator
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/export"
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/spt"
+	"github.com/Verryx-02/PlaylistPorter/internal/state"
+	"github.com/Verryx-02/PlaylistPorter/internal/tidal"
+	"github.com/Verryx-02/PlaylistPorter/internal/tubo"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// ExportPlaylist writes the source tracks of a saved state with their matches and scores
+// to outPath (default exports/playlist_ID.<format>) in one of the export formats
+func (o *Orchestrator) ExportPlaylist(sptURL, format, outPath string) error {
+	defer o.Close()
+
+	format = strings.ToLower(format)
+	if !export.IsFormat(format) {
+		return fmt.Errorf("unknown export format %q (use %s)", format, strings.Join(export.Formats, ", "))
+	}
+
+	playlistID, err := o.extractPlaylistID(sptURL)
+	if err != nil {
+		return fmt.Errorf("extracting playlist ID: %w", err)
+	}
+
+	// Exporting only reads the saved state, no API clients needed
+	stateManager, err := state.NewManager(o.statesPath())
+	if err != nil {
+		return fmt.Errorf("creating state manager: %w", err)
+	}
+
+	portingState, err := stateManager.LoadState(playlistID)
+	if err != nil {
+		return fmt.Errorf("loading state: %w", err)
+	}
+	if portingState == nil {
+		return fmt.Errorf("no saved state for playlist %s, port it first", playlistID)
+	}
+
+	if outPath == "" {
+		outPath = filepath.Join("exports", fmt.Sprintf("playlist_%s%s", playlistID, export.Extension(format)))
+	}
+	playlist, err := writeExport(portingState, format, outPath)
+	if err != nil {
+		return err
+	}
+
+	matched := 0
+	for _, entry := range playlist.Entries {
+		if entry.Matched {
+			matched++
+		}
+	}
+	ui.Printf("📤 Exported \"%s\" as %s (%d tracks, %d matched) to %s\n",
+		playlist.Name, strings.ToUpper(format), len(playlist.Entries), matched, outPath)
+	return nil
+}
+
+// writeExport writes a state to outPath in one of the export formats
+func writeExport(portingState *state.PortingState, format, outPath string) (*export.Playlist, error) {
+	playlist := exportedPlaylist(portingState)
+	var data bytes.Buffer
+	if err := export.Write(&data, format, playlist); err != nil {
+		return nil, fmt.Errorf("exporting playlist: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outPath), 0755); err != nil {
+		return nil, fmt.Errorf("creating output directory: %w", err)
+	}
+	if err := os.WriteFile(outPath, data.Bytes(), 0644); err != nil {
+		return nil, fmt.Errorf("writing export: %w", err)
+	}
+	return playlist, nil
+}
+
+// exportedPlaylist lists the tracks of a state in playlist order with their match results
+func exportedPlaylist(portingState *state.PortingState) *export.Playlist {
+	destination := portingState.GetDestination()
+	playlist := &export.Playlist{
+		Name:        portingState.OriginalPlaylist.Name,
+		Description: portingState.OriginalPlaylist.Description,
+		SourceURL:   portingState.SpotifyURL,
+		Destination: destination,
+	}
+	if portingState.YouTubePlaylistID != "" {
+		playlist.PlaylistURL = destinationPlaylistURL(destination, portingState.YouTubePlaylistID)
+	}
+
+	results := make(map[string]int, len(portingState.MatchResults))
+	for i, result := range portingState.MatchResults {
+		results[result.OriginalTrack.ID] = i
+	}
+
+	for i, track := range portingState.OriginalPlaylist.Tracks {
+		entry := export.Entry{
+			Position:    i + 1,
+			Title:       track.Title,
+			Artist:      track.Artist,
+			Album:       track.Album,
+			Duration:    int(track.Duration.Seconds()),
+			Year:        track.ReleaseYear,
+			ISRC:        track.ISRC,
+			SourceID:    track.ID,
+			SourceURL:   sourceTrackURL(track),
+			AlbumArtURL: track.AlbumArtURL,
+			Note:        portingState.TrackNotes[track.ID],
+		}
+		if index, ok := results[track.ID]; ok {
+			result := portingState.MatchResults[index]
+			if result.Matched && result.MatchedTrack != nil {
+				entry.Matched = true
+				entry.MatchURL = destinationTrackURL(destination, result.MatchedTrack.ID)
+				entry.MatchTitle = result.MatchedTrack.Title
+				entry.MatchArtist = result.MatchedTrack.Artist
+				entry.Score = result.MatchScore
+				entry.Strategy = result.Strategy
+				entry.CrossCheck = result.CrossCheck
+				if result.CrossCheckVideo != "" {
+					entry.OdesliURL = destinationTrackURL(destination, result.CrossCheckVideo)
+				}
+			}
+		}
+		playlist.Entries = append(playlist.Entries, entry)
+	}
+	return playlist
+}
+
+// destinationTrackURL links to a matched track; custom destinations only have the bare ID
+func destinationTrackURL(destination, trackID string) string {
+	switch destination {
+	case DestYouTube:
+		return tubo.VideoURL(trackID)
+	case DestSpotify:
+		return spt.TrackURL(trackID)
+	case DestSoundCloud:
+		return "https://api.soundcloud.com/tracks/" + trackID
+	}
+	return trackID
+}
+
+// sourceTrackURL links to a track on its source service. States saved before links were
+// stored get them from the track ID where the service allows it.
+func sourceTrackURL(track models.Track) string {
+	switch {
+	case track.SourceURL != "":
+		return track.SourceURL
+	case strings.HasPrefix(track.ID, tidal.IDPrefix):
+		return tidal.TrackURL(strings.TrimPrefix(track.ID, tidal.IDPrefix))
+	case strings.HasPrefix(track.ID, tubo.IDPrefix):
+		return tubo.VideoURL(strings.TrimPrefix(track.ID, tubo.IDPrefix))
+	case spt.IsSpotifyID(track.ID):
+		return spt.TrackURL(track.ID)
+	}
+	return ""
+}
+
+// destinationPlaylistURL links to a destination playlist of a saved state
+func destinationPlaylistURL(destination, playlistID string) string {
+	switch destination {
+	case DestYouTube:
+		return "https://www.youtube.com/playlist?list=" + playlistID
+	case DestSpotify:
+		return "https://open.spotify.com/playlist/" + playlistID
+	case DestSoundCloud:
+		return "https://api.soundcloud.com/playlists/" + playlistID
+	}
+	return ""
+}
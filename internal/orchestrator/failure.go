@@ -0,0 +1,113 @@
+package orchestrator
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/tubo"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// What happens when a searched track fails to match (-on-failure)
+const (
+	FailureContinue = "continue" // Record the failure and go on (default)
+	FailurePause    = "pause"    // Ask on the terminal: pick a video, leave it unmatched, or abort
+	FailureAbort    = "abort"    // Stop the batch at the failure; the rest stays pending
+)
+
+// strategyManual marks matches picked by hand when a failure paused the batch
+const strategyManual = "manual"
+
+// SetFailurePolicy selects what happens when a searched track fails to match. Pausing needs a
+// terminal to ask on; without one failures are recorded and the batch goes on.
+func (o *Orchestrator) SetFailurePolicy(policy string) {
+	if policy == FailurePause && !ui.IsTerminal(os.Stdin) {
+		ui.Printf("⚠️  -on-failure pause needs a terminal; failed matches are recorded and the batch goes on\n")
+		policy = FailureContinue
+	}
+	o.failurePolicy = policy
+	if policy != "" && policy != FailureContinue {
+		o.writeToLog("On failure: %s", policy)
+	}
+}
+
+// resolveFailure applies the failure policy to a track that was searched but didn't match.
+// Pausing may turn the result into a match picked by hand. It reports whether to stop the batch.
+func (o *Orchestrator) resolveFailure(result *models.MatchResult) bool {
+	switch o.failurePolicy {
+	case FailureAbort:
+		o.abortOnFailure(result)
+		return true
+	case FailurePause:
+		return o.pauseOnFailure(result)
+	}
+	return false
+}
+
+// abortOnFailure stops the batch at a failed track; its result is kept for investigation
+func (o *Orchestrator) abortOnFailure(result *models.MatchResult) {
+	o.budgetExhausted = true
+	o.failedAt = fmt.Sprintf("%s - %s", result.OriginalTrack.Artist, result.OriginalTrack.Title)
+	if result.Trace != "" {
+		o.failedAt += fmt.Sprintf(" (trace %s)", result.Trace)
+	}
+	o.writeToLog("🛑 Stopping the batch at a failed match (-on-failure abort)")
+}
+
+// pauseOnFailure shows a failed track and asks what to do: a pasted video URL or ID becomes
+// its match (YouTube only), Enter leaves it unmatched, and "a" aborts the batch
+func (o *Orchestrator) pauseOnFailure(result *models.MatchResult) bool {
+	track := result.OriginalTrack
+	ui.Promptf("\n\n⏸️  No match for \"%s\" by %s\n", track.Title, track.Artist)
+	if result.Error != "" {
+		ui.Promptf("   %s\n", result.Error)
+	}
+	if link := sourceTrackURL(track); link != "" {
+		ui.Promptf("   Source: %s\n", link)
+	}
+
+	pickable := o.customDest == nil && o.destinationName() == DestYouTube
+	in := bufio.NewReader(os.Stdin)
+	for {
+		if pickable {
+			ui.Promptf("   Paste a YouTube video URL or ID to use, press Enter to leave it unmatched, or a to abort: ")
+		} else {
+			ui.Promptf("   Press Enter to leave it unmatched, or a to abort: ")
+		}
+		answer, err := in.ReadString('\n')
+		answer = strings.TrimSpace(answer)
+		if err != nil && answer == "" {
+			ui.Promptf("\n")
+			return false
+		}
+
+		switch strings.ToLower(answer) {
+		case "":
+			o.writeToLog("⏸️  Left unmatched after pausing")
+			return false
+		case "a", "abort":
+			o.abortOnFailure(result)
+			return true
+		}
+
+		if !pickable {
+			continue
+		}
+		videoID, err := tubo.VideoIDFromURL(answer)
+		if err != nil {
+			ui.Promptf("   ⚠️  %v\n", err)
+			continue
+		}
+		result.MatchedTrack = &models.Track{ID: videoID, Title: track.Title, Artist: track.Artist}
+		result.MatchScore = 1
+		result.Matched = true
+		result.Strategy = strategyManual
+		result.Error = ""
+		o.writeToLog("✋ Matched by hand to video %s", videoID)
+		ui.Promptf("   ✅ Using %s\n", tubo.VideoURL(videoID))
+		return false
+	}
+}
@@ -0,0 +1,53 @@
+package orchestrator
+
+import (
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/acoustid"
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/tubo"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// verifyFingerprint checks a low-confidence YouTube match by its audio. It returns false when
+// AcoustID identifies the video as a different recording than the source ISRC; every other
+// outcome, including errors, keeps the match.
+func (o *Orchestrator) verifyFingerprint(result *models.MatchResult) bool {
+	if o.fingerprints == nil || o.destinationName() != DestYouTube ||
+		result.MatchScore >= ui.LowConfidenceScore || result.OriginalTrack.ISRC == "" {
+		return true
+	}
+
+	result.Fingerprint = o.fingerprintVerdict(result)
+	return result.Fingerprint != acoustid.Rejected
+}
+
+// fingerprintVerdict compares the audio of the matched video with the recordings of the source
+// ISRC: acoustid.Verified or acoustid.Rejected, or "" when it can't tell
+func (o *Orchestrator) fingerprintVerdict(result *models.MatchResult) string {
+	start := time.Now()
+	defer o.addStageTime(stageScore, start)
+
+	recording, err := o.musicbrainz.Lookup(result.OriginalTrack.ISRC)
+	if err != nil {
+		o.writeToLog("⚠️  Fingerprint check skipped, MusicBrainz lookup failed: %v", err)
+		return ""
+	}
+	if recording == nil || len(recording.IDs) == 0 {
+		o.writeToLog("Fingerprint check skipped, ISRC %s has no MusicBrainz recording", result.OriginalTrack.ISRC)
+		return ""
+	}
+
+	verdict, err := o.fingerprints.Verify(tubo.VideoURL(result.MatchedTrack.ID),
+		result.OriginalTrack.Duration, recording.IDs)
+	if err != nil {
+		o.writeToLog("⚠️  Fingerprint check failed: %v", err)
+		return ""
+	}
+
+	o.writeToLog("🔊 Fingerprint check: %s", verdict)
+	if verdict == acoustid.Unknown {
+		return ""
+	}
+	return verdict
+}
@@ -0,0 +1,211 @@
+package orchestrator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/badge"
+	"github.com/Verryx-02/PlaylistPorter/internal/config"
+	"github.com/Verryx-02/PlaylistPorter/internal/export"
+	"github.com/Verryx-02/PlaylistPorter/internal/state"
+	"github.com/Verryx-02/PlaylistPorter/internal/traffic"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// hookTimeout bounds a webhook request or a hook command
+const hookTimeout = time.Minute
+
+// completionSummary is the JSON body sent by webhook hooks
+type completionSummary struct {
+	Event          string    `json:"event"`
+	PlaylistID     string    `json:"playlist_id"`
+	Name           string    `json:"name"`
+	SourceURL      string    `json:"source_url"`
+	Destination    string    `json:"destination"`
+	DestinationURL string    `json:"destination_url,omitempty"`
+	TotalTracks    int       `json:"total_tracks"`
+	Matched        int       `json:"matched"`
+	Failed         int       `json:"failed"`
+	Sessions       int       `json:"sessions"`
+	CompletedAt    time.Time `json:"completed_at"`
+}
+
+// runCompletionHooks runs the on_complete hooks the first time a playlist is complete with
+// every match uploaded. A failing hook is reported and doesn't stop the others; the hooks
+// are not run again for the playlist either way.
+func (o *Orchestrator) runCompletionHooks(portingState *state.PortingState) {
+	if len(o.cfg.OnComplete) == 0 || !portingState.IsComplete || portingState.IsArchive() ||
+		!portingState.CompletionHooksRunAt.IsZero() || len(portingState.GetPendingUploads()) > 0 {
+		return
+	}
+
+	ui.Printf("\n🪝 Running %d completion hook(s)...\n", len(o.cfg.OnComplete))
+	for _, hook := range o.cfg.OnComplete {
+		if err := o.runCompletionHook(hook, portingState); err != nil {
+			ui.Printf("   ⚠️  %s failed: %v\n", hook.Action, err)
+			o.writeToLog("Completion hook %s failed: %v", hook.Action, err)
+			continue
+		}
+		o.writeToLog("Completion hook %s done", hook.Action)
+	}
+
+	portingState.CompletionHooksRunAt = time.Now()
+	if err := o.stateManager.SaveState(portingState); err != nil {
+		ui.Printf("⚠️  Could not save state: %v\n", err)
+	}
+}
+
+// runCompletionHook runs one hook
+func (o *Orchestrator) runCompletionHook(hook config.CompletionHook, portingState *state.PortingState) error {
+	switch hook.Action {
+	case config.HookSetPublic, config.HookSetUnlisted, config.HookSetPrivate:
+		return o.setPortedPrivacy(portingState, strings.TrimPrefix(hook.Action, "set_"))
+	case config.HookWebhook:
+		return o.sendCompletionWebhook(hook.URL, portingState)
+	case config.HookReport:
+		return o.writeCompletionReport(hook.Path, portingState)
+	case config.HookCommand:
+		return o.runHookCommand(hook.Command, portingState)
+	}
+	return fmt.Errorf("unknown action %q", hook.Action)
+}
+
+// setPortedPrivacy changes the privacy of the playlist and its split targets on YouTube
+func (o *Orchestrator) setPortedPrivacy(portingState *state.PortingState, privacy string) error {
+	if o.tuboClient == nil || portingState.GetDestination() != DestYouTube {
+		return fmt.Errorf("privacy can only be changed for YouTube playlists")
+	}
+
+	playlistIDs := []string{portingState.YouTubePlaylistID}
+	for _, target := range portingState.Targets {
+		playlistIDs = append(playlistIDs, target.YouTubePlaylistID)
+	}
+	for _, playlistID := range playlistIDs {
+		if playlistID == "" {
+			continue
+		}
+		if err := o.tuboClient.SetPlaylistPrivacy(playlistID, privacy); err != nil {
+			return err
+		}
+		ui.Printf("   🔓 %s is now %s\n", o.playlistURL(playlistID), privacy)
+	}
+	return nil
+}
+
+// sendCompletionWebhook posts a JSON summary of the finished playlist
+func (o *Orchestrator) sendCompletionWebhook(url string, portingState *state.PortingState) error {
+	summary := completionSummary{
+		Event:          "playlist_complete",
+		PlaylistID:     portingState.SpotifyID,
+		Name:           portingState.OriginalPlaylist.Name,
+		SourceURL:      portingState.SpotifyURL,
+		Destination:    portingState.GetDestination(),
+		DestinationURL: o.hookPlaylistURL(portingState),
+		TotalTracks:    portingState.TotalTracks,
+		Sessions:       len(portingState.Sessions),
+		CompletedAt:    time.Now(),
+	}
+	for _, result := range portingState.MatchResults {
+		if result.Matched {
+			summary.Matched++
+		} else {
+			summary.Failed++
+		}
+	}
+
+	body, err := json.Marshal(summary)
+	if err != nil {
+		return fmt.Errorf("encoding summary: %w", err)
+	}
+	resp, err := traffic.NewClient(hookTimeout).Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("posting webhook: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	ui.Printf("   📨 Webhook sent\n")
+	return nil
+}
+
+// writeCompletionReport exports the playlist in the format named by the path's extension
+func (o *Orchestrator) writeCompletionReport(path string, portingState *state.PortingState) error {
+	path = o.expandHookPlaceholders(path, portingState, true)
+	format := export.FormatForExtension(filepath.Ext(path))
+	if format == "" {
+		return fmt.Errorf("can't tell the report format from %s (use %s)", path, strings.Join(export.Formats, ", "))
+	}
+	if _, err := writeExport(portingState, format, path); err != nil {
+		return err
+	}
+	ui.Printf("   📤 Report written to %s\n", path)
+	return nil
+}
+
+// runHookCommand runs a program with the placeholders of its arguments filled in
+func (o *Orchestrator) runHookCommand(command []string, portingState *state.PortingState) error {
+	args := make([]string, len(command))
+	for i, arg := range command {
+		args[i] = o.expandHookPlaceholders(arg, portingState, false)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, args[0], args[1:]...).CombinedOutput()
+	if len(output) > 0 {
+		o.writeToLog("Hook command output: %s", strings.TrimSpace(string(output)))
+	}
+	if err != nil {
+		return fmt.Errorf("running %s: %w", args[0], err)
+	}
+	ui.Printf("   ⚙️  Ran %s\n", args[0])
+	return nil
+}
+
+// expandHookPlaceholders fills in {id}, {name}, {url} and {source}; names used in paths
+// have characters that aren't allowed in file names replaced
+func (o *Orchestrator) expandHookPlaceholders(s string, portingState *state.PortingState, inPath bool) string {
+	name := portingState.OriginalPlaylist.Name
+	if inPath {
+		name = strings.Map(func(r rune) rune {
+			if strings.ContainsRune(`/\:*?"<>|`, r) {
+				return '_'
+			}
+			return r
+		}, name)
+	}
+
+	return strings.NewReplacer(
+		"{id}", portingState.SpotifyID,
+		"{name}", name,
+		"{url}", o.hookPlaylistURL(portingState),
+		"{source}", portingState.SpotifyURL,
+	).Replace(s)
+}
+
+// hookPlaylistURL links to the ported playlist, "" when none was created
+func (o *Orchestrator) hookPlaylistURL(portingState *state.PortingState) string {
+	if portingState.YouTubePlaylistID == "" {
+		return ""
+	}
+	return o.playlistURL(portingState.YouTubePlaylistID)
+}
+
+// writeBadge refreshes the published status badge of a playlist when badge_dir is set
+func (o *Orchestrator) writeBadge(portingState *state.PortingState) {
+	if o.cfg.BadgeDir == "" {
+		return
+	}
+	if _, err := badge.Write(o.cfg.BadgeDir, portingState); err != nil {
+		ui.Printf("⚠️  Could not write status badge: %v\n", err)
+		return
+	}
+	o.writeToLog("Status badge written to %s", o.cfg.BadgeDir)
+}
@@ -0,0 +1,228 @@
+package orchestrator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/acoustid"
+	"github.com/Verryx-02/PlaylistPorter/internal/mapfile"
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/state"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// strategyMapping marks matches taken from an imported mapping instead of a search
+const strategyMapping = "mapping"
+
+// ImportMappings adds the matches of shared mapping files to the local mappings, which later
+// searches use before spending quota. Files disagreeing on a track are merged by majority;
+// existing mappings are kept unless replace is set.
+func (o *Orchestrator) ImportMappings(mapPaths []string, replace bool) error {
+	defer o.Close()
+
+	var files [][]mapfile.Entry
+	var names []string
+	for _, path := range mapPaths {
+		entries, invalid, err := mapfile.Read(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		ui.Printf("📄 %s: %d mappings\n", filepath.Base(path), len(entries))
+		if invalid > 0 {
+			ui.Printf("   ⚠️  %d rows skipped because of a malformed Spotify track or YouTube video ID\n", invalid)
+		}
+		files = append(files, entries)
+		names = append(names, filepath.Base(path))
+	}
+	entries, conflicts := mapfile.Merge(files)
+	if conflicts > 0 {
+		ui.Printf("⚖️  %d tracks map to different videos in different files; the video most files agree on was taken\n", conflicts)
+	}
+	origin := strings.Join(names, ", ")
+
+	// Importing only touches the saved mappings, no API clients needed
+	stateManager, err := state.NewManager(o.statesPath())
+	if err != nil {
+		return fmt.Errorf("creating state manager: %w", err)
+	}
+	mappings, err := stateManager.LoadMappings()
+	if err != nil {
+		return err
+	}
+
+	added, updated, kept, unchanged := 0, 0, 0, 0
+	now := time.Now()
+	for _, entry := range entries {
+		existing := mappings.Lookup(entry.TrackID)
+		switch {
+		case existing == nil:
+			added++
+		case existing.VideoID == entry.VideoID:
+			unchanged++
+			continue
+		case !replace:
+			kept++
+			continue
+		default:
+			updated++
+		}
+		mappings.Set(entry.TrackID, state.TrackMapping{
+			VideoID:    entry.VideoID,
+			Artist:     entry.Artist,
+			Title:      entry.Title,
+			Origin:     origin,
+			ImportedAt: now,
+		})
+	}
+
+	if added+updated > 0 {
+		if err := stateManager.SaveMappings(mappings); err != nil {
+			return err
+		}
+	}
+
+	ui.Printf("🗺️  Imported %d new mappings, replaced %d, %d already known\n", added, updated, unchanged)
+	if kept > 0 {
+		ui.Printf("   %d tracks already map to another video and were kept (use -replace to take the imported ones)\n", kept)
+	}
+	ui.Printf("📚 %d mappings in total; they're used instead of searching when porting to YouTube\n", len(mappings.Tracks))
+	return nil
+}
+
+// DefaultMappingMinScore is the score from which an unconfirmed match is exported as a mapping
+const DefaultMappingMinScore = 0.9
+
+// ExportMappings writes the trustworthy YouTube matches of all saved states (only those
+// tagged tag, if set) to a shareable mapping file. A match qualifies with a score of at least
+// minScore, or when Odesli or the audio fingerprint confirmed it; mismatches, rejections and
+// imported mappings are left out. Only track and video IDs, artists and titles are written.
+func (o *Orchestrator) ExportMappings(path string, minScore float64, tag string) error {
+	defer o.Close()
+
+	// Exporting only reads the saved states, no API clients needed
+	stateManager, err := state.NewManager(o.statesPath())
+	if err != nil {
+		return fmt.Errorf("creating state manager: %w", err)
+	}
+	files, err := stateManager.ListStates()
+	if err != nil {
+		return err
+	}
+
+	type pick struct {
+		entry    mapfile.Entry
+		verified bool
+		score    float64
+	}
+	picks := make(map[string]pick)
+	playlists := 0
+	for _, name := range files {
+		portingState, err := stateManager.LoadStateFile(name)
+		if err != nil {
+			o.writeToLog("Skipping unreadable state %s: %v", name, err)
+			continue
+		}
+		if portingState.GetDestination() != DestYouTube || (tag != "" && !portingState.HasTag(tag)) {
+			continue
+		}
+		playlists++
+
+		for _, result := range portingState.MatchResults {
+			if !shareableMatch(result, minScore) {
+				continue
+			}
+			candidate := pick{
+				entry: mapfile.Entry{
+					TrackID: result.OriginalTrack.ID,
+					VideoID: result.MatchedTrack.ID,
+					Artist:  result.OriginalTrack.Artist,
+					Title:   result.OriginalTrack.Title,
+				},
+				verified: confirmedMatch(result),
+				score:    result.MatchScore,
+			}
+			if !mapfile.Valid(candidate.entry) {
+				continue // Not a Spotify track, e.g. from TIDAL or a playlist file
+			}
+
+			// The same track in several playlists: a confirmed match beats a higher score
+			existing, ok := picks[candidate.entry.TrackID]
+			if !ok || candidate.verified && !existing.verified ||
+				candidate.verified == existing.verified && candidate.score > existing.score {
+				picks[candidate.entry.TrackID] = candidate
+			}
+		}
+	}
+
+	entries := make([]mapfile.Entry, 0, len(picks))
+	verified := 0
+	for _, p := range picks {
+		entries = append(entries, p.entry)
+		if p.verified {
+			verified++
+		}
+	}
+	if len(entries) == 0 {
+		ui.Printf("🗺️  No matches of %d YouTube playlists qualify (score %.2f+ or confirmed), nothing exported\n", playlists, minScore)
+		return nil
+	}
+	if err := mapfile.Write(path, entries); err != nil {
+		return err
+	}
+
+	ui.Printf("🗺️  Exported %d matches from %d playlists to %s (%d confirmed by Odesli or fingerprint)\n",
+		len(entries), playlists, path, verified)
+	ui.Printf("💡 Share the file; others import it with: playlistporter mappings -import %s\n", filepath.Base(path))
+	return nil
+}
+
+// shareableMatch reports whether a match may go into an exported mapping file
+func shareableMatch(result models.MatchResult, minScore float64) bool {
+	if !result.Matched || result.MatchedTrack == nil || result.Strategy == strategyMapping {
+		return false // Imported mappings are re-shared by whoever verified them
+	}
+	if result.CrossCheck == models.CrossCheckMismatch || result.Fingerprint == acoustid.Rejected {
+		return false
+	}
+	return result.MatchScore >= minScore || confirmedMatch(result)
+}
+
+// confirmedMatch reports whether Odesli or the audio fingerprint confirmed a match
+func confirmedMatch(result models.MatchResult) bool {
+	return result.CrossCheck == models.CrossCheckConfirmed || result.Fingerprint == acoustid.Verified
+}
+
+// loadMappings returns the imported mappings when they apply to the destination, nil otherwise
+func (o *Orchestrator) loadMappings() *state.TrackMappings {
+	if o.customDest != nil || o.destinationName() != DestYouTube {
+		return nil
+	}
+	mappings, err := o.stateManager.LoadMappings()
+	if err != nil {
+		o.writeToLog("⚠️  Could not load mappings: %v", err)
+		return nil
+	}
+	if len(mappings.Tracks) == 0 {
+		return nil
+	}
+	return mappings
+}
+
+// mappedResult builds the match of a track from its imported mapping, carrying the trace ID of
+// its lines in the log
+func mappedResult(track models.Track, mapping *state.TrackMapping, trace string) models.MatchResult {
+	return models.MatchResult{
+		OriginalTrack: track,
+		MatchedTrack: &models.Track{
+			ID:     mapping.VideoID,
+			Title:  track.Title,
+			Artist: track.Artist,
+		},
+		MatchScore: 1,
+		Matched:    true,
+		Strategy:   strategyMapping,
+		Trace:      trace,
+	}
+}
@@ -0,0 +1,47 @@
+package orchestrator
+
+import (
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+)
+
+// musicbrainzCacheFile keeps ISRC lookups between sessions, next to the states
+const musicbrainzCacheFile = "musicbrainz_cache.json"
+
+// enrichTrack adds the canonical MusicBrainz spellings of a track as search variants.
+// Tracks without an ISRC, or already enriched in an earlier session, are left alone;
+// lookup errors only cost the extra strategies.
+func (o *Orchestrator) enrichTrack(track *models.Track) {
+	if o.musicbrainz == nil || !o.cfg.MusicBrainz.Enabled || track.ISRC == "" || len(track.Variants) > 0 {
+		return
+	}
+
+	start := time.Now()
+	defer o.addStageTime(stageNormalize, start)
+
+	recording, err := o.musicbrainz.Lookup(track.ISRC)
+	if err != nil {
+		o.writeToLog("⚠️  MusicBrainz lookup failed: %v", err)
+		return
+	}
+	if recording == nil {
+		o.writeToLog("MusicBrainz: ISRC %s not found", track.ISRC)
+		return
+	}
+
+	track.Variants = recording.Variants(*track)
+	for _, variant := range track.Variants {
+		o.writeToLog("MusicBrainz variant: \"%s\" by \"%s\"", variant.Title, variant.Artist)
+	}
+}
+
+// saveMusicBrainzCache stores the lookups made in this session
+func (o *Orchestrator) saveMusicBrainzCache() {
+	if o.musicbrainz == nil {
+		return
+	}
+	if err := o.musicbrainz.Save(); err != nil {
+		o.writeToLog("⚠️  Could not save MusicBrainz cache: %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package orchestrator
+
+import (
+	"fmt"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/state"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// AnnotateTrack stores a note on a track of a saved state; trackRef is a Spotify
+// track ID or a 1-based position, and an empty note removes the annotation
+func (o *Orchestrator) AnnotateTrack(sptURL, trackRef, note string) error {
+	defer o.Close()
+
+	playlistID, err := o.extractPlaylistID(sptURL)
+	if err != nil {
+		return fmt.Errorf("extracting playlist ID: %w", err)
+	}
+
+	// Annotating only touches the saved state, no API clients needed
+	stateManager, err := state.NewManager(o.statesPath())
+	if err != nil {
+		return fmt.Errorf("creating state manager: %w", err)
+	}
+
+	portingState, err := stateManager.LoadState(playlistID)
+	if err != nil {
+		return fmt.Errorf("loading state: %w", err)
+	}
+	if portingState == nil {
+		return fmt.Errorf("no saved state for playlist %s, port it first", playlistID)
+	}
+
+	index, ok := portingState.FindTrack(trackRef)
+	if !ok {
+		return fmt.Errorf("track %s not found (use a Spotify track ID or a position from 1 to %d)",
+			trackRef, len(portingState.OriginalPlaylist.Tracks))
+	}
+	track := portingState.OriginalPlaylist.Tracks[index]
+
+	portingState.SetNote(track.ID, note)
+	if err := stateManager.SaveState(portingState); err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+
+	if note == "" {
+		ui.Printf("📝 Removed the note on #%d %s - %s\n", index+1, track.Artist, track.Title)
+		return nil
+	}
+
+	ui.Printf("📝 #%d %s - %s: %s\n", index+1, track.Artist, track.Title, portingState.GetNote(track.ID))
+	if state.IsSkipNote(note) {
+		if portingState.ProcessedTrackIDs[track.ID] {
+			ui.Printf("   Already processed; the note only affects -retry-failed\n")
+		} else {
+			ui.Printf("   The track will be left out when it's reached\n")
+		}
+	}
+	return nil
+}
+
+// noteSuffix formats a track's note for appending to a report line
+func noteSuffix(portingState *state.PortingState, trackID string) string {
+	if note := portingState.GetNote(trackID); note != "" {
+		return fmt.Sprintf(" 📝 %s", note)
+	}
+	return ""
+}
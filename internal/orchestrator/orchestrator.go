@@ -1,18 +1,54 @@
 package orchestrator
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"time"
 
-	"playlistporter/internal/config"
-	"playlistporter/internal/models"
-	"playlistporter/internal/processor"
-	"playlistporter/internal/spt"
-	"playlistporter/internal/state"
-	"playlistporter/internal/tubo"
+	"github.com/Verryx-02/PlaylistPorter/internal/acoustid"
+	"github.com/Verryx-02/PlaylistPorter/internal/amazon"
+	"github.com/Verryx-02/PlaylistPorter/internal/config"
+	"github.com/Verryx-02/PlaylistPorter/internal/localfile"
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/musicbrainz"
+	"github.com/Verryx-02/PlaylistPorter/internal/odesli"
+	"github.com/Verryx-02/PlaylistPorter/internal/paths"
+	"github.com/Verryx-02/PlaylistPorter/internal/processor"
+	"github.com/Verryx-02/PlaylistPorter/internal/quota"
+	"github.com/Verryx-02/PlaylistPorter/internal/spt"
+	"github.com/Verryx-02/PlaylistPorter/internal/state"
+	"github.com/Verryx-02/PlaylistPorter/internal/tidal"
+	"github.com/Verryx-02/PlaylistPorter/internal/traffic"
+	"github.com/Verryx-02/PlaylistPorter/internal/triage"
+	"github.com/Verryx-02/PlaylistPorter/internal/tubo"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// Sync regression guard: warn when a sync batch matches noticeably worse than the playlist's history
+const (
+	regressionThreshold   = 0.20 // Drop in match rate that triggers the warning
+	regressionMinHistory  = 10   // Tracks needed before the historical rate is meaningful
+	regressionMinSyncSize = 3    // New tracks needed before the batch rate is meaningful
+)
+
+// errArtistUnavailable is the failure reason for tracks skipped by the artist heuristic
+const errArtistUnavailable = "artist known unavailable on YouTube (every earlier track failed)"
+
+// errSkippedByNote is the failure reason for tracks annotated "skip"
+const errSkippedByNote = "skipped (annotated \"skip\")"
+
+// Porting phases
+const (
+	PhaseAll    = "all"    // Search and upload in the same session
+	PhaseMatch  = "match"  // Only search, storing matches in state
+	PhaseUpload = "upload" // Only add previously stored matches to YouTube
 )
 
 // Orchestrator coordinates the playlist porting workflow
@@ -21,11 +57,53 @@ type Orchestrator struct {
 	verbose   bool
 	logFile   *os.File
 	logger    *log.Logger
-	maxTracks int  // Maximum tracks to process in this session
-	syncMode  bool // Whether to check for new tracks on completed playlists
+	runID     string // Prefixes log lines and error messages, see trace.go
+	traceSeq  int    // Tracks traced so far this run
+	maxTracks int    // Maximum tracks to process in this session
+	syncMode  bool   // Whether to check for new tracks on completed playlists
+	phase     string // Which part of the workflow to run
+
+	splitRules []config.SplitRule // Rules routing matches into several playlists (split mode)
+
+	holdOnRegression bool // Hold sync batches with a match-rate regression for review instead of uploading
+
+	sessionNote string // Stored with each session this run starts, see SetSessionNote
+
+	statesDir string                     // Saved states directory; "" uses paths.States()
+	askFunc   func(question string) bool // Answers confirm questions in place of the terminal
+
+	mode          string // Port mode requested on the command line ("" keeps the stored or default mode)
+	archiveWeekly bool   // Archive mode: one YouTube playlist per week instead of a cumulative one
+
+	maxDuration     time.Duration // Time budget for the session (0 = unlimited)
+	deadline        time.Time     // When the budget runs out; no new tracks are started after it
+	budgetExhausted bool          // The last matching batch stopped early because of the budget
+	strictISRC      bool          // Only keep matches corroborated by ISRC, see strict.go
+	odesliLimited   bool          // Strict ISRC mode stopped the batch at Odesli's rate limit
+	failurePolicy   string        // What a failed match does to the batch, see failure.go
+	failedAt        string        // Track the batch was aborted at by -on-failure abort
+	odesli          *odesli.Client
+
+	timeout  time.Duration // Hard limit for the whole run (0 = none), see SetTimeout
+	watchdog *time.Timer
+	timedOut atomic.Bool // The hard limit was reached; requests are aborted and the run winds down
 
 	sptClient    *spt.Client
+	tidalClient  *tidal.Client // Created on first use, only TIDAL sources need it
+	amazonReader *amazon.Reader
+	fileReader   *localfile.Reader
 	tuboClient   *tubo.Client
+	dest         Destination // Where matches are searched and uploaded (tuboClient for YouTube)
+	destName     string
+	customSource Source      // Set by UseSource, replaces the built-in services
+	customDest   Destination // Set by UseDestination
+
+	musicbrainz  *musicbrainz.Client // ISRC lookups (nil when neither enrichment nor fingerprinting is enabled)
+	fingerprints *acoustid.Verifier  // Audio checks of low-confidence matches (nil when disabled)
+	ledger       *quota.Ledger       // Shared quota reservations in courtesy mode (nil when off)
+
+	stageTimes map[string]time.Duration // Time spent in each pipeline stage during this run
+
 	processor    *processor.Processor
 	stateManager *state.Manager
 }
@@ -37,6 +115,8 @@ func New(cfg *config.Config, verbose bool, logFilePath string, maxTracks int, sy
 		verbose:   verbose,
 		maxTracks: maxTracks,
 		syncMode:  syncMode,
+		phase:     PhaseAll,
+		runID:     newRunID(),
 	}
 
 	// Setup file logging if verbose mode is enabled
@@ -46,9 +126,10 @@ func New(cfg *config.Config, verbose bool, logFilePath string, maxTracks int, sy
 			log.Printf("Warning: Failed to create log file %s: %v", logFilePath, err)
 		} else {
 			orch.logFile = logFile
-			orch.logger = log.New(logFile, "", log.LstdFlags)
+			orch.useLogger(log.New(logFile, "", log.LstdFlags))
 			orch.writeToLog("=== PlaylistPorter Detailed Log ===")
 			orch.writeToLog("Started at: %s", time.Now().Format("2006-01-02 15:04:05"))
+			orch.writeToLog("Run ID: %s", orch.runID)
 			orch.writeToLog("Max tracks per session: %d", maxTracks)
 			if syncMode {
 				orch.writeToLog("Sync mode: ENABLED")
@@ -59,8 +140,91 @@ func New(cfg *config.Config, verbose bool, logFilePath string, maxTracks int, sy
 	return orch
 }
 
+// SetLogWriter sends the detailed log to w instead of a log file
+func (o *Orchestrator) SetLogWriter(w io.Writer) {
+	o.useLogger(log.New(w, "", 0))
+	o.writeToLog("Run ID: %s", o.runID)
+}
+
+// SetSplit enables split mode using the rules from the configuration
+func (o *Orchestrator) SetSplit(enabled bool) {
+	if enabled {
+		o.splitRules = o.cfg.Split
+		o.writeToLog("Split mode: %d rules", len(o.splitRules))
+	} else {
+		o.splitRules = nil
+	}
+}
+
+// SetHoldOnRegression holds sync batches that match noticeably worse than usual instead of uploading them
+func (o *Orchestrator) SetHoldOnRegression(hold bool) {
+	o.holdOnRegression = hold
+}
+
+// SetMode requests snapshot or follow mode; empty keeps the mode stored in the state
+func (o *Orchestrator) SetMode(mode string) {
+	o.mode = mode
+}
+
+// SetArchiveWeekly makes new archive states use one YouTube playlist per week
+func (o *Orchestrator) SetArchiveWeekly(weekly bool) {
+	o.archiveWeekly = weekly
+}
+
+// SetMaxDuration limits how long the session keeps starting new tracks; the budget starts now
+func (o *Orchestrator) SetMaxDuration(d time.Duration) {
+	o.maxDuration = d
+	o.deadline = time.Time{}
+	if d > 0 {
+		o.deadline = time.Now().Add(d)
+		o.writeToLog("Time budget: %s", d)
+	}
+}
+
+// outOfTime reports whether the session's time budget has elapsed
+func (o *Orchestrator) outOfTime() bool {
+	return o.timedOut.Load() || (!o.deadline.IsZero() && time.Now().After(o.deadline))
+}
+
+// SetStatesDir keeps this orchestrator's saved states in dir instead of the configured directory
+func (o *Orchestrator) SetStatesDir(dir string) {
+	o.statesDir = dir
+}
+
+// statesPath returns the directory of the saved states
+func (o *Orchestrator) statesPath() string {
+	if o.statesDir != "" {
+		return o.statesDir
+	}
+	return paths.States()
+}
+
+// SetConfirm makes ask answer the yes/no questions of a run, e.g. whether to recreate a deleted
+// playlist, instead of asking on the terminal
+func (o *Orchestrator) SetConfirm(ask func(question string) bool) {
+	o.askFunc = ask
+}
+
+// SetSessionNote records a note with every session this run starts, e.g. "after matcher tweak",
+// so match rates can be compared across config experiments
+func (o *Orchestrator) SetSessionNote(note string) {
+	o.sessionNote = strings.TrimSpace(note)
+	if o.sessionNote != "" {
+		o.writeToLog("Session note: %s", o.sessionNote)
+	}
+}
+
+// SetPhase selects which part of the workflow to run
+func (o *Orchestrator) SetPhase(phase string) {
+	o.phase = phase
+	o.writeToLog("Phase: %s", phase)
+}
+
 // Close closes the log file if it's open
 func (o *Orchestrator) Close() {
+	if o.watchdog != nil {
+		o.watchdog.Stop()
+	}
 	if o.logFile != nil {
 		o.writeToLog("=== Session ended at: %s ===", time.Now().Format("2006-01-02 15:04:05"))
 		o.logFile.Close()
@@ -80,6 +244,10 @@ func (o *Orchestrator) PortPlaylist(sptURL string) error {
 
 	o.writeToLog("Starting playlist porting from: %s", sptURL)
 
+	if err := o.matchDirection(sptURL); err != nil {
+		return err
+	}
+
 	// Step 1: Initialize clients and state manager
 	if err := o.initializeClients(); err != nil {
 		return fmt.Errorf("initializing clients: %w", err)
@@ -92,56 +260,141 @@ func (o *Orchestrator) PortPlaylist(sptURL string) error {
 	}
 	o.writeToLog("Extracted playlist ID: %s", playlistID)
 
+	// A single track is only searched for, nothing is saved or created
+	if spt.IsTrackID(playlistID) {
+		return o.testTrackMatch(playlistID)
+	}
+
+	// Upload phase only works on previously matched tracks
+	if o.phase == PhaseUpload {
+		return o.runUploadPhase(playlistID)
+	}
+
 	// Step 3: Load or create state
 	portingState, isNewState, err := o.loadOrCreateState(sptURL, playlistID)
+	if errors.Is(err, spt.ErrPlaylistUnavailable) {
+		ui.Printf("🚫 The Spotify playlist is private, deleted or not available in your region\n")
+		ui.Printf("   Nothing to port. Check the URL, make the playlist public, or set spt.user_auth: true\n")
+		ui.Printf("   to sign in with the Spotify account that can see it.\n")
+		o.writeToLog("Source playlist unavailable: %v", err)
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("loading state: %w", err)
 	}
 
+	return o.runSession(portingState, isNewState)
+}
+
+// runSession processes the next batch of a loaded state (steps 4-11 of the workflow)
+func (o *Orchestrator) runSession(portingState *state.PortingState, isNewState bool) error {
+	defer o.writeBadge(portingState)
+
 	// Step 4: If resuming, show progress
 	if !isNewState {
-		fmt.Printf("📂 Resuming previous porting session\n")
-		fmt.Printf("   Progress: %s\n", portingState.GetProgress())
-		fmt.Printf("   Sessions completed: %d\n", len(portingState.Sessions))
+		ui.Printf("📂 Resuming previous porting session\n")
+		ui.Printf("   Progress: %s\n", portingState.GetProgress())
+		ui.Printf("   Sessions completed: %d\n", len(portingState.Sessions))
 		if portingState.YouTubePlaylistID != "" {
-			fmt.Printf("   YouTube playlist: https://www.youtube.com/playlist?list=%s\n", portingState.YouTubePlaylistID)
+			ui.Printf("   YouTube playlist: %s\n", o.playlistURL(portingState.YouTubePlaylistID))
 		}
 		o.writeToLog("Resuming from checkpoint: %s", portingState.GetProgress())
 	}
 
+	// Archive states pick up the current week's tracks on every run
+	syncing := o.syncMode || portingState.IsArchive()
+
 	// Check if already complete
-	if portingState.IsComplete && !o.syncMode {
-		fmt.Printf("✅ This playlist has already been completely processed!\n")
+	if portingState.IsComplete && !syncing {
+		ui.Printf("✅ This playlist has already been completely processed!\n")
+		uploaded := false
+		if o.phase == PhaseAll && len(portingState.GetPendingUploads()) > 0 {
+			if err := o.uploadPending(portingState); err != nil {
+				return err
+			}
+			uploaded = true
+		}
+		o.reportFinalResults(portingState)
+		if uploaded {
+			// Completed in a match-only run, the playlist is finished now
+			o.runCompletionHooks(portingState)
+		}
+		return nil
+	}
+
+	// Snapshot ports keep the playlist as it was on the first run
+	if portingState.IsComplete && o.syncMode && portingState.IsSnapshot() {
+		ui.Printf("📸 This playlist was ported as a snapshot, sync skipped\n")
+		ui.Printf("   Run with -mode follow -sync to follow changes on Spotify from now on\n")
+		o.writeToLog("Sync skipped: snapshot mode")
 		o.reportFinalResults(portingState)
 		return nil
 	}
 
 	// If in sync mode and playlist is complete, check for new tracks
-	if portingState.IsComplete && o.syncMode {
-		fmt.Printf("🔄 Sync mode enabled - checking for new tracks...\n")
+	isSyncBatch := false
+	if portingState.IsComplete && syncing {
+		isSyncBatch = true
+		if portingState.IsArchive() {
+			ui.Printf("🗄️  Archive mode - checking this week's tracks...\n")
+		} else {
+			ui.Printf("🔄 Sync mode enabled - checking for new tracks...\n")
+		}
 
 		// Fetch current playlist from Spotify
-		currentPlaylist, err := o.sptClient.GetPlaylist(playlistID)
+		currentPlaylist, err := o.fetchCurrentPlaylist(portingState)
+		if errors.Is(err, spt.ErrPlaylistUnavailable) {
+			return o.handleSourceUnavailable(portingState, err)
+		}
 		if err != nil {
 			return fmt.Errorf("fetching current playlist: %w", err)
 		}
 
-		// Detect new tracks
-		newTracks := portingState.DetectNewTracks(*currentPlaylist)
+		if portingState.IsSourceUnavailable() {
+			ui.Printf("✅ The Spotify playlist is available again\n")
+			portingState.MarkSourceAvailable()
+		}
+
+		// Replace matched videos that disappeared from YouTube since the last run
+		if err := o.verifyMatchedVideos(portingState); err != nil {
+			ui.Printf("⚠️  Could not verify matched videos: %v\n", err)
+			o.writeToLog("Video verification failed: %v", err)
+		}
+
+		// Detect new tracks (archives keep the tracks that left the playlist)
+		var newTracks []models.Track
+		if portingState.IsArchive() {
+			newTracks = portingState.AppendToArchive(*currentPlaylist)
+			if portingState.ArchiveWeekly {
+				week := state.ArchiveWeekTarget(time.Now())
+				for _, track := range newTracks {
+					portingState.AssignTarget(track.ID, week)
+				}
+			}
+		} else {
+			newTracks = portingState.DetectNewTracks(*currentPlaylist)
+		}
 
 		if len(newTracks) == 0 {
-			fmt.Printf("✅ Playlist is up to date! No new tracks found.\n")
-			fmt.Printf("   Last sync: %s\n", portingState.LastSyncCheck.Format("2006-01-02 15:04"))
+			ui.Printf("✅ Playlist is up to date! No new tracks found.\n")
+			ui.Printf("   Last sync: %s\n", portingState.LastSyncCheck.Format("2006-01-02 15:04"))
+			portingState.LastSyncCheck = time.Now()
+			if err := o.stateManager.SaveState(portingState); err != nil {
+				return fmt.Errorf("saving state: %w", err)
+			}
 			return nil
 		}
 
-		fmt.Printf("🆕 Found %d new tracks added to the Spotify playlist!\n", len(newTracks))
+		ui.Printf("🆕 Found %d new tracks added to the Spotify playlist!\n", len(newTracks))
 
-		// Update state for sync
-		portingState.UpdateForSync(*currentPlaylist)
+		if !portingState.IsArchive() {
+			// Update state for sync
+			portingState.UpdateForSync(*currentPlaylist)
 
-		// Update the playlist tracks to include new ones
-		portingState.OriginalPlaylist.Tracks = currentPlaylist.Tracks
+			// Update the playlist tracks to include new ones
+			portingState.KeepNormalized(currentPlaylist.Tracks)
+			portingState.OriginalPlaylist.Tracks = currentPlaylist.Tracks
+		}
 
 		// Log new tracks
 		o.writeToLog("\n=== NEW TRACKS DETECTED ===")
@@ -150,42 +403,75 @@ func (o *Orchestrator) PortPlaylist(sptURL string) error {
 		}
 	}
 
+	// Normalized metadata is saved in the state, so resumed sessions match with the same values
+	normalizeStart := time.Now()
+	o.normalizeStateTracks(portingState)
+	o.addStageTime(stageNormalize, normalizeStart)
+
 	// Step 5: Get next batch of tracks to process
 	tracksToProcess := portingState.GetNextBatch(o.maxTracks)
 	if len(tracksToProcess) == 0 {
-		fmt.Printf("✅ No more tracks to process!\n")
+		ui.Printf("✅ No more tracks to process!\n")
 		return nil
 	}
 
-	fmt.Printf("\n📋 Processing batch: %d tracks (starting from track %d)\n",
+	// A session budget in the quota config caps the batch
+	if budget := o.cfg.Quota.SessionBudget; budget > 0 && o.destinationName() == DestYouTube && len(tracksToProcess)*o.trackCost() > budget {
+		limit := budget / o.trackCost()
+		if limit == 0 {
+			ui.Printf("⚠️  The session budget of %d quota units doesn't cover a single track (~%d units)\n",
+				budget, o.trackCost())
+			return nil
+		}
+		ui.Printf("📊 Session budget: %d quota units cover %d of %d tracks\n", budget, limit, len(tracksToProcess))
+		tracksToProcess = tracksToProcess[:limit]
+	}
+
+	// Courtesy mode: only spend what the shared ledger grants
+	reservation, allowed, err := o.reserveQuota(len(tracksToProcess))
+	if err != nil {
+		return fmt.Errorf("reserving shared quota: %w", err)
+	}
+	if allowed == 0 {
+		return nil
+	}
+	tracksToProcess = tracksToProcess[:allowed]
+	var matchResults []models.MatchResult
+	defer func() { o.releaseQuota(reservation, matchResults) }()
+
+	ui.Printf("\n📋 Processing batch: %d tracks (starting from track %d)\n",
 		len(tracksToProcess), portingState.ProcessedTracks+1)
 
 	// Start new session tracking
-	portingState.StartNewSession()
-
-	// Step 6: Process and normalize track data
-	fmt.Printf("🔧 Processing track metadata...\n")
-	o.writeToLog("\n=== NORMALIZING METADATA (Batch) ===")
+	portingState.StartNewSession(o.sessionNote, o.runID)
 
-	// Create a temporary playlist with just the tracks to process
+	// Step 6: Create a temporary playlist with just the tracks to process (already normalized)
 	batchPlaylist := &models.Playlist{
 		Tracks: tracksToProcess,
 	}
-	o.processor.NormalizePlaylist(batchPlaylist)
 
 	// Step 7: Search and match tracks on YouTube
-	fmt.Printf("🔍 Searching for tracks on YouTube...\n")
+	ui.Printf("🔍 Searching for tracks on %s...\n", o.destinationLabel())
 	if o.verbose {
-		fmt.Printf("    💡 Detailed search progress is being logged to file\n")
+		ui.Printf("    💡 Detailed search progress is being logged to file\n")
 	}
 
 	o.writeToLog("\n=== YOUTUBE SEARCH & MATCHING (Batch) ===")
-	matchResults, err := o.matchTracks(batchPlaylist.Tracks, portingState.ProcessedTracks)
+	matchResults, err = o.matchTracks(batchPlaylist.Tracks, portingState.ProcessedTracks, portingState.TrackNotes)
 	if err != nil {
 		return fmt.Errorf("matching tracks: %w", err)
 	}
 
+	// The time budget can run out before the first track; there is no session to record then
+	if len(matchResults) == 0 {
+		portingState.DropCurrentSession()
+		ui.Summaryf("\n⏸️  No tracks were processed this session; the next run starts from track %d\n",
+			portingState.ProcessedTracks+1)
+		return nil
+	}
+
 	// Step 8: Update state with results
+	historicalRate, historicalCount := portingState.GetMatchRate()
 	portingState.AddMatchResults(matchResults)
 
 	// Count successful matches in this session
@@ -195,40 +481,354 @@ func (o *Orchestrator) PortPlaylist(sptURL string) error {
 			sessionMatches++
 		}
 	}
-	portingState.EndCurrentSession(len(matchResults), sessionMatches)
-
-	// Step 9: Create or update YouTube playlist
-	if err := o.manageYouTubePlaylist(portingState, matchResults); err != nil {
-		return fmt.Errorf("managing YouTube playlist: %w", err)
+	portingState.EndCurrentSession(len(matchResults), sessionMatches, o.trackCost())
+
+	// Guard against sync batches matching much worse than the playlist's history
+	holdBatch := false
+	if isSyncBatch && historicalCount >= regressionMinHistory && len(matchResults) >= regressionMinSyncSize {
+		batchRate := float64(sessionMatches) / float64(len(matchResults))
+		if batchRate < historicalRate-regressionThreshold {
+			ui.Printf("\n⚠️  Match rate for the new tracks is %.0f%%, well below this playlist's usual %.0f%%\n",
+				batchRate*100, historicalRate*100)
+			ui.Printf("   The new tracks may use metadata the matcher handles poorly (check the failed tracks)\n")
+			o.writeToLog("Match rate regression: batch %.2f vs historical %.2f", batchRate, historicalRate)
+
+			if o.holdOnRegression {
+				holdBatch = true
+				ui.Printf("   ✋ Batch held for review: run with -phase upload to add the matches anyway\n")
+			}
+		}
 	}
 
-	// Step 10: Save state
+	// Step 9: Save the matches before uploading, so a failed upload doesn't lose them and the
+	// search quota spent on them
+	o.recordStageTimes(portingState)
 	if err := o.stateManager.SaveState(portingState); err != nil {
 		return fmt.Errorf("saving state: %w", err)
 	}
-	fmt.Printf("💾 Progress saved to checkpoint\n")
+
+	// Step 10: Create or update YouTube playlist and save state (upload skipped in match-only phase)
+	if o.phase == PhaseMatch || holdBatch || o.timedOut.Load() {
+		ui.Printf("🔎 %d matches stored, nothing uploaded\n", sessionMatches)
+		o.writeToLog("Skipping YouTube playlist update (phase: %s, held: %t, timed out: %t)", o.phase, holdBatch, o.timedOut.Load())
+		ui.Printf("💾 Progress saved to checkpoint\n")
+	} else if err := o.uploadPending(portingState); err != nil {
+		return err
+	}
 
 	// Step 11: Report session results
 	o.reportSessionResults(portingState, matchResults)
+	o.reportStrictRefusals(matchResults)
+
+	if o.phase == PhaseMatch {
+		ui.Printf("💡 Review the matches, then run with -phase upload to add them to YouTube\n")
+	}
 
 	// Check if we're done
 	if portingState.IsComplete {
-		fmt.Printf("\n🎉 Playlist porting completed!\n")
+		ui.Summaryf("\n🎉 Playlist porting completed!\n")
 		o.reportFinalResults(portingState)
+		o.runCompletionHooks(portingState)
 
-		if !o.syncMode {
-			fmt.Printf("\n💡 Tip: Run with -sync flag to check for new tracks added to the Spotify playlist\n")
+		if !syncing && !portingState.IsSnapshot() {
+			ui.Printf("\n💡 Tip: Run with -sync flag to check for new tracks added to the Spotify playlist\n")
 		}
 	} else {
 		remainingTracks := portingState.TotalTracks - portingState.ProcessedTracks
-		fmt.Printf("\n⏸️  Session complete. %d tracks remaining.\n", remainingTracks)
-		fmt.Printf("📅 Run again tomorrow to continue (YouTube quota resets daily)\n")
-		fmt.Printf("💡 Next run will automatically resume from track %d\n", portingState.ProcessedTracks+1)
+		ui.Summaryf("\n⏸️  Session complete. %d tracks remaining.\n", remainingTracks)
+		if o.timedOut.Load() {
+			ui.Summaryf("⏱️  Stopped by the %s run timeout; the next run picks up the rest\n", o.timeout)
+		} else if o.failedAt != "" {
+			ui.Summaryf("🛑 Stopped at the failed match of %s (-on-failure abort); check the log, then run again\n", o.failedAt)
+		} else if o.odesliLimited {
+			ui.Summaryf("🔗 Stopped at Odesli's rate limit; run again in a few minutes, or set odesli.api_key\n")
+		} else if o.budgetExhausted {
+			ui.Summaryf("⏱️  Stopped by the %s time budget; quota is left for the next run\n", o.maxDuration)
+		} else {
+			ui.Summaryf("📅 Run again after the YouTube quota resets: %s\n", quota.ResetMessage(time.Now()))
+		}
+		ui.Printf("💡 Next run will automatically resume from track %d\n", portingState.ProcessedTracks+1)
 	}
 
 	return nil
 }
 
+// MergePlaylists ports the union of several Spotify playlists into a single YouTube playlist
+func (o *Orchestrator) MergePlaylists(sptURLs []string, name string) error {
+	defer o.Close()
+
+	o.writeToLog("Starting merge of %d playlists", len(sptURLs))
+
+	if err := o.initializeClients(); err != nil {
+		return fmt.Errorf("initializing clients: %w", err)
+	}
+
+	var sources []state.SourceInfo
+	var ids []string
+	for _, sptURL := range sptURLs {
+		playlistID, err := o.extractPlaylistID(sptURL)
+		if err != nil {
+			return fmt.Errorf("extracting playlist ID from %s: %w", sptURL, err)
+		}
+		ids = append(ids, playlistID)
+		sources = append(sources, state.SourceInfo{SpotifyID: playlistID, SpotifyURL: sptURL})
+	}
+
+	mergedID := state.MergedStateID(ids)
+	o.writeToLog("Merged state ID: %s", mergedID)
+
+	if o.phase == PhaseUpload {
+		return o.runUploadPhase(mergedID)
+	}
+
+	portingState, err := o.stateManager.LoadState(mergedID)
+	if err != nil {
+		return fmt.Errorf("loading state: %w", err)
+	}
+
+	isNewState := portingState == nil
+	if !isNewState {
+		if err := o.checkDestination(portingState); err != nil {
+			return err
+		}
+	}
+	if isNewState {
+		ui.Printf("🎵 Fetching %d playlists from Spotify...\n", len(sources))
+
+		playlist, trackSources, err := o.fetchMergedPlaylist(sources)
+		if errors.Is(err, spt.ErrPlaylistUnavailable) {
+			ui.Printf("🚫 One of the Spotify playlists is private, deleted or not available in your region\n")
+			o.writeToLog("Source playlist unavailable: %v", err)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("fetching SPT playlists: %w", err)
+		}
+
+		if name != "" {
+			playlist.Name = name
+		}
+
+		portingState = o.stateManager.CreateNewState(strings.Join(sptURLs, " "), mergedID, *playlist)
+		portingState.SetSources(sources, trackSources)
+		portingState.Mode = o.initialMode(playlist)
+		portingState.ArchiveWeekly = portingState.IsArchive() && o.archiveWeekly
+		portingState.Destination = o.stateDestination()
+		portingState.YouTubeAccount = o.stateAccount()
+
+		for _, source := range portingState.Sources {
+			ui.Printf("   • %s\n", source.Name)
+		}
+		ui.Printf("📋 Merged playlist: \"%s\" (%d unique tracks)\n", playlist.Name, len(playlist.Tracks))
+		o.writeToLog("Created merged state: %s (%d unique tracks)", playlist.Name, len(playlist.Tracks))
+
+		if err := o.stateManager.SaveState(portingState); err != nil {
+			return fmt.Errorf("saving initial state: %w", err)
+		}
+		o.announceMode(portingState)
+	} else {
+		o.reportRecovery(portingState)
+		if err := o.switchMode(portingState); err != nil {
+			return err
+		}
+	}
+
+	return o.runSession(portingState, isNewState)
+}
+
+// fetchMergedPlaylist fetches all source playlists and returns their deduplicated union
+// along with the source playlists each track came from
+func (o *Orchestrator) fetchMergedPlaylist(sources []state.SourceInfo) (*models.Playlist, map[string][]string, error) {
+	merged := &models.Playlist{}
+	trackSources := make(map[string][]string)
+	var names []string
+
+	for i, source := range sources {
+		playlist, err := o.fetchPlaylist(source.SpotifyID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("fetching playlist %s: %w", source.SpotifyID, err)
+		}
+		sources[i].Name = playlist.Name
+		names = append(names, playlist.Name)
+
+		for _, track := range playlist.Tracks {
+			if _, seen := trackSources[track.ID]; !seen {
+				merged.Tracks = append(merged.Tracks, track)
+			}
+			trackSources[track.ID] = append(trackSources[track.ID], source.SpotifyID)
+		}
+	}
+
+	merged.ID = state.MergedStateID(sourceIDs(sources))
+	merged.Name = strings.Join(names, " + ")
+	merged.Description = fmt.Sprintf("Merged from %d Spotify playlists", len(sources))
+	merged.TotalTracks = len(merged.Tracks)
+
+	return merged, trackSources, nil
+}
+
+// fetchCurrentPlaylist fetches the latest version of a state's source playlist(s)
+func (o *Orchestrator) fetchCurrentPlaylist(portingState *state.PortingState) (*models.Playlist, error) {
+	if !portingState.IsMerged() {
+		return o.fetchPlaylist(portingState.SpotifyID)
+	}
+
+	playlist, trackSources, err := o.fetchMergedPlaylist(portingState.Sources)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the user's chosen name and record the refreshed provenance
+	playlist.Name = portingState.OriginalPlaylist.Name
+	portingState.SetSources(portingState.Sources, trackSources)
+
+	return playlist, nil
+}
+
+// sourceIDs returns the Spotify IDs of the given sources
+func sourceIDs(sources []state.SourceInfo) []string {
+	ids := make([]string, 0, len(sources))
+	for _, source := range sources {
+		ids = append(ids, source.SpotifyID)
+	}
+	return ids
+}
+
+// RecreateTarget builds a brand-new YouTube playlist from the matches stored in state, without searching
+func (o *Orchestrator) RecreateTarget(sptURL string) error {
+	defer o.Close()
+
+	o.writeToLog("Recreating target playlist for: %s", sptURL)
+
+	if err := o.initializeClients(); err != nil {
+		return fmt.Errorf("initializing clients: %w", err)
+	}
+
+	playlistID, err := o.extractPlaylistID(sptURL)
+	if err != nil {
+		return fmt.Errorf("extracting playlist ID: %w", err)
+	}
+
+	portingState, err := o.stateManager.LoadState(playlistID)
+	if err != nil {
+		return fmt.Errorf("loading state: %w", err)
+	}
+	if portingState == nil {
+		return fmt.Errorf("no saved state for playlist %s, nothing to recreate", playlistID)
+	}
+	o.reportRecovery(portingState)
+	if err := o.checkDestination(portingState); err != nil {
+		return err
+	}
+
+	matched := o.replayResults(portingState)
+	if len(portingState.GetMatchedResults()) == 0 {
+		ui.Printf("✅ No matched tracks stored yet, nothing to recreate\n")
+		return nil
+	}
+
+	if portingState.YouTubePlaylistID != "" {
+		ui.Printf("📺 Previous YouTube playlist: %s\n", o.playlistURL(portingState.YouTubePlaylistID))
+		o.writeToLog("Replacing previous target playlist %s", portingState.YouTubePlaylistID)
+	}
+	ui.Printf("♻️  Replaying %d stored matches into a new playlist (no searches needed)\n", len(matched))
+
+	portingState.ResetTargetPlaylist()
+	if err := o.manageYouTubePlaylist(portingState, matched); err != nil {
+		return fmt.Errorf("managing YouTube playlist: %w", err)
+	}
+	portingState.MarkUploaded(matched)
+
+	if err := o.stateManager.SaveState(portingState); err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+	ui.Printf("💾 Progress saved to checkpoint\n")
+	ui.Printf("🔗 New YouTube playlist: %s\n", o.playlistURL(portingState.YouTubePlaylistID))
+
+	return nil
+}
+
+// runUploadPhase adds all stored but not yet uploaded matches to the YouTube playlist
+func (o *Orchestrator) runUploadPhase(playlistID string) error {
+	portingState, err := o.stateManager.LoadState(playlistID)
+	if err != nil {
+		return fmt.Errorf("loading state: %w", err)
+	}
+	if portingState == nil {
+		return fmt.Errorf("no saved state for playlist %s, run with -phase match first", playlistID)
+	}
+	o.reportRecovery(portingState)
+	if err := o.checkDestination(portingState); err != nil {
+		return err
+	}
+
+	if portingState.NeedsMigration() {
+		portingState.Migrate()
+	}
+
+	pending := portingState.GetPendingUploads()
+	if len(pending) == 0 {
+		ui.Printf("✅ No pending matches to upload\n")
+		return nil
+	}
+
+	ui.Printf("📤 Upload phase: %d matched tracks waiting to be added\n", len(pending))
+	if err := o.uploadPending(portingState); err != nil {
+		return err
+	}
+
+	ui.Printf("🔗 YouTube playlist: %s\n", o.playlistURL(portingState.YouTubePlaylistID))
+	return nil
+}
+
+// replayResults returns the results to add when rebuilding a playlist from scratch
+func (o *Orchestrator) replayResults(portingState *state.PortingState) []models.MatchResult {
+	if o.cfg.TUBO.PlaceholderVideoID != "" {
+		return portingState.MatchResults
+	}
+	return portingState.GetMatchedResults()
+}
+
+// uploadPending adds pending matches to YouTube and saves the state
+func (o *Orchestrator) uploadPending(portingState *state.PortingState) error {
+	pending := portingState.GetPendingUploads()
+	if o.cfg.TUBO.PlaceholderVideoID != "" {
+		pending = portingState.GetPendingUploadsWithPlaceholders()
+	}
+
+	err := o.manageYouTubePlaylist(portingState, pending)
+	if errors.Is(err, tubo.ErrPlaylistNotAccessible) {
+		ui.Promptf("\n⚠️  The YouTube playlist %s can no longer be modified (deleted or not accessible)\n", portingState.YouTubePlaylistID)
+		o.writeToLog("Target playlist not accessible: %v", err)
+
+		if !o.confirm("Create a new YouTube playlist and add all matched tracks again?") {
+			if o.askFunc == nil && !ui.IsTerminal(os.Stdin) {
+				ui.Summaryf("⏭️  Skipped recreating the playlist: there is no terminal to confirm on\n")
+				ui.Summaryf("   Run again in a terminal, or with -recreate-target to build a new playlist from the matches\n")
+			}
+			return fmt.Errorf("managing YouTube playlist: %w", err)
+		}
+
+		// Replay every match, not just the pending ones
+		portingState.ResetTargetPlaylist()
+		pending = o.replayResults(portingState)
+		err = o.manageYouTubePlaylist(portingState, pending)
+	}
+	if err != nil {
+		return fmt.Errorf("managing YouTube playlist: %w", err)
+	}
+	portingState.MarkUploaded(pending)
+	o.recordStageTimes(portingState)
+
+	if err := o.stateManager.SaveState(portingState); err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+	ui.Printf("💾 Progress saved to checkpoint\n")
+	o.writePositionNotes(portingState)
+
+	return nil
+}
+
 // loadOrCreateState loads existing state or creates new one
 func (o *Orchestrator) loadOrCreateState(sptURL, playlistID string) (*state.PortingState, bool, error) {
 	// Load existing state if it exists
@@ -239,10 +839,15 @@ func (o *Orchestrator) loadOrCreateState(sptURL, playlistID string) (*state.Port
 
 	if existingState != nil {
 		o.writeToLog("Loaded existing state for playlist %s", playlistID)
+		o.reportRecovery(existingState)
+
+		if err := o.checkDestination(existingState); err != nil {
+			return nil, false, err
+		}
 
 		// Migrate old state files if needed
 		if existingState.NeedsMigration() {
-			fmt.Printf("📦 Migrating state file to support new features...\n")
+			ui.Printf("📦 Migrating state file to support new features...\n")
 			existingState.Migrate()
 			// Save migrated state
 			if err := o.stateManager.SaveState(existingState); err != nil {
@@ -250,24 +855,36 @@ func (o *Orchestrator) loadOrCreateState(sptURL, playlistID string) (*state.Port
 			}
 		}
 
+		if err := o.switchMode(existingState); err != nil {
+			return nil, false, err
+		}
+
 		return existingState, false, nil
 	}
 
 	// No existing state, fetch playlist and create new state
-	fmt.Printf("🎵 Fetching playlist from Spotify...\n")
-	o.writeToLog("Fetching playlist from SPT...")
+	ui.Printf("🎵 Fetching playlist from %s...\n", sourceName(playlistID))
+	o.writeToLog("Fetching playlist from %s...", sourceName(playlistID))
 
-	playlist, err := o.sptClient.GetPlaylist(playlistID)
+	playlist, err := o.fetchPlaylist(playlistID)
 	if err != nil {
 		return nil, false, fmt.Errorf("fetching SPT playlist: %w", err)
 	}
 
-	fmt.Printf("📋 Found playlist: \"%s\" (%d tracks)\n", playlist.Name, len(playlist.Tracks))
+	ui.Printf("📋 Found playlist: \"%s\" (%d tracks)\n", playlist.Name, len(playlist.Tracks))
+	if playlist.SkippedEpisodes > 0 {
+		ui.Printf("⏭️  Skipped %d podcast episodes, only music tracks are ported\n", playlist.SkippedEpisodes)
+	}
 	o.writeToLog("Found playlist: %s (%d tracks)", playlist.Name, len(playlist.Tracks))
 
 	// Create new state
 	newState := o.stateManager.CreateNewState(sptURL, playlistID, *playlist)
-	o.writeToLog("Created new state for playlist")
+	newState.Mode = o.initialMode(playlist)
+	newState.ArchiveWeekly = newState.IsArchive() && o.archiveWeekly
+	newState.Destination = o.stateDestination()
+	newState.YouTubeAccount = o.stateAccount()
+	o.writeToLog("Created new state for playlist (mode: %s)", newState.Mode)
+	o.announceMode(newState)
 
 	// Save initial state
 	if err := o.stateManager.SaveState(newState); err != nil {
@@ -277,18 +894,181 @@ func (o *Orchestrator) loadOrCreateState(sptURL, playlistID string) (*state.Port
 	return newState, true, nil
 }
 
+// spotifyEditorialOwner owns editorial playlists such as "This Is" and artist radios
+const spotifyEditorialOwner = "spotify"
+
+// initialMode picks the port mode for a new state: the requested one, otherwise snapshot
+// for Spotify's editorial playlists (which are reshuffled often) and follow for the rest
+func (o *Orchestrator) initialMode(playlist *models.Playlist) string {
+	if o.mode != "" {
+		return o.mode
+	}
+	if playlist.OwnerID == spotifyEditorialOwner || spt.IsAlbumID(playlist.ID) {
+		return state.ModeSnapshot
+	}
+	return state.ModeFollow
+}
+
+// switchMode applies a mode requested on the command line to an existing state
+func (o *Orchestrator) switchMode(portingState *state.PortingState) error {
+	if o.mode == "" || o.mode == portingState.GetMode() {
+		return nil
+	}
+
+	ui.Printf("🔁 Switching from %s to %s mode\n", portingState.GetMode(), o.mode)
+	o.writeToLog("Mode changed: %s -> %s", portingState.GetMode(), o.mode)
+	portingState.Mode = o.mode
+	portingState.ArchiveWeekly = portingState.IsArchive() && o.archiveWeekly
+
+	if err := o.stateManager.SaveState(portingState); err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+	return nil
+}
+
+// announceMode explains what the state's mode means for future runs
+func (o *Orchestrator) announceMode(portingState *state.PortingState) {
+	if portingState.IsArchive() {
+		ui.Printf("🗄️  Archive mode: every run adds this week's new tracks, tracks that leave the playlist are kept\n")
+		if portingState.ArchiveWeekly {
+			ui.Printf("   Each week gets its own YouTube playlist\n")
+		}
+	} else if portingState.IsSnapshot() {
+		ui.Printf("📸 Snapshot port: the playlist is ported as it is now, later changes on Spotify are ignored\n")
+		if portingState.OriginalPlaylist.OwnerID == spotifyEditorialOwner && o.mode == "" {
+			ui.Printf("   (Spotify editorial playlist; use -mode follow to keep syncing it)\n")
+		}
+	} else {
+		ui.Printf("👀 Follow mode: run with -sync to pick up tracks added on Spotify\n")
+	}
+}
+
+// handleSourceUnavailable marks the state when the Spotify playlist can no longer be fetched
+func (o *Orchestrator) handleSourceUnavailable(portingState *state.PortingState, cause error) error {
+	portingState.MarkSourceUnavailable()
+	o.writeToLog("Source playlist unavailable: %v", cause)
+
+	if err := o.stateManager.SaveState(portingState); err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+
+	ui.Printf("🚫 The Spotify playlist is no longer available (private, deleted or region-locked)\n")
+	ui.Printf("   Unavailable since: %s\n", portingState.SourceUnavailableSince.Format("2006-01-02 15:04"))
+	if portingState.YouTubePlaylistID != "" {
+		ui.Printf("   Already ported tracks remain in: %s\n", o.playlistURL(portingState.YouTubePlaylistID))
+	}
+	ui.Printf("   Sync will pick up again once the playlist is reachable\n")
+
+	return nil
+}
+
+// confirm asks a yes/no question on the terminal, or through SetConfirm's function, defaulting
+// to no. Without a terminal on standard input nobody can answer, so the answer is no.
+func (o *Orchestrator) confirm(question string) bool {
+	if o.askFunc != nil {
+		return o.askFunc(question)
+	}
+	if !ui.IsTerminal(os.Stdin) {
+		o.writeToLog("Not asked, no terminal: %s", question)
+		return false
+	}
+	ui.Promptf("%s [y/N]: ", question)
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		ui.Promptf("\n")
+		return false
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// reportRecovery tells the user when a corrupted state was restored from a backup
+func (o *Orchestrator) reportRecovery(portingState *state.PortingState) {
+	if portingState.Recovery == nil {
+		return
+	}
+
+	ui.Summaryf("⚠️  The state file was corrupted and has been restored from a backup\n")
+	ui.Summaryf("   Corrupted file moved to: %s\n", portingState.Recovery.QuarantinedFile)
+	ui.Summaryf("   Restored from: %s\n", portingState.Recovery.BackupFile)
+	ui.Summaryf("   Progress since that backup will be processed again\n")
+	if portingState.Recovery.OnlyChecksum {
+		ui.Summaryf("   The file was valid JSON and only failed its checksum. If you edited it by hand,\n")
+		ui.Summaryf("   move it back over the state file and run 'playlistporter fsck -repair' to keep the edit\n")
+	}
+	o.writeToLog("State recovered from backup %s (corrupted file: %s)",
+		portingState.Recovery.BackupFile, portingState.Recovery.QuarantinedFile)
+}
+
 // initializeClients sets up all required service clients
 func (o *Orchestrator) initializeClients() error {
 	o.writeToLog("🔧 Initializing service clients...")
 
-	// Initialize SPT client
+	// Spotify is signed in to when a Spotify playlist is read, unless it is the destination
+	if o.destinationName() == DestSpotify {
+		if err := o.initializeSpt(); err != nil {
+			return err
+		}
+	}
+
+	if err := o.initializeDestination(); err != nil {
+		return err
+	}
+	o.openLedger()
+
+	// Initialize processor
+	o.processor = processor.New()
+	o.writeToLog("✅ Processor initialized")
+
+	// Initialize state manager
+	stateManager, err := state.NewManager(o.statesPath())
+	if err != nil {
+		return fmt.Errorf("creating state manager: %w", err)
+	}
+	o.stateManager = stateManager
+	o.writeToLog("✅ State manager initialized")
+
+	if o.cfg.MusicBrainz.Enabled || o.cfg.AcoustID.Enabled {
+		client, err := musicbrainz.NewClient(o.cfg.MusicBrainz.Contact, filepath.Join(o.statesPath(), musicbrainzCacheFile))
+		if err != nil {
+			return fmt.Errorf("creating MusicBrainz client: %w", err)
+		}
+		o.musicbrainz = client
+		o.writeToLog("✅ MusicBrainz lookups enabled")
+	}
+
+	if o.cfg.AcoustID.Enabled {
+		verifier, err := acoustid.NewVerifier(&o.cfg.AcoustID)
+		if err != nil {
+			return fmt.Errorf("creating AcoustID verifier: %w", err)
+		}
+		o.fingerprints = verifier
+		o.writeToLog("✅ Fingerprint verification of low-confidence matches enabled")
+	}
+
+	return nil
+}
+
+// initializeSpt creates the Spotify client
+func (o *Orchestrator) initializeSpt() error {
+	// Creating Spotify playlists needs the user's account
+	if o.destinationName() == DestSpotify {
+		o.cfg.SPT.UserAuth = true
+	}
+
 	sptClient, err := spt.NewClient(&o.cfg.SPT)
 	if err != nil {
 		return fmt.Errorf("creating SPT client: %w", err)
 	}
 	o.sptClient = sptClient
 	o.writeToLog("✅ Spotify client initialized")
+	return nil
+}
 
+// initializeTubo creates the YouTube client
+func (o *Orchestrator) initializeTubo() error {
 	// Initialize TUBO client
 	tuboClient, err := tubo.NewClient(&o.cfg.TUBO)
 	if err != nil {
@@ -300,108 +1080,293 @@ func (o *Orchestrator) initializeClients() error {
 		tuboClient.SetLogger(o.logger)
 	}
 	tuboClient.SetVerbose(o.verbose)
+	tuboClient.SetMatching(o.cfg.Matching.Params())
 	o.tuboClient = tuboClient
 	o.writeToLog("✅ YouTube client initialized")
 
-	// Initialize processor
-	o.processor = processor.New()
-	o.writeToLog("✅ Processor initialized")
-
-	// Initialize state manager
-	stateManager, err := state.NewManager("states")
-	if err != nil {
-		return fmt.Errorf("creating state manager: %w", err)
+	// The placeholder may be given as a watch or youtu.be link
+	if placeholder := o.cfg.TUBO.PlaceholderVideoID; placeholder != "" {
+		videoID, err := tubo.VideoIDFromURL(placeholder)
+		if err != nil {
+			return fmt.Errorf("tubo.placeholder_video_id: %w", err)
+		}
+		o.cfg.TUBO.PlaceholderVideoID = videoID
 	}
-	o.stateManager = stateManager
-	o.writeToLog("✅ State manager initialized")
 
 	return nil
 }
 
-// extractPlaylistID extracts playlist ID from SPT URL
+// extractPlaylistID extracts playlist ID from SPT URL; TIDAL and Amazon Music links give a prefixed ID
 func (o *Orchestrator) extractPlaylistID(url string) (string, error) {
-	// Expected format: https://open.spotify.com/playlist/37i9dQZF1DXcBWIGoYBM5M?si=...
-	parts := strings.Split(url, "/")
-	if len(parts) < 2 {
-		return "", fmt.Errorf("invalid SPT URL format")
-	}
-
-	for i, part := range parts {
-		if part == "playlist" && i+1 < len(parts) {
-			playlistID := parts[i+1]
-			// Remove query parameters if present
-			if idx := strings.Index(playlistID, "?"); idx != -1 {
-				playlistID = playlistID[:idx]
-			}
-			return playlistID, nil
-		}
+	if parser, ok := o.customSource.(SourceIDParser); ok {
+		return parser.PlaylistIDFromURL(url)
 	}
 
-	return "", fmt.Errorf("playlist ID not found in URL")
+	switch {
+	case localfile.IsPlaylistFile(url):
+		return o.localFiles().PlaylistIDFromPath(url)
+	case tidal.IsTidalURL(url):
+		return tidal.PlaylistIDFromURL(url)
+	case amazon.IsAmazonURL(url):
+		return amazon.PlaylistIDFromURL(url)
+	case tubo.IsYouTubeURL(url):
+		return tubo.PlaylistIDFromURL(url)
+	}
+	return spt.PlaylistIDFromURL(url)
+}
+
+// sourceName names the service a source playlist ID belongs to
+func sourceName(playlistID string) string {
+	switch {
+	case tidal.IsTidalID(playlistID):
+		return "TIDAL"
+	case amazon.IsAmazonID(playlistID):
+		return "Amazon Music"
+	case tubo.IsYouTubeID(playlistID):
+		return "YouTube"
+	case localfile.IsLocalID(playlistID):
+		return "playlist file"
+	}
+	return "Spotify"
 }
 
-// matchTracks searches for each track on YouTube (with offset for progress display)
-func (o *Orchestrator) matchTracks(tracks []models.Track, startOffset int) ([]models.MatchResult, error) {
+// matchTracks searches for each track on YouTube (with offset for progress display);
+// tracks annotated "skip" are recorded as failed without searching
+func (o *Orchestrator) matchTracks(tracks []models.Track, startOffset int, notes map[string]string) ([]models.MatchResult, error) {
 	results := make([]models.MatchResult, 0, len(tracks))
+	if err := o.checkStrictISRC(); err != nil {
+		return nil, err
+	}
 
+	// Artists whose every track failed before are skipped to save quota
+	artists, err := o.stateManager.LoadArtistHistory()
+	if err != nil {
+		return nil, fmt.Errorf("loading artist history: %w", err)
+	}
+	defer func() {
+		if err := o.stateManager.SaveArtistHistory(artists); err != nil {
+			o.writeToLog("⚠️  Could not save artist history: %v", err)
+		}
+	}()
+	defer o.saveMusicBrainzCache()
+
+	// Matches imported from shared mapping files replace the search
+	mappings := o.loadMappings()
+
+	// Searches made ahead of time by warm-cache are used once, then dropped
+	searchCache := o.loadSearchCache()
+	cacheHits := 0
+	defer func() {
+		if cacheHits == 0 {
+			return
+		}
+		o.writeToLog("⚡ %d searches came from the warm-cache search cache", cacheHits)
+		if err := o.stateManager.SaveSearchCache(searchCache); err != nil {
+			o.writeToLog("⚠️  Could not save search cache: %v", err)
+		}
+	}()
+
+	o.budgetExhausted = false
+	o.odesliLimited = false
+	o.failedAt = ""
 	for i, track := range tracks {
 		actualTrackNumber := startOffset + i + 1
 
+		// Finish the batch early once the time budget is used up; the rest stays pending
+		if o.outOfTime() {
+			o.budgetExhausted = true
+			o.writeToLog("⏱️  %s reached after %d of %d tracks", o.stopReason(), i, len(tracks))
+			break
+		}
+
 		// Show progress in terminal (clean)
-		fmt.Printf("\r🎵 Matching tracks: %d/%d - %s",
+		ui.Printf("\r🎵 Matching tracks: %d/%d - %s",
 			actualTrackNumber,
 			startOffset+len(tracks),
 			truncateString(fmt.Sprintf("%s - %s", track.Artist, track.Title), 40))
 
-		// Detailed logging to file
-		o.writeToLog("\n--- TRACK %d ---", actualTrackNumber)
+		// Detailed logging to file; the track's lines carry its trace ID
+		trace := o.startTrace()
+		o.writeToLog("--- TRACK %d ---", actualTrackNumber)
 		o.writeToLog("Searching for: \"%s\" by \"%s\"", track.Title, track.Artist)
 		o.writeToLog("Normalized: \"%s\" by \"%s\"", track.NormalizedTitle, track.NormalizedArtist)
 
-		matchedTrack, score, err := o.tuboClient.SearchTrack(track)
+		if note := notes[track.ID]; note != "" {
+			o.writeToLog("📝 Note: %s", note)
+			if state.IsSkipNote(note) {
+				results = append(results, models.MatchResult{
+					OriginalTrack: track,
+					Matched:       false,
+					Error:         errSkippedByNote,
+					Trace:         trace,
+				})
+				continue
+			}
+		}
+
+		if mapping := mappings.Lookup(track.ID); mapping != nil {
+			o.writeToLog("🗺️  Mapped to video %s (imported from %s), no search needed", mapping.VideoID, mapping.Origin)
+			artists.Record(track.Artist, true)
+			// Mapping files may come from other people, so strict mode checks them like a search
+			result := mappedResult(track, mapping, trace)
+			if err := o.corroborate(&result); err != nil {
+				o.budgetExhausted = true
+				o.odesliLimited = true
+				o.writeToLog("⏸️  %s reached while corroborating track %d", o.stopReason(), actualTrackNumber)
+				break
+			}
+			results = append(results, result)
+			continue
+		}
+
+		// Without an ISRC no match could be corroborated, so the search is saved
+		if o.strictISRC && track.ISRC == "" {
+			o.writeToLog("🔒 Skipped: no ISRC, strict ISRC mode can't check a match")
+			results = append(results, models.MatchResult{
+				OriginalTrack: track,
+				Matched:       false,
+				Error:         errNoISRC,
+				Trace:         trace,
+			})
+			continue
+		}
+
+		if artists.IsKnownUnavailable(track.Artist) {
+			o.writeToLog("⏭️  Skipped: no track by \"%s\" has ever matched", track.Artist)
+			results = append(results, models.MatchResult{
+				OriginalTrack: track,
+				Matched:       false,
+				Error:         errArtistUnavailable,
+				Trace:         trace,
+			})
+			continue
+		}
+
+		o.enrichTrack(&track)
+
+		searchStart := time.Now()
+		outcome, cached := o.takeCachedSearch(searchCache, track)
+		var err error
+		if cached {
+			cacheHits++
+		} else {
+			outcome, err = o.dest.SearchTrackOutcome(track)
+		}
+		searchTime := time.Since(searchStart)
+		if err != nil && traffic.Aborted() {
+			// Cut off by the run timeout: the track stays pending instead of failing
+			o.budgetExhausted = true
+			o.writeToLog("⏱️  %s reached during the search for track %d", o.stopReason(), actualTrackNumber)
+			break
+		}
 		if err != nil {
+			o.addStageDuration(stageSearch, searchTime)
 			o.writeToLog("❌ Search error: %v", err)
 			results = append(results, models.MatchResult{
 				OriginalTrack: track,
 				Matched:       false,
-				Error:         err.Error(),
+				Error:         tracedError(err, trace),
+				Trace:         trace,
 			})
+			if o.resolveFailure(&results[len(results)-1]) {
+				break
+			}
 			continue
 		}
 
-		if matchedTrack != nil {
-			o.writeToLog("✅ MATCH FOUND (score: %.2f)", score)
+		o.addStageDuration(stageSearch, searchTime-outcome.ScoreTime)
+		o.addStageDuration(stageScore, outcome.ScoreTime)
+
+		artists.Record(track.Artist, outcome.Track != nil)
+
+		if matchedTrack := outcome.Track; matchedTrack != nil {
+			o.writeToLog("✅ MATCH FOUND (score: %.2f, strategy: %s)", outcome.Score, outcome.Strategy)
 			o.writeToLog("   YouTube: \"%s\" by \"%s\"", matchedTrack.Title, matchedTrack.Artist)
 			o.writeToLog("   Video ID: %s", matchedTrack.ID)
 
-			results = append(results, models.MatchResult{
+			result := models.MatchResult{
 				OriginalTrack: track,
 				MatchedTrack:  matchedTrack,
-				MatchScore:    score,
+				MatchScore:    outcome.Score,
 				Matched:       true,
-			})
+				Strategy:      outcome.Strategy,
+				SearchesUsed:  outcome.SearchesUsed,
+				Trace:         trace,
+			}
+			if o.strictISRC {
+				if err := o.corroborate(&result); err != nil {
+					// Odesli can't be asked right now: the track stays pending for the next run
+					o.budgetExhausted = true
+					o.odesliLimited = true
+					o.writeToLog("⏸️  %s reached while corroborating track %d", o.stopReason(), actualTrackNumber)
+					break
+				}
+			} else if !o.verifyFingerprint(&result) {
+				o.writeToLog("❌ Rejected: the audio belongs to a different recording")
+				result.MatchedTrack = nil
+				result.Matched = false
+				result.Error = "fingerprint: audio of the best match is a different recording"
+			}
+			results = append(results, result)
 		} else {
 			o.writeToLog("❌ NO MATCH FOUND")
 			results = append(results, models.MatchResult{
 				OriginalTrack: track,
 				Matched:       false,
+				SearchesUsed:  outcome.SearchesUsed,
+				Trace:         trace,
 			})
 		}
+
+		if last := &results[len(results)-1]; !last.Matched && o.resolveFailure(last) {
+			break
+		}
+	}
+	o.endTrace()
+
+	for i := range results {
+		stampRules(&results[i])
 	}
 
 	// Clear progress line
-	fmt.Printf("\r🎵 Batch matching complete!                                        \n")
+	if o.failedAt != "" {
+		ui.Printf("\r🛑 Stopped at a failed match after %d tracks                \n", len(results))
+	} else if o.budgetExhausted {
+		ui.Printf("\r⏱️  %s reached, stopped after %d tracks                \n", o.stopReason(), len(results))
+	} else {
+		ui.Printf("\r🎵 Batch matching complete!                                        \n")
+	}
 
 	return results, nil
 }
 
 // manageYouTubePlaylist creates or updates the YouTube playlist
 func (o *Orchestrator) manageYouTubePlaylist(portingState *state.PortingState, newResults []models.MatchResult) error {
-	// Get video IDs from new results
+	if portingState.IsArchive() {
+		return o.manageArchivePlaylists(portingState, newResults)
+	}
+	if len(o.splitRules) > 0 {
+		return o.manageSplitPlaylists(portingState, newResults)
+	}
+
+	playlistName := fmt.Sprintf("%s (Ported from %s)", portingState.OriginalPlaylist.Name, sourceName(portingState.OriginalPlaylist.ID))
+	return o.addToPlaylist(portingState, &portingState.YouTubePlaylistID, &portingState.YouTubePlaylistName,
+		playlistName, newResults)
+}
+
+// addToPlaylist adds matched results to a YouTube playlist, creating it first if playlistID is empty
+func (o *Orchestrator) addToPlaylist(portingState *state.PortingState, playlistID, playlistName *string,
+	newName string, newResults []models.MatchResult) error {
+	// Get video IDs from new results (failed tracks get the placeholder video, if configured)
 	var newVideoIDs []string
+	var entries []models.MatchResult
 	for _, result := range newResults {
 		if result.Matched && result.MatchedTrack != nil {
 			newVideoIDs = append(newVideoIDs, result.MatchedTrack.ID)
+			entries = append(entries, result)
+		} else if placeholder := o.cfg.TUBO.PlaceholderVideoID; placeholder != "" {
+			newVideoIDs = append(newVideoIDs, placeholder)
+			entries = append(entries, result)
 		}
 	}
 
@@ -411,28 +1376,53 @@ func (o *Orchestrator) manageYouTubePlaylist(portingState *state.PortingState, n
 	}
 
 	// If playlist doesn't exist yet, create it
-	if portingState.YouTubePlaylistID == "" {
-		playlistName := fmt.Sprintf("%s (Ported from Spotify)", portingState.OriginalPlaylist.Name)
-		description := fmt.Sprintf("Ported from Spotify using PlaylistPorter. Original: %s", portingState.SpotifyURL)
+	if *playlistID == "" {
+		description := fmt.Sprintf("Ported from %s using PlaylistPorter. Original: %s",
+			sourceName(portingState.OriginalPlaylist.ID), portingState.SpotifyURL)
 
-		fmt.Printf("📝 Creating YouTube playlist: \"%s\"\n", playlistName)
-		o.writeToLog("Creating YouTube playlist: %s", playlistName)
+		ui.Printf("📝 Creating %s playlist: \"%s\"\n", o.destinationLabel(), newName)
+		o.writeToLog("Creating %s playlist: %s", o.destinationLabel(), newName)
 
-		playlist, err := o.tuboClient.CreatePlaylist(playlistName, description)
+		createStart := time.Now()
+		playlist, err := o.dest.CreatePlaylist(newName, description)
+		o.addStageTime(stageInsert, createStart)
 		if err != nil {
 			return fmt.Errorf("creating playlist: %w", err)
 		}
 
-		portingState.YouTubePlaylistID = playlist.ID
-		portingState.YouTubePlaylistName = playlist.Name
+		*playlistID = playlist.ID
+		*playlistName = playlist.Name
 		o.writeToLog("Created playlist with ID: %s", playlist.ID)
 	}
 
 	// Add new tracks to playlist
-	fmt.Printf("📝 Adding %d tracks to YouTube playlist...\n", len(newVideoIDs))
-	o.writeToLog("Adding %d tracks to existing playlist %s", len(newVideoIDs), portingState.YouTubePlaylistID)
+	ui.Printf("📝 Adding %d tracks to %s playlist...\n", len(newVideoIDs), o.destinationLabel())
+	o.writeToLog("Adding %d tracks to existing playlist %s", len(newVideoIDs), *playlistID)
+
+	insertStart := time.Now()
+	itemIDs, err := o.dest.AddTracksToPlaylist(*playlistID, newVideoIDs)
+	o.addStageTime(stageInsert, insertStart)
+
+	// Remember placeholder items and uploaded matches, even from a partially completed batch;
+	// the error path below saves them
+	placeholders := 0
+	for i, itemID := range itemIDs {
+		if !entries[i].Matched {
+			portingState.SetPlaceholder(entries[i].OriginalTrack.ID, itemID)
+			placeholders++
+		}
+	}
+	portingState.MarkUploaded(entries[:len(itemIDs)])
 
-	if err := o.tuboClient.AddTracksToPlaylist(portingState.YouTubePlaylistID, newVideoIDs); err != nil {
+	if err != nil {
+		// Save what did land (and a playlist created just now), so the next run neither adds
+		// those items again nor leaves the new playlist behind
+		if saveErr := o.stateManager.SaveState(portingState); saveErr != nil {
+			o.writeToLog("Saving the partial upload failed: %v", saveErr)
+		} else if len(itemIDs) > 0 {
+			ui.Printf("💾 Saved the %d of %d tracks added before the error (%d placeholders)\n",
+				len(itemIDs), len(newVideoIDs), placeholders)
+		}
 		return fmt.Errorf("adding tracks to playlist: %w", err)
 	}
 
@@ -444,30 +1434,48 @@ func (o *Orchestrator) manageYouTubePlaylist(portingState *state.PortingState, n
 func (o *Orchestrator) reportSessionResults(portingState *state.PortingState, sessionResults []models.MatchResult) {
 	successful := 0
 	failed := 0
+	lowConfidence := 0
 
 	for _, result := range sessionResults {
 		if result.Matched {
 			successful++
+			if result.MatchScore < ui.LowConfidenceScore {
+				lowConfidence++
+			}
 		} else {
 			failed++
 		}
 	}
 
-	fmt.Printf("\n📊 SESSION RESULTS\n")
-	fmt.Printf("==================\n")
-	fmt.Printf("🎵 Tracks processed: %d\n", len(sessionResults))
-	fmt.Printf("✅ Successfully matched: %d\n", successful)
-	fmt.Printf("❌ Failed to match: %d\n", failed)
-	fmt.Printf("📈 Session success rate: %.1f%%\n", float64(successful)/float64(len(sessionResults))*100)
-	fmt.Printf("\n📊 OVERALL PROGRESS\n")
-	fmt.Printf("==================\n")
-	fmt.Printf("📋 Total progress: %s\n", portingState.GetProgress())
-	fmt.Printf("🔗 YouTube playlist: https://www.youtube.com/playlist?list=%s\n", portingState.YouTubePlaylistID)
+	ui.Summaryf("\n📊 SESSION RESULTS\n")
+	ui.Summaryf("==================\n")
+	ui.Summaryf("🎵 Tracks processed: %d\n", len(sessionResults))
+	ui.Summaryf("%s\n", ui.Green(fmt.Sprintf("✅ Successfully matched: %d", successful)))
+	if lowConfidence > 0 {
+		ui.Summaryf("%s\n", ui.Yellow(fmt.Sprintf("⚠️  Low-confidence matches: %d (score below %.2f, worth a check)", lowConfidence, ui.LowConfidenceScore)))
+	}
+	ui.Summaryf("%s\n", ui.Red(fmt.Sprintf("❌ Failed to match: %d", failed)))
+	if len(sessionResults) > 0 {
+		ui.Summaryf("📈 Session success rate: %.1f%%\n", float64(successful)/float64(len(sessionResults))*100)
+	}
+	ui.Summaryf("🔖 Run ID: %s (log lines and search errors carry it; quote it in bug reports)\n", o.runID)
+	ui.Summaryf("\n📊 OVERALL PROGRESS\n")
+	ui.Summaryf("==================\n")
+	ui.Summaryf("📋 Total progress: %s\n", portingState.GetProgress())
+	ui.Summaryf("🔗 YouTube playlist: %s\n", o.playlistURL(portingState.YouTubePlaylistID))
 
 	// Estimate quota usage
-	quotaEstimate := len(sessionResults) * 200 // Rough estimate
-	fmt.Printf("📊 Estimated quota used this session: ~%d units\n", quotaEstimate)
-	fmt.Printf("📊 Total estimated quota used: ~%d units\n", portingState.GetTotalQuotaUsed())
+	quotaEstimate := len(sessionResults) * o.trackCost() // Rough estimate, see the quota config
+	ui.Summaryf("📊 Estimated quota used this session: ~%d units\n", quotaEstimate)
+	ui.Summaryf("📊 Total estimated quota used: ~%d units\n", portingState.GetTotalQuotaUsed())
+	if timings := formatStageTimes(o.stageTimes); timings != "" {
+		ui.Summaryf("⏱️  Time per stage: %s\n", timings)
+		o.writeToLog("Stage timings: %s", timings)
+	}
+	if traffic.IsLight() {
+		ui.Summaryf("📶 Data transferred this run: %s received, %s sent\n",
+			ui.FormatBytes(traffic.Received()), ui.FormatBytes(traffic.Sent()))
+	}
 }
 
 // reportFinalResults prints final summary when porting is complete
@@ -475,6 +1483,7 @@ func (o *Orchestrator) reportFinalResults(portingState *state.PortingState) {
 	successful := 0
 	failed := 0
 	var failedTracks []models.Track
+	var failedResults []models.MatchResult
 
 	for _, result := range portingState.MatchResults {
 		if result.Matched {
@@ -482,30 +1491,41 @@ func (o *Orchestrator) reportFinalResults(portingState *state.PortingState) {
 		} else {
 			failed++
 			failedTracks = append(failedTracks, result.OriginalTrack)
+			failedResults = append(failedResults, result)
 		}
 	}
 
-	fmt.Printf("\n🎉 FINAL RESULTS\n")
-	fmt.Printf("==================\n")
-	fmt.Printf("📋 Playlist: %s\n", portingState.OriginalPlaylist.Name)
-	fmt.Printf("📊 Total tracks: %d\n", portingState.TotalTracks)
-	fmt.Printf("✅ Successfully matched: %d\n", successful)
-	fmt.Printf("❌ Failed to match: %d\n", failed)
-	fmt.Printf("📈 Success rate: %.1f%%\n", float64(successful)/float64(portingState.TotalTracks)*100)
-	fmt.Printf("📅 Sessions required: %d\n", len(portingState.Sessions))
-	fmt.Printf("🔗 YouTube playlist: https://www.youtube.com/playlist?list=%s\n", portingState.YouTubePlaylistID)
+	ui.Printf("\n🎉 FINAL RESULTS\n")
+	ui.Printf("==================\n")
+	ui.Printf("📋 Playlist: %s\n", portingState.OriginalPlaylist.Name)
+	ui.Printf("📊 Total tracks: %d\n", portingState.TotalTracks)
+	ui.Printf("%s\n", ui.Green(fmt.Sprintf("✅ Successfully matched: %d", successful)))
+	ui.Printf("%s\n", ui.Red(fmt.Sprintf("❌ Failed to match: %d", failed)))
+	ui.Printf("📈 Success rate: %.1f%%\n", float64(successful)/float64(portingState.TotalTracks)*100)
+	ui.Printf("📅 Sessions required: %d\n", len(portingState.Sessions))
+	ui.Printf("🔗 YouTube playlist: %s\n", o.playlistURL(portingState.YouTubePlaylistID))
+	for _, target := range portingState.Targets {
+		ui.Printf("🔗 %s: %s\n", target.Name, o.playlistURL(target.YouTubePlaylistID))
+	}
 
 	// Show failed tracks
 	if len(failedTracks) > 0 && len(failedTracks) <= 10 {
-		fmt.Printf("\n❌ Failed to match:\n")
-		for _, track := range failedTracks {
-			fmt.Printf("    • %s - %s\n", track.Artist, track.Title)
+		ui.Printf("\n❌ Failed to match:\n")
+		for i, track := range failedTracks {
+			ui.Printf("    • %s%s\n", ui.Red(fmt.Sprintf("%s - %s", track.Artist, track.Title)), noteSuffix(portingState, track.ID))
+			if suggestions := triage.Suggest(failedResults[i], portingState.GetDestination()); len(suggestions) > 0 {
+				ui.Printf("      💡 %s\n", suggestions[0])
+			}
 		}
 	} else if len(failedTracks) > 10 {
-		fmt.Printf("\n❌ Failed to match %d tracks (showing first 10):\n", len(failedTracks))
+		ui.Printf("\n❌ Failed to match %d tracks (showing first 10):\n", len(failedTracks))
 		for i := 0; i < 10; i++ {
-			fmt.Printf("    • %s - %s\n", failedTracks[i].Artist, failedTracks[i].Title)
+			ui.Printf("    • %s%s\n", ui.Red(fmt.Sprintf("%s - %s", failedTracks[i].Artist, failedTracks[i].Title)), noteSuffix(portingState, failedTracks[i].ID))
+			if suggestions := triage.Suggest(failedResults[i], portingState.GetDestination()); len(suggestions) > 0 {
+				ui.Printf("      💡 %s\n", suggestions[0])
+			}
 		}
+		ui.Printf("    Run stateviewer -detailed for every failed track with suggestions\n")
 	}
 }
 
@@ -519,3 +1539,23 @@ func truncateString(s string, length int) string {
 	}
 	return s[:length-3] + "..."
 }
+
+// normalizeStateTracks fills in the normalized metadata of the state's tracks. Saved values are
+// reused unless the normalization rules changed since they were computed, in which case
+// every track, including those of earlier match results, is normalized again.
+func (o *Orchestrator) normalizeStateTracks(portingState *state.PortingState) {
+	if portingState.NormalizerVersion != processor.RulesVersion {
+		o.processor.NormalizePlaylist(&portingState.OriginalPlaylist)
+		for i := range portingState.MatchResults {
+			o.processor.NormalizeTrack(&portingState.MatchResults[i].OriginalTrack)
+		}
+		o.writeToLog("Normalized %d tracks (rules version %d, saved version %d)",
+			len(portingState.OriginalPlaylist.Tracks), processor.RulesVersion, portingState.NormalizerVersion)
+		portingState.NormalizerVersion = processor.RulesVersion
+		return
+	}
+
+	if count := o.processor.NormalizeMissing(portingState.OriginalPlaylist.Tracks); count > 0 {
+		o.writeToLog("Normalized %d new tracks, reused saved metadata for the rest", count)
+	}
+}
@@ -0,0 +1,205 @@
+package orchestrator
+
+import (
+	"fmt"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/processor"
+	"github.com/Verryx-02/PlaylistPorter/internal/state"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// stampRules records the current normalization and matching rules on a result
+func stampRules(result *models.MatchResult) {
+	result.NormalizerVersion = processor.RulesVersion
+	result.MatcherVersion = processor.MatcherVersion
+}
+
+// outdatedResults lists the results produced by older rules, leaving out tracks annotated "skip"
+func outdatedResults(portingState *state.PortingState) []models.MatchResult {
+	var outdated []models.MatchResult
+	for _, result := range portingState.GetOutdatedResults(processor.RulesVersion, processor.MatcherVersion) {
+		if !state.IsSkipNote(portingState.GetNote(result.OriginalTrack.ID)) {
+			outdated = append(outdated, result)
+		}
+	}
+	return outdated
+}
+
+// ListOutdated prints the results of a saved state that older normalization or matching rules produced
+func (o *Orchestrator) ListOutdated(sptURL string) error {
+	defer o.Close()
+
+	playlistID, err := o.extractPlaylistID(sptURL)
+	if err != nil {
+		return fmt.Errorf("extracting playlist ID: %w", err)
+	}
+
+	// Listing only reads the saved state, no API clients needed
+	stateManager, err := state.NewManager(o.statesPath())
+	if err != nil {
+		return fmt.Errorf("creating state manager: %w", err)
+	}
+
+	portingState, err := stateManager.LoadState(playlistID)
+	if err != nil {
+		return fmt.Errorf("loading state: %w", err)
+	}
+	if portingState == nil {
+		return fmt.Errorf("no saved state for playlist %s, port it first", playlistID)
+	}
+
+	outdated := outdatedResults(portingState)
+	if len(outdated) == 0 {
+		ui.Printf("✅ All %d results of \"%s\" were produced by the current rules (normalizer v%d, matcher v%d)\n",
+			len(portingState.MatchResults), portingState.OriginalPlaylist.Name, processor.RulesVersion, processor.MatcherVersion)
+		return nil
+	}
+
+	ui.Printf("🧮 %d of %d results of \"%s\" come from older rules (current: normalizer v%d, matcher v%d)\n",
+		len(outdated), len(portingState.MatchResults), portingState.OriginalPlaylist.Name,
+		processor.RulesVersion, processor.MatcherVersion)
+	for _, result := range outdated {
+		status := "❌ not matched"
+		if result.Matched && result.MatchedTrack != nil {
+			status = fmt.Sprintf("✅ %s (score %.2f)", result.MatchedTrack.ID, result.MatchScore)
+		}
+		ui.Printf("   %s - %s: %s [normalizer v%d, matcher v%d]\n",
+			result.OriginalTrack.Artist, result.OriginalTrack.Title, status,
+			result.NormalizerVersion, result.MatcherVersion)
+	}
+	ui.Printf("\n💡 Re-match them with: playlistporter -url %s -rematch-outdated\n", sptURL)
+
+	return nil
+}
+
+// RematchOutdated searches again for tracks whose results older rules produced, at most maxTracks
+// per run. Changed matches replace the old video in place; a previous match is kept when the
+// new rules find nothing, so an upgrade never loses a track.
+func (o *Orchestrator) RematchOutdated(sptURL string) error {
+	defer o.Close()
+
+	o.writeToLog("Re-matching outdated results for: %s", sptURL)
+
+	// Changed matches are swapped at their playlist position, which needs YouTube
+	if err := o.requireYouTube("-rematch-outdated"); err != nil {
+		return err
+	}
+
+	if err := o.initializeClients(); err != nil {
+		return fmt.Errorf("initializing clients: %w", err)
+	}
+
+	playlistID, err := o.extractPlaylistID(sptURL)
+	if err != nil {
+		return fmt.Errorf("extracting playlist ID: %w", err)
+	}
+
+	portingState, err := o.stateManager.LoadState(playlistID)
+	if err != nil {
+		return fmt.Errorf("loading state: %w", err)
+	}
+	if portingState == nil {
+		return fmt.Errorf("no saved state for playlist %s, nothing to re-match", playlistID)
+	}
+	o.reportRecovery(portingState)
+	if err := o.checkDestination(portingState); err != nil {
+		return err
+	}
+
+	o.normalizeStateTracks(portingState)
+
+	outdated := outdatedResults(portingState)
+	if len(outdated) == 0 {
+		ui.Printf("✅ All results were produced by the current rules\n")
+		return nil
+	}
+	if len(outdated) > o.maxTracks {
+		outdated = outdated[:o.maxTracks]
+	}
+
+	ui.Printf("🧮 Re-matching %d tracks matched by older rules\n", len(outdated))
+	portingState.StartNewSession(o.sessionNote, o.runID)
+
+	var tracks []models.Track
+	for _, result := range outdated {
+		tracks = append(tracks, result.OriginalTrack)
+	}
+
+	o.writeToLog("\n=== RE-MATCHING OUTDATED RESULTS ===")
+	results, err := o.matchTracks(tracks, 0, portingState.TrackNotes)
+	if err != nil {
+		return fmt.Errorf("matching tracks: %w", err)
+	}
+
+	var items map[string]playlistItemRef
+	changed, unchanged, kept, recovered, sessionMatches := 0, 0, 0, 0, 0
+	for i, result := range results {
+		old := outdated[i]
+		oldMatched := old.Matched && old.MatchedTrack != nil
+		if result.Matched {
+			sessionMatches++
+		}
+
+		switch {
+		case !result.Matched && oldMatched:
+			// Keep the earlier match rather than dropping the track
+			stampRules(&old)
+			portingState.ReplaceMatchResult(old)
+			kept++
+			o.writeToLog("Kept %s for \"%s\": the current rules found no match", old.MatchedTrack.ID, old.OriginalTrack.Title)
+
+		case result.Matched && oldMatched && result.MatchedTrack.ID == old.MatchedTrack.ID:
+			portingState.ReplaceMatchResult(result)
+			unchanged++
+
+		case result.Matched && oldMatched:
+			if items == nil {
+				if items, err = o.findPlaylistItems(portingState); err != nil {
+					return fmt.Errorf("listing playlist items: %w", err)
+				}
+			}
+			if err := o.replacePlaylistItem(items[old.MatchedTrack.ID], result); err != nil {
+				ui.Printf("⚠️  Could not update the playlist for \"%s\": %v\n", result.OriginalTrack.Title, err)
+				o.writeToLog("❌ Could not update playlist item: %v", err)
+				continue
+			}
+			portingState.RecordSubstitution(old.MatchedTrack.ID, result, "re-matched with newer matching rules")
+			changed++
+			o.writeToLog("Replaced %s with %s for \"%s\"", old.MatchedTrack.ID, result.MatchedTrack.ID, result.OriginalTrack.Title)
+
+		case result.Matched:
+			portingState.ReplaceMatchResult(result)
+			recovered++
+
+			itemID := portingState.PlaceholderItems[result.OriginalTrack.ID]
+			if itemID == "" || o.phase == PhaseMatch {
+				continue // Added by the regular upload below
+			}
+			if err := o.replacePlaceholder(portingState, result, itemID); err != nil {
+				o.writeToLog("❌ Could not replace placeholder for %s: %v", result.OriginalTrack.ID, err)
+				ui.Printf("⚠️  Could not replace placeholder for \"%s\": %v\n", result.OriginalTrack.Title, err)
+			}
+
+		default:
+			portingState.ReplaceMatchResult(result)
+		}
+	}
+	portingState.EndCurrentSession(len(results), sessionMatches, o.trackCost())
+
+	if o.phase == PhaseMatch {
+		if err := o.stateManager.SaveState(portingState); err != nil {
+			return fmt.Errorf("saving state: %w", err)
+		}
+		ui.Printf("💾 Progress saved to checkpoint\n")
+	} else if err := o.uploadPending(portingState); err != nil {
+		return err
+	}
+
+	ui.Printf("\n🧮 Re-match complete: %d changed, %d unchanged, %d newly matched, %d kept their earlier match\n",
+		changed, unchanged, recovered, kept)
+	if remaining := len(outdatedResults(portingState)); remaining > 0 {
+		ui.Printf("   %d outdated results left for the next run\n", remaining)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package orchestrator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/mapfile"
+	"github.com/Verryx-02/PlaylistPorter/internal/state"
+	"github.com/Verryx-02/PlaylistPorter/internal/tubo"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// writePositionNotes refreshes the block listing the source track of every position in the
+// descriptions of the state's YouTube playlists when tubo.position_notes is set. Positions are
+// read from YouTube, so videos moved or removed by hand are listed where they are now. A
+// failure is reported and doesn't stop the run.
+func (o *Orchestrator) writePositionNotes(portingState *state.PortingState) {
+	if !o.cfg.TUBO.PositionNotes || o.tuboClient == nil || o.customDest != nil ||
+		portingState.GetDestination() != DestYouTube || portingState.IsArchive() {
+		return
+	}
+
+	playlists := map[string]string{portingState.YouTubePlaylistID: ""}
+	for _, target := range portingState.Targets {
+		playlists[target.YouTubePlaylistID] = target.Name
+	}
+	for playlistID, target := range playlists {
+		if playlistID == "" {
+			continue
+		}
+		if err := o.writePlaylistPositions(portingState, playlistID, target); err != nil {
+			ui.Printf("⚠️  Could not update the position notes of %s: %v\n", o.playlistURL(playlistID), err)
+			o.writeToLog("Position notes of %s not updated: %v", playlistID, err)
+		}
+	}
+}
+
+// writePlaylistPositions writes the position notes of one playlist, the main one or the split
+// target of that name; the description is only updated when the notes changed
+func (o *Orchestrator) writePlaylistPositions(portingState *state.PortingState, playlistID, target string) error {
+	items, err := o.tuboClient.ListPlaylistItems(playlistID)
+	if err != nil {
+		return fmt.Errorf("listing playlist items: %w", err)
+	}
+
+	// Placeholders are known by their item, matches by their video in the order they were added
+	placeholders := make(map[string]string)
+	for trackID, itemID := range portingState.PlaceholderItems {
+		placeholders[itemID] = trackID
+	}
+	byVideo := make(map[string][]string)
+	for _, result := range portingState.MatchResults {
+		trackID := result.OriginalTrack.ID
+		if !result.Matched || result.MatchedTrack == nil || portingState.TrackTargets[trackID] != target {
+			continue
+		}
+		byVideo[result.MatchedTrack.ID] = append(byVideo[result.MatchedTrack.ID], trackID)
+	}
+
+	trackIDs := make([]string, len(items))
+	for _, item := range items {
+		if item.Position < 0 || item.Position >= len(trackIDs) {
+			continue
+		}
+		if trackID, ok := placeholders[item.ID]; ok {
+			trackIDs[item.Position] = trackID
+		} else if queue := byVideo[item.VideoID]; len(queue) > 0 {
+			trackIDs[item.Position] = queue[0]
+			byVideo[item.VideoID] = queue[1:]
+		}
+	}
+
+	title, description, err := o.tuboClient.GetPlaylistSnippet(playlistID)
+	if err != nil {
+		return fmt.Errorf("reading description: %w", err)
+	}
+	updated := tubo.WithPositions(description, trackIDs)
+	if updated == description {
+		o.writeToLog("Position notes of %s are up to date", playlistID)
+		return nil
+	}
+	if err := o.tuboClient.SetPlaylistDescription(playlistID, title, updated); err != nil {
+		return fmt.Errorf("updating description: %w", err)
+	}
+	o.writeToLog("📝 Position notes of %s updated (%d positions)", playlistID, len(trackIDs))
+	return nil
+}
+
+// ReadPositionNotes reads the position notes of a YouTube playlist and prints the source
+// track of every position. With outPath, the tracks and the videos now at their positions
+// are written as a mapping file that `mappings -import` accepts.
+func (o *Orchestrator) ReadPositionNotes(youtubeURL, outPath string) error {
+	defer o.Close()
+
+	playlistID, err := tubo.PlaylistIDFromURL(youtubeURL)
+	if err != nil {
+		return err
+	}
+	playlistID = strings.TrimPrefix(playlistID, tubo.IDPrefix)
+
+	if err := o.initializeTubo(); err != nil {
+		return err
+	}
+	title, description, err := o.tuboClient.GetPlaylistSnippet(playlistID)
+	if err != nil {
+		return fmt.Errorf("reading description: %w", err)
+	}
+	trackIDs, total, found := tubo.ParsePositions(description)
+	if !found {
+		return fmt.Errorf("%s has no position notes; set tubo.position_notes and run the playlist once", title)
+	}
+
+	items, err := o.tuboClient.ListPlaylistItems(playlistID)
+	if err != nil {
+		return fmt.Errorf("listing playlist items: %w", err)
+	}
+	videos := make(map[int]string, len(items))
+	for _, item := range items {
+		videos[item.Position] = item.VideoID
+	}
+
+	ui.Printf("📍 Position notes of \"%s\" (%d positions)\n\n", title, total)
+	var entries []mapfile.Entry
+	for i, trackID := range trackIDs {
+		if trackID == "" {
+			continue
+		}
+		videoID := videos[i]
+		ui.Printf("%4d  %s → %s\n", i+1, trackID, videoID)
+		if entry := (mapfile.Entry{TrackID: trackID, VideoID: videoID}); mapfile.Valid(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	if len(trackIDs) < total {
+		ui.Printf("\n⚠️  The description only had room for the first %d positions\n", len(trackIDs))
+	}
+	if len(items) != total {
+		ui.Printf("⚠️  The playlist has %d items now; it changed since the notes were written\n", len(items))
+	}
+
+	if outPath == "" {
+		return nil
+	}
+	if err := mapfile.Write(outPath, entries); err != nil {
+		return err
+	}
+	ui.Printf("\n📤 Wrote %d mappings to %s\n", len(entries), outPath)
+	return nil
+}
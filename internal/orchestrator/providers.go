@@ -0,0 +1,118 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/amazon"
+	"github.com/Verryx-02/PlaylistPorter/internal/localfile"
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/spt"
+	"github.com/Verryx-02/PlaylistPorter/internal/tidal"
+	"github.com/Verryx-02/PlaylistPorter/internal/tubo"
+)
+
+// Source is a service playlists are read from
+type Source interface {
+	GetPlaylist(id string) (*models.Playlist, error)
+}
+
+// Destination is a service matched tracks are searched on and uploaded to.
+// AddTracksToPlaylist returns one item ID per added track, in order.
+type Destination interface {
+	SearchTrackOutcome(track models.Track) (*models.SearchOutcome, error)
+	CreatePlaylist(name, description string) (*models.Playlist, error)
+	AddTracksToPlaylist(playlistID string, trackIDs []string) ([]string, error)
+	PlaylistURL(playlistID string) string
+}
+
+// SourceIDParser is implemented by custom sources whose links the built-in services don't know
+type SourceIDParser interface {
+	PlaylistIDFromURL(url string) (string, error)
+}
+
+// UseSource reads every playlist from source instead of the built-in services
+func (o *Orchestrator) UseSource(source Source) {
+	o.customSource = source
+	o.writeToLog("Source: custom %T", source)
+}
+
+// UseDestination ports to dest instead of a built-in service; name is recorded in states
+// and must stay the same across runs of a playlist
+func (o *Orchestrator) UseDestination(name string, dest Destination) {
+	o.customDest = dest
+	o.SetDestination(name)
+}
+
+// sourceFor returns the service a source playlist ID belongs to, creating its client on first use
+func (o *Orchestrator) sourceFor(playlistID string) (Source, error) {
+	switch {
+	case o.customSource != nil:
+		return o.customSource, nil
+	case tubo.IsYouTubeID(playlistID):
+		if o.tuboClient == nil {
+			if err := o.initializeTubo(); err != nil {
+				return nil, err
+			}
+		}
+		return o.tuboClient, nil
+	case localfile.IsLocalID(playlistID):
+		return o.localFiles(), nil
+	case amazon.IsAmazonID(playlistID):
+		if o.amazonReader == nil {
+			o.amazonReader = amazon.NewReader()
+		}
+		return o.amazonReader, nil
+	case tidal.IsTidalID(playlistID):
+		if o.tidalClient == nil {
+			tidalClient, err := tidal.NewClient(&o.cfg.Tidal)
+			if err != nil {
+				return nil, fmt.Errorf("creating TIDAL client: %w", err)
+			}
+			o.tidalClient = tidalClient
+		}
+		return o.tidalClient, nil
+	}
+
+	if o.sptClient == nil {
+		// Liked Songs can only be read with the user's account
+		if spt.IsLikedSongs(playlistID) {
+			o.cfg.SPT.UserAuth = true
+		}
+		if err := o.initializeSpt(); err != nil {
+			return nil, err
+		}
+	}
+	return o.sptClient, nil
+}
+
+// localFiles returns the playlist file reader, which remembers the files passed in this run
+func (o *Orchestrator) localFiles() *localfile.Reader {
+	if o.fileReader == nil {
+		o.fileReader = localfile.NewReader()
+	}
+	return o.fileReader
+}
+
+// fetchPlaylist fetches a source playlist from the service its ID belongs to.
+// Unavailable playlists of every service are reported as spt.ErrPlaylistUnavailable.
+func (o *Orchestrator) fetchPlaylist(playlistID string) (*models.Playlist, error) {
+	source, err := o.sourceFor(playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	playlist, err := source.GetPlaylist(playlistID)
+	o.addStageTime(stageFetch, start)
+	switch {
+	case errors.Is(err, spt.ErrPlaylistUnavailable):
+		return nil, err
+	case errors.Is(err, tubo.ErrPlaylistNotAccessible),
+		errors.Is(err, amazon.ErrPlaylistUnavailable),
+		errors.Is(err, tidal.ErrPlaylistUnavailable):
+		return nil, fmt.Errorf("%w: %v", spt.ErrPlaylistUnavailable, err)
+	}
+	return playlist, err
+}
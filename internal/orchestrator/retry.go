@@ -0,0 +1,129 @@
+package orchestrator
+
+import (
+	"fmt"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/state"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// RetryFailed searches again for tracks that previously failed to match, replacing
+// their placeholder entries in place when a match is found
+func (o *Orchestrator) RetryFailed(sptURL string) error {
+	defer o.Close()
+
+	o.writeToLog("Retrying failed tracks for: %s", sptURL)
+
+	// Retried matches are inserted at their placeholder's position, which needs YouTube
+	if err := o.requireYouTube("-retry-failed"); err != nil {
+		return err
+	}
+
+	if err := o.initializeClients(); err != nil {
+		return fmt.Errorf("initializing clients: %w", err)
+	}
+
+	playlistID, err := o.extractPlaylistID(sptURL)
+	if err != nil {
+		return fmt.Errorf("extracting playlist ID: %w", err)
+	}
+
+	portingState, err := o.stateManager.LoadState(playlistID)
+	if err != nil {
+		return fmt.Errorf("loading state: %w", err)
+	}
+	if portingState == nil {
+		return fmt.Errorf("no saved state for playlist %s, nothing to retry", playlistID)
+	}
+	o.reportRecovery(portingState)
+	if err := o.checkDestination(portingState); err != nil {
+		return err
+	}
+
+	o.normalizeStateTracks(portingState)
+
+	// Tracks annotated "skip" stay failed on purpose
+	var failed []models.MatchResult
+	for _, result := range portingState.GetFailedResults() {
+		if !state.IsSkipNote(portingState.GetNote(result.OriginalTrack.ID)) {
+			failed = append(failed, result)
+		}
+	}
+	if len(failed) == 0 {
+		ui.Printf("✅ No failed tracks to retry\n")
+		return nil
+	}
+	if len(failed) > o.maxTracks {
+		failed = failed[:o.maxTracks]
+	}
+
+	ui.Printf("🔁 Retrying %d failed tracks\n", len(failed))
+	portingState.StartNewSession(o.sessionNote, o.runID)
+
+	batch := &models.Playlist{}
+	for _, result := range failed {
+		batch.Tracks = append(batch.Tracks, result.OriginalTrack)
+	}
+
+	o.writeToLog("\n=== RETRYING FAILED TRACKS ===")
+	results, err := o.matchTracks(batch.Tracks, 0, portingState.TrackNotes)
+	if err != nil {
+		return fmt.Errorf("matching tracks: %w", err)
+	}
+
+	recovered := 0
+	for _, result := range results {
+		if !result.Matched {
+			continue
+		}
+		recovered++
+		portingState.ReplaceMatchResult(result)
+
+		itemID := portingState.PlaceholderItems[result.OriginalTrack.ID]
+		if itemID == "" || o.phase == PhaseMatch {
+			continue // Added by the regular upload below
+		}
+		if err := o.replacePlaceholder(portingState, result, itemID); err != nil {
+			o.writeToLog("❌ Could not replace placeholder for %s: %v", result.OriginalTrack.ID, err)
+			ui.Printf("⚠️  Could not replace placeholder for \"%s\": %v\n", result.OriginalTrack.Title, err)
+		}
+	}
+	portingState.EndCurrentSession(len(results), recovered, o.trackCost())
+
+	if o.phase == PhaseMatch {
+		if err := o.stateManager.SaveState(portingState); err != nil {
+			return fmt.Errorf("saving state: %w", err)
+		}
+		ui.Printf("💾 Progress saved to checkpoint\n")
+	} else if err := o.uploadPending(portingState); err != nil {
+		return err
+	}
+
+	ui.Printf("\n🔁 Retry complete: %d of %d tracks matched this time\n", recovered, len(results))
+	o.reportStrictRefusals(results)
+	return nil
+}
+
+// replacePlaceholder inserts the matched video where its placeholder was and removes the placeholder
+func (o *Orchestrator) replacePlaceholder(portingState *state.PortingState, result models.MatchResult, itemID string) error {
+	playlistID, position, err := o.tuboClient.GetPlaylistItemPosition(itemID)
+	if err != nil {
+		return fmt.Errorf("locating placeholder: %w", err)
+	}
+
+	if _, err := o.tuboClient.InsertTrackAt(playlistID, result.MatchedTrack.ID, position); err != nil {
+		return fmt.Errorf("inserting match: %w", err)
+	}
+
+	// The placeholder moved down one position, delete it by its item ID
+	if err := o.tuboClient.DeletePlaylistItem(itemID); err != nil {
+		return fmt.Errorf("removing placeholder: %w", err)
+	}
+
+	portingState.ClearPlaceholder(result.OriginalTrack.ID)
+	portingState.MarkUploaded([]models.MatchResult{result})
+	o.writeToLog("Replaced placeholder at position %d with %s", position, result.MatchedTrack.ID)
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package orchestrator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/state"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// SelfTestTracks is how many tracks of the source playlist a self-test ports
+const SelfTestTracks = 3
+
+// SelfTest runs the whole workflow once on a small scale: it reads the source playlist,
+// matches its first SelfTestTracks tracks, adds them to a new private YouTube playlist,
+// checks the playlist and the saved state hold what was matched, and deletes the playlist
+// again unless keep is set. The state is saved to the usual states directory, which the
+// caller points somewhere disposable.
+func (o *Orchestrator) SelfTest(sourceURL string, keep bool) error {
+	defer o.Close()
+
+	if err := o.matchDirection(sourceURL); err != nil {
+		return err
+	}
+	if o.destinationName() != DestYouTube || o.customDest != nil {
+		return fmt.Errorf("the self-test needs a Spotify, TIDAL or Amazon Music playlist, not a YouTube one")
+	}
+
+	ui.Printf("🧪 1/5 Signing in\n")
+	if err := o.initializeClients(); err != nil {
+		return fmt.Errorf("initializing clients: %w", err)
+	}
+	playlistID, err := o.extractPlaylistID(sourceURL)
+	if err != nil {
+		return fmt.Errorf("extracting playlist ID: %w", err)
+	}
+	channel, err := o.tuboClient.ChannelTitle()
+	if err != nil {
+		return fmt.Errorf("reading the YouTube channel: %w", err)
+	}
+	ui.Printf("   ✅ YouTube channel: %s\n", channel)
+
+	ui.Printf("🧪 2/5 Reading the source playlist\n")
+	portingState, _, err := o.loadOrCreateState(sourceURL, playlistID)
+	if err != nil {
+		return fmt.Errorf("loading state: %w", err)
+	}
+	o.normalizeStateTracks(portingState)
+	tracks := portingState.GetNextBatch(SelfTestTracks)
+	if len(tracks) == 0 {
+		return fmt.Errorf("%s has no tracks to port", portingState.OriginalPlaylist.Name)
+	}
+
+	ui.Printf("🧪 3/5 Matching %d tracks\n", len(tracks))
+	reservation, allowed, err := o.reserveQuota(len(tracks))
+	if err != nil {
+		return fmt.Errorf("reserving shared quota: %w", err)
+	}
+	if allowed < len(tracks) {
+		o.releaseQuota(reservation, nil)
+		return fmt.Errorf("the shared quota doesn't cover %d tracks today", len(tracks))
+	}
+	var results []models.MatchResult
+	defer func() { o.releaseQuota(reservation, results) }()
+
+	portingState.StartNewSession("self-test", o.runID)
+	results, err = o.matchTracks(tracks, portingState.ProcessedTracks, portingState.TrackNotes)
+	if err != nil {
+		return fmt.Errorf("matching tracks: %w", err)
+	}
+	var videoIDs []string
+	for _, result := range results {
+		if result.Matched && result.MatchedTrack != nil {
+			videoIDs = append(videoIDs, result.MatchedTrack.ID)
+			ui.Printf("   ✅ \"%s\" → %s (score %.2f)\n", result.OriginalTrack.Title, result.MatchedTrack.ID, result.MatchScore)
+		} else {
+			ui.Printf("   ❌ \"%s\" not matched\n", result.OriginalTrack.Title)
+		}
+	}
+	if len(videoIDs) == 0 {
+		return fmt.Errorf("none of the %d tracks matched; searches work but found nothing usable, check the log", len(tracks))
+	}
+
+	ui.Printf("🧪 4/5 Creating a throwaway playlist and adding %d videos\n", len(videoIDs))
+	name := fmt.Sprintf("PlaylistPorter self-test %s", time.Now().Format("2006-01-02 15:04"))
+	playlist, err := o.tuboClient.CreatePlaylist(name, "Created by playlistporter selftest; safe to delete.")
+	if err != nil {
+		return fmt.Errorf("creating playlist: %w", err)
+	}
+	o.writeToLog("Self-test playlist: %s", playlist.ID)
+	testErr := o.checkSelfTestPlaylist(portingState, playlist.ID, name, results, videoIDs)
+
+	ui.Printf("🧪 5/5 Cleaning up\n")
+	if keep {
+		ui.Printf("   📌 Kept %s\n", o.playlistURL(playlist.ID))
+	} else if err := o.tuboClient.DeletePlaylist(playlist.ID); err != nil {
+		ui.Printf("   ⚠️  Could not delete %s, remove it by hand: %v\n", o.playlistURL(playlist.ID), err)
+	} else {
+		ui.Printf("   🗑️  Deleted the throwaway playlist\n")
+	}
+	return testErr
+}
+
+// checkSelfTestPlaylist adds the matched videos to the throwaway playlist, then checks the
+// playlist lists them in order and the saved state reads back with the results
+func (o *Orchestrator) checkSelfTestPlaylist(portingState *state.PortingState, playlistID, name string,
+	results []models.MatchResult, videoIDs []string) error {
+	if _, err := o.tuboClient.AddTracksToPlaylist(playlistID, videoIDs); err != nil {
+		return fmt.Errorf("adding videos: %w", err)
+	}
+
+	items, err := o.tuboClient.ListPlaylistItems(playlistID)
+	if err != nil {
+		return fmt.Errorf("listing playlist items: %w", err)
+	}
+	if len(items) != len(videoIDs) {
+		return fmt.Errorf("the playlist has %d items, expected %d", len(items), len(videoIDs))
+	}
+	for _, item := range items {
+		if item.Position < 0 || item.Position >= len(videoIDs) || item.VideoID != videoIDs[item.Position] {
+			return fmt.Errorf("position %d holds video %s, not the matched one", item.Position+1, item.VideoID)
+		}
+	}
+	ui.Printf("   ✅ The playlist holds the %d matched videos in order\n", len(items))
+
+	portingState.YouTubePlaylistID = playlistID
+	portingState.YouTubePlaylistName = name
+	portingState.AddMatchResults(results)
+	portingState.EndCurrentSession(len(results), len(videoIDs), o.trackCost())
+	if err := o.stateManager.SaveState(portingState); err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+	saved, err := o.stateManager.LoadState(portingState.SpotifyID)
+	if err != nil {
+		return fmt.Errorf("loading the saved state: %w", err)
+	}
+	if saved == nil || saved.ProcessedTracks != portingState.ProcessedTracks {
+		return fmt.Errorf("the saved state doesn't hold the %d processed tracks", portingState.ProcessedTracks)
+	}
+	ui.Printf("   ✅ The state saves and loads back\n")
+	return nil
+}
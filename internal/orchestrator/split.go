@@ -0,0 +1,85 @@
+package orchestrator
+
+import (
+	"fmt"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/collate"
+	"github.com/Verryx-02/PlaylistPorter/internal/config"
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/state"
+)
+
+// manageSplitPlaylists routes matches into the playlist of the first rule they satisfy;
+// tracks matching no rule go to the main playlist
+func (o *Orchestrator) manageSplitPlaylists(portingState *state.PortingState, newResults []models.MatchResult) error {
+	positions := make(map[string]int)
+	for i, track := range portingState.OriginalPlaylist.Tracks {
+		positions[track.ID] = i + 1
+	}
+
+	// Group results by target, keeping playlist order inside each group
+	var targetOrder []string
+	groups := make(map[string][]models.MatchResult)
+	for _, result := range newResults {
+		target := ""
+		for _, rule := range o.splitRules {
+			if splitRuleMatches(rule, result.OriginalTrack, positions[result.OriginalTrack.ID]) {
+				target = rule.Name
+				break
+			}
+		}
+
+		if _, exists := groups[target]; !exists {
+			targetOrder = append(targetOrder, target)
+		}
+		groups[target] = append(groups[target], result)
+		portingState.AssignTarget(result.OriginalTrack.ID, target)
+	}
+
+	for _, target := range targetOrder {
+		results := groups[target]
+
+		if target == "" {
+			playlistName := fmt.Sprintf("%s (Ported from Spotify)", portingState.OriginalPlaylist.Name)
+			if err := o.addToPlaylist(portingState, &portingState.YouTubePlaylistID, &portingState.YouTubePlaylistName,
+				playlistName, results); err != nil {
+				return err
+			}
+			continue
+		}
+
+		o.writeToLog("Split target \"%s\": %d tracks", target, len(results))
+		info := portingState.GetOrAddTarget(target)
+		playlistName := fmt.Sprintf("%s - %s (Ported from Spotify)", portingState.OriginalPlaylist.Name, target)
+		if err := o.addToPlaylist(portingState, &info.YouTubePlaylistID, &info.YouTubePlaylistName,
+			playlistName, results); err != nil {
+			return fmt.Errorf("split target %s: %w", target, err)
+		}
+	}
+
+	return nil
+}
+
+// splitRuleMatches reports whether a track at the given source position satisfies every condition of a rule
+func splitRuleMatches(rule config.SplitRule, track models.Track, position int) bool {
+	if rule.Artist != "" && !collate.Contains(track.Artist, rule.Artist) {
+		return false
+	}
+
+	if rule.Decade != 0 && (track.ReleaseYear < rule.Decade || track.ReleaseYear >= rule.Decade+10) {
+		return false
+	}
+
+	if rule.Explicit != nil && track.Explicit != *rule.Explicit {
+		return false
+	}
+
+	if rule.Positions != "" {
+		from, to, err := rule.PositionRange()
+		if err != nil || position < from || position > to {
+			return false
+		}
+	}
+
+	return true
+}
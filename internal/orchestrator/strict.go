@@ -0,0 +1,103 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/acoustid"
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/odesli"
+	"github.com/Verryx-02/PlaylistPorter/internal/tubo"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// strictPrefix starts the error of every track strict ISRC mode left unmatched
+const strictPrefix = "strict ISRC: "
+
+// errNoISRC marks tracks strict ISRC mode skips before searching
+const errNoISRC = strictPrefix + "the source track has no ISRC to check a match against"
+
+// SetStrictISRC only accepts matches that Odesli or the audio fingerprint tie to the source
+// track's ISRC; everything else stays unmatched and is listed for manual handling
+func (o *Orchestrator) SetStrictISRC(strict bool) {
+	o.strictISRC = strict
+	if strict {
+		o.writeToLog("Strict ISRC mode: matches must be corroborated by Odesli or the audio fingerprint")
+	}
+}
+
+// checkStrictISRC reports whether strict ISRC mode can work with the destination
+func (o *Orchestrator) checkStrictISRC() error {
+	if o.strictISRC && (o.customDest != nil || o.destinationName() != DestYouTube) {
+		return fmt.Errorf("-strict-isrc only works with YouTube as the destination, since Odesli and the fingerprint check YouTube videos")
+	}
+	return nil
+}
+
+// corroborate keeps a match in strict ISRC mode only when Odesli links the source track to
+// the matched video, or the video's audio fingerprint belongs to a recording with the source
+// ISRC. Refused matches become unmatched results naming the candidate. The error is
+// odesli.ErrRateLimited when Odesli couldn't be asked; the track should then stay pending.
+func (o *Orchestrator) corroborate(result *models.MatchResult) error {
+	if !o.strictISRC || !result.Matched || result.MatchedTrack == nil {
+		return nil
+	}
+
+	if link := sourceTrackURL(result.OriginalTrack); link != "" {
+		if o.odesli == nil {
+			o.odesli = odesli.NewClient(o.cfg.Odesli.APIKey)
+		}
+		videos, err := o.odesli.YouTubeVideos(link)
+		if errors.Is(err, odesli.ErrRateLimited) {
+			return err
+		}
+		if err != nil {
+			o.writeToLog("⚠️  Odesli lookup failed: %v", err)
+		} else {
+			applyCrossCheck(result, videos)
+			o.writeToLog("🔗 Odesli: %s", result.CrossCheck)
+			if result.CrossCheck == models.CrossCheckConfirmed {
+				return nil
+			}
+		}
+	}
+
+	if o.fingerprints != nil && result.OriginalTrack.ISRC != "" && result.Fingerprint == "" {
+		result.Fingerprint = o.fingerprintVerdict(result)
+	}
+	if result.Fingerprint == acoustid.Verified {
+		return nil
+	}
+
+	o.writeToLog("🔒 Refused: no ISRC corroboration for video %s", result.MatchedTrack.ID)
+	result.Error = fmt.Sprintf("%sbest match %s (score %.2f) is not corroborated by ISRC",
+		strictPrefix, tubo.VideoURL(result.MatchedTrack.ID), result.MatchScore)
+	result.MatchedTrack = nil
+	result.Matched = false
+	return nil
+}
+
+// reportStrictRefusals lists the tracks strict ISRC mode left unmatched in this session, with
+// the video Odesli links to the track where there is one
+func (o *Orchestrator) reportStrictRefusals(results []models.MatchResult) {
+	var refused []models.MatchResult
+	for _, result := range results {
+		if strings.HasPrefix(result.Error, strictPrefix) {
+			refused = append(refused, result)
+		}
+	}
+	if len(refused) == 0 {
+		return
+	}
+
+	ui.Summaryf("\n🔒 Strict ISRC mode left %d tracks unmatched for manual handling:\n", len(refused))
+	for _, result := range refused {
+		ui.Summaryf("   • %s - %s\n", result.OriginalTrack.Artist, result.OriginalTrack.Title)
+		ui.Summaryf("      %s\n", strings.TrimPrefix(result.Error, strictPrefix))
+		if result.CrossCheckVideo != "" {
+			ui.Summaryf("      Odesli links the recording to %s\n", tubo.VideoURL(result.CrossCheckVideo))
+		}
+	}
+	ui.Summaryf("💡 Add the right videos to the YouTube playlist by hand\n")
+}
@@ -0,0 +1,126 @@
+package orchestrator
+
+import (
+	"fmt"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/state"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// TagPlaylist adds and removes tags on a saved state and prints the resulting tags
+func (o *Orchestrator) TagPlaylist(sptURL string, add, remove []string) error {
+	defer o.Close()
+
+	playlistID, err := o.extractPlaylistID(sptURL)
+	if err != nil {
+		return fmt.Errorf("extracting playlist ID: %w", err)
+	}
+
+	// Tagging only touches the saved state, no API clients needed
+	stateManager, err := state.NewManager(o.statesPath())
+	if err != nil {
+		return fmt.Errorf("creating state manager: %w", err)
+	}
+
+	portingState, err := stateManager.LoadState(playlistID)
+	if err != nil {
+		return fmt.Errorf("loading state: %w", err)
+	}
+	if portingState == nil {
+		return fmt.Errorf("no saved state for playlist %s, port it first", playlistID)
+	}
+
+	if len(add) > 0 || len(remove) > 0 {
+		portingState.RemoveTags(remove...)
+		portingState.AddTags(add...)
+		if err := stateManager.SaveState(portingState); err != nil {
+			return fmt.Errorf("saving state: %w", err)
+		}
+	}
+
+	if len(portingState.Tags) == 0 {
+		ui.Printf("🏷️  %s has no tags\n", portingState.OriginalPlaylist.Name)
+		return nil
+	}
+	ui.Printf("🏷️  %s: %s\n", portingState.OriginalPlaylist.Name, state.FormatTags(portingState.Tags))
+	return nil
+}
+
+// PortTagged runs a session on every saved playlist carrying the tag, e.g. to sync them all.
+// The playlists share maxTracks in turn; a playlist that fails is reported and skipped.
+func (o *Orchestrator) PortTagged(tag string) error {
+	defer o.Close()
+
+	if err := o.initializeClients(); err != nil {
+		return fmt.Errorf("initializing clients: %w", err)
+	}
+
+	states, err := o.stateManager.StatesWithTag(tag)
+	if err != nil {
+		return fmt.Errorf("listing states: %w", err)
+	}
+	if len(states) == 0 {
+		return fmt.Errorf("no saved playlist is tagged %q (see playlistporter tag)", state.NormalizeTag(tag))
+	}
+	ui.Printf("🏷️  %d playlists tagged #%s\n", len(states), state.NormalizeTag(tag))
+	o.writeToLog("Tag %s: %d playlists", tag, len(states))
+
+	budget := o.maxTracks
+	var failed []string
+	for _, tagged := range states {
+		if budget == 0 || o.outOfTime() {
+			break
+		}
+		ui.Printf("\n━━━ %s ━━━\n", tagged.OriginalPlaylist.Name)
+
+		used, err := o.portTaggedPlaylist(tagged, budget)
+		if err != nil {
+			ui.Printf("⚠️  %v\n", err)
+			o.writeToLog("❌ Playlist %s failed: %v", tagged.SpotifyID, err)
+			failed = append(failed, tagged.OriginalPlaylist.Name)
+			continue
+		}
+		budget -= used
+	}
+
+	ui.Summaryf("\n🏷️  #%s: %d tracks processed this run\n", state.NormalizeTag(tag), o.maxTracks-budget)
+	for _, tagged := range states {
+		status := "⏳"
+		if tagged.IsComplete {
+			status = "✅"
+		}
+		ui.Summaryf("   %s %s: %s\n", status, tagged.OriginalPlaylist.Name, tagged.GetProgress())
+	}
+	if len(failed) > 0 {
+		ui.Summaryf("   ⚠️  %d playlists failed, see above\n", len(failed))
+	}
+	return nil
+}
+
+// portTaggedPlaylist runs one session of at most limit tracks on a tagged state
+// and returns how many tracks were processed
+func (o *Orchestrator) portTaggedPlaylist(tagged *state.PortingState, limit int) (int, error) {
+	if tagged.IsMerged() {
+		return 0, fmt.Errorf("merged playlists are continued with -merge")
+	}
+
+	portingState, isNew, err := o.loadOrCreateState(tagged.SpotifyURL, tagged.SpotifyID)
+	if err != nil {
+		return 0, err
+	}
+
+	if portingState.IsComplete && !o.syncMode && !portingState.IsArchive() {
+		ui.Printf("✅ Already complete\n")
+		return 0, nil
+	}
+
+	before := len(portingState.MatchResults)
+	maxTracks := o.maxTracks
+	o.maxTracks = limit
+	o.stageTimes = nil
+	err = o.runSession(portingState, isNew)
+	o.maxTracks = maxTracks
+
+	*tagged = *portingState
+	return len(portingState.MatchResults) - before, err
+}
@@ -0,0 +1,181 @@
+package orchestrator
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/export"
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/quota"
+	"github.com/Verryx-02/PlaylistPorter/internal/state"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// ExportTimeline writes the session history of a saved state as an HTML page to outPath
+// (default exports/timeline_ID.html), to spot when a port started going wrong
+func (o *Orchestrator) ExportTimeline(sptURL, outPath string) error {
+	defer o.Close()
+
+	playlistID, err := o.extractPlaylistID(sptURL)
+	if err != nil {
+		return fmt.Errorf("extracting playlist ID: %w", err)
+	}
+
+	// The timeline only reads the saved state, no API clients needed
+	stateManager, err := state.NewManager(o.statesPath())
+	if err != nil {
+		return fmt.Errorf("creating state manager: %w", err)
+	}
+
+	portingState, err := stateManager.LoadState(playlistID)
+	if err != nil {
+		return fmt.Errorf("loading state: %w", err)
+	}
+	if portingState == nil {
+		return fmt.Errorf("no saved state for playlist %s, port it first", playlistID)
+	}
+	if len(portingState.Sessions) == 0 {
+		return fmt.Errorf("%s has no sessions yet", portingState.OriginalPlaylist.Name)
+	}
+
+	timeline := buildTimeline(portingState, o.costs())
+	var data bytes.Buffer
+	if err := export.WriteTimeline(&data, timeline); err != nil {
+		return err
+	}
+
+	if outPath == "" {
+		outPath = filepath.Join("exports", fmt.Sprintf("timeline_%s.html", playlistID))
+	}
+	if err := os.MkdirAll(filepath.Dir(outPath), 0755); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+	if err := os.WriteFile(outPath, data.Bytes(), 0644); err != nil {
+		return fmt.Errorf("writing timeline: %w", err)
+	}
+
+	ui.Printf("🕰️  Wrote the timeline of %d sessions of \"%s\" to %s\n",
+		len(timeline.Sessions), timeline.Name, outPath)
+	return nil
+}
+
+// buildTimeline lays out the sessions of a state. Tracks are placed in the session whose run
+// their trace belongs to; sessions saved before run IDs were recorded show their totals only.
+// Quota is counted at costs.
+func buildTimeline(portingState *state.PortingState, costs quota.Costs) *export.Timeline {
+	timeline := &export.Timeline{
+		Name:      portingState.OriginalPlaylist.Name,
+		SourceURL: portingState.SpotifyURL,
+	}
+
+	byRun := make(map[string][]models.MatchResult)
+	for _, result := range portingState.MatchResults {
+		if run, _, ok := splitTrace(result.Trace); ok {
+			byRun[run] = append(byRun[run], result)
+		}
+	}
+
+	for _, session := range portingState.Sessions {
+		entry := export.TimelineSession{
+			Start:     session.StartTime,
+			Processed: session.TracksProcessed,
+			Matched:   session.TracksMatched,
+			Quota:     session.QuotaUsed,
+			Estimated: true,
+			Note:      session.Note,
+			RunID:     session.RunID,
+		}
+		if session.EndTime.After(session.StartTime) {
+			entry.Duration = session.EndTime.Sub(session.StartTime)
+		}
+
+		if results := byRun[session.RunID]; session.RunID != "" && len(results) > 0 {
+			sort.SliceStable(results, func(i, j int) bool {
+				_, a, _ := splitTrace(results[i].Trace)
+				_, b, _ := splitTrace(results[j].Trace)
+				return a < b
+			})
+			entry.Quota, entry.Estimated = 0, false
+			entry.Tracks, entry.Failures = timelineTracks(results)
+			for _, result := range results {
+				entry.Quota += result.SearchesUsed * costs.Search
+				if result.Matched {
+					entry.Quota += costs.Insert
+				}
+			}
+		}
+
+		timeline.TotalQuota += entry.Quota
+		if rate := entry.PerMinute(); rate > timeline.MaxPerMin {
+			timeline.MaxPerMin = rate
+		}
+		timeline.Sessions = append(timeline.Sessions, entry)
+	}
+	return timeline
+}
+
+// timelineTracks turns the results of a session into its strip and its failures by reason
+func timelineTracks(results []models.MatchResult) ([]export.TimelineTrack, []export.TimelineFailure) {
+	tracks := make([]export.TimelineTrack, 0, len(results))
+	counts := make(map[string]int)
+	for _, result := range results {
+		track := export.TimelineTrack{
+			Label:  fmt.Sprintf("%s - %s", result.OriginalTrack.Artist, result.OriginalTrack.Title),
+			Status: "matched",
+		}
+		switch {
+		case !result.Matched:
+			track.Status = "failed"
+			track.Reason = failureReason(result.Error)
+			counts[track.Reason]++
+		case result.MatchScore < ui.LowConfidenceScore:
+			track.Status = "low"
+		}
+		tracks = append(tracks, track)
+	}
+
+	failures := make([]export.TimelineFailure, 0, len(counts))
+	for reason, count := range counts {
+		failures = append(failures, export.TimelineFailure{Reason: reason, Count: count})
+	}
+	sort.Slice(failures, func(i, j int) bool {
+		if failures[i].Count != failures[j].Count {
+			return failures[i].Count > failures[j].Count
+		}
+		return failures[i].Reason < failures[j].Reason
+	})
+	return tracks, failures
+}
+
+// failureReason groups failure messages: the trace is dropped, and messages with details
+// after a colon, like search errors, keep only their kind
+func failureReason(message string) string {
+	if message == "" {
+		return "no match found"
+	}
+	if i := strings.Index(message, " [trace "); i >= 0 {
+		message = message[:i]
+	}
+	if i := strings.Index(message, ": "); i > 0 {
+		message = message[:i]
+	}
+	return message
+}
+
+// splitTrace splits a trace ID "<run>-t<n>" into the run ID and the track number
+func splitTrace(trace string) (string, int, bool) {
+	i := strings.LastIndex(trace, "-t")
+	if i <= 0 {
+		return "", 0, false
+	}
+	n, err := strconv.Atoi(trace[i+2:])
+	if err != nil {
+		return "", 0, false
+	}
+	return trace[:i], n, true
+}
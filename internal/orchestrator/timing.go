@@ -0,0 +1,50 @@
+package orchestrator
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/state"
+)
+
+// Pipeline stages timed in every session
+const (
+	stageFetch     = "fetch"     // Reading the source playlist
+	stageNormalize = "normalize" // Cleaning up track metadata
+	stageSearch    = "search"    // Search requests, without scoring
+	stageScore     = "score"     // Scoring search candidates
+	stageInsert    = "insert"    // Creating playlists and adding tracks
+)
+
+// stageOrder is the order stages are reported in
+var stageOrder = []string{stageFetch, stageNormalize, stageSearch, stageScore, stageInsert}
+
+// addStageTime adds the time since start to a pipeline stage
+func (o *Orchestrator) addStageTime(stage string, start time.Time) {
+	o.addStageDuration(stage, time.Since(start))
+}
+
+// addStageDuration adds a measured duration to a pipeline stage
+func (o *Orchestrator) addStageDuration(stage string, d time.Duration) {
+	if o.stageTimes == nil {
+		o.stageTimes = make(map[string]time.Duration)
+	}
+	o.stageTimes[stage] += d
+}
+
+// recordStageTimes stores this run's stage timings in the current session of the state
+func (o *Orchestrator) recordStageTimes(portingState *state.PortingState) {
+	portingState.SetSessionStageTimes(o.stageTimes)
+}
+
+// formatStageTimes renders the timings as "fetch 1.2s · search 40.1s · ..." in pipeline order
+func formatStageTimes(times map[string]time.Duration) string {
+	var parts []string
+	for _, stage := range stageOrder {
+		if d, ok := times[stage]; ok && d > 0 {
+			parts = append(parts, fmt.Sprintf("%s %s", stage, d.Round(time.Millisecond)))
+		}
+	}
+	return strings.Join(parts, " · ")
+}
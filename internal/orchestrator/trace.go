@@ -0,0 +1,51 @@
+package orchestrator
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+)
+
+// newRunID returns a short random ID naming one run in logs and error messages
+func newRunID() string {
+	b := make([]byte, 4)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// RunID returns the ID of this run, which prefixes every line of the detailed log
+func (o *Orchestrator) RunID() string {
+	return o.runID
+}
+
+// useLogger installs the detailed logger, prefixing its lines with the run ID
+func (o *Orchestrator) useLogger(logger *log.Logger) {
+	logger.SetFlags(logger.Flags() | log.Lmsgprefix)
+	o.logger = logger
+	o.endTrace()
+}
+
+// startTrace gives the next track a trace ID, "<run>-t<n>", numbered across the whole run so
+// it stays unique when several playlists are ported. Until endTrace, log lines (including those
+// of the API clients sharing the logger) carry it.
+func (o *Orchestrator) startTrace() string {
+	o.traceSeq++
+	trace := fmt.Sprintf("%s-t%d", o.runID, o.traceSeq)
+	if o.logger != nil {
+		o.logger.SetPrefix("[" + trace + "] ")
+	}
+	return trace
+}
+
+// endTrace goes back to prefixing log lines with the run ID only
+func (o *Orchestrator) endTrace() {
+	if o.logger != nil {
+		o.logger.SetPrefix("[" + o.runID + "] ")
+	}
+}
+
+// tracedError is the message of an error met while handling a traced track
+func tracedError(err error, trace string) string {
+	return fmt.Sprintf("%v [trace %s]", err, trace)
+}
@@ -0,0 +1,52 @@
+package orchestrator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// testTrackMatch searches the destination for a single Spotify track and prints the result,
+// without saving a state or creating a playlist. Useful to check how a track would match.
+func (o *Orchestrator) testTrackMatch(trackID string) error {
+	if _, err := o.sourceFor(trackID); err != nil {
+		return err
+	}
+
+	track, err := o.sptClient.GetTrack(trackID)
+	if err != nil {
+		return fmt.Errorf("fetching SPT track: %w", err)
+	}
+	o.processor.NormalizeTrack(track)
+
+	ui.Printf("🎵 Track: \"%s\" by %s (%s)\n", track.Title, track.Artist, formatTrackDuration(track.Duration))
+	ui.Printf("🔍 Searching on %s...\n", o.destinationLabel())
+	o.writeToLog("Match test for \"%s\" by \"%s\"", track.Title, track.Artist)
+
+	outcome, err := o.dest.SearchTrackOutcome(*track)
+	if err != nil {
+		return fmt.Errorf("searching: %w", err)
+	}
+
+	if outcome.Track == nil {
+		ui.Printf("❌ No match found (%d searches)\n", outcome.SearchesUsed)
+		return nil
+	}
+
+	match := outcome.Track
+	ui.Printf("✅ Match: \"%s\" by %s\n", match.Title, match.Artist)
+	ui.Printf("   Score: %.2f · strategy: %s · searches: %d\n", outcome.Score, outcome.Strategy, outcome.SearchesUsed)
+	if link := destinationTrackURL(o.destinationName(), match.ID); link != match.ID {
+		ui.Printf("   %s\n", link)
+	}
+	ui.Printf("💡 Nothing was saved; pass an album or playlist link to port\n")
+	o.writeToLog("Match test result: %s (score %.2f, strategy %s)", match.ID, outcome.Score, outcome.Strategy)
+	return nil
+}
+
+// formatTrackDuration renders a track length as m:ss
+func formatTrackDuration(d time.Duration) string {
+	total := int(d.Seconds())
+	return fmt.Sprintf("%d:%02d", total/60, total%60)
+}
@@ -0,0 +1,212 @@
+package orchestrator
+
+import (
+	"fmt"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/state"
+	"github.com/Verryx-02/PlaylistPorter/internal/tubo"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// VerifyPlaylist checks that matched videos are still available and re-matches the ones that aren't
+func (o *Orchestrator) VerifyPlaylist(sptURL string) error {
+	defer o.Close()
+
+	o.writeToLog("Verifying matched videos for: %s", sptURL)
+
+	if err := o.requireYouTube("-verify"); err != nil {
+		return err
+	}
+
+	if err := o.initializeClients(); err != nil {
+		return fmt.Errorf("initializing clients: %w", err)
+	}
+
+	playlistID, err := o.extractPlaylistID(sptURL)
+	if err != nil {
+		return fmt.Errorf("extracting playlist ID: %w", err)
+	}
+
+	portingState, err := o.stateManager.LoadState(playlistID)
+	if err != nil {
+		return fmt.Errorf("loading state: %w", err)
+	}
+	if portingState == nil {
+		return fmt.Errorf("no saved state for playlist %s, nothing to verify", playlistID)
+	}
+	o.reportRecovery(portingState)
+	if err := o.checkDestination(portingState); err != nil {
+		return err
+	}
+
+	if err := o.verifyMatchedVideos(portingState); err != nil {
+		return err
+	}
+
+	if err := o.stateManager.SaveState(portingState); err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+	ui.Printf("💾 Progress saved to checkpoint\n")
+
+	return nil
+}
+
+// verifyMatchedVideos replaces matched videos that were deleted or made private,
+// re-matching at most maxTracks of them to stay within the quota budget
+func (o *Orchestrator) verifyMatchedVideos(portingState *state.PortingState) error {
+	if o.tuboClient == nil || portingState.GetDestination() != DestYouTube {
+		return nil // Only YouTube videos can be checked
+	}
+
+	matched := portingState.GetMatchedResults()
+	if len(matched) == 0 {
+		return nil
+	}
+
+	ui.Printf("🩺 Checking %d matched videos are still available...\n", len(matched))
+
+	videoIDs := make([]string, 0, len(matched))
+	for _, result := range matched {
+		videoIDs = append(videoIDs, result.MatchedTrack.ID)
+	}
+
+	unavailable, err := o.tuboClient.FindUnavailableVideos(videoIDs)
+	if err != nil {
+		return fmt.Errorf("checking video availability: %w", err)
+	}
+	if len(unavailable) == 0 {
+		ui.Printf("✅ All matched videos are still available\n")
+		return nil
+	}
+
+	ui.Printf("⚠️  %d matched videos were deleted or made private\n", len(unavailable))
+	o.writeToLog("\n=== REPLACING UNAVAILABLE VIDEOS ===")
+
+	items, err := o.findPlaylistItems(portingState)
+	if err != nil {
+		return fmt.Errorf("listing playlist items: %w", err)
+	}
+
+	budget := o.maxTracks
+	replaced, removed := 0, 0
+	for _, result := range matched {
+		oldVideoID := result.MatchedTrack.ID
+		if !unavailable[oldVideoID] {
+			continue
+		}
+
+		if budget == 0 {
+			ui.Printf("⏸️  Search budget used up, remaining unavailable videos will be handled next run\n")
+			break
+		}
+		budget--
+
+		track := result.OriginalTrack
+		o.processor.NormalizeTrack(&track)
+		trace := o.startTrace()
+		o.writeToLog("Re-matching \"%s\" by \"%s\" (video %s unavailable)", track.Title, track.Artist, oldVideoID)
+
+		outcome, err := o.dest.SearchTrackOutcome(track)
+		if err != nil {
+			o.writeToLog("❌ Search error: %v", err)
+			continue
+		}
+		newMatch, score := outcome.Track, outcome.Score
+
+		replacement := models.MatchResult{OriginalTrack: track, SearchesUsed: outcome.SearchesUsed, Trace: trace}
+		if newMatch != nil && newMatch.ID != oldVideoID {
+			replacement.MatchedTrack = newMatch
+			replacement.MatchScore = score
+			replacement.Matched = true
+			replacement.Strategy = outcome.Strategy
+		} else {
+			replacement.Error = "matched video is no longer available"
+		}
+		stampRules(&replacement)
+
+		if err := o.replacePlaylistItem(items[oldVideoID], replacement); err != nil {
+			ui.Printf("⚠️  Could not update the playlist for \"%s\": %v\n", track.Title, err)
+			o.writeToLog("❌ Could not update playlist item: %v", err)
+			continue
+		}
+
+		portingState.RecordSubstitution(oldVideoID, replacement, "video deleted or private")
+		if replacement.Matched {
+			replaced++
+			o.writeToLog("✅ Replaced with \"%s\" (%s, score: %.2f)", newMatch.Title, newMatch.ID, score)
+		} else {
+			removed++
+			shiftPositionsAfter(items, items[oldVideoID])
+			o.writeToLog("❌ No replacement found, track marked as failed")
+		}
+	}
+
+	o.endTrace()
+
+	ui.Printf("🔁 Replaced %d videos, %d tracks had no replacement\n", replaced, removed)
+	return nil
+}
+
+// playlistItemRef locates a video inside one of the state's YouTube playlists
+type playlistItemRef struct {
+	PlaylistID string
+	Item       tubo.PlaylistItem
+}
+
+// findPlaylistItems maps video IDs to their items across the main and split playlists
+func (o *Orchestrator) findPlaylistItems(portingState *state.PortingState) (map[string]playlistItemRef, error) {
+	playlistIDs := []string{portingState.YouTubePlaylistID}
+	for _, target := range portingState.Targets {
+		playlistIDs = append(playlistIDs, target.YouTubePlaylistID)
+	}
+
+	refs := make(map[string]playlistItemRef)
+	for _, playlistID := range playlistIDs {
+		if playlistID == "" {
+			continue
+		}
+		items, err := o.tuboClient.ListPlaylistItems(playlistID)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			refs[item.VideoID] = playlistItemRef{PlaylistID: playlistID, Item: item}
+		}
+	}
+
+	return refs, nil
+}
+
+// shiftPositionsAfter updates cached positions after an item was removed from its playlist
+func shiftPositionsAfter(refs map[string]playlistItemRef, removed playlistItemRef) {
+	if removed.Item.ID == "" {
+		return
+	}
+	for videoID, ref := range refs {
+		if ref.PlaylistID == removed.PlaylistID && ref.Item.Position > removed.Item.Position {
+			ref.Item.Position--
+			refs[videoID] = ref
+		}
+	}
+}
+
+// replacePlaylistItem swaps an unavailable video for its replacement at the same position,
+// or just removes it when there is no replacement
+func (o *Orchestrator) replacePlaylistItem(ref playlistItemRef, replacement models.MatchResult) error {
+	if ref.Item.ID == "" {
+		return nil // Not in any playlist (e.g. match-only phase), only the state changes
+	}
+
+	if replacement.Matched {
+		if _, err := o.tuboClient.InsertTrackAt(ref.PlaylistID, replacement.MatchedTrack.ID, ref.Item.Position); err != nil {
+			return fmt.Errorf("inserting replacement: %w", err)
+		}
+	}
+
+	if err := o.tuboClient.DeletePlaylistItem(ref.Item.ID); err != nil {
+		return fmt.Errorf("removing unavailable video: %w", err)
+	}
+
+	return nil
+}
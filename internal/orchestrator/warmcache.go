@@ -0,0 +1,245 @@
+package orchestrator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/config"
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/processor"
+	"github.com/Verryx-02/PlaylistPorter/internal/state"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// searchCacheTTL is how long a search made ahead of time stays usable; videos come and go,
+// so an old search is made again
+const searchCacheTTL = 7 * 24 * time.Hour
+
+// warmTrackCost is the quota warm-cache sets aside before searching a track: two searches,
+// as most tracks match by the second strategy
+func (o *Orchestrator) warmTrackCost() int {
+	return 2 * o.costs().Search
+}
+
+// searchBackend names the backend YouTube searches go to, which keys the search cache
+func (o *Orchestrator) searchBackend() string {
+	if o.cfg.TUBO.SearchBackend == config.SearchYTMusic {
+		return config.SearchYTMusic
+	}
+	return config.SearchDataAPI
+}
+
+// loadSearchCache loads the searches made by warm-cache, nil when there are none or the
+// destination doesn't search YouTube
+func (o *Orchestrator) loadSearchCache() *state.SearchCache {
+	if o.customDest != nil || o.destinationName() != DestYouTube {
+		return nil
+	}
+	cache, err := o.stateManager.LoadSearchCache()
+	if err != nil {
+		o.writeToLog("⚠️  Could not load search cache: %v", err)
+		return nil
+	}
+	if len(cache.Entries) == 0 {
+		return nil
+	}
+	return cache
+}
+
+// takeCachedSearch returns the search warm-cache made for a track and removes it from the
+// cache. Searches made with other matching rules or older than searchCacheTTL are ignored.
+// The outcome spends no quota now, so SearchesUsed is 0.
+func (o *Orchestrator) takeCachedSearch(cache *state.SearchCache, track models.Track) (*models.SearchOutcome, bool) {
+	entry := cache.Lookup(o.searchBackend(), track.ID)
+	if entry == nil {
+		return nil, false
+	}
+	cache.Remove(o.searchBackend(), track.ID)
+
+	if !o.cachedSearchUsable(entry) {
+		return nil, false
+	}
+	o.writeToLog("⚡ Using the search made by warm-cache on %s", entry.CachedAt.Format("2006-01-02 15:04"))
+	return &models.SearchOutcome{
+		Track:    entry.Track,
+		Score:    entry.Score,
+		Strategy: entry.Strategy,
+	}, true
+}
+
+// cachedSearchUsable reports whether a cached search still matches the current rules and age limit
+func (o *Orchestrator) cachedSearchUsable(entry *state.CachedSearch) bool {
+	return entry.NormalizerVersion == processor.RulesVersion &&
+		entry.MatcherVersion == processor.MatcherVersion &&
+		time.Since(entry.CachedAt) < searchCacheTTL
+}
+
+// WarmCache searches ahead for the tracks the next session of each playlist will match, so
+// that session mostly finds its searches cached. With a playlist URL only that playlist is
+// warmed, with a tag the playlists carrying it, otherwise every unfinished YouTube playlist.
+// At most about units of YouTube quota are spent; with the ytmusic search backend searches
+// are free and only maxTracks per playlist limits the work. Progress is not changed.
+func (o *Orchestrator) WarmCache(sptURL, tag string, units int) error {
+	defer o.Close()
+
+	if err := o.requireYouTube("warm-cache"); err != nil {
+		return err
+	}
+	if err := o.initializeClients(); err != nil {
+		return fmt.Errorf("initializing clients: %w", err)
+	}
+
+	states, err := o.warmCacheStates(sptURL, tag)
+	if err != nil {
+		return err
+	}
+	if len(states) == 0 {
+		ui.Printf("✅ No unfinished YouTube playlists to warm the cache for\n")
+		return nil
+	}
+
+	cache, err := o.stateManager.LoadSearchCache()
+	if err != nil {
+		return fmt.Errorf("loading search cache: %w", err)
+	}
+	pruned := cache.Prune(time.Now().Add(-searchCacheTTL))
+	if pruned > 0 {
+		o.writeToLog("Dropped %d cached searches older than %s", pruned, searchCacheTTL)
+	}
+
+	free := o.searchBackend() == config.SearchYTMusic
+	if free {
+		ui.Printf("🔥 Warming the search cache with YouTube Music (no quota spent)\n")
+	} else {
+		ui.Printf("🔥 Warming the search cache with up to ~%d quota units\n", units)
+	}
+
+	// Courtesy mode: only spend what the shared ledger grants
+	spent := 0
+	if !free && o.ledger != nil {
+		reservation, err := o.ledger.Reserve(units)
+		if err != nil {
+			return fmt.Errorf("reserving shared quota: %w", err)
+		}
+		defer func() {
+			if err := o.ledger.Release(reservation, spent); err != nil {
+				ui.Printf("⚠️  Could not release unused quota in the shared ledger: %v\n", err)
+			}
+		}()
+		if reservation.Units < o.warmTrackCost() {
+			o.reportSharedQuota()
+			return nil
+		}
+		units = reservation.Units
+	}
+
+	mappings := o.loadMappings()
+	searched, cached, matched := 0, 0, 0
+	changed := pruned > 0
+
+warm:
+	for _, portingState := range states {
+		o.normalizeStateTracks(portingState)
+		ui.Printf("\n━━━ %s ━━━\n", portingState.OriginalPlaylist.Name)
+
+		for _, track := range portingState.GetNextBatch(o.maxTracks) {
+			if o.outOfTime() {
+				o.writeToLog("⏱️  %s reached while warming the cache", o.stopReason())
+				break warm
+			}
+			if !free && spent+o.warmTrackCost() > units {
+				o.writeToLog("Warm-cache budget of %d units used up (%d spent)", units, spent)
+				break warm
+			}
+
+			// Tracks the session won't search need no cached search
+			if state.IsSkipNote(portingState.GetNote(track.ID)) || mappings.Lookup(track.ID) != nil ||
+				(o.strictISRC && track.ISRC == "") {
+				continue
+			}
+			if entry := cache.Lookup(o.searchBackend(), track.ID); entry != nil && o.cachedSearchUsable(entry) {
+				cached++
+				continue
+			}
+
+			ui.Printf("\r🔎 Searching ahead: %s", truncateString(fmt.Sprintf("%s - %s", track.Artist, track.Title), 50))
+			trace := o.startTrace()
+			o.writeToLog("Warming: \"%s\" by \"%s\"", track.Title, track.Artist)
+			o.enrichTrack(&track)
+
+			outcome, err := o.dest.SearchTrackOutcome(track)
+			o.endTrace()
+			if err != nil {
+				o.writeToLog("❌ Search error: %s", tracedError(err, trace))
+				continue
+			}
+			spent += outcome.SearchesUsed * o.costs().Search
+			searched++
+			if outcome.Track != nil {
+				matched++
+			}
+
+			cache.Set(o.searchBackend(), track.ID, state.CachedSearch{
+				Track:             outcome.Track,
+				Score:             outcome.Score,
+				Strategy:          outcome.Strategy,
+				SearchesUsed:      outcome.SearchesUsed,
+				NormalizerVersion: processor.RulesVersion,
+				MatcherVersion:    processor.MatcherVersion,
+				CachedAt:          time.Now(),
+			})
+			changed = true
+		}
+		ui.Printf("\r%-70s\r", "")
+	}
+
+	if changed {
+		if err := o.stateManager.SaveSearchCache(cache); err != nil {
+			return fmt.Errorf("saving search cache: %w", err)
+		}
+	}
+	o.saveMusicBrainzCache()
+
+	ui.Summaryf("\n🔥 Search cache warmed: %d tracks searched ahead (%d matched), %d already cached\n",
+		searched, matched, cached)
+	if !free {
+		ui.Summaryf("📊 Quota spent: ~%d of %d units\n", spent, units)
+	}
+	ui.Summaryf("💡 The next session uses these searches for free; they're kept for %d days\n",
+		int(searchCacheTTL.Hours()/24))
+	return nil
+}
+
+// warmCacheStates loads the states warm-cache works on, sorted by playlist name
+func (o *Orchestrator) warmCacheStates(sptURL, tag string) ([]*state.PortingState, error) {
+	if sptURL != "" {
+		playlistID, err := o.extractPlaylistID(sptURL)
+		if err != nil {
+			return nil, fmt.Errorf("extracting playlist ID: %w", err)
+		}
+		portingState, err := o.stateManager.LoadState(playlistID)
+		if err != nil {
+			return nil, fmt.Errorf("loading state: %w", err)
+		}
+		if portingState == nil {
+			return nil, fmt.Errorf("no saved state for playlist %s; start it with a normal run first", playlistID)
+		}
+		if err := o.checkDestination(portingState); err != nil {
+			return nil, err
+		}
+		return []*state.PortingState{portingState}, nil
+	}
+
+	candidates, err := o.stateManager.StatesWithTag(tag)
+	if err != nil {
+		return nil, fmt.Errorf("listing states: %w", err)
+	}
+
+	var states []*state.PortingState
+	for _, candidate := range candidates {
+		if !candidate.IsComplete && candidate.GetDestination() == DestYouTube {
+			states = append(states, candidate)
+		}
+	}
+	return states, nil
+}
@@ -0,0 +1,57 @@
+package orchestrator
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/traffic"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// watchdogGrace is how long a timed-out run gets to save its state before it is ended
+const watchdogGrace = 30 * time.Second
+
+// SetTimeout sets a hard limit for the whole run, starting now. When it is reached, no new
+// tracks are started and requests in flight are aborted, so the run saves its state and
+// prints its summary even if an API hangs; a run that still hasn't finished after a grace
+// period is ended, keeping the state of the last checkpoint.
+func (o *Orchestrator) SetTimeout(d time.Duration) {
+	if o.watchdog != nil {
+		o.watchdog.Stop()
+	}
+	o.timeout = d
+	if d <= 0 {
+		return
+	}
+	o.writeToLog("Run timeout: %s", d)
+	o.watchdog = time.AfterFunc(d, o.expire)
+}
+
+// expire runs when the timeout is reached: first it winds the run down, then, if the run is
+// still going after the grace period, it ends the process
+func (o *Orchestrator) expire() {
+	if !o.timedOut.Swap(true) {
+		o.writeToLog("⏱️  Run timeout of %s reached, aborting requests in flight", o.timeout)
+		traffic.Abort()
+		o.watchdog.Reset(watchdogGrace)
+		return
+	}
+
+	ui.Summaryf("\n⏱️  The run didn't finish within %s of the %s timeout and was ended\n", watchdogGrace, o.timeout)
+	ui.Summaryf("💾 Progress up to the last checkpoint is saved; the next run resumes from there\n")
+	o.writeToLog("Run ended by the watchdog %s after the timeout", watchdogGrace)
+	o.Close()
+	os.Exit(1)
+}
+
+// stopReason names the limit that stopped a batch early
+func (o *Orchestrator) stopReason() string {
+	if o.timedOut.Load() {
+		return fmt.Sprintf("Run timeout of %s", o.timeout)
+	}
+	if o.odesliLimited {
+		return "Odesli rate limit"
+	}
+	return fmt.Sprintf("Time budget of %s", o.maxDuration)
+}
@@ -0,0 +1,155 @@
+package paths
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// Move is a file or directory of an older setup in the working directory and the place it
+// belongs now
+type Move struct {
+	From string
+	To   string
+}
+
+// Legacy lists the configs/config.yaml, states/ and logs/ of an older setup in the working
+// directory that are no longer read, each with the location in use instead
+func Legacy() []Move {
+	var moves []Move
+	for _, m := range []Move{
+		{legacyConfig, Config()},
+		{legacyStates, States()},
+		{legacyLogs, Logs()},
+	} {
+		if !exists(m.From) || samePath(m.From, m.To) {
+			continue
+		}
+		moves = append(moves, m)
+	}
+	return moves
+}
+
+// Migrate moves what Legacy lists to the locations in use, once: a location that already
+// holds something is left alone and reported as an error, so nothing is overwritten or
+// merged. It returns the moves made.
+func Migrate() ([]Move, error) {
+	var moved []Move
+	for _, m := range Legacy() {
+		if exists(m.To) {
+			if !unused(m.To) {
+				return moved, fmt.Errorf("%s already exists; merge %s into it by hand", m.To, m.From)
+			}
+			if err := os.RemoveAll(m.To); err != nil {
+				return moved, fmt.Errorf("removing the empty %s: %w", m.To, err)
+			}
+		}
+		if err := os.MkdirAll(filepath.Dir(m.To), 0755); err != nil {
+			return moved, fmt.Errorf("creating %s: %w", filepath.Dir(m.To), err)
+		}
+		if err := move(m.From, m.To); err != nil {
+			return moved, fmt.Errorf("moving %s to %s: %w", m.From, m.To, err)
+		}
+		moved = append(moved, m)
+	}
+	return moved, nil
+}
+
+// rebuilt are the files a command creates in a fresh states directory and rebuilds from the
+// state files, so a directory holding only them has nothing to lose
+var rebuilt = map[string]bool{"index.json": true}
+
+// unused reports whether dir is a directory holding nothing but rebuilt files, as the XDG
+// states directory does after a command ran before the migration
+func unused(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if !rebuilt[entry.Name()] {
+			return false
+		}
+	}
+	return true
+}
+
+// move renames from to to, copying and then removing when they are on different file systems
+func move(from, to string) error {
+	err := os.Rename(from, to)
+	if !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+	if err := copyTree(from, to); err != nil {
+		os.RemoveAll(to)
+		return err
+	}
+	return os.RemoveAll(from)
+}
+
+// copyTree copies a file or a directory with everything in it
+func copyTree(from, to string) error {
+	return filepath.WalkDir(from, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(from, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(to, rel)
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return os.MkdirAll(target, info.Mode().Perm())
+		}
+		return copyFile(path, target, info.Mode().Perm())
+	})
+}
+
+// copyFile copies one file and syncs it
+func copyFile(from, to string, perm fs.FileMode) error {
+	in, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(to, os.O_CREATE|os.O_EXCL|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// samePath reports whether two paths name the same file or directory
+func samePath(a, b string) bool {
+	infoA, errA := os.Stat(a)
+	infoB, errB := os.Stat(b)
+	if errA == nil && errB == nil {
+		return os.SameFile(infoA, infoB)
+	}
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
+
+// exists reports whether a file or directory exists
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
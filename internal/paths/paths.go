@@ -0,0 +1,109 @@
+// Package paths locates the config file and the directories of saved states and logs. A
+// location set with a flag wins over its PLAYLISTPORTER_* environment variable; without
+// either, everything follows the XDG base directories, so the binary behaves the same from
+// any directory. The configs/, states/ and logs/ of older setups in the working directory
+// are no longer read; Legacy finds them and Migrate moves them.
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Names under the XDG base directories and in the working directory of older setups
+const (
+	appName      = "playlistporter"
+	legacyConfig = "configs/config.yaml"
+	legacyStates = "states"
+	legacyLogs   = "logs"
+)
+
+var (
+	mu        sync.Mutex
+	statesDir string // Set with SetStates
+	logsDir   string // Set with SetLogs
+)
+
+// SetStates makes dir the directory of saved states; empty goes back to the default
+func SetStates(dir string) {
+	mu.Lock()
+	defer mu.Unlock()
+	statesDir = dir
+}
+
+// SetLogs makes dir the directory of log files; empty goes back to the default
+func SetLogs(dir string) {
+	mu.Lock()
+	defer mu.Unlock()
+	logsDir = dir
+}
+
+// Config returns the default config file: PLAYLISTPORTER_CONFIG, otherwise
+// $XDG_CONFIG_HOME/playlistporter/config.yaml (~/.config/playlistporter/config.yaml)
+func Config() string {
+	if path := os.Getenv("PLAYLISTPORTER_CONFIG"); path != "" {
+		return path
+	}
+	return xdgConfig()
+}
+
+// States returns the directory of saved states: the one set with SetStates,
+// PLAYLISTPORTER_STATES_DIR, otherwise $XDG_STATE_HOME/playlistporter/states
+// (~/.local/state/playlistporter/states)
+func States() string {
+	mu.Lock()
+	dir := statesDir
+	mu.Unlock()
+	return resolve(dir, "PLAYLISTPORTER_STATES_DIR", legacyStates)
+}
+
+// Logs returns the directory of log files, found like States:
+// $XDG_STATE_HOME/playlistporter/logs by default
+func Logs() string {
+	mu.Lock()
+	dir := logsDir
+	mu.Unlock()
+	return resolve(dir, "PLAYLISTPORTER_LOGS_DIR", legacyLogs)
+}
+
+// resolve picks the set directory, the environment variable or the directory named name
+// under the XDG state home, in that order
+func resolve(set, env, name string) string {
+	if set != "" {
+		return set
+	}
+	if dir := os.Getenv(env); dir != "" {
+		return dir
+	}
+	return filepath.Join(stateHome(), appName, name)
+}
+
+// xdgConfig returns the config file under the XDG config home
+func xdgConfig() string {
+	return filepath.Join(configHome(), appName, "config.yaml")
+}
+
+// configHome returns $XDG_CONFIG_HOME, ~/.config by default
+func configHome() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(home(), ".config")
+}
+
+// stateHome returns $XDG_STATE_HOME, ~/.local/state by default
+func stateHome() string {
+	if dir := os.Getenv("XDG_STATE_HOME"); filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(home(), ".local", "state")
+}
+
+// home returns the home directory, or the working directory when there is none
+func home() string {
+	if dir, err := os.UserHomeDir(); err == nil {
+		return dir
+	}
+	return "."
+}
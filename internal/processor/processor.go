@@ -5,9 +5,17 @@ import (
 	"strings"
 	"unicode"
 
-	"playlistporter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
 )
 
+// RulesVersion identifies the normalization rules. Normalized metadata saved in states
+// is recomputed when it differs, so bump it whenever the rules below change.
+const RulesVersion = 1
+
+// MatcherVersion identifies the scoring rules and search strategies. Bump it when they change
+// so results matched by older rules can be listed and re-matched.
+const MatcherVersion = 2
+
 // Processor handles data normalization and track matching logic
 type Processor struct {
 	// Common patterns to remove from track titles and artist names
@@ -47,6 +55,19 @@ func (p *Processor) NormalizeTrack(track *models.Track) {
 	track.NormalizedArtist = p.normalizeString(track.Artist)
 }
 
+// NormalizeMissing normalizes the tracks that have no normalized metadata yet
+// and returns how many were normalized
+func (p *Processor) NormalizeMissing(tracks []models.Track) int {
+	count := 0
+	for i := range tracks {
+		if tracks[i].NormalizedTitle == "" && tracks[i].NormalizedArtist == "" {
+			p.NormalizeTrack(&tracks[i])
+			count++
+		}
+	}
+	return count
+}
+
 // normalizeString applies various normalization techniques to improve matching
 func (p *Processor) normalizeString(input string) string {
 	if input == "" {
@@ -132,6 +153,10 @@ func (p *Processor) removeCommonWords(input string) string {
 func (p *Processor) CalculateMatchScore(track1, track2 models.Track) float64 {
 	titleScore := p.stringSimilarity(track1.NormalizedTitle, track2.NormalizedTitle)
 	artistScore := p.stringSimilarity(track1.NormalizedArtist, track2.NormalizedArtist)
+	if !track1.KnownArtist() || !track2.KnownArtist() {
+		// A "Various Artists" placeholder says nothing about the track, the title decides alone
+		artistScore = titleScore
+	}
 
 	// Weight title more heavily than artist
 	finalScore := (titleScore * 0.7) + (artistScore * 0.3)
@@ -0,0 +1,73 @@
+package quota
+
+// Costs are the YouTube Data API quota figures PlaylistPorter accounts with
+type Costs struct {
+	DailyLimit int // Units per day of the Google Cloud project
+	Search     int // Units of one search
+	Insert     int // Units of one playlist insert
+}
+
+// DefaultCosts are Google's defaults: a 10,000 unit project, 100 units per search.list and
+// 50 per playlistItems.insert
+var DefaultCosts = Costs{DailyLimit: DailyLimit, Search: 100, Insert: 50}
+
+// PerTrack estimates the units of a ported track: one and a half searches on average and an
+// insert, or only the insert when searching YouTube Music
+func (c Costs) PerTrack(ytmusic bool) int {
+	if ytmusic {
+		return c.Insert
+	}
+	return c.Search*3/2 + c.Insert
+}
+
+// Forecast estimates what finishing a playlist still takes
+type Forecast struct {
+	Tracks   int // Tracks not processed yet
+	Sessions int // Runs needed at the given tracks per session
+	Units    int // YouTube quota units
+	Days     int // Quota days needed at the daily limit
+
+	dailyLimit int
+}
+
+// NewForecast estimates the sessions, quota and days needed for the remaining tracks;
+// unitsPerTrack is 0 for destinations that don't spend YouTube quota
+func NewForecast(remaining, tracksPerSession, unitsPerTrack, dailyLimit int) Forecast {
+	if remaining <= 0 {
+		return Forecast{dailyLimit: dailyLimit}
+	}
+	forecast := Forecast{
+		Tracks:     remaining,
+		Units:      remaining * unitsPerTrack,
+		dailyLimit: dailyLimit,
+	}
+	if tracksPerSession > 0 {
+		forecast.Sessions = (remaining + tracksPerSession - 1) / tracksPerSession
+	}
+	forecast.Days = days(forecast.Units, dailyLimit)
+	return forecast
+}
+
+// Add sums two forecasts, e.g. for all playlists together; sessions add up, days are
+// recomputed from the combined quota
+func (f Forecast) Add(other Forecast) Forecast {
+	sum := Forecast{
+		Tracks:     f.Tracks + other.Tracks,
+		Sessions:   f.Sessions + other.Sessions,
+		Units:      f.Units + other.Units,
+		dailyLimit: f.dailyLimit,
+	}
+	if sum.dailyLimit <= 0 {
+		sum.dailyLimit = other.dailyLimit
+	}
+	sum.Days = days(sum.Units, sum.dailyLimit)
+	return sum
+}
+
+// days returns the quota days units take, at DailyLimit when dailyLimit isn't set
+func days(units, dailyLimit int) int {
+	if dailyLimit <= 0 {
+		dailyLimit = DailyLimit
+	}
+	return (units + dailyLimit - 1) / dailyLimit
+}
@@ -0,0 +1,345 @@
+package quota
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/traffic"
+)
+
+// DailyLimit is the default quota of a Google Cloud project
+const DailyLimit = 10000
+
+const (
+	lockWait     = 15 * time.Second // How long to wait for another user's lock on a file ledger
+	staleLock    = time.Minute      // Locks older than this were left by a crashed run
+	httpTimeout  = 15 * time.Second
+	httpAttempts = 5 // Tries when another user updated an HTTP ledger at the same time
+)
+
+// ErrLedgerBusy is returned when the ledger stayed locked or kept changing during an update
+var ErrLedgerBusy = errors.New("the shared quota ledger is busy, try again in a minute")
+
+// Ledger shares the daily quota of one Google Cloud project between several people. Each
+// session reserves the units it may spend before starting and releases what it didn't use.
+// The ledger is a JSON document on a shared drive or at an HTTP URL accepting PUT.
+type Ledger struct {
+	store      ledgerStore
+	user       string
+	dailyLimit int
+	userLimit  int
+}
+
+// Reservation is a budget granted to one session
+type Reservation struct {
+	ID    string
+	Units int
+}
+
+// errUnchanged is returned by an update's change function that left the document as it was,
+// so the store skips writing it back
+var errUnchanged = errors.New("ledger unchanged")
+
+// ledgerDoc is the shared document; entries are dropped when the quota resets
+type ledgerDoc struct {
+	Day     string        `json:"day"` // Pacific date the entries count against
+	Entries []ledgerEntry `json:"entries"`
+}
+
+type ledgerEntry struct {
+	ID         string    `json:"id"`
+	User       string    `json:"user"`
+	Reserved   int       `json:"reserved"`
+	Used       int       `json:"used"`
+	Released   bool      `json:"released"`
+	ReservedAt time.Time `json:"reserved_at"`
+}
+
+// units is what an entry counts against the day: reservations never released
+// (e.g. by a crashed run) keep their whole budget
+func (e ledgerEntry) units() int {
+	if e.Released {
+		return e.Used
+	}
+	return e.Reserved
+}
+
+// ledgerStore reads and writes the document under a lock or a version check. The change
+// function may run several times, once per attempt, and returns errUnchanged to skip the write.
+type ledgerStore interface {
+	update(change func(doc *ledgerDoc) error) error
+}
+
+// OpenLedger connects to a ledger at a file path or http(s) URL. dailyLimit defaults to
+// DailyLimit; userLimit caps what one user may spend per day (0 for no cap).
+func OpenLedger(location, user string, dailyLimit, userLimit int) *Ledger {
+	if dailyLimit <= 0 {
+		dailyLimit = DailyLimit
+	}
+
+	var store ledgerStore = &fileStore{path: location}
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		store = &httpStore{url: location, client: traffic.NewClient(httpTimeout)}
+	}
+	return &Ledger{store: store, user: user, dailyLimit: dailyLimit, userLimit: userLimit}
+}
+
+// Reserve asks for up to units and returns what was granted, which may be less or 0
+// when the project or this user has little budget left today
+func (l *Ledger) Reserve(units int) (*Reservation, error) {
+	reservation := &Reservation{}
+	err := l.store.update(func(doc *ledgerDoc) error {
+		reset := resetIfNewDay(doc)
+
+		projectUsed, userUsed := 0, 0
+		for _, entry := range doc.Entries {
+			projectUsed += entry.units()
+			if entry.User == l.user {
+				userUsed += entry.units()
+			}
+		}
+
+		granted := min(units, l.dailyLimit-projectUsed)
+		if l.userLimit > 0 {
+			granted = min(granted, l.userLimit-userUsed)
+		}
+		if granted <= 0 {
+			reservation.Units = 0
+			if !reset {
+				return errUnchanged
+			}
+			return nil
+		}
+
+		reservation.ID = newReservationID()
+		reservation.Units = granted
+		doc.Entries = append(doc.Entries, ledgerEntry{
+			ID:         reservation.ID,
+			User:       l.user,
+			Reserved:   granted,
+			ReservedAt: time.Now(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// Release records what a session actually spent, returning the rest to the others
+func (l *Ledger) Release(reservation *Reservation, used int) error {
+	if reservation == nil || reservation.ID == "" {
+		return nil
+	}
+	return l.store.update(func(doc *ledgerDoc) error {
+		for i := range doc.Entries {
+			if doc.Entries[i].ID == reservation.ID {
+				doc.Entries[i].Used = min(max(used, 0), doc.Entries[i].Reserved)
+				doc.Entries[i].Released = true
+			}
+		}
+		return nil
+	})
+}
+
+// Usage lists today's spending per user, largest first, and what is left for the project
+func (l *Ledger) Usage() ([]UserUsage, int, error) {
+	var usage []UserUsage
+	var remaining int
+	err := l.store.update(func(doc *ledgerDoc) error {
+		reset := resetIfNewDay(doc)
+		byUser := make(map[string]int)
+		remaining = l.dailyLimit
+		for _, entry := range doc.Entries {
+			byUser[entry.User] += entry.units()
+			remaining -= entry.units()
+		}
+		usage = usage[:0]
+		for user, units := range byUser {
+			usage = append(usage, UserUsage{User: user, Units: units})
+		}
+		if !reset {
+			return errUnchanged // Only a new day changes the ledger here
+		}
+		return nil
+	})
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Units > usage[j].Units })
+	return usage, max(remaining, 0), err
+}
+
+// UserUsage is what one user spent today
+type UserUsage struct {
+	User  string
+	Units int
+}
+
+// resetIfNewDay drops the entries of previous quota days and reports whether it did
+func resetIfNewDay(doc *ledgerDoc) bool {
+	today := time.Now().In(pacific()).Format("2006-01-02")
+	if doc.Day == today {
+		return false
+	}
+	doc.Day = today
+	doc.Entries = nil
+	return true
+}
+
+// newReservationID returns a random ID for a reservation
+func newReservationID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// fileStore keeps the ledger in a file, e.g. on a network share, guarded by a lock file
+type fileStore struct {
+	path string
+}
+
+func (s *fileStore) update(change func(doc *ledgerDoc) error) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("creating ledger directory: %w", err)
+	}
+
+	lockPath := s.path + ".lock"
+	deadline := time.Now().Add(lockWait)
+	for {
+		lock, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			lock.Close()
+			break
+		}
+		if !os.IsExist(err) {
+			return fmt.Errorf("locking quota ledger: %w", err)
+		}
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > staleLock {
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return ErrLedgerBusy
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	defer os.Remove(lockPath)
+
+	var doc ledgerDoc
+	data, err := os.ReadFile(s.path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading quota ledger: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("parsing quota ledger: %w", err)
+		}
+	}
+
+	if err := change(&doc); err != nil {
+		if errors.Is(err, errUnchanged) {
+			return nil
+		}
+		return err
+	}
+
+	data, err = json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding quota ledger: %w", err)
+	}
+	// Synced before the rename, so a crash leaves the old ledger or the new one, never a torn one
+	tmp := s.path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("writing quota ledger: %w", err)
+	}
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("writing quota ledger: %w", err)
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// httpStore keeps the ledger as a JSON document on a server supporting GET and PUT with
+// ETags, such as WebDAV; If-Match makes concurrent updates retry instead of overwriting
+type httpStore struct {
+	url    string
+	client *http.Client
+}
+
+func (s *httpStore) update(change func(doc *ledgerDoc) error) error {
+	for attempt := 0; attempt < httpAttempts; attempt++ {
+		var doc ledgerDoc
+		resp, err := s.client.Get(s.url)
+		if err != nil {
+			return fmt.Errorf("reading quota ledger: %w", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		etag := resp.Header.Get("ETag")
+		switch {
+		case resp.StatusCode == http.StatusNotFound:
+		case resp.StatusCode < 200 || resp.StatusCode >= 300:
+			return fmt.Errorf("reading quota ledger: %s", resp.Status)
+		case len(bytes.TrimSpace(body)) > 0:
+			if err := json.Unmarshal(body, &doc); err != nil {
+				return fmt.Errorf("parsing quota ledger: %w", err)
+			}
+		}
+
+		if err := change(&doc); err != nil {
+			if errors.Is(err, errUnchanged) {
+				return nil
+			}
+			return err
+		}
+
+		data, err := json.Marshal(doc)
+		if err != nil {
+			return fmt.Errorf("encoding quota ledger: %w", err)
+		}
+		req, err := http.NewRequest("PUT", s.url, bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("creating request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if etag != "" {
+			req.Header.Set("If-Match", etag)
+		} else if resp.StatusCode == http.StatusNotFound {
+			req.Header.Set("If-None-Match", "*")
+		}
+
+		put, err := s.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("writing quota ledger: %w", err)
+		}
+		put.Body.Close()
+		if put.StatusCode == http.StatusPreconditionFailed {
+			time.Sleep(time.Duration(attempt+1) * 300 * time.Millisecond)
+			continue // Someone else updated the ledger meanwhile
+		}
+		if put.StatusCode < 200 || put.StatusCode >= 300 {
+			return fmt.Errorf("writing quota ledger: %s", put.Status)
+		}
+		return nil
+	}
+	return ErrLedgerBusy
+}
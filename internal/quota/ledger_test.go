@@ -0,0 +1,69 @@
+package quota
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestUsageRetriesAndReadOnly(t *testing.T) {
+	doc := ledgerDoc{
+		Day: time.Now().In(pacific()).Format("2006-01-02"),
+		Entries: []ledgerEntry{
+			{ID: "a", User: "ann", Reserved: 300, Used: 100, Released: true},
+			{ID: "b", User: "bob", Reserved: 500},
+		},
+	}
+	body, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var puts atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("ETag", `"v1"`)
+			w.Write(body)
+		case http.MethodPut:
+			// Another user updates the ledger in between, twice
+			if puts.Add(1) <= 2 {
+				w.WriteHeader(http.StatusPreconditionFailed)
+			}
+		}
+	}))
+	defer server.Close()
+
+	ledger := OpenLedger(server.URL, "ann", 10000, 0)
+	usage, remaining, err := ledger.Usage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if remaining != 10000-600 {
+		t.Errorf("remaining = %d, want %d", remaining, 10000-600)
+	}
+	if len(usage) != 2 || usage[0].User != "bob" || usage[0].Units != 500 {
+		t.Errorf("usage = %+v", usage)
+	}
+	if puts.Load() != 0 {
+		t.Errorf("Usage wrote the ledger %d times, want no writes on the same day", puts.Load())
+	}
+
+	// A new day resets the ledger, so the write goes out and is retried on conflicts
+	doc.Day = "2000-01-01"
+	if body, err = json.Marshal(doc); err != nil {
+		t.Fatal(err)
+	}
+	if _, remaining, err = ledger.Usage(); err != nil {
+		t.Fatal(err)
+	}
+	if puts.Load() != 3 {
+		t.Errorf("Usage wrote %d times, want 3 attempts", puts.Load())
+	}
+	if remaining != 10000 {
+		t.Errorf("remaining after retries = %d, want %d", remaining, 10000)
+	}
+}
@@ -0,0 +1,55 @@
+package quota
+
+import (
+	"fmt"
+	"time"
+	_ "time/tzdata" // Embedded zone database so the reset time works on minimal systems
+)
+
+// resetZone is the time zone in which the YouTube Data API quota resets at midnight
+const resetZone = "America/Los_Angeles"
+
+// pacific returns the quota reset location, falling back to a fixed PST offset
+func pacific() *time.Location {
+	loc, err := time.LoadLocation(resetZone)
+	if err != nil {
+		return time.FixedZone("PST", -8*60*60)
+	}
+	return loc
+}
+
+// NextReset returns the next Pacific midnight after now, in the local time zone
+func NextReset(now time.Time) time.Time {
+	pt := now.In(pacific())
+	midnight := time.Date(pt.Year(), pt.Month(), pt.Day()+1, 0, 0, 0, 0, pt.Location())
+	return midnight.In(now.Location())
+}
+
+// UntilReset returns how long until the quota resets
+func UntilReset(now time.Time) time.Duration {
+	return NextReset(now).Sub(now)
+}
+
+// ResetMessage describes the next reset in the user's local time, e.g. "09:00 CEST (in 5h 12m)"
+func ResetMessage(now time.Time) string {
+	next := NextReset(now)
+
+	day := ""
+	if next.YearDay() != now.YearDay() || next.Year() != now.Year() {
+		day = "tomorrow "
+	}
+
+	return fmt.Sprintf("%s%s (in %s)", day, next.Format("15:04 MST"), FormatWait(next.Sub(now)))
+}
+
+// FormatWait formats a wait duration as hours and minutes
+func FormatWait(d time.Duration) string {
+	d = d.Round(time.Minute)
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+
+	if hours == 0 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh %dm", hours, minutes)
+}
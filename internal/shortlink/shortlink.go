@@ -0,0 +1,44 @@
+// Package shortlink resolves share links such as spotify.link and youtu.be to canonical URLs
+package shortlink
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/traffic"
+)
+
+// resolveTimeout bounds the whole redirect chain
+const resolveTimeout = 10 * time.Second
+
+// hosts lists the share-link domains that redirect to a canonical URL
+var hosts = map[string]bool{
+	"spotify.link":     true,
+	"spotify.app.link": true,
+	"youtu.be":         true,
+}
+
+// IsShortLink reports whether host serves share links that need resolving
+func IsShortLink(host string) bool {
+	return hosts[strings.ToLower(host)]
+}
+
+// Resolve follows the redirects of a share link with HEAD requests and returns the final URL.
+// Servers that reject HEAD are retried with GET.
+func Resolve(link string) (string, error) {
+	client := traffic.NewClient(resolveTimeout)
+
+	resp, err := client.Head(link)
+	if err == nil && resp.StatusCode == http.StatusMethodNotAllowed {
+		resp.Body.Close()
+		resp, err = client.Get(link)
+	}
+	if err != nil {
+		return "", fmt.Errorf("resolving %s: %w", link, err)
+	}
+	resp.Body.Close()
+
+	return resp.Request.URL.String(), nil
+}
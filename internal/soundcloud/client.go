@@ -0,0 +1,316 @@
+// Package soundcloud uploads ported playlists to SoundCloud
+package soundcloud
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/auth"
+	"github.com/Verryx-02/PlaylistPorter/internal/config"
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/processor"
+	"github.com/Verryx-02/PlaylistPorter/internal/traffic"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+const (
+	baseURL = "https://api.soundcloud.com"
+
+	tokenCacheName     = "soundcloud" // Cached OAuth token, see auth.TokenDir
+	defaultRedirectURI = "http://127.0.0.1:8080/callback"
+
+	searchLimit  = 10
+	minScore     = 0.5  // Lowest score accepted as a match
+	durationSlop = 10.0 // Seconds of difference still counted as the same recording
+)
+
+// Client represents a SoundCloud API client
+type Client struct {
+	config     *config.SoundCloudConfig
+	httpClient *http.Client
+	processor  *processor.Processor
+	permalinks map[string]string // Web links of playlists seen in this run
+}
+
+// NewClient creates a new SoundCloud client, signing in with the browser when no saved token works
+func NewClient(cfg *config.SoundCloudConfig) (*Client, error) {
+	if cfg.ClientID == "" || cfg.ClientSecret == "" {
+		return nil, fmt.Errorf("soundcloud.client_id and soundcloud.client_secret are required for -dest soundcloud")
+	}
+
+	client := &Client{
+		config:     cfg,
+		processor:  processor.New(),
+		permalinks: make(map[string]string),
+	}
+
+	if err := client.authenticate(); err != nil {
+		return nil, fmt.Errorf("authentication failed: %w", err)
+	}
+
+	return client, nil
+}
+
+// authenticate runs the OAuth 2.1 authorization code flow with PKCE, reusing the cached token
+func (c *Client) authenticate() error {
+	redirectURI := c.config.RedirectURI
+	if redirectURI == "" {
+		redirectURI = defaultRedirectURI
+	}
+
+	cfg := &oauth2.Config{
+		ClientID:     c.config.ClientID,
+		ClientSecret: c.config.ClientSecret,
+		RedirectURL:  redirectURI,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  "https://secure.soundcloud.com/authorize",
+			TokenURL: "https://secure.soundcloud.com/oauth/token",
+		},
+	}
+
+	cached, err := auth.LoadToken(tokenCacheName)
+	if err != nil {
+		fmt.Printf("Ignoring saved SoundCloud authorization: %v\n", err)
+	}
+	if cached != nil && cached.RefreshToken != "" {
+		source := auth.NewSavingTokenSource(tokenCacheName, cfg.TokenSource(traffic.Context(), cached))
+		if _, err := source.Token(); err == nil {
+			c.httpClient = oauth2.NewClient(traffic.Context(), source)
+			ui.Println("Using saved SoundCloud authorization")
+			return nil
+		}
+		fmt.Printf("Saved SoundCloud authorization is no longer valid (%v), signing in again\n", err)
+	}
+
+	verifier := oauth2.GenerateVerifier()
+	state := auth.NewState()
+	authURL := cfg.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier))
+
+	fmt.Println("\nSoundCloud Authentication Required")
+	fmt.Println("=====================================")
+
+	var authCode string
+	if auth.NoBrowser() {
+		code, err := auth.PromptForCode(authURL, redirectURI, state)
+		if err != nil {
+			return err
+		}
+		authCode = code
+	} else {
+		// Spotify and SoundCloud only accept the exact registered redirect URI, so no fallback port
+		listener, _, err := auth.ListenCallback(redirectURI, "", false)
+		if err != nil {
+			return err
+		}
+		server := auth.StartCallbackServer(listener, state)
+
+		fmt.Printf("Open this URL in your browser and allow access:\n\n%s\n\n", authURL)
+
+		code, err := server.Wait(auth.CallbackTimeout)
+		if err != nil {
+			return err
+		}
+		authCode = code
+		fmt.Println("Authorization code received!")
+	}
+
+	token, err := cfg.Exchange(traffic.Context(), authCode, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return fmt.Errorf("exchanging authorization code: %w", err)
+	}
+
+	if err := auth.SaveToken(tokenCacheName, token); err != nil {
+		fmt.Printf("Warning: could not save SoundCloud authorization, you'll be asked to sign in again next time: %v\n", err)
+	}
+	source := auth.NewSavingTokenSource(tokenCacheName, cfg.TokenSource(traffic.Context(), token))
+	c.httpClient = oauth2.NewClient(traffic.Context(), source)
+
+	ui.Println("SoundCloud authentication successful!")
+	return nil
+}
+
+// SearchTrackOutcome searches SoundCloud for a track and scores the results by title, uploader and duration
+func (c *Client) SearchTrackOutcome(track models.Track) (*models.SearchOutcome, error) {
+	query := url.Values{}
+	query.Set("q", fmt.Sprintf("%s %s", track.Artist, track.Title))
+	query.Set("limit", fmt.Sprint(searchLimit))
+
+	var results []soundcloudTrack
+	if err := c.makeRequest("GET", baseURL+"/tracks?"+query.Encode(), nil, &results); err != nil {
+		return nil, err
+	}
+
+	scoreStart := time.Now()
+	original := track
+	c.processor.NormalizeTrack(&original)
+
+	var best *models.Track
+	bestScore := 0.0
+	for _, result := range results {
+		candidate := models.Track{
+			ID:       fmt.Sprint(result.ID),
+			Title:    result.Title,
+			Artist:   result.User.Username,
+			Duration: time.Duration(result.Duration) * time.Millisecond,
+		}
+		if result.PublisherMetadata.Artist != "" {
+			candidate.Artist = result.PublisherMetadata.Artist
+		}
+		c.processor.NormalizeTrack(&candidate)
+
+		score := c.processor.CalculateMatchScore(original, candidate)
+		if original.Duration > 0 && candidate.Duration > 0 {
+			diff := (original.Duration - candidate.Duration).Seconds()
+			if diff < 0 {
+				diff = -diff
+			}
+			if diff <= durationSlop {
+				score += 0.1
+			}
+		}
+		if score > 1 {
+			score = 1
+		}
+
+		if score > bestScore {
+			bestScore = score
+			matched := candidate
+			best = &matched
+		}
+	}
+
+	outcome := &models.SearchOutcome{SearchesUsed: 1, ScoreTime: time.Since(scoreStart)}
+	if best != nil && bestScore >= minScore {
+		outcome.Track = best
+		outcome.Score = bestScore
+		outcome.Strategy = "soundcloud"
+	}
+	return outcome, nil
+}
+
+// CreatePlaylist creates a private SoundCloud playlist
+func (c *Client) CreatePlaylist(name, description string) (*models.Playlist, error) {
+	request := map[string]interface{}{
+		"playlist": map[string]interface{}{
+			"title":       name,
+			"description": description,
+			"sharing":     "private",
+			"tracks":      []interface{}{},
+		},
+	}
+
+	response := &soundcloudPlaylist{}
+	if err := c.makeRequest("POST", baseURL+"/playlists", request, response); err != nil {
+		return nil, err
+	}
+
+	c.permalinks[fmt.Sprint(response.ID)] = response.PermalinkURL
+	return &models.Playlist{
+		ID:          fmt.Sprint(response.ID),
+		Name:        response.Title,
+		Description: description,
+	}, nil
+}
+
+// AddTracksToPlaylist appends tracks to a playlist. SoundCloud replaces a playlist's track list
+// as a whole, so the current list is read first. The track IDs double as item IDs.
+func (c *Client) AddTracksToPlaylist(playlistID string, trackIDs []string) ([]string, error) {
+	current := &soundcloudPlaylist{}
+	if err := c.makeRequest("GET", fmt.Sprintf("%s/playlists/%s", baseURL, playlistID), nil, current); err != nil {
+		return nil, fmt.Errorf("reading playlist: %w", err)
+	}
+	c.permalinks[playlistID] = current.PermalinkURL
+
+	var tracks []map[string]interface{}
+	for _, track := range current.Tracks {
+		tracks = append(tracks, map[string]interface{}{"id": track.ID})
+	}
+	for _, id := range trackIDs {
+		tracks = append(tracks, map[string]interface{}{"id": id})
+	}
+
+	request := map[string]interface{}{
+		"playlist": map[string]interface{}{"tracks": tracks},
+	}
+	if err := c.makeRequest("PUT", fmt.Sprintf("%s/playlists/%s", baseURL, playlistID), request, nil); err != nil {
+		return nil, err
+	}
+
+	return trackIDs, nil
+}
+
+// PlaylistURL returns a link to a SoundCloud playlist, the web page when it is known
+func (c *Client) PlaylistURL(playlistID string) string {
+	if link := c.permalinks[playlistID]; link != "" {
+		return link
+	}
+	return fmt.Sprintf("%s/playlists/%s", baseURL, playlistID)
+}
+
+// makeRequest performs an HTTP request to the SoundCloud API
+func (c *Client) makeRequest(method, requestURL string, body interface{}, result interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("marshaling request body: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, requestURL, reqBody)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json; charset=utf-8")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, respBody)
+	}
+
+	if result != nil {
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+			return fmt.Errorf("decoding response: %w", err)
+		}
+	}
+	return nil
+}
+
+// SoundCloud API response structures
+
+type soundcloudTrack struct {
+	ID                int64  `json:"id"`
+	Title             string `json:"title"`
+	Duration          int64  `json:"duration"` // Milliseconds
+	PermalinkURL      string `json:"permalink_url"`
+	PublisherMetadata struct {
+		Artist string `json:"artist"`
+	} `json:"publisher_metadata"`
+	User struct {
+		Username string `json:"username"`
+	} `json:"user"`
+}
+
+type soundcloudPlaylist struct {
+	ID           int64             `json:"id"`
+	Title        string            `json:"title"`
+	PermalinkURL string            `json:"permalink_url"`
+	Tracks       []soundcloudTrack `json:"tracks"`
+}
@@ -0,0 +1,109 @@
+package spt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+)
+
+// Prefixes of the IDs standing for albums and single tracks, which are ported like playlists
+const (
+	albumPrefix = "album-"
+	trackPrefix = "track-"
+
+	maxTracksPerLookup = 50 // Spotify returns up to 50 tracks per /tracks request
+)
+
+// IsAlbumID reports whether a playlist ID refers to a Spotify album
+func IsAlbumID(id string) bool {
+	return strings.HasPrefix(id, albumPrefix)
+}
+
+// IsTrackID reports whether a playlist ID refers to a single Spotify track
+func IsTrackID(id string) bool {
+	return strings.HasPrefix(id, trackPrefix)
+}
+
+// getAlbum reads an album as a playlist named "Artist - Album". Album track listings
+// lack ISRCs and popularity, so the full tracks are looked up in batches.
+func (c *Client) getAlbum(id string) (*models.Playlist, error) {
+	albumID := strings.TrimPrefix(id, albumPrefix)
+
+	album := &spotifyAlbumResponse{}
+	if err := c.makeRequest("GET", fmt.Sprintf("%s/albums/%s", baseURL, albumID), nil, album); err != nil {
+		return nil, checkUnavailable(err)
+	}
+
+	var trackIDs []string
+	page := album.Tracks
+	for {
+		for _, track := range page.Items {
+			if track.ID != "" {
+				trackIDs = append(trackIDs, track.ID)
+			}
+		}
+		if page.Next == "" {
+			break
+		}
+		next := page.Next
+		page = spotifyAlbumTracks{}
+		if err := c.makeRequest("GET", next, nil, &page); err != nil {
+			return nil, fmt.Errorf("fetching album tracks: %w", err)
+		}
+	}
+
+	var tracks []models.Track
+	for start := 0; start < len(trackIDs); start += maxTracksPerLookup {
+		end := start + maxTracksPerLookup
+		if end > len(trackIDs) {
+			end = len(trackIDs)
+		}
+		response := &spotifyTracksLookup{}
+		url := fmt.Sprintf("%s/tracks?ids=%s", baseURL, strings.Join(trackIDs[start:end], ","))
+		if err := c.makeRequest("GET", url, nil, response); err != nil {
+			return nil, fmt.Errorf("fetching album tracks: %w", err)
+		}
+		for _, track := range response.Tracks {
+			if track.ID != "" {
+				tracks = append(tracks, track.toTrack())
+			}
+		}
+	}
+
+	return &models.Playlist{
+		ID:          id,
+		Name:        fmt.Sprintf("%s - %s", getFirstArtist(album.Artists), album.Name),
+		Description: fmt.Sprintf("Album released %s", album.ReleaseDate),
+		Tracks:      tracks,
+		TotalTracks: len(tracks),
+		IsPublic:    true,
+	}, nil
+}
+
+// GetTrack fetches a single track by its Spotify track ID
+func (c *Client) GetTrack(trackID string) (*models.Track, error) {
+	response := &spotifyTrack{}
+	url := fmt.Sprintf("%s/tracks/%s", baseURL, strings.TrimPrefix(trackID, trackPrefix))
+	if err := c.makeRequest("GET", url, nil, response); err != nil {
+		return nil, checkUnavailable(err)
+	}
+	track := response.toTrack()
+	return &track, nil
+}
+
+type spotifyAlbumResponse struct {
+	spotifyAlbum
+	Tracks spotifyAlbumTracks `json:"tracks"`
+}
+
+type spotifyAlbumTracks struct {
+	Items []struct {
+		ID string `json:"id"`
+	} `json:"items"`
+	Next string `json:"next"`
+}
+
+type spotifyTracksLookup struct {
+	Tracks []spotifyTrack `json:"tracks"`
+}
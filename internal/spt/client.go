@@ -1,23 +1,43 @@
 package spt
 
 import (
-	"context"
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
 
-	"playlistporter/internal/config"
-	"playlistporter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/config"
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/traffic"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
 )
 
 const (
 	baseURL = "https://api.spotify.com/v1"
+
+	// Response fields requested in bandwidth-light mode
+	playlistFields = "id,name,description,public,owner(id,display_name)"
+	trackFields    = "next,total,items(track(id,type,name,duration_ms,explicit,popularity,external_ids(isrc),artists(name),album(name,release_date,images,artists(name))))"
 )
 
+// ErrPlaylistUnavailable is returned when a playlist is private, deleted or not available in the region
+var ErrPlaylistUnavailable = errors.New("playlist is private, deleted or not available")
+
+// APIError is returned when the Spotify API responds with a non-OK status
+type APIError struct {
+	StatusCode int
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API request failed with status %d", e.StatusCode)
+}
+
 // Client represents a Spotify API client
 type Client struct {
 	config     *config.SPTConfig
@@ -38,41 +58,69 @@ func NewClient(cfg *config.SPTConfig) (*Client, error) {
 	return client, nil
 }
 
-// authenticate performs OAuth2 client credentials flow
+// authenticate performs OAuth2 client credentials flow, or signs in the user when spt.user_auth is set
 func (c *Client) authenticate() error {
-	// Note: redirect_uri is not used in Client Credentials Flow
-	cfg := &clientcredentials.Config{
-		ClientID:     c.config.ClientID,
-		ClientSecret: c.config.ClientSecret,
-		TokenURL:     "https://accounts.spotify.com/api/token",
+	if c.config.UserAuth {
+		return c.authenticateUser()
 	}
 
-	fmt.Println("Authenticating with Spotify...")
-	token, err := cfg.Token(context.Background())
+	cfg := clientCredentials(c.config)
+
+	ui.Println("Authenticating with Spotify...")
+	token, err := cfg.Token(traffic.Context())
 	if err != nil {
 		return fmt.Errorf("getting access token: %w", err)
 	}
 
 	c.token = token
-	c.httpClient = cfg.Client(context.Background())
-	fmt.Println("Spotify authentication successful!")
+	c.httpClient = cfg.Client(traffic.Context())
+	ui.Println("Spotify authentication successful!")
+
+	return nil
+}
 
+// clientCredentials configures the app-only Client Credentials Flow; redirect_uri is not used in it
+func clientCredentials(cfg *config.SPTConfig) *clientcredentials.Config {
+	return &clientcredentials.Config{
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		TokenURL:     "https://accounts.spotify.com/api/token",
+	}
+}
+
+// CheckCredentials requests an app token, which confirms client_id and client_secret
+// without reading anything
+func CheckCredentials(cfg *config.SPTConfig) error {
+	if _, err := clientCredentials(cfg).Token(traffic.Context()); err != nil {
+		return fmt.Errorf("getting access token: %w", err)
+	}
 	return nil
 }
 
 // GetPlaylist fetches a playlist by ID
 func (c *Client) GetPlaylist(playlistID string) (*models.Playlist, error) {
+	switch {
+	case IsLikedSongs(playlistID):
+		return c.getLikedSongs()
+	case IsAlbumID(playlistID):
+		return c.getAlbum(playlistID)
+	}
+
 	url := fmt.Sprintf("%s/playlists/%s", baseURL, playlistID)
+	if traffic.IsLight() {
+		// The first 100 tracks come with the playlist by default, but they are fetched separately
+		url += "?fields=" + playlistFields
+	}
 
 	playlist := &spotifyPlaylist{}
 	if err := c.makeRequest("GET", url, nil, playlist); err != nil {
-		return nil, err
+		return nil, checkUnavailable(err)
 	}
 
 	// Fetch all tracks (Spotify API paginates results)
-	tracks, err := c.getAllPlaylistTracks(playlistID)
+	tracks, skippedEpisodes, err := c.getAllPlaylistTracks(playlistID)
 	if err != nil {
-		return nil, fmt.Errorf("fetching playlist tracks: %w", err)
+		return nil, fmt.Errorf("fetching playlist tracks: %w", checkUnavailable(err))
 	}
 
 	return &models.Playlist{
@@ -83,49 +131,60 @@ func (c *Client) GetPlaylist(playlistID string) (*models.Playlist, error) {
 		TotalTracks: len(tracks),
 		IsPublic:    playlist.Public,
 		OwnerID:     playlist.Owner.ID,
+
+		SkippedEpisodes: skippedEpisodes,
 	}, nil
 }
 
-// getAllPlaylistTracks fetches all tracks from a playlist (handles pagination)
-func (c *Client) getAllPlaylistTracks(playlistID string) ([]models.Track, error) {
+// getAllPlaylistTracks fetches all tracks from a playlist (handles pagination),
+// leaving out podcast episodes of mixed playlists and returning how many were skipped
+func (c *Client) getAllPlaylistTracks(playlistID string) ([]models.Track, int, error) {
 	var allTracks []models.Track
-	url := fmt.Sprintf("%s/playlists/%s/tracks", baseURL, playlistID)
+	skippedEpisodes := 0
+	// Ask for episodes explicitly so they can be told apart from unavailable tracks
+	url := fmt.Sprintf("%s/playlists/%s/tracks?additional_types=track,episode", baseURL, playlistID)
+	if traffic.IsLight() {
+		url += "&fields=" + trackFields // Spotify keeps the parameter in the next-page links
+	}
 
 	for url != "" {
 		response := &spotifyTracksResponse{}
 		if err := c.makeRequest("GET", url, nil, response); err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
 		for _, item := range response.Items {
+			if item.Track.Type == "episode" {
+				skippedEpisodes++
+				continue
+			}
 			if item.Track.ID != "" { // Skip local files or unavailable tracks
-				track := models.Track{
-					ID:          item.Track.ID,
-					Title:       item.Track.Name,
-					Artist:      getFirstArtist(item.Track.Artists),
-					Album:       item.Track.Album.Name,
-					Duration:    time.Duration(item.Track.DurationMS) * time.Millisecond,
-					ReleaseYear: parseReleaseYear(item.Track.Album.ReleaseDate),
-					ISRC:        getISRC(item.Track.ExternalIDs),
-				}
-				allTracks = append(allTracks, track)
+				allTracks = append(allTracks, item.Track.toTrack())
 			}
 		}
 
 		url = response.Next
 	}
 
-	return allTracks, nil
+	return allTracks, skippedEpisodes, nil
 }
 
 // makeRequest performs an HTTP request to Spotify API
 func (c *Client) makeRequest(method, requestURL string, body interface{}, result interface{}) error {
-	req, err := http.NewRequest(method, requestURL, nil)
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("marshaling request body: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, requestURL, reqBody)
 	if err != nil {
 		return fmt.Errorf("creating request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.token.AccessToken)
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := c.httpClient.Do(req)
@@ -134,10 +193,14 @@ func (c *Client) makeRequest(method, requestURL string, body interface{}, result
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("API request failed with status %d", resp.StatusCode)
+	// Playlist creation answers 201 Created
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return &APIError{StatusCode: resp.StatusCode}
 	}
 
+	if result == nil {
+		return nil
+	}
 	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
 		return fmt.Errorf("decoding response: %w", err)
 	}
@@ -147,6 +210,58 @@ func (c *Client) makeRequest(method, requestURL string, body interface{}, result
 
 // Helper functions
 
+// toTrack converts a Spotify track to the shared model
+func (t spotifyTrack) toTrack() models.Track {
+	track := models.Track{
+		ID:          t.ID,
+		Title:       t.Name,
+		Album:       t.Album.Name,
+		Duration:    time.Duration(t.DurationMS) * time.Millisecond,
+		ReleaseYear: parseReleaseYear(t.Album.ReleaseDate),
+		ISRC:        getISRC(t.ExternalIDs),
+		Explicit:    t.Explicit,
+		AlbumArtURL: albumArtURL(t.Album.Images),
+		Popularity:  t.Popularity,
+		SourceURL:   TrackURL(t.ID),
+	}
+	track.SetArtists(artistNames(t.Artists), getFirstArtist(t.Album.Artists))
+	return track
+}
+
+// albumArtURL picks the smallest album image that still fills a collage tile
+func albumArtURL(images []spotifyImage) string {
+	best := ""
+	bestWidth := 0
+	for _, img := range images {
+		if img.Width >= 300 && (best == "" || img.Width < bestWidth) {
+			best, bestWidth = img.URL, img.Width
+		}
+	}
+	if best == "" && len(images) > 0 {
+		best = images[0].URL // Spotify lists the largest image first
+	}
+	return best
+}
+
+// checkUnavailable maps 403/404 responses to ErrPlaylistUnavailable
+func checkUnavailable(err error) error {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) &&
+		(apiErr.StatusCode == http.StatusNotFound || apiErr.StatusCode == http.StatusForbidden) {
+		return fmt.Errorf("%w (status %d)", ErrPlaylistUnavailable, apiErr.StatusCode)
+	}
+	return err
+}
+
+// artistNames returns the names of the artists in credit order
+func artistNames(artists []spotifyArtist) []string {
+	names := make([]string, len(artists))
+	for i, artist := range artists {
+		names[i] = artist.Name
+	}
+	return names
+}
+
 func getFirstArtist(artists []spotifyArtist) string {
 	if len(artists) > 0 {
 		return artists[0].Name
@@ -197,10 +312,13 @@ type spotifyTrackItem struct {
 
 type spotifyTrack struct {
 	ID          string            `json:"id"`
+	Type        string            `json:"type"` // "track" or "episode"
 	Name        string            `json:"name"`
 	Artists     []spotifyArtist   `json:"artists"`
 	Album       spotifyAlbum      `json:"album"`
 	DurationMS  int               `json:"duration_ms"`
+	Explicit    bool              `json:"explicit"`
+	Popularity  *int              `json:"popularity"`
 	ExternalIDs map[string]string `json:"external_ids"`
 }
 
@@ -210,7 +328,15 @@ type spotifyArtist struct {
 }
 
 type spotifyAlbum struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	ReleaseDate string `json:"release_date"`
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	ReleaseDate string          `json:"release_date"`
+	Images      []spotifyImage  `json:"images"`
+	Artists     []spotifyArtist `json:"artists"`
+}
+
+type spotifyImage struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
 }
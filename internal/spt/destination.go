@@ -0,0 +1,156 @@
+package spt
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/processor"
+)
+
+const (
+	searchLimit       = 10
+	minScore          = 0.5  // Lowest score accepted as a match
+	durationSlop      = 10.0 // Seconds of difference still counted as the same recording
+	maxTracksPerWrite = 100  // Spotify accepts up to 100 URIs per request
+)
+
+// matcher scores Spotify search results; shared by all searches of a client
+var matcher = processor.New()
+
+// SearchTrackOutcome searches the Spotify catalog for a track read from another service,
+// first with field filters and then with a plain query
+func (c *Client) SearchTrackOutcome(track models.Track) (*models.SearchOutcome, error) {
+	original := track
+	matcher.NormalizeTrack(&original)
+
+	queries := []string{
+		fmt.Sprintf("track:\"%s\" artist:\"%s\"", track.Title, track.Artist),
+		fmt.Sprintf("%s %s", track.Artist, track.Title),
+	}
+
+	outcome := &models.SearchOutcome{}
+	bestScore := 0.0
+	for _, query := range queries {
+		params := url.Values{}
+		params.Set("q", query)
+		params.Set("type", "track")
+		params.Set("limit", fmt.Sprint(searchLimit))
+
+		response := &spotifySearchResponse{}
+		outcome.SearchesUsed++
+		if err := c.makeRequest("GET", baseURL+"/search?"+params.Encode(), nil, response); err != nil {
+			return nil, fmt.Errorf("searching Spotify: %w", err)
+		}
+
+		scoreStart := time.Now()
+		for _, result := range response.Tracks.Items {
+			candidate := models.Track{
+				ID:       result.ID,
+				Title:    result.Name,
+				Artist:   getFirstArtist(result.Artists),
+				Album:    result.Album.Name,
+				Duration: time.Duration(result.DurationMS) * time.Millisecond,
+			}
+			normalized := candidate
+			matcher.NormalizeTrack(&normalized)
+
+			score := matcher.CalculateMatchScore(original, normalized)
+			if original.Duration > 0 && candidate.Duration > 0 {
+				diff := (original.Duration - candidate.Duration).Seconds()
+				if diff < 0 {
+					diff = -diff
+				}
+				if diff <= durationSlop {
+					score += 0.1
+				}
+			}
+			if score > 1 {
+				score = 1
+			}
+
+			if score > bestScore && score >= minScore {
+				bestScore = score
+				matched := candidate
+				outcome.Track = &matched
+				outcome.Score = score
+				outcome.Strategy = "spotify"
+			}
+		}
+
+		outcome.ScoreTime += time.Since(scoreStart)
+
+		if outcome.Track != nil {
+			break
+		}
+	}
+
+	return outcome, nil
+}
+
+// CreatePlaylist creates a private playlist in the signed-in user's library
+func (c *Client) CreatePlaylist(name, description string) (*models.Playlist, error) {
+	request := map[string]interface{}{
+		"name":        name,
+		"description": description,
+		"public":      false,
+	}
+
+	response := &spotifyPlaylist{}
+	if err := c.makeRequest("POST", baseURL+"/me/playlists", request, response); err != nil {
+		return nil, scopeHint(err)
+	}
+
+	return &models.Playlist{
+		ID:          response.ID,
+		Name:        response.Name,
+		Description: response.Description,
+	}, nil
+}
+
+// AddTracksToPlaylist appends tracks to a playlist. Spotify has no item IDs,
+// so the track IDs are returned in their place.
+func (c *Client) AddTracksToPlaylist(playlistID string, trackIDs []string) ([]string, error) {
+	for start := 0; start < len(trackIDs); start += maxTracksPerWrite {
+		end := start + maxTracksPerWrite
+		if end > len(trackIDs) {
+			end = len(trackIDs)
+		}
+
+		uris := make([]string, 0, end-start)
+		for _, id := range trackIDs[start:end] {
+			uris = append(uris, "spotify:track:"+id)
+		}
+
+		request := map[string]interface{}{"uris": uris}
+		if err := c.makeRequest("POST", fmt.Sprintf("%s/playlists/%s/tracks", baseURL, playlistID), request, nil); err != nil {
+			return trackIDs[:start], scopeHint(err)
+		}
+	}
+
+	return trackIDs, nil
+}
+
+// PlaylistURL returns a link to a Spotify playlist
+func (c *Client) PlaylistURL(playlistID string) string {
+	return fmt.Sprintf("https://open.spotify.com/playlist/%s", playlistID)
+}
+
+// scopeHint explains 401/403 errors of write calls, usually caused by a token saved without write access
+func scopeHint(err error) error {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) &&
+		(apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden) {
+		return fmt.Errorf("%w (the saved Spotify authorization may lack the playlist-modify-private scope; delete it and sign in again)", err)
+	}
+	return err
+}
+
+type spotifySearchResponse struct {
+	Tracks struct {
+		Items []spotifyTrack `json:"items"`
+	} `json:"tracks"`
+}
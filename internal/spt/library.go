@@ -0,0 +1,47 @@
+package spt
+
+import (
+	"fmt"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+)
+
+// GetUserPlaylists lists the playlists in the signed-in user's library, owned and followed,
+// without their tracks (TotalTracks holds the count)
+func (c *Client) GetUserPlaylists() ([]models.Playlist, error) {
+	if !c.config.UserAuth {
+		return nil, fmt.Errorf("listing your playlists needs your Spotify account; set spt.user_auth: true to sign in")
+	}
+
+	var playlists []models.Playlist
+	url := fmt.Sprintf("%s/me/playlists?limit=%d", baseURL, libraryPageSize)
+	for url != "" {
+		response := &spotifyPlaylistsResponse{}
+		if err := c.makeRequest("GET", url, nil, response); err != nil {
+			return nil, fmt.Errorf("listing playlists: %w", err)
+		}
+		for _, item := range response.Items {
+			playlists = append(playlists, models.Playlist{
+				ID:          item.ID,
+				Name:        item.Name,
+				Description: item.Description,
+				TotalTracks: item.Tracks.Total,
+				IsPublic:    item.Public,
+				OwnerID:     item.Owner.ID,
+			})
+		}
+		url = response.Next
+	}
+
+	return playlists, nil
+}
+
+type spotifyPlaylistsResponse struct {
+	Items []struct {
+		spotifyPlaylist
+		Tracks struct {
+			Total int `json:"total"`
+		} `json:"tracks"`
+	} `json:"items"`
+	Next string `json:"next"`
+}
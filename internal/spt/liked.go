@@ -0,0 +1,52 @@
+package spt
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+)
+
+// libraryPageSize is the largest page /me/tracks and /me/playlists return
+const libraryPageSize = 50
+
+// getLikedSongs reads the signed-in user's Liked Songs as a virtual playlist, oldest first,
+// so songs liked later are appended at the end like tracks added to a playlist
+func (c *Client) getLikedSongs() (*models.Playlist, error) {
+	if !c.config.UserAuth {
+		return nil, fmt.Errorf("Liked Songs belong to your Spotify account; set spt.user_auth: true to sign in")
+	}
+
+	var tracks []models.Track
+	url := fmt.Sprintf("%s/me/tracks?limit=%d", baseURL, libraryPageSize)
+	for url != "" {
+		response := &spotifyTracksResponse{}
+		if err := c.makeRequest("GET", url, nil, response); err != nil {
+			var apiErr *APIError
+			if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusForbidden {
+				return nil, fmt.Errorf("fetching Liked Songs: %w (the saved Spotify authorization may lack the user-library-read scope; delete it and sign in again)", err)
+			}
+			return nil, fmt.Errorf("fetching Liked Songs: %w", err)
+		}
+		for _, item := range response.Items {
+			if item.Track.ID != "" { // Skip local files or unavailable tracks
+				tracks = append(tracks, item.Track.toTrack())
+			}
+		}
+		url = response.Next
+	}
+
+	// Spotify lists the most recently liked songs first
+	for i, j := 0, len(tracks)-1; i < j; i, j = i+1, j-1 {
+		tracks[i], tracks[j] = tracks[j], tracks[i]
+	}
+
+	return &models.Playlist{
+		ID:          LikedSongsID,
+		Name:        "Liked Songs",
+		Description: "Songs liked on Spotify",
+		Tracks:      tracks,
+		TotalTracks: len(tracks),
+	}, nil
+}
@@ -0,0 +1,126 @@
+package spt
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/shortlink"
+)
+
+// idPattern matches Spotify's base62 resource IDs
+var idPattern = regexp.MustCompile(`^[0-9A-Za-z]{22}$`)
+
+// IsSpotifyID reports whether id looks like a Spotify ID (22 base62 characters)
+func IsSpotifyID(id string) bool {
+	return idPattern.MatchString(id)
+}
+
+// TrackURL links to a track on Spotify
+func TrackURL(trackID string) string {
+	return "https://open.spotify.com/track/" + trackID
+}
+
+// LikedSongsID stands for the signed-in user's Liked Songs, which are ported like a playlist
+const LikedSongsID = "liked"
+
+// IsLikedSongs reports whether a playlist ID refers to the Liked Songs collection
+func IsLikedSongs(id string) bool {
+	return id == LikedSongsID
+}
+
+// resolveShortLink follows spotify.link redirects; tests replace it
+var resolveShortLink = shortlink.Resolve
+
+// unsupportedResources describes the Spotify link types that can't be ported
+var unsupportedResources = map[string]string{
+	"show":      "a Spotify podcast show",
+	"episode":   "a Spotify podcast episode",
+	"audiobook": "a Spotify audiobook",
+	"chapter":   "a Spotify audiobook chapter",
+	"artist":    "a Spotify artist page",
+	"user":      "a Spotify user profile",
+}
+
+// PlaylistIDFromURL extracts the playlist ID from any known shape of Spotify link:
+//
+//	https://open.spotify.com/playlist/ID?si=...
+//	https://open.spotify.com/intl-it/playlist/ID/
+//	https://open.spotify.com/embed/playlist/ID
+//	https://open.spotify.com/user/NAME/playlist/ID
+//	open.spotify.com/playlist/ID (no scheme)
+//	spotify:playlist:ID and spotify:user:NAME:playlist:ID
+//	https://spotify.link/... (resolved by following the redirect)
+//	a bare 22-character playlist ID
+//
+// "liked" and https://open.spotify.com/collection/tracks select the user's Liked Songs.
+// Album and track links return "album-ID" and "track-ID".
+func PlaylistIDFromURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", fmt.Errorf("invalid SPT URL format")
+	}
+
+	if idPattern.MatchString(raw) {
+		return raw, nil
+	}
+	if strings.EqualFold(raw, LikedSongsID) {
+		return LikedSongsID, nil
+	}
+
+	var segments []string
+	if strings.HasPrefix(raw, "spotify:") {
+		segments = strings.Split(strings.TrimPrefix(raw, "spotify:"), ":")
+	} else {
+		if !strings.Contains(raw, "://") {
+			raw = "https://" + raw
+		}
+		u, err := url.Parse(raw)
+		if err != nil {
+			return "", fmt.Errorf("invalid SPT URL format: %w", err)
+		}
+
+		host := strings.ToLower(u.Hostname())
+		if shortlink.IsShortLink(host) {
+			resolved, err := resolveShortLink(u.String())
+			if err != nil {
+				return "", err
+			}
+			if !strings.Contains(resolved, "open.spotify.com") {
+				return "", fmt.Errorf("%s did not lead to open.spotify.com", u.String())
+			}
+			return PlaylistIDFromURL(resolved)
+		}
+		if host != "open.spotify.com" && host != "play.spotify.com" {
+			return "", fmt.Errorf("not a Spotify link: %s", host)
+		}
+
+		for _, segment := range strings.Split(u.Path, "/") {
+			if segment != "" {
+				segments = append(segments, segment)
+			}
+		}
+	}
+
+	for i, segment := range segments {
+		if segment == "playlist" && i+1 < len(segments) && segments[i+1] != "" {
+			return segments[i+1], nil
+		}
+		if segment == "collection" && (i+1 == len(segments) || segments[i+1] == "tracks") {
+			return LikedSongsID, nil
+		}
+		if (segment == "album" || segment == "track") && i+1 < len(segments) && idPattern.MatchString(segments[i+1]) {
+			return segment + "-" + segments[i+1], nil
+		}
+	}
+
+	// Explain links to other kinds of Spotify content instead of a generic error
+	for i, segment := range segments {
+		if description, unsupported := unsupportedResources[segment]; unsupported && i+1 < len(segments) {
+			return "", fmt.Errorf("this is a link to %s; only playlists, albums and tracks can be ported (supported: https://open.spotify.com/playlist/..., /album/..., /track/...)", description)
+		}
+	}
+
+	return "", fmt.Errorf("playlist ID not found in URL")
+}
@@ -0,0 +1,101 @@
+package spt
+
+import (
+	"strings"
+	"testing"
+)
+
+const testID = "37i9dQZF1DXcBWIGoYBM5M"
+
+func TestPlaylistIDFromURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    string
+		wantErr string
+	}{
+		{"plain link", "https://open.spotify.com/playlist/" + testID, testID, ""},
+		{"share query", "https://open.spotify.com/playlist/" + testID + "?si=abc123", testID, ""},
+		{"trailing slash", "https://open.spotify.com/playlist/" + testID + "/", testID, ""},
+		{"locale prefix", "https://open.spotify.com/intl-it/playlist/" + testID + "/", testID, ""},
+		{"locale prefix and query", "https://open.spotify.com/intl-it/playlist/" + testID + "/?si=abc", testID, ""},
+		{"embed", "https://open.spotify.com/embed/playlist/" + testID, testID, ""},
+		{"user playlist", "https://open.spotify.com/user/someone/playlist/" + testID, testID, ""},
+		{"no scheme", "open.spotify.com/playlist/" + testID, testID, ""},
+		{"surrounding spaces", "  https://open.spotify.com/playlist/" + testID + "  ", testID, ""},
+		{"uri", "spotify:playlist:" + testID, testID, ""},
+		{"user uri", "spotify:user:someone:playlist:" + testID, testID, ""},
+		{"bare id", testID, testID, ""},
+		{"liked", "liked", LikedSongsID, ""},
+		{"liked songs link", "https://open.spotify.com/collection/tracks", LikedSongsID, ""},
+		{"album", "https://open.spotify.com/album/" + testID, "album-" + testID, ""},
+		{"track", "https://open.spotify.com/intl-de/track/" + testID + "?si=x", "track-" + testID, ""},
+		{"album uri", "spotify:album:" + testID, "album-" + testID, ""},
+		{"show", "https://open.spotify.com/show/" + testID, "", "podcast show"},
+		{"episode", "https://open.spotify.com/episode/" + testID, "", "podcast episode"},
+		{"other host", "https://example.com/playlist/" + testID, "", "not a Spotify link"},
+		{"no id", "https://open.spotify.com/", "", "playlist ID not found"},
+		{"empty", "", "", "invalid SPT URL format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PlaylistIDFromURL(tt.raw)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("PlaylistIDFromURL(%q) error = %v, want one containing %q", tt.raw, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PlaylistIDFromURL(%q) error = %v", tt.raw, err)
+			}
+			if got != tt.want {
+				t.Errorf("PlaylistIDFromURL(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlaylistIDFromShortLink(t *testing.T) {
+	resolve := resolveShortLink
+	defer func() { resolveShortLink = resolve }()
+
+	tests := []struct {
+		name     string
+		resolved string
+		want     string
+		wantErr  string
+	}{
+		{"playlist", "https://open.spotify.com/playlist/" + testID + "?si=abc", testID, ""},
+		{"album", "https://open.spotify.com/album/" + testID, "album-" + testID, ""},
+		{"elsewhere", "https://example.com/", "", "did not lead to open.spotify.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requested string
+			resolveShortLink = func(link string) (string, error) {
+				requested = link
+				return tt.resolved, nil
+			}
+
+			got, err := PlaylistIDFromURL("https://spotify.link/AbCdEf")
+			if requested != "https://spotify.link/AbCdEf" {
+				t.Errorf("resolved %q, want the short link", requested)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package spt
+
+import (
+	"fmt"
+
+	"golang.org/x/oauth2"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/auth"
+	"github.com/Verryx-02/PlaylistPorter/internal/config"
+	"github.com/Verryx-02/PlaylistPorter/internal/traffic"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+const (
+	TokenCacheName     = "spotify" // Cached user token, see auth.TokenDir
+	defaultRedirectURI = "http://127.0.0.1:8080/callback"
+)
+
+// userScopes are requested when no scopes are configured
+var userScopes = []string{
+	"playlist-read-private",
+	"playlist-read-collaborative",
+	"user-library-read",
+	"playlist-modify-private", // Reverse porting creates private Spotify playlists
+}
+
+// RedirectURI returns the redirect URI of the user sign-in, which must be registered with the app
+func RedirectURI(cfg *config.SPTConfig) string {
+	if cfg.RedirectURI == "" {
+		return defaultRedirectURI
+	}
+	return cfg.RedirectURI
+}
+
+// authenticateUser signs in with the user's Spotify account using Authorization Code + PKCE,
+// reusing the cached token when possible
+func (c *Client) authenticateUser() error {
+	redirectURI := RedirectURI(c.config)
+	scopes := c.config.Scopes
+	if len(scopes) == 0 {
+		scopes = userScopes
+	}
+
+	// PKCE replaces the client secret, the client ID is sent in the request body
+	cfg := &oauth2.Config{
+		ClientID:    c.config.ClientID,
+		RedirectURL: redirectURI,
+		Scopes:      scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:   "https://accounts.spotify.com/authorize",
+			TokenURL:  "https://accounts.spotify.com/api/token",
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+	}
+
+	cached, err := auth.LoadToken(TokenCacheName)
+	if err != nil {
+		fmt.Printf("Ignoring saved Spotify authorization: %v\n", err)
+	}
+	if cached != nil && cached.RefreshToken != "" {
+		source := auth.NewSavingTokenSource(TokenCacheName, cfg.TokenSource(traffic.Context(), cached))
+		token, err := source.Token()
+		if err == nil {
+			c.token = token
+			c.httpClient = oauth2.NewClient(traffic.Context(), source)
+			ui.Println("Using saved Spotify authorization")
+			return nil
+		}
+		fmt.Printf("Saved Spotify authorization is no longer valid (%v), signing in again\n", err)
+	}
+
+	verifier := oauth2.GenerateVerifier()
+	state := auth.NewState()
+	authURL := cfg.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier))
+
+	fmt.Println("\nSpotify Authentication Required")
+	fmt.Println("=====================================")
+
+	var authCode string
+	if auth.NoBrowser() {
+		code, err := auth.PromptForCode(authURL, redirectURI, state)
+		if err != nil {
+			return err
+		}
+		authCode = code
+	} else {
+		// Spotify and SoundCloud only accept the exact registered redirect URI, so no fallback port
+		listener, _, err := auth.ListenCallback(redirectURI, "", false)
+		if err != nil {
+			return err
+		}
+		server := auth.StartCallbackServer(listener, state)
+
+		fmt.Printf("Open this URL in your browser and allow access:\n\n%s\n\n", authURL)
+		fmt.Printf("The redirect URI %s must be registered in your Spotify app settings\n", redirectURI)
+
+		code, err := server.Wait(auth.CallbackTimeout)
+		if err != nil {
+			return err
+		}
+		authCode = code
+		fmt.Println("Authorization code received!")
+	}
+
+	token, err := cfg.Exchange(traffic.Context(), authCode, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return fmt.Errorf("exchanging authorization code: %w", err)
+	}
+
+	if err := auth.SaveToken(TokenCacheName, token); err != nil {
+		fmt.Printf("Warning: could not save Spotify authorization, you'll be asked to sign in again next time: %v\n", err)
+	}
+	source := auth.NewSavingTokenSource(TokenCacheName, cfg.TokenSource(traffic.Context(), token))
+
+	c.token = token
+	c.httpClient = oauth2.NewClient(traffic.Context(), source)
+
+	ui.Println("Spotify authentication successful!")
+	return nil
+}
@@ -0,0 +1,59 @@
+package state
+
+import (
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+)
+
+// IsArchive reports whether the state accumulates a weekly playlist
+func (s *PortingState) IsArchive() bool {
+	return s.GetMode() == ModeArchive
+}
+
+// AppendToArchive adds the tracks of this week's version of the playlist that were
+// never seen before. Unlike a sync, tracks that left the playlist are kept, so the
+// state grows into a cumulative archive. Returns the new tracks.
+func (s *PortingState) AppendToArchive(currentPlaylist models.Playlist) []models.Track {
+	// A track listed twice in the same week is only archived once
+	var newTracks []models.Track
+	seen := make(map[string]bool)
+	for _, track := range s.DetectNewTracks(currentPlaylist) {
+		if !seen[track.ID] {
+			seen[track.ID] = true
+			newTracks = append(newTracks, track)
+		}
+	}
+
+	s.OriginalPlaylist.Name = currentPlaylist.Name
+	s.OriginalPlaylist.Description = currentPlaylist.Description
+	s.OriginalPlaylist.Tracks = append(s.OriginalPlaylist.Tracks, newTracks...)
+	s.OriginalPlaylist.TotalTracks = len(s.OriginalPlaylist.Tracks)
+	s.TotalTracks = len(s.OriginalPlaylist.Tracks)
+
+	if len(newTracks) > 0 {
+		s.IsComplete = false
+	}
+	s.LastSyncCheck = time.Now()
+
+	return newTracks
+}
+
+// ArchiveWeekTarget returns the split target name of the week containing t.
+// Weeks start on Monday, when Spotify refreshes Discover Weekly.
+func ArchiveWeekTarget(t time.Time) string {
+	offset := (int(t.Weekday()) + 6) % 7 // Days since Monday
+	monday := t.AddDate(0, 0, -offset)
+	return "Week of " + monday.Format("2006-01-02")
+}
+
+// ArchivedVideoIDs returns the YouTube videos already added to the archive
+func (s *PortingState) ArchivedVideoIDs() map[string]bool {
+	videoIDs := make(map[string]bool)
+	for _, result := range s.MatchResults {
+		if result.Matched && result.MatchedTrack != nil && s.UploadedTrackIDs[result.OriginalTrack.ID] {
+			videoIDs[result.MatchedTrack.ID] = true
+		}
+	}
+	return videoIDs
+}
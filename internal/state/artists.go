@@ -0,0 +1,115 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+)
+
+// ArtistHistoryFileName is the file in the states directory holding the artist history
+const ArtistHistoryFileName = "artists.json"
+
+// Artist heuristics: skip searching artists whose every track has failed to match
+const (
+	artistMinFailures = 3                   // Failed tracks needed before an artist is considered unavailable
+	artistRetryAfter  = 30 * 24 * time.Hour // Search the artist again after this long, it may have been uploaded since
+)
+
+// ArtistRecord counts match outcomes for one artist across all playlists
+type ArtistRecord struct {
+	Artist      string    `json:"artist"`
+	Matched     int       `json:"matched"`
+	Failed      int       `json:"failed"`
+	LastFailure time.Time `json:"last_failure,omitempty"`
+}
+
+// ArtistHistory remembers per-artist match outcomes across sessions and playlists
+type ArtistHistory struct {
+	Artists map[string]*ArtistRecord `json:"artists"` // Keyed by lowercase artist name
+}
+
+// artistKey normalizes an artist name for lookups
+func artistKey(artist string) string {
+	return strings.ToLower(strings.TrimSpace(artist))
+}
+
+// IsKnownUnavailable reports whether every recent search for the artist has failed
+func (h *ArtistHistory) IsKnownUnavailable(artist string) bool {
+	if models.IsVariousArtists(artist) {
+		return false // Stands for many artists, the failures say nothing about the next track
+	}
+	record, ok := h.Artists[artistKey(artist)]
+	if !ok {
+		return false
+	}
+	return record.Matched == 0 &&
+		record.Failed >= artistMinFailures &&
+		time.Since(record.LastFailure) < artistRetryAfter
+}
+
+// Record adds the outcome of a search for one of the artist's tracks
+func (h *ArtistHistory) Record(artist string, matched bool) {
+	key := artistKey(artist)
+	if key == "" || models.IsVariousArtists(artist) {
+		return
+	}
+
+	record, ok := h.Artists[key]
+	if !ok {
+		record = &ArtistRecord{Artist: artist}
+		h.Artists[key] = record
+	}
+
+	if matched {
+		record.Matched++
+	} else {
+		record.Failed++
+		record.LastFailure = time.Now()
+	}
+}
+
+// artistHistoryPath returns the path of the artist history file
+func (m *Manager) artistHistoryPath() string {
+	return filepath.Join(m.stateDir, ArtistHistoryFileName)
+}
+
+// LoadArtistHistory loads the artist history, starting empty if it doesn't exist yet
+func (m *Manager) LoadArtistHistory() (*ArtistHistory, error) {
+	history := &ArtistHistory{Artists: make(map[string]*ArtistRecord)}
+
+	data, err := os.ReadFile(m.artistHistoryPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return history, nil
+		}
+		return nil, fmt.Errorf("reading artist history: %w", err)
+	}
+
+	if err := json.Unmarshal(data, history); err != nil {
+		return nil, fmt.Errorf("parsing artist history: %w", err)
+	}
+	if history.Artists == nil {
+		history.Artists = make(map[string]*ArtistRecord)
+	}
+
+	return history, nil
+}
+
+// SaveArtistHistory writes the artist history atomically
+func (m *Manager) SaveArtistHistory(history *ArtistHistory) error {
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling artist history: %w", err)
+	}
+
+	if err := writeFileDurable(m.artistHistoryPath(), data); err != nil {
+		return fmt.Errorf("writing artist history: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	backupDirName = "backups"
+	maxBackups    = 5 // Backups kept per playlist
+)
+
+// ErrCorruptState is returned when a state file exists but cannot be parsed
+var ErrCorruptState = errors.New("corrupted state file")
+
+// ErrChecksumMismatch is returned when a state file parses but doesn't match its checksum,
+// as after damage on disk or an edit by hand; it is an ErrCorruptState
+var ErrChecksumMismatch = fmt.Errorf("%w: checksum mismatch", ErrCorruptState)
+
+// RecoveryInfo describes a state that was restored from a backup
+type RecoveryInfo struct {
+	QuarantinedFile string // Where the corrupted file was moved
+	BackupFile      string // Backup the state was restored from
+	OnlyChecksum    bool   // The file parsed and only failed its checksum, e.g. after an edit by hand
+}
+
+// backupDir returns the directory holding state backups
+func (m *Manager) backupDir() string {
+	return filepath.Join(m.stateDir, backupDirName)
+}
+
+// backupState copies the current state file into the backup directory before it is overwritten
+func (m *Manager) backupState(spotifyID string) error {
+	data, err := os.ReadFile(m.GetStateFilePath(spotifyID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil // Nothing to back up yet
+		}
+		return fmt.Errorf("reading state for backup: %w", err)
+	}
+
+	if err := os.MkdirAll(m.backupDir(), 0755); err != nil {
+		return fmt.Errorf("creating backup directory: %w", err)
+	}
+
+	filename := fmt.Sprintf("playlist_%s_state.%s.json", spotifyID, time.Now().Format("20060102_150405.000000000"))
+	if err := writeAndSync(filepath.Join(m.backupDir(), filename), data); err != nil {
+		return fmt.Errorf("writing state backup: %w", err)
+	}
+
+	// Drop the oldest backups
+	backups, err := m.listBackups(spotifyID)
+	if err != nil {
+		return err
+	}
+	if len(backups) > maxBackups {
+		for _, old := range backups[maxBackups:] {
+			os.Remove(old)
+		}
+	}
+
+	return nil
+}
+
+// listBackups returns the backup files for a playlist, newest first
+func (m *Manager) listBackups(spotifyID string) ([]string, error) {
+	entries, err := os.ReadDir(m.backupDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading backup directory: %w", err)
+	}
+
+	prefix := fmt.Sprintf("playlist_%s_state.", spotifyID)
+	var backups []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasPrefix(entry.Name(), prefix) && strings.HasSuffix(entry.Name(), ".json") {
+			backups = append(backups, filepath.Join(m.backupDir(), entry.Name()))
+		}
+	}
+
+	// Timestamps in the names sort chronologically
+	sort.Sort(sort.Reverse(sort.StringSlice(backups)))
+
+	return backups, nil
+}
+
+// recoverState quarantines a corrupted state file and restores the newest valid backup
+func (m *Manager) recoverState(spotifyID string, cause error) (*PortingState, error) {
+	statePath := m.GetStateFilePath(spotifyID)
+	quarantinePath := fmt.Sprintf("%s.corrupt-%s", statePath, time.Now().Format("20060102_150405"))
+
+	if err := os.Rename(statePath, quarantinePath); err != nil {
+		return nil, fmt.Errorf("quarantining corrupted state file: %w", err)
+	}
+
+	backups, err := m.listBackups(spotifyID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, backupPath := range backups {
+		state, err := m.readStateFile(backupPath)
+		if err != nil {
+			continue
+		}
+
+		if err := m.SaveState(state); err != nil {
+			return nil, fmt.Errorf("restoring state from backup: %w", err)
+		}
+
+		state.Recovery = &RecoveryInfo{
+			QuarantinedFile: quarantinePath,
+			BackupFile:      backupPath,
+			OnlyChecksum:    errors.Is(cause, ErrChecksumMismatch),
+		}
+		return state, nil
+	}
+
+	return nil, fmt.Errorf("%w (moved to %s) and no valid backup was found", cause, quarantinePath)
+}
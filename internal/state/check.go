@@ -0,0 +1,166 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+)
+
+// Check validates the state's invariants and returns a description of each problem found
+func (s *PortingState) Check() []string {
+	var issues []string
+
+	if s.ProcessedTracks != len(s.MatchResults) {
+		issues = append(issues, fmt.Sprintf("processed_tracks is %d but there are %d match results",
+			s.ProcessedTracks, len(s.MatchResults)))
+	}
+
+	if s.TotalTracks != len(s.OriginalPlaylist.Tracks) {
+		issues = append(issues, fmt.Sprintf("total_tracks is %d but the playlist has %d tracks",
+			s.TotalTracks, len(s.OriginalPlaylist.Tracks)))
+	}
+
+	// Duplicate results for the same track
+	resultIDs := make(map[string]bool)
+	matchedIDs := make(map[string]bool)
+	for _, result := range s.MatchResults {
+		id := result.OriginalTrack.ID
+		if resultIDs[id] {
+			issues = append(issues, fmt.Sprintf("duplicate match result for track %s", id))
+		}
+		resultIDs[id] = true
+		if result.Matched {
+			matchedIDs[id] = true
+		}
+
+		if result.Matched && result.MatchedTrack == nil {
+			issues = append(issues, fmt.Sprintf("track %s is marked matched but has no matched video", id))
+		}
+	}
+
+	// Duplicate tracks in the source playlist
+	playlistIDs := make(map[string]bool)
+	for _, track := range s.OriginalPlaylist.Tracks {
+		if playlistIDs[track.ID] {
+			issues = append(issues, fmt.Sprintf("duplicate track %s in the original playlist", track.ID))
+		}
+		playlistIDs[track.ID] = true
+	}
+
+	// Processed IDs must mirror the match results
+	if s.ProcessedTrackIDs == nil {
+		if len(s.MatchResults) > 0 {
+			issues = append(issues, "processed_track_ids is missing")
+		}
+	} else {
+		for id := range resultIDs {
+			if !s.ProcessedTrackIDs[id] {
+				issues = append(issues, fmt.Sprintf("track %s has a match result but is not in processed_track_ids", id))
+			}
+		}
+		for id := range s.ProcessedTrackIDs {
+			if !resultIDs[id] {
+				issues = append(issues, fmt.Sprintf("track %s is in processed_track_ids but has no match result", id))
+			}
+		}
+	}
+
+	// Uploaded IDs must refer to matched results
+	for id := range s.UploadedTrackIDs {
+		if !matchedIDs[id] {
+			issues = append(issues, fmt.Sprintf("track %s is marked uploaded but was never matched", id))
+		}
+	}
+
+	if wantComplete := len(s.MatchResults) >= s.TotalTracks; s.IsComplete != wantComplete {
+		issues = append(issues, fmt.Sprintf("is_complete is %t but %d of %d tracks are processed",
+			s.IsComplete, len(s.MatchResults), s.TotalTracks))
+	}
+
+	// Sessions must be well-formed
+	for i, session := range s.Sessions {
+		if session.EndTime.IsZero() {
+			issues = append(issues, fmt.Sprintf("session %d never ended", i+1))
+		} else if session.EndTime.Before(session.StartTime) {
+			issues = append(issues, fmt.Sprintf("session %d ends before it starts", i+1))
+		}
+		if session.TracksProcessed < 0 || session.TracksMatched < 0 {
+			issues = append(issues, fmt.Sprintf("session %d has negative counters", i+1))
+		}
+		if session.TracksMatched > session.TracksProcessed {
+			issues = append(issues, fmt.Sprintf("session %d matched %d tracks but processed only %d",
+				i+1, session.TracksMatched, session.TracksProcessed))
+		}
+	}
+
+	return issues
+}
+
+// Repair fixes the problems reported by Check, treating match results as the source of truth
+func (s *PortingState) Repair() {
+	// Drop duplicate and inconsistent results, keeping the first one for each track
+	seen := make(map[string]bool)
+	results := make([]models.MatchResult, 0, len(s.MatchResults))
+	for _, result := range s.MatchResults {
+		if seen[result.OriginalTrack.ID] {
+			continue
+		}
+		seen[result.OriginalTrack.ID] = true
+
+		if result.Matched && result.MatchedTrack == nil {
+			result.Matched = false
+			result.MatchScore = 0
+		}
+		results = append(results, result)
+	}
+	s.MatchResults = results
+
+	// Drop duplicate tracks from the source playlist
+	playlistIDs := make(map[string]bool)
+	tracks := make([]models.Track, 0, len(s.OriginalPlaylist.Tracks))
+	for _, track := range s.OriginalPlaylist.Tracks {
+		if playlistIDs[track.ID] {
+			continue
+		}
+		playlistIDs[track.ID] = true
+		tracks = append(tracks, track)
+	}
+	s.OriginalPlaylist.Tracks = tracks
+
+	// Rebuild counters and ID sets from the results
+	s.ProcessedTracks = len(s.MatchResults)
+	s.TotalTracks = len(s.OriginalPlaylist.Tracks)
+	s.IsComplete = s.ProcessedTracks >= s.TotalTracks
+
+	s.ProcessedTrackIDs = make(map[string]bool)
+	matched := make(map[string]bool)
+	for _, result := range s.MatchResults {
+		s.ProcessedTrackIDs[result.OriginalTrack.ID] = true
+		if result.Matched {
+			matched[result.OriginalTrack.ID] = true
+		}
+	}
+
+	for id := range s.UploadedTrackIDs {
+		if !matched[id] {
+			delete(s.UploadedTrackIDs, id)
+		}
+	}
+
+	// Close unfinished sessions and fix impossible counters
+	for i := range s.Sessions {
+		session := &s.Sessions[i]
+		if session.EndTime.IsZero() || session.EndTime.Before(session.StartTime) {
+			session.EndTime = session.StartTime
+		}
+		if session.TracksProcessed < 0 {
+			session.TracksProcessed = 0
+		}
+		if session.TracksMatched < 0 {
+			session.TracksMatched = 0
+		}
+		if session.TracksMatched > session.TracksProcessed {
+			session.TracksMatched = session.TracksProcessed
+		}
+	}
+}
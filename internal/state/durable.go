@@ -0,0 +1,90 @@
+package state
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// writeFileDurable replaces path with data so that after a crash or power loss the file holds
+// either the old or the new content: the data goes to a temporary file that is fsynced,
+// renamed over path, and the directory is fsynced so the rename itself is persisted
+func writeFileDurable(path string, data []byte) error {
+	tmpPath := path + ".tmp"
+	if err := writeAndSync(tmpPath, data); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("renaming %s: %w", filepath.Base(tmpPath), err)
+	}
+
+	return syncDir(filepath.Dir(path))
+}
+
+// writeAndSync writes data to a new file and flushes it to disk before closing
+func writeAndSync(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", filepath.Base(path), err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("writing %s: %w", filepath.Base(path), err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("syncing %s: %w", filepath.Base(path), err)
+	}
+	return f.Close()
+}
+
+// syncDir flushes a directory entry change such as a rename. Some platforms can't
+// sync directories; that only weakens the guarantee, so those errors are ignored.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return nil
+	}
+	defer d.Close()
+	d.Sync()
+	return nil
+}
+
+// checksumPlaceholder is the checksum field as marshaled while the checksum is computed
+var checksumPlaceholder = []byte(`"checksum": ""`)
+
+// marshalWithChecksum encodes a state and fills in its checksum: the SHA-256 of the
+// encoded state with an empty checksum field
+func marshalWithChecksum(state *PortingState) ([]byte, error) {
+	state.Checksum = ""
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	state.Checksum = hex.EncodeToString(sum[:])
+	return bytes.Replace(data, checksumPlaceholder, []byte(fmt.Sprintf(`"checksum": %q`, state.Checksum)), 1), nil
+}
+
+// verifyChecksum checks the raw content of a state file against its checksum field.
+// Files written before checksums were recorded have none and are accepted.
+func verifyChecksum(data []byte, checksum string) error {
+	if checksum == "" {
+		return nil
+	}
+
+	field := []byte(fmt.Sprintf(`"checksum": %q`, checksum))
+	sum := sha256.Sum256(bytes.Replace(data, field, checksumPlaceholder, 1))
+	if hex.EncodeToString(sum[:]) != checksum {
+		return ErrChecksumMismatch
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package state
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestEditedStateFile(t *testing.T) {
+	m, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.SaveState(newTestState(m, "edited")); err != nil {
+		t.Fatal(err)
+	}
+
+	// An edit by hand that keeps the file valid JSON
+	path := m.GetStateFilePath("edited")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = bytes.Replace(data, []byte(`"Playlist edited"`), []byte(`"Renamed by hand"`), 1)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	name := "playlist_edited_state.json"
+	if _, err := m.LoadStateFile(name); !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("LoadStateFile error = %v, want a checksum mismatch", err)
+	}
+	edited, err := m.LoadEditedStateFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edited.OriginalPlaylist.Name != "Renamed by hand" {
+		t.Fatalf("edited name = %q", edited.OriginalPlaylist.Name)
+	}
+
+	// Saving stamps a checksum that matches the edit
+	if err := m.SaveState(edited); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.LoadStateFile(name); err != nil {
+		t.Fatalf("edit still fails after saving: %v", err)
+	}
+}
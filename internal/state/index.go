@@ -0,0 +1,316 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/collate"
+)
+
+const indexFileName = "index.json"
+
+// indexVersion is raised when entries gain fields; older indexes are rebuilt on load
+const indexVersion = 2
+
+// Track statuses stored in the index
+const (
+	TrackStatusMatched = "matched"
+	TrackStatusFailed  = "failed"
+	TrackStatusPending = "pending"
+)
+
+// Index is a lightweight on-disk summary of all saved states
+type Index struct {
+	Version   int                   `json:"version"`
+	UpdatedAt time.Time             `json:"updated_at"`
+	Playlists map[string]IndexEntry `json:"playlists"` // Keyed by Spotify playlist ID
+}
+
+// IndexEntry summarizes a single saved state
+type IndexEntry struct {
+	SpotifyID         string         `json:"spotify_id"`
+	PlaylistName      string         `json:"playlist_name"`
+	StateFile         string         `json:"state_file"`
+	YouTubePlaylistID string         `json:"youtube_playlist_id,omitempty"`
+	ProcessedTracks   int            `json:"processed_tracks"`
+	TotalTracks       int            `json:"total_tracks"`
+	IsComplete        bool           `json:"is_complete"`
+	LastUpdatedAt     time.Time      `json:"last_updated_at"`
+	Destination       string         `json:"destination"`
+	Tags              []string       `json:"tags,omitempty"`
+	Tracks            []IndexedTrack `json:"tracks"`
+}
+
+// HasTag reports whether the indexed state carries a tag
+func (e IndexEntry) HasTag(tag string) bool {
+	tag = NormalizeTag(tag)
+	for _, t := range e.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// IndexedTrack holds the searchable fields of a track and its match
+type IndexedTrack struct {
+	SpotifyID  string  `json:"spotify_id"`
+	Title      string  `json:"title"`
+	Artist     string  `json:"artist"`
+	Album      string  `json:"album,omitempty"`
+	Status     string  `json:"status"`
+	VideoID    string  `json:"video_id,omitempty"`
+	VideoTitle string  `json:"video_title,omitempty"`
+	Score      float64 `json:"score,omitempty"`
+	Error      string  `json:"error,omitempty"`
+	Note       string  `json:"note,omitempty"`
+}
+
+// SearchHit is a track matching a search query
+type SearchHit struct {
+	Playlist IndexEntry
+	Track    IndexedTrack
+}
+
+// indexPath returns the path of the index file
+func (m *Manager) indexPath() string {
+	return filepath.Join(m.stateDir, indexFileName)
+}
+
+// newIndexEntry builds the index entry for a state
+func (m *Manager) newIndexEntry(state *PortingState) IndexEntry {
+	entry := IndexEntry{
+		SpotifyID:         state.SpotifyID,
+		PlaylistName:      state.OriginalPlaylist.Name,
+		StateFile:         filepath.Base(m.GetStateFilePath(state.SpotifyID)),
+		YouTubePlaylistID: state.YouTubePlaylistID,
+		ProcessedTracks:   state.ProcessedTracks,
+		TotalTracks:       state.TotalTracks,
+		IsComplete:        state.IsComplete,
+		LastUpdatedAt:     state.LastUpdatedAt,
+		Destination:       state.GetDestination(),
+		Tags:              state.Tags,
+	}
+
+	seen := make(map[string]bool)
+	for _, result := range state.MatchResults {
+		track := IndexedTrack{
+			SpotifyID: result.OriginalTrack.ID,
+			Title:     result.OriginalTrack.Title,
+			Artist:    result.OriginalTrack.Artist,
+			Album:     result.OriginalTrack.Album,
+			Status:    TrackStatusFailed,
+			Error:     result.Error,
+			Note:      state.GetNote(result.OriginalTrack.ID),
+		}
+		if result.Matched && result.MatchedTrack != nil {
+			track.Status = TrackStatusMatched
+			track.VideoID = result.MatchedTrack.ID
+			track.VideoTitle = result.MatchedTrack.Title
+			track.Score = result.MatchScore
+		}
+		seen[track.SpotifyID] = true
+		entry.Tracks = append(entry.Tracks, track)
+	}
+
+	// Tracks not processed yet are still searchable
+	for _, t := range state.OriginalPlaylist.Tracks {
+		if seen[t.ID] {
+			continue
+		}
+		entry.Tracks = append(entry.Tracks, IndexedTrack{
+			SpotifyID: t.ID,
+			Title:     t.Title,
+			Artist:    t.Artist,
+			Album:     t.Album,
+			Status:    TrackStatusPending,
+			Note:      state.GetNote(t.ID),
+		})
+	}
+
+	return entry
+}
+
+// LoadIndex loads the index, rebuilding it from the state files if it is missing, unreadable
+// or behind them
+func (m *Manager) LoadIndex() (*Index, error) {
+	index, err := m.readIndex()
+	if err != nil || index != nil {
+		return index, err
+	}
+	return m.RebuildIndex()
+}
+
+// RebuildIndex scans all state files and writes a fresh index
+func (m *Manager) RebuildIndex() (*Index, error) {
+	unlock, err := m.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	index, err := m.scanIndex()
+	if err != nil {
+		return nil, err
+	}
+	if err := m.saveIndex(index); err != nil {
+		return nil, err
+	}
+
+	return index, nil
+}
+
+// readIndex reads the index file. It returns nil when the index has to be rebuilt: the file is
+// missing, unreadable or from an older version, or it doesn't cover every state file.
+func (m *Manager) readIndex() (*Index, error) {
+	info, err := os.Stat(m.indexPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading index file: %w", err)
+	}
+	data, err := os.ReadFile(m.indexPath())
+	if err != nil {
+		return nil, fmt.Errorf("reading index file: %w", err)
+	}
+
+	var index Index
+	if err := json.Unmarshal(data, &index); err != nil || index.Version < indexVersion {
+		return nil, nil
+	}
+	if index.Playlists == nil {
+		index.Playlists = make(map[string]IndexEntry)
+	}
+	if !m.indexCovers(&index, info.ModTime()) {
+		return nil, nil
+	}
+
+	return &index, nil
+}
+
+// indexCovers reports whether every state file has an entry in the index and none was written
+// after it. Either can happen when a state is restored or repaired by hand, or when runs from
+// before the states directory was locked overwrote each other's index.
+func (m *Manager) indexCovers(index *Index, written time.Time) bool {
+	files, err := m.ListStates()
+	if err != nil {
+		return false
+	}
+
+	listed := make(map[string]bool, len(index.Playlists))
+	for _, entry := range index.Playlists {
+		listed[entry.StateFile] = true
+	}
+	for _, name := range files {
+		if !listed[name] {
+			return false
+		}
+		info, err := os.Stat(filepath.Join(m.stateDir, name))
+		if err != nil || info.ModTime().After(written) {
+			return false
+		}
+	}
+	return true
+}
+
+// scanIndex builds the index from the state files without writing it
+func (m *Manager) scanIndex() (*Index, error) {
+	index := &Index{
+		Playlists: make(map[string]IndexEntry),
+	}
+
+	files, err := m.ListStates()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range files {
+		state, err := m.readStateFile(filepath.Join(m.stateDir, name))
+		if err != nil {
+			continue // Skip unreadable states, they don't belong in the index
+		}
+		index.Playlists[state.SpotifyID] = m.newIndexEntry(state)
+	}
+
+	return index, nil
+}
+
+// saveIndex writes the index atomically
+func (m *Manager) saveIndex(index *Index) error {
+	data, err := marshalIndex(index)
+	if err != nil {
+		return err
+	}
+
+	if err := writeFileDurable(m.indexPath(), data); err != nil {
+		return fmt.Errorf("writing index file: %w", err)
+	}
+
+	return nil
+}
+
+// marshalIndex encodes the index, stamping its version and update time
+func marshalIndex(index *Index) ([]byte, error) {
+	index.Version = indexVersion
+	index.UpdatedAt = time.Now()
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshaling index: %w", err)
+	}
+	return data, nil
+}
+
+// Search finds tracks across all states whose title, artist, album or matched
+// video contain every word of the query (ignoring case and accents)
+func (m *Manager) Search(query string) ([]SearchHit, error) {
+	words := strings.Fields(collate.Key(query))
+	if len(words) == 0 {
+		return nil, fmt.Errorf("empty search query")
+	}
+
+	index, err := m.LoadIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	var hits []SearchHit
+	for _, entry := range index.Playlists {
+		for _, track := range entry.Tracks {
+			haystack := collate.Key(strings.Join([]string{
+				track.Title, track.Artist, track.Album, track.VideoTitle, track.Note,
+			}, " "))
+
+			matchesAll := true
+			for _, word := range words {
+				if !strings.Contains(haystack, word) {
+					matchesAll = false
+					break
+				}
+			}
+
+			if matchesAll {
+				hits = append(hits, SearchHit{Playlist: entry, Track: track})
+			}
+		}
+	}
+
+	// Stable output: by playlist name, then artist and title
+	sort.Slice(hits, func(i, j int) bool {
+		if c := collate.Compare(hits[i].Playlist.PlaylistName, hits[j].Playlist.PlaylistName); c != 0 {
+			return c < 0
+		}
+		if c := collate.Compare(hits[i].Track.Artist, hits[j].Track.Artist); c != 0 {
+			return c < 0
+		}
+		return collate.Less(hits[i].Track.Title, hits[j].Track.Title)
+	})
+
+	return hits, nil
+}
@@ -0,0 +1,84 @@
+package state
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+)
+
+func newTestState(m *Manager, id string) *PortingState {
+	return m.CreateNewState("https://open.spotify.com/playlist/"+id, id, models.Playlist{ID: id, Name: "Playlist " + id})
+}
+
+func TestSaveStateConcurrentRuns(t *testing.T) {
+	dir := t.TempDir()
+
+	const runs = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, runs)
+	for i := 0; i < runs; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Each run opens the directory on its own, like separate processes
+			m, err := NewManager(dir)
+			if err != nil {
+				errs <- err
+				return
+			}
+			errs <- m.SaveState(newTestState(m, fmt.Sprintf("list%d", i)))
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m, err := NewManager(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	index, err := m.readIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if index == nil || len(index.Playlists) != runs {
+		t.Fatalf("index lost entries of concurrent saves: %v", index)
+	}
+}
+
+func TestLoadIndexAddsMissingStates(t *testing.T) {
+	dir := t.TempDir()
+	m, err := NewManager(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"one", "two"} {
+		if err := m.SaveState(newTestState(m, id)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// An index written without "two", as an overlapping save by an older version could leave it
+	index, err := m.LoadIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	delete(index.Playlists, "two")
+	if err := m.saveIndex(index); err != nil {
+		t.Fatal(err)
+	}
+
+	index, err = m.LoadIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := index.Playlists["two"]; !ok {
+		t.Error("LoadIndex kept an index missing a state file")
+	}
+}
@@ -0,0 +1,121 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const journalFileName = "journal.json"
+
+// journal holds the new content of every file of a transaction. It is written durably before
+// any of the files, so a crash in between leaves it behind and the next start finishes the
+// transaction instead of leaving, say, a state file ahead of the index.
+//
+// The shared quota ledger of courtesy mode is not part of transactions. Other people update it
+// under its lock (or ETag) while a run is going, so replaying a copy of it from the journal
+// would undo their reservations; it may also be an HTTP URL or live outside the state
+// directory. It stays consistent on its own instead: each ledger update is one atomic
+// replacement under the lock, and a reservation a crash never released keeps counting in full,
+// so a crash can overstate the day's usage until the quota resets but never understate it.
+type journal struct {
+	CreatedAt time.Time     `json:"created_at"`
+	Files     []journalFile `json:"files"`
+}
+
+// journalFile is one file of a transaction, named relative to the state directory
+type journalFile struct {
+	Name string `json:"name"`
+	Data []byte `json:"data"`
+}
+
+// transaction collects writes of several files that must land together
+type transaction struct {
+	m     *Manager
+	files []journalFile
+}
+
+// begin starts a transaction over files in the state directory
+func (m *Manager) begin() *transaction {
+	return &transaction{m: m}
+}
+
+// write queues the new content of a file in the state directory
+func (t *transaction) write(path string, data []byte) error {
+	name, err := filepath.Rel(t.m.stateDir, path)
+	if err != nil || !filepath.IsLocal(name) {
+		return fmt.Errorf("%s is outside the state directory", path)
+	}
+	t.files = append(t.files, journalFile{Name: name, Data: data})
+	return nil
+}
+
+// commit writes the journal, then each file, then drops the journal. If writing a file fails
+// the journal stays, and the transaction is completed when the state directory is next opened.
+func (t *transaction) commit() error {
+	data, err := json.Marshal(journal{CreatedAt: time.Now(), Files: t.files})
+	if err != nil {
+		return fmt.Errorf("marshaling journal: %w", err)
+	}
+	if err := writeFileDurable(t.m.journalPath(), data); err != nil {
+		return fmt.Errorf("writing journal: %w", err)
+	}
+
+	if err := t.m.applyJournal(t.files); err != nil {
+		return err
+	}
+	return t.m.dropJournal()
+}
+
+// journalPath returns the path of the transaction journal
+func (m *Manager) journalPath() string {
+	return filepath.Join(m.stateDir, journalFileName)
+}
+
+// applyJournal writes the files of a transaction
+func (m *Manager) applyJournal(files []journalFile) error {
+	for _, file := range files {
+		if err := writeFileDurable(filepath.Join(m.stateDir, file.Name), file.Data); err != nil {
+			return fmt.Errorf("writing %s: %w", file.Name, err)
+		}
+	}
+	return nil
+}
+
+// dropJournal removes the journal of a completed transaction
+func (m *Manager) dropJournal() error {
+	if err := os.Remove(m.journalPath()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing journal: %w", err)
+	}
+	return syncDir(m.stateDir)
+}
+
+// replayJournal completes a transaction interrupted by a crash. It reports whether there was
+// one. An unreadable journal can only come from damage on disk, since it is renamed into
+// place whole; it is dropped, as the files it names were not touched yet.
+func (m *Manager) replayJournal() (bool, error) {
+	data, err := os.ReadFile(m.journalPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("reading journal: %w", err)
+	}
+
+	var pending journal
+	if err := json.Unmarshal(data, &pending); err != nil {
+		return false, m.dropJournal()
+	}
+	for _, file := range pending.Files {
+		if !filepath.IsLocal(file.Name) {
+			return false, fmt.Errorf("journal names %s outside the state directory", file.Name)
+		}
+	}
+
+	if err := m.applyJournal(pending.Files); err != nil {
+		return false, fmt.Errorf("replaying journal: %w", err)
+	}
+	return true, m.dropJournal()
+}
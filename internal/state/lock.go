@@ -0,0 +1,43 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	lockFileName = ".lock"
+	lockWait     = 30 * time.Second // How long to wait for another run saving to the same directory
+	staleLock    = time.Minute      // Locks older than this were left by a crashed run
+)
+
+// ErrStatesBusy is returned when another run kept the states directory locked
+var ErrStatesBusy = errors.New("the states directory is locked by another run")
+
+// lock takes the lock file of the states directory, so runs saving different playlists
+// don't overwrite each other's index entries or journal. The returned function releases it.
+func (m *Manager) lock() (func(), error) {
+	lockPath := filepath.Join(m.stateDir, lockFileName)
+	deadline := time.Now().Add(lockWait)
+	for {
+		lock, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			lock.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("locking states directory: %w", err)
+		}
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > staleLock {
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, ErrStatesBusy
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
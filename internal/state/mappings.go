@@ -0,0 +1,79 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const mappingsFileName = "mappings.json"
+
+// TrackMapping is an imported match of a Spotify track, used instead of searching
+type TrackMapping struct {
+	VideoID    string    `json:"youtube_video_id"`
+	Artist     string    `json:"artist,omitempty"`
+	Title      string    `json:"title,omitempty"`
+	Origin     string    `json:"origin"` // Name of the file the mapping was imported from
+	ImportedAt time.Time `json:"imported_at"`
+}
+
+// TrackMappings holds the imported matches of all playlists
+type TrackMappings struct {
+	Tracks map[string]*TrackMapping `json:"tracks"` // Keyed by Spotify track ID
+}
+
+// Lookup returns the mapping of a track, nil if there is none (or no mappings are loaded)
+func (m *TrackMappings) Lookup(trackID string) *TrackMapping {
+	if m == nil {
+		return nil
+	}
+	return m.Tracks[trackID]
+}
+
+// Set stores the mapping of a track, replacing an earlier one
+func (m *TrackMappings) Set(trackID string, mapping TrackMapping) {
+	m.Tracks[trackID] = &mapping
+}
+
+// mappingsPath returns the path of the mappings file
+func (m *Manager) mappingsPath() string {
+	return filepath.Join(m.stateDir, mappingsFileName)
+}
+
+// LoadMappings loads the imported mappings, starting empty if there are none yet
+func (m *Manager) LoadMappings() (*TrackMappings, error) {
+	mappings := &TrackMappings{Tracks: make(map[string]*TrackMapping)}
+
+	data, err := os.ReadFile(m.mappingsPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return mappings, nil
+		}
+		return nil, fmt.Errorf("reading mappings: %w", err)
+	}
+
+	if err := json.Unmarshal(data, mappings); err != nil {
+		return nil, fmt.Errorf("parsing mappings: %w", err)
+	}
+	if mappings.Tracks == nil {
+		mappings.Tracks = make(map[string]*TrackMapping)
+	}
+
+	return mappings, nil
+}
+
+// SaveMappings writes the imported mappings atomically
+func (m *Manager) SaveMappings(mappings *TrackMappings) error {
+	data, err := json.MarshalIndent(mappings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling mappings: %w", err)
+	}
+
+	if err := writeFileDurable(m.mappingsPath(), data); err != nil {
+		return fmt.Errorf("writing mappings: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package state
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"sort"
+	"strings"
+)
+
+// SourceInfo describes one Spotify playlist feeding a merged state
+type SourceInfo struct {
+	SpotifyID  string `json:"spotify_id"`
+	SpotifyURL string `json:"spotify_url"`
+	Name       string `json:"name"`
+}
+
+// MergedStateID derives a stable state ID from the set of source playlist IDs
+func MergedStateID(spotifyIDs []string) string {
+	ids := append([]string(nil), spotifyIDs...)
+	sort.Strings(ids)
+
+	sum := sha1.Sum([]byte(strings.Join(ids, ",")))
+	return "merge_" + hex.EncodeToString(sum[:])[:12]
+}
+
+// IsMerged reports whether the state combines several source playlists
+func (s *PortingState) IsMerged() bool {
+	return len(s.Sources) > 0
+}
+
+// SetSources records the source playlists and which of them contain each track
+func (s *PortingState) SetSources(sources []SourceInfo, trackSources map[string][]string) {
+	s.Sources = sources
+	s.TrackSources = trackSources
+}
+
+// GetTrackSourceNames returns the names of the source playlists containing a track
+func (s *PortingState) GetTrackSourceNames(trackID string) []string {
+	names := make(map[string]string)
+	for _, source := range s.Sources {
+		names[source.SpotifyID] = source.Name
+	}
+
+	var result []string
+	for _, id := range s.TrackSources[trackID] {
+		result = append(result, names[id])
+	}
+	return result
+}
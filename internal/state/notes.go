@@ -0,0 +1,48 @@
+package state
+
+import (
+	"strconv"
+	"strings"
+)
+
+// skipNote is the note that excludes a track from matching
+const skipNote = "skip"
+
+// SetNote annotates a track; an empty note removes the annotation
+func (s *PortingState) SetNote(trackID, note string) {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		delete(s.TrackNotes, trackID)
+		return
+	}
+
+	if s.TrackNotes == nil {
+		s.TrackNotes = make(map[string]string)
+	}
+	s.TrackNotes[trackID] = note
+}
+
+// GetNote returns the annotation of a track, if any
+func (s *PortingState) GetNote(trackID string) string {
+	return s.TrackNotes[trackID]
+}
+
+// IsSkipNote reports whether a note asks for the track to be left out
+func IsSkipNote(note string) bool {
+	return strings.EqualFold(strings.TrimSpace(note), skipNote)
+}
+
+// FindTrack looks up a track by Spotify ID or 1-based playlist position
+func (s *PortingState) FindTrack(ref string) (int, bool) {
+	for i, track := range s.OriginalPlaylist.Tracks {
+		if track.ID == ref {
+			return i, true
+		}
+	}
+
+	position, err := strconv.Atoi(ref)
+	if err != nil || position < 1 || position > len(s.OriginalPlaylist.Tracks) {
+		return 0, false
+	}
+	return position - 1, true
+}
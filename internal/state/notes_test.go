@@ -0,0 +1,33 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+)
+
+func TestFindTrack(t *testing.T) {
+	s := &PortingState{OriginalPlaylist: models.Playlist{Tracks: []models.Track{{ID: "a1"}, {ID: "b2"}, {ID: "c3"}}}}
+
+	tests := []struct {
+		ref   string
+		index int
+		found bool
+	}{
+		{"b2", 1, true},
+		{"1", 0, true},
+		{"3", 2, true},
+		{"0", 0, false},
+		{"4", 0, false},
+		{"-1", 0, false},
+		{"2x", 0, false},
+		{"", 0, false},
+		{"18446744073709551618", 0, false}, // Wraps around to 2 with unchecked arithmetic
+	}
+	for _, tt := range tests {
+		index, found := s.FindTrack(tt.ref)
+		if index != tt.index || found != tt.found {
+			t.Errorf("FindTrack(%q) = %d, %t, want %d, %t", tt.ref, index, found, tt.index, tt.found)
+		}
+	}
+}
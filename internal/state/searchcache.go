@@ -0,0 +1,107 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+)
+
+const searchCacheFileName = "search_cache.json"
+
+// CachedSearch is the outcome of a search made ahead of a session by warm-cache
+type CachedSearch struct {
+	Track             *models.Track `json:"track,omitempty"` // nil when the search found no match
+	Score             float64       `json:"score,omitempty"`
+	Strategy          string        `json:"strategy,omitempty"`
+	SearchesUsed      int           `json:"searches_used"`
+	NormalizerVersion int           `json:"normalizer_version"`
+	MatcherVersion    int           `json:"matcher_version"`
+	CachedAt          time.Time     `json:"cached_at"`
+}
+
+// SearchCache holds searches made ahead of time, keyed by search backend and Spotify track ID
+type SearchCache struct {
+	Entries map[string]*CachedSearch `json:"entries"`
+}
+
+// searchCacheKey names the cached search of a track on a backend
+func searchCacheKey(backend, trackID string) string {
+	return backend + ":" + trackID
+}
+
+// Lookup returns the cached search of a track, nil if there is none (or no cache is loaded)
+func (c *SearchCache) Lookup(backend, trackID string) *CachedSearch {
+	if c == nil {
+		return nil
+	}
+	return c.Entries[searchCacheKey(backend, trackID)]
+}
+
+// Set stores the search of a track, replacing an earlier one
+func (c *SearchCache) Set(backend, trackID string, search CachedSearch) {
+	c.Entries[searchCacheKey(backend, trackID)] = &search
+}
+
+// Remove drops the cached search of a track once it has been used
+func (c *SearchCache) Remove(backend, trackID string) {
+	if c != nil {
+		delete(c.Entries, searchCacheKey(backend, trackID))
+	}
+}
+
+// Prune drops searches cached before cutoff and returns how many were dropped
+func (c *SearchCache) Prune(cutoff time.Time) int {
+	pruned := 0
+	for key, entry := range c.Entries {
+		if entry.CachedAt.Before(cutoff) {
+			delete(c.Entries, key)
+			pruned++
+		}
+	}
+	return pruned
+}
+
+// searchCachePath returns the path of the search cache file
+func (m *Manager) searchCachePath() string {
+	return filepath.Join(m.stateDir, searchCacheFileName)
+}
+
+// LoadSearchCache loads the searches made ahead of time, starting empty if there are none
+func (m *Manager) LoadSearchCache() (*SearchCache, error) {
+	cache := &SearchCache{Entries: make(map[string]*CachedSearch)}
+
+	data, err := os.ReadFile(m.searchCachePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cache, nil
+		}
+		return nil, fmt.Errorf("reading search cache: %w", err)
+	}
+
+	if err := json.Unmarshal(data, cache); err != nil {
+		return nil, fmt.Errorf("parsing search cache: %w", err)
+	}
+	if cache.Entries == nil {
+		cache.Entries = make(map[string]*CachedSearch)
+	}
+
+	return cache, nil
+}
+
+// SaveSearchCache writes the search cache atomically
+func (m *Manager) SaveSearchCache(cache *SearchCache) error {
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling search cache: %w", err)
+	}
+
+	if err := writeFileDurable(m.searchCachePath(), data); err != nil {
+		return fmt.Errorf("writing search cache: %w", err)
+	}
+
+	return nil
+}
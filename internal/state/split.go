@@ -0,0 +1,28 @@
+package state
+
+// TargetInfo is one of the YouTube playlists of a split state
+type TargetInfo struct {
+	Name                string `json:"name"` // Split rule name
+	YouTubePlaylistID   string `json:"youtube_playlist_id,omitempty"`
+	YouTubePlaylistName string `json:"youtube_playlist_name,omitempty"`
+}
+
+// GetOrAddTarget returns the split target with the given name, adding it if needed
+func (s *PortingState) GetOrAddTarget(name string) *TargetInfo {
+	for i := range s.Targets {
+		if s.Targets[i].Name == name {
+			return &s.Targets[i]
+		}
+	}
+
+	s.Targets = append(s.Targets, TargetInfo{Name: name})
+	return &s.Targets[len(s.Targets)-1]
+}
+
+// AssignTarget records which split target a track was routed to
+func (s *PortingState) AssignTarget(trackID, target string) {
+	if s.TrackTargets == nil {
+		s.TrackTargets = make(map[string]string)
+	}
+	s.TrackTargets[trackID] = target
+}
@@ -2,12 +2,14 @@ package state
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
-	"playlistporter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
 )
 
 // PortingState represents the persistent state of a porting operation
@@ -18,21 +20,29 @@ type PortingState struct {
 	LastUpdatedAt time.Time `json:"last_updated_at"`
 	SpotifyURL    string    `json:"spotify_url"`
 	SpotifyID     string    `json:"spotify_id"`
+	Checksum      string    `json:"checksum"` // SHA-256 of the file with this field empty, see marshalWithChecksum
 
 	// Original playlist info
 	OriginalPlaylist models.Playlist `json:"original_playlist"`
 
+	// Normalization rules version of the saved normalized metadata (0 for older states)
+	NormalizerVersion int `json:"normalizer_version,omitempty"`
+
 	// Progress tracking
 	ProcessedTracks int  `json:"processed_tracks"`
 	TotalTracks     int  `json:"total_tracks"`
 	IsComplete      bool `json:"is_complete"`
 
+	// When the on_complete hooks ran (zero until the playlist was first completed)
+	CompletionHooksRunAt time.Time `json:"completion_hooks_run_at,omitempty"`
+
 	// Match results for all processed tracks
 	MatchResults []models.MatchResult `json:"match_results"`
 
 	// YouTube playlist info (if created)
 	YouTubePlaylistID   string `json:"youtube_playlist_id,omitempty"`
 	YouTubePlaylistName string `json:"youtube_playlist_name,omitempty"`
+	YouTubeAccount      string `json:"youtube_account,omitempty"` // Account owning the playlist ("" is the default account)
 
 	// Session history
 	Sessions []SessionInfo `json:"sessions"`
@@ -40,8 +50,58 @@ type PortingState struct {
 	// Sync tracking
 	LastSyncCheck     time.Time       `json:"last_sync_check,omitempty"`
 	ProcessedTrackIDs map[string]bool `json:"processed_track_ids"` // Track Spotify IDs already processed
+
+	// Upload tracking (matches can be searched and uploaded in separate phases)
+	UploadedTrackIDs map[string]bool `json:"uploaded_track_ids"` // Spotify IDs whose match was added to YouTube
+
+	// Merge tracking (several Spotify playlists ported into one YouTube playlist)
+	Sources      []SourceInfo        `json:"sources,omitempty"`
+	TrackSources map[string][]string `json:"track_sources,omitempty"` // Track ID -> source playlist IDs
+
+	// Matched videos replaced after being deleted or made private on YouTube
+	Substitutions []Substitution `json:"substitutions,omitempty"`
+
+	// Placeholder videos keeping YouTube positions aligned for failed tracks
+	PlaceholderItems map[string]string `json:"placeholder_items,omitempty"` // Track ID -> playlist item ID
+
+	// Split targets (matches routed into several YouTube playlists)
+	Targets      []TargetInfo      `json:"targets,omitempty"`
+	TrackTargets map[string]string `json:"track_targets,omitempty"` // Track ID -> target name ("" is the main playlist)
+
+	// Source availability (playlist made private or deleted on Spotify)
+	SourceStatus           string    `json:"source_status,omitempty"`
+	SourceUnavailableSince time.Time `json:"source_unavailable_since,omitempty"`
+
+	// User annotations such as "prefer live version" or "skip"
+	TrackNotes map[string]string `json:"track_notes,omitempty"` // Track ID -> note
+
+	// User tags grouping playlists, e.g. "workout"; lowercase, sorted
+	Tags []string `json:"tags,omitempty"`
+
+	// Port mode: follow keeps syncing new tracks, snapshot ports the playlist as first seen
+	Mode          string `json:"mode,omitempty"`
+	ArchiveWeekly bool   `json:"archive_weekly,omitempty"` // Archive mode: one YouTube playlist per week
+
+	// Service the playlist is ported to ("" is YouTube); the YouTube* fields hold its playlist
+	Destination string `json:"destination,omitempty"`
+
+	// Set when the state was restored from a backup while loading (not persisted)
+	Recovery *RecoveryInfo `json:"-"`
 }
 
+// Source statuses
+const (
+	SourceStatusAvailable   = ""
+	SourceStatusUnavailable = "unavailable"
+)
+
+// Port modes
+const (
+	ModeFollow   = "follow"   // Sync new tracks from Spotify on -sync runs
+	ModeSnapshot = "snapshot" // Port the playlist as it was on the first run, never sync
+	ModeArchive  = "archive"  // Accumulate every version of a weekly playlist (Discover Weekly, Release Radar)
+)
+
 // SessionInfo tracks information about each processing session
 type SessionInfo struct {
 	StartTime       time.Time `json:"start_time"`
@@ -49,11 +109,17 @@ type SessionInfo struct {
 	TracksProcessed int       `json:"tracks_processed"`
 	TracksMatched   int       `json:"tracks_matched"`
 	QuotaUsed       int       `json:"quota_used_estimate"` // Rough estimate
+	Note            string    `json:"note,omitempty"`      // Set with -session-note, e.g. "after matcher tweak"
+	RunID           string    `json:"run_id,omitempty"`    // Run the session belongs to; its tracks' traces start with it
+
+	// Milliseconds spent in each pipeline stage (fetch, normalize, search, score, insert)
+	StageMS map[string]int64 `json:"stage_ms,omitempty"`
 }
 
 // Manager handles state persistence
 type Manager struct {
 	stateDir string
+	replayed bool // An interrupted transaction was completed when the manager was created
 }
 
 // NewManager creates a new state manager
@@ -63,9 +129,30 @@ func NewManager(stateDir string) (*Manager, error) {
 		return nil, fmt.Errorf("creating state directory: %w", err)
 	}
 
-	return &Manager{
+	m := &Manager{
 		stateDir: stateDir,
-	}, nil
+	}
+
+	// Finish a multi-file save a crash interrupted before anything reads the files. The lock
+	// keeps this from taking another run's save in progress for an interrupted one.
+	unlock, err := m.lock()
+	if err != nil {
+		return nil, err
+	}
+	replayed, err := m.replayJournal()
+	unlock()
+	if err != nil {
+		return nil, err
+	}
+	m.replayed = replayed
+
+	return m, nil
+}
+
+// ReplayedJournal reports whether opening the state directory completed a save that a crash
+// had interrupted
+func (m *Manager) ReplayedJournal() bool {
+	return m.replayed
 }
 
 // GetStateFilePath returns the path to the state file for a given Spotify playlist ID
@@ -74,21 +161,61 @@ func (m *Manager) GetStateFilePath(spotifyID string) string {
 	return filepath.Join(m.stateDir, filename)
 }
 
+// IsStateFile reports whether a file name looks like a saved playlist state
+func IsStateFile(name string) bool {
+	return strings.HasPrefix(name, "playlist_") && strings.HasSuffix(name, "_state.json")
+}
+
 // LoadState loads the state for a given Spotify playlist ID
 func (m *Manager) LoadState(spotifyID string) (*PortingState, error) {
-	statePath := m.GetStateFilePath(spotifyID)
+	state, err := m.readStateFile(m.GetStateFilePath(spotifyID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil // No state exists yet
+		}
+		if errors.Is(err, ErrCorruptState) {
+			return m.recoverState(spotifyID, err)
+		}
+		return nil, err
+	}
+
+	return state, nil
+}
 
+// LoadStateFile loads a state by its file name inside the state directory
+func (m *Manager) LoadStateFile(name string) (*PortingState, error) {
+	return m.readStateFile(filepath.Join(m.stateDir, name))
+}
+
+// LoadEditedStateFile loads a state by its file name like LoadStateFile, but accepts a checksum
+// mismatch, as left by an edit by hand; the next save stamps a fresh checksum
+func (m *Manager) LoadEditedStateFile(name string) (*PortingState, error) {
+	return m.parseStateFile(filepath.Join(m.stateDir, name), true)
+}
+
+// readStateFile reads and validates a state file
+func (m *Manager) readStateFile(statePath string) (*PortingState, error) {
+	return m.parseStateFile(statePath, false)
+}
+
+// parseStateFile reads and validates a state file; acceptEdits skips the checksum check
+func (m *Manager) parseStateFile(statePath string, acceptEdits bool) (*PortingState, error) {
 	data, err := os.ReadFile(statePath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, nil // No state exists yet
+			return nil, err
 		}
 		return nil, fmt.Errorf("reading state file: %w", err)
 	}
 
 	var state PortingState
 	if err := json.Unmarshal(data, &state); err != nil {
-		return nil, fmt.Errorf("parsing state file: %w", err)
+		return nil, fmt.Errorf("%w: %v", ErrCorruptState, err)
+	}
+
+	// Catches files cut short or altered on disk that still happen to parse
+	if err := verifyChecksum(data, state.Checksum); err != nil && !acceptEdits {
+		return nil, err
 	}
 
 	// Validate state version
@@ -103,22 +230,49 @@ func (m *Manager) LoadState(spotifyID string) (*PortingState, error) {
 func (m *Manager) SaveState(state *PortingState) error {
 	state.LastUpdatedAt = time.Now()
 
-	data, err := json.MarshalIndent(state, "", "  ")
+	data, err := marshalWithChecksum(state)
 	if err != nil {
 		return fmt.Errorf("marshaling state: %w", err)
 	}
 
 	statePath := m.GetStateFilePath(state.SpotifyID)
 
-	// Write to temporary file first, then rename (atomic operation)
-	tmpPath := statePath + ".tmp"
-	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
-		return fmt.Errorf("writing state file: %w", err)
+	// Keep a copy of the previous version in case this one gets corrupted
+	if err := m.backupState(state.SpotifyID); err != nil {
+		return err
 	}
 
-	if err := os.Rename(tmpPath, statePath); err != nil {
-		os.Remove(tmpPath) // Clean up temp file
-		return fmt.Errorf("renaming state file: %w", err)
+	// Other runs may be saving other playlists: the index and the journal are shared
+	unlock, err := m.lock()
+	if err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+	defer unlock()
+
+	// Keep the search index in sync with the saved state
+	index, err := m.readIndex()
+	if err == nil && index == nil {
+		index, err = m.scanIndex()
+	}
+	if err != nil {
+		return fmt.Errorf("updating state index: %w", err)
+	}
+	index.Playlists[state.SpotifyID] = m.newIndexEntry(state)
+	indexData, err := marshalIndex(index)
+	if err != nil {
+		return fmt.Errorf("updating state index: %w", err)
+	}
+
+	// The state and the index are written together, so a crash can't leave one behind
+	tx := m.begin()
+	if err := tx.write(statePath, data); err != nil {
+		return err
+	}
+	if err := tx.write(m.indexPath(), indexData); err != nil {
+		return err
+	}
+	if err := tx.commit(); err != nil {
+		return fmt.Errorf("writing state file: %w", err)
 	}
 
 	return nil
@@ -142,6 +296,7 @@ func (m *Manager) CreateNewState(spotifyURL, spotifyID string, playlist models.P
 		MatchResults:      make([]models.MatchResult, 0, len(playlist.Tracks)),
 		Sessions:          []SessionInfo{},
 		ProcessedTrackIDs: processedIDs,
+		UploadedTrackIDs:  make(map[string]bool),
 	}
 }
 
@@ -239,6 +394,63 @@ func (s *PortingState) UpdateForSync(currentPlaylist models.Playlist) {
 	s.LastSyncCheck = time.Now()
 }
 
+// KeepNormalized copies saved normalized metadata into freshly fetched tracks
+// whose title and artist haven't changed, so they aren't normalized again
+func (s *PortingState) KeepNormalized(tracks []models.Track) {
+	saved := make(map[string]models.Track, len(s.OriginalPlaylist.Tracks))
+	for _, track := range s.OriginalPlaylist.Tracks {
+		saved[track.ID] = track
+	}
+	for i := range tracks {
+		old, ok := saved[tracks[i].ID]
+		if ok && old.Title == tracks[i].Title && old.Artist == tracks[i].Artist {
+			tracks[i].NormalizedTitle = old.NormalizedTitle
+			tracks[i].NormalizedArtist = old.NormalizedArtist
+		}
+	}
+}
+
+// MarkSourceUnavailable records that the Spotify playlist can no longer be fetched
+func (s *PortingState) MarkSourceUnavailable() {
+	if s.SourceStatus != SourceStatusUnavailable {
+		s.SourceStatus = SourceStatusUnavailable
+		s.SourceUnavailableSince = time.Now()
+	}
+	s.LastSyncCheck = time.Now()
+}
+
+// MarkSourceAvailable clears a previous unavailable status
+func (s *PortingState) MarkSourceAvailable() {
+	s.SourceStatus = SourceStatusAvailable
+	s.SourceUnavailableSince = time.Time{}
+}
+
+// IsSourceUnavailable reports whether the Spotify playlist was last seen as unavailable
+func (s *PortingState) IsSourceUnavailable() bool {
+	return s.SourceStatus == SourceStatusUnavailable
+}
+
+// GetDestination returns the destination service (states from before destinations existed use YouTube)
+func (s *PortingState) GetDestination() string {
+	if s.Destination == "" {
+		return "youtube"
+	}
+	return s.Destination
+}
+
+// GetMode returns the port mode (states created before modes existed follow their source)
+func (s *PortingState) GetMode() string {
+	if s.Mode == "" {
+		return ModeFollow
+	}
+	return s.Mode
+}
+
+// IsSnapshot reports whether the state is a one-off snapshot that sync must leave alone
+func (s *PortingState) IsSnapshot() bool {
+	return s.GetMode() == ModeSnapshot
+}
+
 // GetProcessedTrackCount returns the actual number of unique tracks processed
 func (s *PortingState) GetProcessedTrackCount() int {
 	if s.ProcessedTrackIDs == nil {
@@ -249,7 +461,7 @@ func (s *PortingState) GetProcessedTrackCount() int {
 
 // NeedsMigration checks if the state needs migration to support new features
 func (s *PortingState) NeedsMigration() bool {
-	return s.ProcessedTrackIDs == nil && len(s.MatchResults) > 0
+	return (s.ProcessedTrackIDs == nil || s.UploadedTrackIDs == nil) && len(s.MatchResults) > 0
 }
 
 // Migrate updates old state files to support new features
@@ -260,18 +472,146 @@ func (s *PortingState) Migrate() {
 			s.ProcessedTrackIDs[result.OriginalTrack.ID] = true
 		}
 	}
+
+	// Older states always uploaded matches right away
+	if s.UploadedTrackIDs == nil {
+		s.UploadedTrackIDs = make(map[string]bool)
+		for _, result := range s.MatchResults {
+			if result.Matched {
+				s.UploadedTrackIDs[result.OriginalTrack.ID] = true
+			}
+		}
+	}
+}
+
+// GetPendingUploads returns matched results that haven't been added to YouTube yet
+func (s *PortingState) GetPendingUploads() []models.MatchResult {
+	var pending []models.MatchResult
+	for _, result := range s.MatchResults {
+		if result.Matched && result.MatchedTrack != nil && !s.UploadedTrackIDs[result.OriginalTrack.ID] {
+			pending = append(pending, result)
+		}
+	}
+	return pending
+}
+
+// GetPendingUploadsWithPlaceholders returns, in playlist order, matches not uploaded yet
+// and failed results that don't have a placeholder entry yet
+func (s *PortingState) GetPendingUploadsWithPlaceholders() []models.MatchResult {
+	var pending []models.MatchResult
+	for _, result := range s.MatchResults {
+		id := result.OriginalTrack.ID
+		if result.Matched && result.MatchedTrack != nil {
+			if !s.UploadedTrackIDs[id] {
+				pending = append(pending, result)
+			}
+		} else if s.PlaceholderItems[id] == "" {
+			pending = append(pending, result)
+		}
+	}
+	return pending
+}
+
+// GetFailedResults returns the results of tracks that could not be matched
+func (s *PortingState) GetFailedResults() []models.MatchResult {
+	var failed []models.MatchResult
+	for _, result := range s.MatchResults {
+		if !result.Matched {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// GetOutdatedResults returns the results produced by older normalization or matching rules
+func (s *PortingState) GetOutdatedResults(normalizerVersion, matcherVersion int) []models.MatchResult {
+	var outdated []models.MatchResult
+	for _, result := range s.MatchResults {
+		if result.NormalizerVersion < normalizerVersion || result.MatcherVersion < matcherVersion {
+			outdated = append(outdated, result)
+		}
+	}
+	return outdated
+}
+
+// ReplaceMatchResult replaces the stored result for the same track
+func (s *PortingState) ReplaceMatchResult(result models.MatchResult) {
+	for i := range s.MatchResults {
+		if s.MatchResults[i].OriginalTrack.ID == result.OriginalTrack.ID {
+			s.MatchResults[i] = result
+			return
+		}
+	}
+}
+
+// SetPlaceholder records the playlist item holding the place of a failed track
+func (s *PortingState) SetPlaceholder(trackID, itemID string) {
+	if s.PlaceholderItems == nil {
+		s.PlaceholderItems = make(map[string]string)
+	}
+	s.PlaceholderItems[trackID] = itemID
+}
+
+// ClearPlaceholder forgets the placeholder of a track once it has been replaced
+func (s *PortingState) ClearPlaceholder(trackID string) {
+	delete(s.PlaceholderItems, trackID)
+}
+
+// GetMatchedResults returns every matched result in playlist processing order
+func (s *PortingState) GetMatchedResults() []models.MatchResult {
+	var matched []models.MatchResult
+	for _, result := range s.MatchResults {
+		if result.Matched && result.MatchedTrack != nil {
+			matched = append(matched, result)
+		}
+	}
+	return matched
+}
+
+// ResetTargetPlaylist forgets the YouTube playlists so all matches are uploaded again
+func (s *PortingState) ResetTargetPlaylist() {
+	s.YouTubePlaylistID = ""
+	s.YouTubePlaylistName = ""
+	for i := range s.Targets {
+		s.Targets[i].YouTubePlaylistID = ""
+		s.Targets[i].YouTubePlaylistName = ""
+	}
+	s.UploadedTrackIDs = make(map[string]bool)
+	s.PlaceholderItems = nil
+}
+
+// MarkUploaded records that the given results were added to the YouTube playlist
+func (s *PortingState) MarkUploaded(results []models.MatchResult) {
+	if s.UploadedTrackIDs == nil {
+		s.UploadedTrackIDs = make(map[string]bool)
+	}
+	for _, result := range results {
+		if result.Matched {
+			s.UploadedTrackIDs[result.OriginalTrack.ID] = true
+		}
+	}
 }
 
 // StartNewSession starts tracking a new processing session
-func (s *PortingState) StartNewSession() {
+func (s *PortingState) StartNewSession(note, runID string) {
 	session := SessionInfo{
 		StartTime: time.Now(),
+		Note:      note,
+		RunID:     runID,
 	}
 	s.Sessions = append(s.Sessions, session)
 }
 
-// EndCurrentSession ends the current session with statistics
-func (s *PortingState) EndCurrentSession(tracksProcessed, tracksMatched int) {
+// DropCurrentSession forgets the session just started, when it ended without processing a track
+func (s *PortingState) DropCurrentSession() {
+	if len(s.Sessions) > 0 {
+		s.Sessions = s.Sessions[:len(s.Sessions)-1]
+	}
+}
+
+// EndCurrentSession ends the current session with statistics; unitsPerTrack is the quota
+// estimate of a processed track
+func (s *PortingState) EndCurrentSession(tracksProcessed, tracksMatched, unitsPerTrack int) {
 	if len(s.Sessions) == 0 {
 		return
 	}
@@ -281,8 +621,19 @@ func (s *PortingState) EndCurrentSession(tracksProcessed, tracksMatched int) {
 	s.Sessions[lastIdx].EndTime = time.Now()
 	s.Sessions[lastIdx].TracksProcessed = tracksProcessed
 	s.Sessions[lastIdx].TracksMatched = tracksMatched
-	// Rough estimate: 100 quota units per search, assume 2 searches per track average
-	s.Sessions[lastIdx].QuotaUsed = tracksProcessed * 200
+	s.Sessions[lastIdx].QuotaUsed = tracksProcessed * unitsPerTrack
+}
+
+// SetSessionStageTimes records the time spent in each pipeline stage on the current session
+func (s *PortingState) SetSessionStageTimes(times map[string]time.Duration) {
+	if len(s.Sessions) == 0 || len(times) == 0 {
+		return
+	}
+	stageMS := make(map[string]int64, len(times))
+	for stage, d := range times {
+		stageMS[stage] = d.Milliseconds()
+	}
+	s.Sessions[len(s.Sessions)-1].StageMS = stageMS
 }
 
 // GetProgress returns a human-readable progress string
@@ -291,6 +642,21 @@ func (s *PortingState) GetProgress() string {
 	return fmt.Sprintf("%d/%d tracks (%.1f%%)", s.ProcessedTracks, s.TotalTracks, percentage)
 }
 
+// GetMatchRate returns the fraction of processed tracks that were matched and how many were processed
+func (s *PortingState) GetMatchRate() (float64, int) {
+	if len(s.MatchResults) == 0 {
+		return 0, 0
+	}
+
+	matched := 0
+	for _, result := range s.MatchResults {
+		if result.Matched {
+			matched++
+		}
+	}
+	return float64(matched) / float64(len(s.MatchResults)), len(s.MatchResults)
+}
+
 // GetTotalQuotaUsed estimates total quota used across all sessions
 func (s *PortingState) GetTotalQuotaUsed() int {
 	total := 0
@@ -300,6 +666,26 @@ func (s *PortingState) GetTotalQuotaUsed() int {
 	return total
 }
 
+// GetTotalDuration sums the length of the playlist's tracks
+func (s *PortingState) GetTotalDuration() time.Duration {
+	var total time.Duration
+	for _, track := range s.OriginalPlaylist.Tracks {
+		total += track.Duration
+	}
+	return total
+}
+
+// GetTotalSessionTime sums the wall-clock time of all sessions
+func (s *PortingState) GetTotalSessionTime() time.Duration {
+	var total time.Duration
+	for _, session := range s.Sessions {
+		if session.EndTime.After(session.StartTime) {
+			total += session.EndTime.Sub(session.StartTime)
+		}
+	}
+	return total
+}
+
 // ListStates lists all saved states in the state directory
 func (m *Manager) ListStates() ([]string, error) {
 	entries, err := os.ReadDir(m.stateDir)
@@ -309,7 +695,7 @@ func (m *Manager) ListStates() ([]string, error) {
 
 	var states []string
 	for _, entry := range entries {
-		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".json" {
+		if !entry.IsDir() && IsStateFile(entry.Name()) {
 			states = append(states, entry.Name())
 		}
 	}
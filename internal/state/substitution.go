@@ -0,0 +1,34 @@
+package state
+
+import (
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+)
+
+// Substitution records a matched video that was replaced after it became unavailable
+type Substitution struct {
+	TrackID    string    `json:"track_id"`
+	OldVideoID string    `json:"old_video_id"`
+	NewVideoID string    `json:"new_video_id,omitempty"` // Empty when no replacement was found
+	Reason     string    `json:"reason"`
+	At         time.Time `json:"at"`
+}
+
+// RecordSubstitution stores the new result for a track whose matched video became unavailable
+func (s *PortingState) RecordSubstitution(oldVideoID string, replacement models.MatchResult, reason string) {
+	sub := Substitution{
+		TrackID:    replacement.OriginalTrack.ID,
+		OldVideoID: oldVideoID,
+		Reason:     reason,
+		At:         time.Now(),
+	}
+	if replacement.Matched && replacement.MatchedTrack != nil {
+		sub.NewVideoID = replacement.MatchedTrack.ID
+	} else {
+		delete(s.UploadedTrackIDs, replacement.OriginalTrack.ID)
+	}
+
+	s.ReplaceMatchResult(replacement)
+	s.Substitutions = append(s.Substitutions, sub)
+}
@@ -0,0 +1,101 @@
+package state
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/collate"
+)
+
+// NormalizeTag trims and lowercases a tag so "Workout " and "workout" are the same
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// ParseTags splits a comma-separated list of tags, dropping empty ones
+func ParseTags(list string) []string {
+	var tags []string
+	for _, tag := range strings.Split(list, ",") {
+		if tag = NormalizeTag(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// HasTag reports whether the state carries a tag
+func (s *PortingState) HasTag(tag string) bool {
+	tag = NormalizeTag(tag)
+	for _, t := range s.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// AddTags adds tags the state doesn't carry yet
+func (s *PortingState) AddTags(tags ...string) {
+	for _, tag := range tags {
+		if tag = NormalizeTag(tag); tag != "" && !s.HasTag(tag) {
+			s.Tags = append(s.Tags, tag)
+		}
+	}
+	sort.Strings(s.Tags)
+}
+
+// RemoveTags removes tags from the state; unknown tags are ignored
+func (s *PortingState) RemoveTags(tags ...string) {
+	kept := s.Tags[:0]
+	for _, t := range s.Tags {
+		remove := false
+		for _, tag := range tags {
+			if t == NormalizeTag(tag) {
+				remove = true
+				break
+			}
+		}
+		if !remove {
+			kept = append(kept, t)
+		}
+	}
+	s.Tags = kept
+	if len(s.Tags) == 0 {
+		s.Tags = nil
+	}
+}
+
+// StatesWithTag loads every saved state carrying the tag (every state when tag is empty),
+// sorted by playlist name. States that can't be read are skipped; fsck reports them.
+func (m *Manager) StatesWithTag(tag string) ([]*PortingState, error) {
+	files, err := m.ListStates()
+	if err != nil {
+		return nil, err
+	}
+
+	var states []*PortingState
+	for _, name := range files {
+		portingState, err := m.LoadStateFile(name)
+		if err != nil {
+			continue
+		}
+		if tag == "" || portingState.HasTag(tag) {
+			states = append(states, portingState)
+		}
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		return collate.Less(states[i].OriginalPlaylist.Name, states[j].OriginalPlaylist.Name)
+	})
+	return states, nil
+}
+
+// FormatTags renders tags for display, e.g. "#workout #running"
+func FormatTags(tags []string) string {
+	parts := make([]string, len(tags))
+	for i, tag := range tags {
+		parts[i] = fmt.Sprintf("#%s", tag)
+	}
+	return strings.Join(parts, " ")
+}
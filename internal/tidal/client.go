@@ -0,0 +1,301 @@
+// Package tidal reads playlists from the TIDAL API so they can be ported like Spotify ones
+package tidal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2/clientcredentials"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/config"
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/traffic"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+const (
+	baseURL  = "https://openapi.tidal.com/v2"
+	tokenURL = "https://auth.tidal.com/v1/oauth2/token"
+
+	// IDPrefix marks TIDAL playlist IDs in states, keeping them apart from Spotify IDs
+	IDPrefix = "tidal-"
+
+	defaultCountryCode = "US"
+)
+
+// ErrPlaylistUnavailable is returned when a playlist is private, deleted or not available in the country
+var ErrPlaylistUnavailable = errors.New("TIDAL playlist is private, deleted or not available")
+
+// playlistURLPattern matches tidal.com/playlist/UUID, tidal.com/browse/playlist/UUID and listen.tidal.com/playlist/UUID
+var playlistURLPattern = regexp.MustCompile(`(?i)^(?:https?://)?(?:www\.|listen\.)?tidal\.com/(?:browse/)?playlist/([0-9a-f-]{36})`)
+
+// IsTidalURL reports whether a link points to TIDAL
+func IsTidalURL(link string) bool {
+	return strings.Contains(strings.ToLower(link), "tidal.com/")
+}
+
+// PlaylistIDFromURL extracts the prefixed playlist ID from a TIDAL playlist link
+func PlaylistIDFromURL(link string) (string, error) {
+	parts := playlistURLPattern.FindStringSubmatch(strings.TrimSpace(link))
+	if parts == nil {
+		return "", fmt.Errorf("not a TIDAL playlist link (expected https://tidal.com/browse/playlist/...)")
+	}
+	return IDPrefix + strings.ToLower(parts[1]), nil
+}
+
+// TrackURL links to a track on TIDAL; trackID is without the prefix
+func TrackURL(trackID string) string {
+	return "https://tidal.com/browse/track/" + trackID
+}
+
+// IsTidalID reports whether a state ID belongs to a TIDAL playlist
+func IsTidalID(id string) bool {
+	return strings.HasPrefix(id, IDPrefix)
+}
+
+// Client represents a TIDAL API client
+type Client struct {
+	config     *config.TidalConfig
+	httpClient *http.Client
+}
+
+// NewClient creates a new TIDAL client using the client credentials flow
+func NewClient(cfg *config.TidalConfig) (*Client, error) {
+	if cfg.ClientID == "" || cfg.ClientSecret == "" {
+		return nil, fmt.Errorf("tidal.client_id and tidal.client_secret are required to read TIDAL playlists")
+	}
+
+	creds := &clientcredentials.Config{
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		TokenURL:     tokenURL,
+	}
+
+	ui.Println("Authenticating with TIDAL...")
+	if _, err := creds.Token(traffic.Context()); err != nil {
+		return nil, fmt.Errorf("authentication failed: %w", err)
+	}
+	ui.Println("TIDAL authentication successful!")
+
+	return &Client{
+		config:     cfg,
+		httpClient: creds.Client(traffic.Context()),
+	}, nil
+}
+
+// GetPlaylist fetches a playlist and all its tracks; id may carry IDPrefix
+func (c *Client) GetPlaylist(id string) (*models.Playlist, error) {
+	playlistID := strings.TrimPrefix(id, IDPrefix)
+
+	playlist := &tidalPlaylistResponse{}
+	if err := c.makeRequest(fmt.Sprintf("%s/playlists/%s?countryCode=%s", baseURL, playlistID, c.countryCode()), playlist); err != nil {
+		return nil, err
+	}
+
+	tracks, err := c.getAllPlaylistTracks(playlistID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching playlist tracks: %w", err)
+	}
+
+	return &models.Playlist{
+		ID:          IDPrefix + playlistID,
+		Name:        playlist.Data.Attributes.Name,
+		Description: playlist.Data.Attributes.Description,
+		Tracks:      tracks,
+		TotalTracks: len(tracks),
+		IsPublic:    playlist.Data.Attributes.AccessType == "PUBLIC",
+	}, nil
+}
+
+// getAllPlaylistTracks follows the cursor pagination of the playlist items,
+// resolving track and artist details from the included resources
+func (c *Client) getAllPlaylistTracks(playlistID string) ([]models.Track, error) {
+	var tracks []models.Track
+	next := fmt.Sprintf("%s/playlists/%s/relationships/items?countryCode=%s&include=items,items.artists,items.albums",
+		baseURL, playlistID, c.countryCode())
+
+	for next != "" {
+		response := &tidalItemsResponse{}
+		if err := c.makeRequest(next, response); err != nil {
+			return nil, err
+		}
+
+		included := make(map[string]tidalResource)
+		for _, resource := range response.Included {
+			included[resource.Type+"/"+resource.ID] = resource
+		}
+
+		for _, item := range response.Data {
+			if item.Type != "tracks" {
+				continue // Videos can be added to TIDAL playlists too
+			}
+			resource, ok := included["tracks/"+item.ID]
+			if !ok {
+				continue // Unavailable in the country
+			}
+
+			track := models.Track{
+				ID:        IDPrefix + item.ID,
+				Title:     resource.Attributes.Title,
+				ISRC:      resource.Attributes.ISRC,
+				Duration:  parseISODuration(resource.Attributes.Duration),
+				Explicit:  resource.Attributes.Explicit,
+				SourceURL: TrackURL(item.ID),
+			}
+			if version := resource.Attributes.Version; version != "" {
+				track.Title = fmt.Sprintf("%s (%s)", track.Title, version)
+			}
+			var artists []string
+			for _, artist := range resource.Relationships.Artists.Data {
+				artists = append(artists, included["artists/"+artist.ID].Attributes.Name)
+			}
+			track.SetArtists(artists, "")
+			if albums := resource.Relationships.Albums.Data; len(albums) > 0 {
+				album := included["albums/"+albums[0].ID].Attributes
+				track.Album = album.Title
+				track.ReleaseYear = parseReleaseYear(album.ReleaseDate)
+			}
+			tracks = append(tracks, track)
+		}
+
+		next = ""
+		if response.Links.Next != "" {
+			next = resolveLink(response.Links.Next)
+		}
+	}
+
+	return tracks, nil
+}
+
+// countryCode returns the configured catalog country
+func (c *Client) countryCode() string {
+	if c.config.CountryCode != "" {
+		return c.config.CountryCode
+	}
+	return defaultCountryCode
+}
+
+// makeRequest performs a GET request against the TIDAL API
+func (c *Client) makeRequest(requestURL string, result interface{}) error {
+	req, err := http.NewRequest("GET", requestURL, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.api+json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("%w (status %d)", ErrPlaylistUnavailable, resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("API request failed with status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+// resolveLink turns a pagination link, which may be relative to the API root, into a full URL
+func resolveLink(link string) string {
+	base, _ := url.Parse(baseURL + "/")
+	ref, err := url.Parse(strings.TrimPrefix(link, "/"))
+	if err != nil {
+		return ""
+	}
+	return base.ResolveReference(ref).String()
+}
+
+// isoDurationPattern matches ISO 8601 durations such as PT3M45S
+var isoDurationPattern = regexp.MustCompile(`^PT(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?$`)
+
+// parseISODuration converts an ISO 8601 duration, returning 0 if it can't be parsed
+func parseISODuration(value string) time.Duration {
+	parts := isoDurationPattern.FindStringSubmatch(value)
+	if parts == nil {
+		return 0
+	}
+
+	units := []time.Duration{time.Hour, time.Minute, time.Second}
+	var total time.Duration
+	for i, unit := range units {
+		if parts[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(parts[i+1])
+		if err != nil {
+			return 0
+		}
+		total += time.Duration(n) * unit
+	}
+	return total
+}
+
+func parseReleaseYear(releaseDate string) int {
+	if len(releaseDate) >= 4 {
+		year, _ := strconv.Atoi(releaseDate[:4])
+		return year
+	}
+	return 0
+}
+
+// TIDAL API (JSON:API) response structures
+
+type tidalPlaylistResponse struct {
+	Data struct {
+		ID         string `json:"id"`
+		Attributes struct {
+			Name        string `json:"name"`
+			Description string `json:"description"`
+			AccessType  string `json:"accessType"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+type tidalItemsResponse struct {
+	Data     []tidalIdentifier `json:"data"`
+	Included []tidalResource   `json:"included"`
+	Links    struct {
+		Next string `json:"next"`
+	} `json:"links"`
+}
+
+type tidalIdentifier struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
+
+type tidalResource struct {
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	Attributes struct {
+		Title       string `json:"title"`
+		Name        string `json:"name"` // Artists
+		Version     string `json:"version"`
+		ISRC        string `json:"isrc"`
+		Duration    string `json:"duration"`
+		Explicit    bool   `json:"explicit"`
+		ReleaseDate string `json:"releaseDate"` // Albums
+	} `json:"attributes"`
+	Relationships struct {
+		Artists struct {
+			Data []tidalIdentifier `json:"data"`
+		} `json:"artists"`
+		Albums struct {
+			Data []tidalIdentifier `json:"data"`
+		} `json:"albums"`
+	} `json:"relationships"`
+}
@@ -0,0 +1,35 @@
+package traffic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// ErrAborted is returned for requests cut off by Abort
+var ErrAborted = errors.New("request aborted, the run timed out")
+
+// aborted is cancelled by Abort; every request in flight is tied to it
+var aborted, abort = context.WithCancel(context.Background())
+
+// Abort cancels the requests in flight and fails all further ones, so a run stuck on a
+// hanging API call can wind down and save its state
+func Abort() {
+	abort()
+}
+
+// Aborted reports whether Abort was called
+func Aborted() bool {
+	return aborted.Err() != nil
+}
+
+// abortable ties a request to the abort signal; release must be called once the response
+// body is closed or the request failed
+func abortable(req *http.Request) (*http.Request, func()) {
+	ctx, cancel := context.WithCancel(req.Context())
+	stop := context.AfterFunc(aborted, cancel)
+	return req.WithContext(ctx), func() {
+		stop()
+		cancel()
+	}
+}
@@ -0,0 +1,4 @@
+package traffic
+
+// SharedTransport lets the external tests reach the transport shared by the API clients
+var SharedTransport = transport
@@ -0,0 +1,129 @@
+// Package traffic provides the HTTP transport shared by all API clients. It counts the bytes
+// exchanged with the music APIs and implements the bandwidth-light mode for metered connections
+package traffic
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"golang.org/x/oauth2"
+)
+
+var (
+	light    atomic.Bool
+	sent     atomic.Int64
+	received atomic.Int64 // Bytes as transferred, i.e. before decompression
+)
+
+// SetLight enables the bandwidth-light mode: compressed responses and trimmed fields
+func SetLight(enabled bool) {
+	light.Store(enabled)
+}
+
+// IsLight reports whether the bandwidth-light mode is on
+func IsLight() bool {
+	return light.Load()
+}
+
+// Sent returns the bytes of request bodies sent so far
+func Sent() int64 {
+	return sent.Load()
+}
+
+// Received returns the bytes of response bodies received so far
+func Received() int64 {
+	return received.Load()
+}
+
+// client is the base HTTP client of all API clients
+var client = &http.Client{Transport: &countingTransport{base: transport}}
+
+// Context returns a context that makes oauth2 build its clients on the counting transport
+func Context() context.Context {
+	return context.WithValue(context.Background(), oauth2.HTTPClient, client)
+}
+
+// countingTransport measures request and response bodies. It asks for gzip itself so
+// the counted size is what actually went over the wire.
+type countingTransport struct {
+	base http.RoundTripper
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if Aborted() {
+		return nil, ErrAborted
+	}
+	req, release := abortable(req)
+
+	if req.ContentLength > 0 {
+		sent.Add(req.ContentLength)
+	}
+
+	askedGzip := false
+	if req.Header.Get("Accept-Encoding") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("Accept-Encoding", "gzip")
+		if IsLight() {
+			// Google APIs only compress for user agents that mention gzip
+			req.Header.Set("User-Agent", "PlaylistPorter (gzip)")
+		}
+		askedGzip = true
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		release()
+		if Aborted() {
+			return nil, ErrAborted
+		}
+		return nil, err
+	}
+
+	resp.Body = &countingReader{ReadCloser: resp.Body, release: release}
+	if askedGzip && strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+		resp.Body = &gzipBody{Reader: gz, raw: resp.Body}
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = -1
+		resp.Uncompressed = true
+	}
+	return resp, nil
+}
+
+// countingReader adds every byte read to the received total
+type countingReader struct {
+	io.ReadCloser
+	release func() // Detaches the request from the abort signal
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	received.Add(int64(n))
+	return n, err
+}
+
+func (r *countingReader) Close() error {
+	err := r.ReadCloser.Close()
+	r.release()
+	return err
+}
+
+// gzipBody decompresses a response while closing the underlying body
+type gzipBody struct {
+	*gzip.Reader
+	raw io.ReadCloser
+}
+
+func (b *gzipBody) Close() error {
+	b.Reader.Close()
+	return b.raw.Close()
+}
@@ -0,0 +1,30 @@
+package traffic
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// transport is shared by every API client so connections to the same host are reused
+// across clients and requests instead of each client keeping its own small pool.
+// HTTP/2 multiplexes concurrent requests to a host over a single connection.
+var transport = &http.Transport{
+	Proxy: http.ProxyFromEnvironment,
+	DialContext: (&net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}).DialContext,
+	ForceAttemptHTTP2:     true,
+	MaxIdleConns:          64,
+	MaxIdleConnsPerHost:   16, // The default of 2 forces reconnects under concurrent matching
+	IdleConnTimeout:       90 * time.Second,
+	TLSHandshakeTimeout:   10 * time.Second,
+	ExpectContinueTimeout: 1 * time.Second,
+	ResponseHeaderTimeout: 30 * time.Second,
+}
+
+// NewClient returns an HTTP client on the shared transport, for services called without OAuth
+func NewClient(timeout time.Duration) *http.Client {
+	return &http.Client{Transport: client.Transport, Timeout: timeout}
+}
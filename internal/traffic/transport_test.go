@@ -0,0 +1,175 @@
+package traffic_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/auth"
+	"github.com/Verryx-02/PlaylistPorter/internal/config"
+	"github.com/Verryx-02/PlaylistPorter/internal/odesli"
+	"github.com/Verryx-02/PlaylistPorter/internal/spt"
+	"github.com/Verryx-02/PlaylistPorter/internal/traffic"
+	"github.com/Verryx-02/PlaylistPorter/internal/tubo"
+)
+
+const (
+	// benchmarkClients is how many API clients share the requests, like the Spotify, YouTube,
+	// MusicBrainz and Odesli clients of one run
+	benchmarkClients = 4
+
+	// benchmarkConcurrency is how many requests are in flight at once
+	benchmarkConcurrency = 16
+)
+
+// TestClientsShareTransport builds the Spotify, YouTube and Odesli clients and checks that
+// their requests, the Spotify token request included, all open connections on the shared
+// transport. Its dialer is pointed at a local server for the test.
+func TestClientsShareTransport(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/api/token") {
+			io.WriteString(w, `{"access_token":"token","token_type":"Bearer","expires_in":3600}`)
+			return
+		}
+		io.WriteString(w, `{}`)
+	}))
+	defer server.Close()
+
+	var mu sync.Mutex
+	dialed := make(map[string]bool)
+	shared := traffic.SharedTransport
+	proxy, dialTLS := shared.Proxy, shared.DialTLSContext
+	shared.Proxy = nil
+	shared.DialTLSContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, _, _ := net.SplitHostPort(addr)
+		mu.Lock()
+		dialed[host] = true
+		mu.Unlock()
+		dialer := &tls.Dialer{Config: &tls.Config{InsecureSkipVerify: true}}
+		return dialer.DialContext(ctx, network, server.Listener.Addr().String())
+	}
+	defer func() {
+		shared.CloseIdleConnections()
+		shared.Proxy, shared.DialTLSContext = proxy, dialTLS
+	}()
+
+	// A saved YouTube token spares the sign-in
+	t.Setenv("PLAYLISTPORTER_TOKEN_DIR", t.TempDir())
+	token := &oauth2.Token{AccessToken: "token", RefreshToken: "refresh", TokenType: "Bearer", Expiry: time.Now().Add(time.Hour)}
+	if err := auth.SaveToken(tubo.TokenName(""), token); err != nil {
+		t.Fatal(err)
+	}
+
+	spotify, err := spt.NewClient(&config.SPTConfig{ClientID: "id", ClientSecret: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	spotify.GetTrack("track")
+
+	youtube, err := tubo.NewClient(&config.TUBOConfig{ClientID: "id", ClientSecret: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	youtube.ChannelTitle()
+
+	odesli.NewClient("").YouTubeVideos("https://open.spotify.com/track/track")
+
+	for _, host := range []string{"accounts.spotify.com", "api.spotify.com", "www.googleapis.com", "api.song.link"} {
+		if !dialed[host] {
+			t.Errorf("no request to %s went through the shared transport (dialed %v)", host, dialed)
+		}
+	}
+}
+
+// BenchmarkTransport compares the shared transport with a clone of http.DefaultTransport per
+// client, as each client had before, under concurrent requests to one TLS host
+func BenchmarkTransport(b *testing.B) {
+	for _, protocol := range []string{"http1", "http2"} {
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, `{"items":[]}`)
+		}))
+		server.EnableHTTP2 = protocol == "http2"
+		server.StartTLS()
+		roots := server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+
+		b.Run(protocol+"/shared", func(b *testing.B) {
+			shared := traffic.SharedTransport.Clone()
+			var handshakes atomic.Int64
+			trust(shared, roots, &handshakes)
+			defer shared.CloseIdleConnections()
+
+			clients := make([]*http.Client, benchmarkClients)
+			for i := range clients {
+				clients[i] = &http.Client{Transport: shared}
+			}
+			benchmarkRequests(b, server.URL, clients, &handshakes)
+		})
+
+		b.Run(protocol+"/per-client", func(b *testing.B) {
+			var handshakes atomic.Int64
+			clients := make([]*http.Client, benchmarkClients)
+			for i := range clients {
+				own := http.DefaultTransport.(*http.Transport).Clone()
+				trust(own, roots, &handshakes)
+				defer own.CloseIdleConnections()
+				clients[i] = &http.Client{Transport: own}
+			}
+			benchmarkRequests(b, server.URL, clients, &handshakes)
+		})
+
+		server.Close()
+	}
+}
+
+// trust makes a transport accept the test server's certificate and count its TLS handshakes
+func trust(t *http.Transport, roots *x509.CertPool, handshakes *atomic.Int64) {
+	t.TLSClientConfig = &tls.Config{
+		RootCAs: roots,
+		VerifyConnection: func(tls.ConnectionState) error {
+			handshakes.Add(1)
+			return nil
+		},
+	}
+}
+
+// benchmarkRequests spreads b.N GET requests over the clients from benchmarkConcurrency
+// goroutines and reports the TLS handshakes per request
+func benchmarkRequests(b *testing.B, url string, clients []*http.Client, handshakes *atomic.Int64) {
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	b.ResetTimer()
+	for w := 0; w < benchmarkConcurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				n := next.Add(1)
+				if n > int64(b.N) {
+					return
+				}
+				resp, err := clients[n%int64(len(clients))].Get(url)
+				if err != nil {
+					b.Error(err)
+					return
+				}
+				io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+			}
+		}()
+	}
+	wg.Wait()
+	b.StopTimer()
+	b.ReportMetric(float64(handshakes.Load())/float64(b.N), "handshakes/op")
+}
@@ -0,0 +1,159 @@
+// Package triage suggests what to try for tracks that failed to match, from simple rules
+// about their metadata and the recorded failure reason
+package triage
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/paths"
+	"github.com/Verryx-02/PlaylistPorter/internal/state"
+)
+
+// Failure reasons recorded by the orchestrator, matched by prefix
+const (
+	reasonArtistUnavailable = "artist known unavailable"
+	reasonSkippedByNote     = "skipped (annotated"
+	reasonFingerprint       = "fingerprint:"
+	reasonVideoGone         = "matched video is no longer available"
+)
+
+var (
+	mixPattern     = regexp.MustCompile(`(?i)\b(original|extended|radio|club|dub|vip)\s+mix\b|\bremix\b|\bbootleg\b|\brework\b`)
+	livePattern    = regexp.MustCompile(`(?i)\blive\b|\bunplugged\b|\bsession\b|\bdemo\b`)
+	featPattern    = regexp.MustCompile(`(?i)\b(feat|ft)\.?\s|\bwith\s`)
+	versionPattern = regexp.MustCompile(`(?i)\b(acoustic|instrumental|karaoke|sped up|slowed|cover)\b`)
+)
+
+// Suggest returns actionable suggestions for a failed result, most specific first.
+// destination is the service the playlist is ported to ("" for YouTube).
+func Suggest(result models.MatchResult, destination string) []string {
+	if result.Matched {
+		return nil
+	}
+	track := result.OriginalTrack
+	var suggestions []string
+	add := func(format string, args ...interface{}) {
+		suggestions = append(suggestions, fmt.Sprintf(format, args...))
+	}
+
+	switch {
+	case strings.HasPrefix(result.Error, reasonSkippedByNote):
+		return nil // Skipped on purpose
+	case strings.HasPrefix(result.Error, reasonArtistUnavailable):
+		add("Skipped without searching because no track by %q ever matched; remove the artist from %s and run -retry-failed to search anyway", track.Artist,
+			filepath.Join(paths.States(), state.ArtistHistoryFileName))
+		return suggestions
+	case strings.HasPrefix(result.Error, reasonFingerprint):
+		add("The best result sounded like a different recording; search YouTube by hand and check the alternatives")
+		return suggestions
+	case strings.HasPrefix(result.Error, reasonVideoGone):
+		add("The matched video was removed and no replacement was found; run -retry-failed later, re-uploads often appear")
+		return suggestions
+	case result.Error != "":
+		add("The search itself failed (%s); run -retry-failed once the problem is gone", truncate(result.Error, 60))
+		return suggestions
+	}
+
+	if destination == "jellyfin" || destination == "folder" {
+		add("Not found in your library; it may simply not be there, or be tagged with a different title or artist")
+	}
+
+	if track.Artist == "" {
+		add("The source has no artist; add an artist column or an \"Artist - Title\" name to the playlist file")
+	} else if !track.KnownArtist() {
+		add("The source only names the compilation's %q, so the title was searched alone; add the real artist to the playlist file", track.Artist)
+	}
+
+	if script := nonLatinScript(track.Artist + " " + track.Title); script != "" {
+		if latinVariant(track.Variants) == "" {
+			add("The %s title or artist may be uploaded under a romanized name; enable musicbrainz (with a contact) so aliases are searched too", script)
+		} else {
+			add("The MusicBrainz spelling %q was searched too; the track may not be on YouTube", latinVariant(track.Variants))
+		}
+	}
+
+	if m := mixPattern.FindString(track.Title); m != "" {
+		add("The title names a specific mix (%q), which matching ignores; DJ mixes are often uploaded only by labels or not at all", m)
+	}
+	if m := livePattern.FindString(track.Title); m != "" {
+		add("%q recordings are rarely uploaded officially; annotate \"skip\" or settle for the studio version by hand", strings.ToLower(m))
+	}
+	if m := versionPattern.FindString(track.Title); m != "" {
+		add("The %s version may score below the original; search by hand and check the lower results", strings.ToLower(m))
+	}
+	if featPattern.MatchString(track.Title) || strings.ContainsAny(track.Artist, ",&") {
+		add("Several artists are credited; uploads often list only the main one, so a match may have scored too low on the artist")
+	}
+
+	switch {
+	case track.Duration > 0 && track.Duration < 60*time.Second:
+		add("At %s this is likely an intro, skit or interlude, which is seldom uploaded separately; annotate \"skip\"", formatLength(track.Duration))
+	case track.Duration > 15*time.Minute:
+		add("At %s this is likely a continuous mix or long edit; uploads of it may be split or cut", formatLength(track.Duration))
+	}
+
+	if len([]rune(track.Title)) > 60 {
+		add("The title is very long; YouTube titles are usually shorter, so similarity scores stay low")
+	}
+
+	if len(suggestions) == 0 && track.ISRC == "" {
+		add("No ISRC is known for this track; matching relied on the title and artist alone")
+	}
+	if len(suggestions) == 0 {
+		add("Nothing unusual about the metadata; the track is probably not on YouTube, or only under another title")
+	}
+	return suggestions
+}
+
+// nonLatinScript names the non-Latin script used in text, "" when there is none.
+// Kana anywhere makes Han characters Japanese.
+func nonLatinScript(text string) string {
+	scripts := []struct {
+		name  string
+		table *unicode.RangeTable
+	}{
+		{"Japanese", unicode.Hiragana}, {"Japanese", unicode.Katakana}, {"Korean", unicode.Hangul},
+		{"Cyrillic", unicode.Cyrillic}, {"Greek", unicode.Greek}, {"Arabic", unicode.Arabic},
+		{"Hebrew", unicode.Hebrew}, {"Thai", unicode.Thai}, {"Devanagari", unicode.Devanagari},
+		{"Chinese or Japanese", unicode.Han},
+	}
+	for _, script := range scripts {
+		for _, r := range text {
+			if unicode.Is(script.table, r) {
+				return script.name
+			}
+		}
+	}
+	return ""
+}
+
+// latinVariant returns the first search variant written in Latin script
+func latinVariant(variants []models.TrackVariant) string {
+	for _, variant := range variants {
+		text := variant.Artist + " " + variant.Title
+		if nonLatinScript(text) == "" {
+			return fmt.Sprintf("%s - %s", variant.Artist, variant.Title)
+		}
+	}
+	return ""
+}
+
+// formatLength formats a track length as m:ss
+func formatLength(d time.Duration) string {
+	seconds := int(d.Seconds())
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+// truncate shortens text to n runes
+func truncate(text string, n int) string {
+	if runes := []rune(text); len(runes) > n {
+		return string(runes[:n-1]) + "…"
+	}
+	return text
+}
@@ -0,0 +1,27 @@
+package tubo
+
+import "fmt"
+
+// channelFields keeps the channel lookup to the title
+const channelFields = "items/snippet/title"
+
+// ChannelTitle returns the name of the signed-in account's YouTube channel (1 quota unit).
+// It is a cheap way to check that the credentials and the enabled API work end to end.
+func (c *Client) ChannelTitle() (string, error) {
+	response := &struct {
+		Items []struct {
+			Snippet struct {
+				Title string `json:"title"`
+			} `json:"snippet"`
+		} `json:"items"`
+	}{}
+
+	url := fmt.Sprintf("%s/channels?part=snippet&mine=true&fields=%s", baseURL, channelFields)
+	if err := c.makeRequest("GET", url, nil, response); err != nil {
+		return "", err
+	}
+	if len(response.Items) == 0 {
+		return "", fmt.Errorf("the signed-in Google account has no YouTube channel yet; open youtube.com once to create it")
+	}
+	return response.Items[0].Snippet.Title, nil
+}
@@ -2,8 +2,8 @@ package tubo
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -15,15 +15,47 @@ import (
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 
-	"playlistporter/internal/auth"
-	"playlistporter/internal/config"
-	"playlistporter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/auth"
+	"github.com/Verryx-02/PlaylistPorter/internal/config"
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/traffic"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
 )
 
 const (
 	baseURL = "https://www.googleapis.com/youtube/v3"
+
+	tokenCacheName = "youtube" // Cached OAuth token of the default account, see auth.TokenDir
+)
+
+// Partial responses (fields=) request only what the client reads, which keeps large batches fast
+const (
+	searchFields       = "items(id/videoId,snippet(title,channelTitle,description))"
+	playlistFields     = "id,snippet(title,description),status/privacyStatus"
+	playlistItemFields = "id"
+	itemPositionFields = "items/snippet(playlistId,position)"
+	itemListFields     = "nextPageToken,items(id,snippet(position,resourceId/videoId))"
+	videoStatusFields  = "items(id,status(privacyStatus,uploadStatus))"
+	videoDetailsFields = "items(id,contentDetails(duration,regionRestriction))"
 )
 
+// ErrPlaylistNotAccessible is returned when the target playlist was deleted or can no longer be modified
+var ErrPlaylistNotAccessible = errors.New("YouTube playlist is not accessible")
+
+// APIError is returned when the YouTube API responds with a non-2xx status
+type APIError struct {
+	StatusCode int
+	Reason     string // First error reason reported by the API, e.g. "quotaExceeded"
+	Message    string
+	URL        string
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API request failed with status %d. URL: %s, Response: %s",
+		e.StatusCode, e.URL, e.Body)
+}
+
 // Client represents a YouTube Data API client
 type Client struct {
 	config     *config.TUBOConfig
@@ -31,12 +63,18 @@ type Client struct {
 	token      *oauth2.Token
 	verbose    bool        // Add verbose logging
 	logger     *log.Logger // Add file logger
+	throttle   *mutationThrottle
+	details    map[string]videoDetails // Candidate metadata already fetched this run
+	matching   config.MatchingParams
 }
 
 // NewClient creates a new YouTube client
 func NewClient(cfg *config.TUBOConfig) (*Client, error) {
 	client := &Client{
-		config: cfg,
+		config:   cfg,
+		throttle: newMutationThrottle(cfg.MutationDelayMS, cfg.MutationJitterMS),
+		details:  make(map[string]videoDetails),
+		matching: config.DefaultMatching,
 	}
 
 	if err := client.authenticate(); err != nil {
@@ -51,6 +89,11 @@ func (c *Client) SetVerbose(verbose bool) {
 	c.verbose = verbose
 }
 
+// SetMatching replaces the default matching parameters, see the matching section of the config
+func (c *Client) SetMatching(params config.MatchingParams) {
+	c.matching = params
+}
+
 // SetLogger sets the file logger for detailed logging
 func (c *Client) SetLogger(logger *log.Logger) {
 	c.logger = logger
@@ -63,62 +106,102 @@ func (c *Client) logToFile(format string, args ...interface{}) {
 	}
 }
 
-// authenticate performs OAuth2 authentication for YouTube using HTTP server
+// authenticate performs OAuth2 authentication for YouTube, reusing the cached token
+// when possible and falling back to the browser flow using HTTP server
 func (c *Client) authenticate() error {
-	cfg := &oauth2.Config{
-		ClientID:     c.config.ClientID,
-		ClientSecret: c.config.ClientSecret,
-		RedirectURL:  c.config.RedirectURI,
-		Scopes:       c.config.Scopes,
-		Endpoint:     google.Endpoint,
+	cfg := oauthConfig(c.config)
+
+	// A cached refresh token avoids the browser flow entirely
+	cached, err := auth.LoadToken(c.tokenName())
+	if err != nil {
+		fmt.Printf("Ignoring saved YouTube authorization: %v\n", err)
+	}
+	if cached != nil && cached.RefreshToken != "" {
+		source := auth.NewSavingTokenSource(c.tokenName(), cfg.TokenSource(traffic.Context(), cached))
+		token, err := source.Token() // Refreshes the access token if it expired
+		if err == nil {
+			c.token = token
+			c.httpClient = oauth2.NewClient(traffic.Context(), source)
+			ui.Println("Using saved YouTube authorization" + c.accountLabel())
+			return nil
+		}
+		fmt.Printf("Saved YouTube authorization is no longer valid (%v), signing in again\n", err)
+	}
+
+	if c.config.DeviceCode {
+		token, err := c.authenticateDevice(cfg)
+		if err == nil {
+			c.useToken(cfg, token)
+			fmt.Println("YouTube authentication successful!")
+			return nil
+		}
+		// A client that isn't a "TVs and Limited Input devices" one can still paste a code
+		if !errors.Is(err, errDeviceCodeUnavailable) || !auth.NoBrowser() {
+			return err
+		}
+		fmt.Printf("Device code sign-in unavailable (%v), pasting an authorization code instead\n", err)
 	}
 
 	// Debug: Print the scopes we're requesting
 	fmt.Printf("Requesting OAuth scopes: %v\n", c.config.Scopes)
 
 	// Generate authorization URL
-	authURL := cfg.AuthCodeURL("state",
-		oauth2.AccessTypeOffline,
-		oauth2.ApprovalForce)
+	options := []oauth2.AuthCodeOption{oauth2.AccessTypeOffline, oauth2.ApprovalForce}
+	if c.config.Account != "" {
+		// Let the browser ask which Google account to use instead of picking the last one
+		options = append(options, oauth2.SetAuthURLParam("prompt", "select_account consent"))
+	}
 
-	fmt.Println("\nYouTube Authentication Required")
+	fmt.Println("\nYouTube Authentication Required" + c.accountLabel())
 	fmt.Println("=====================================")
-	fmt.Printf("1. Starting local HTTP server...\n")
 
-	// Create channels for communication
-	codeChan := make(chan string, 1)
-	errChan := make(chan error, 1)
+	state := auth.NewState()
+	var authCode string
+	if auth.NoBrowser() {
+		if port := c.config.CallbackPort; port != "" && port != auth.AutoPort {
+			cfg.RedirectURL = auth.WithPort(cfg.RedirectURL, port)
+		}
+		code, err := auth.PromptForCode(cfg.AuthCodeURL(state, options...), cfg.RedirectURL, state)
+		if err != nil {
+			return err
+		}
+		authCode = code
+	} else {
+		fmt.Printf("1. Starting local HTTP server...\n")
 
-	// Start HTTP server in background
-	go auth.StartHTTPServer("8080", codeChan, errChan)
+		// Google accepts any loopback port for desktop clients, so the redirect URI follows
+		// the port actually bound
+		listener, redirectURI, err := auth.ListenCallback(c.config.RedirectURI, c.config.CallbackPort, true)
+		if err != nil {
+			return err
+		}
+		cfg.RedirectURL = redirectURI
 
-	// Give server time to start
-	time.Sleep(1 * time.Second)
+		// Start HTTP server in background
+		server := auth.StartCallbackServer(listener, state)
 
-	fmt.Printf("2. Opening authorization URL in browser:\n\n%s\n\n", authURL)
-	fmt.Println("3. Complete the authorization in your browser")
-	fmt.Println("4. The app will automatically receive the authorization code")
-	fmt.Println("\nIf the browser doesn't open automatically, copy the URL above and paste it in your browser")
-	fmt.Println("IMPORTANT: Make sure you see 'Manage your YouTube account' permissions in the browser!")
+		authURL := cfg.AuthCodeURL(state, options...)
+		fmt.Printf("2. Opening authorization URL in browser:\n\n%s\n\n", authURL)
+		fmt.Println("3. Complete the authorization in your browser")
+		fmt.Println("4. The app will automatically receive the authorization code")
+		fmt.Println("\nIf the browser doesn't open automatically, copy the URL above and paste it in your browser")
+		fmt.Println("IMPORTANT: Make sure you see 'Manage your YouTube account' permissions in the browser!")
 
-	// Wait for either code or error
-	var authCode string
-	select {
-	case authCode = <-codeChan:
+		// Wait for either code or error
+		code, err := server.Wait(auth.CallbackTimeout)
+		if err != nil {
+			return err
+		}
+		authCode = code
 		fmt.Println("Authorization code received!")
-	case err := <-errChan:
-		return fmt.Errorf("HTTP server error: %w", err)
-	case <-time.After(5 * time.Minute):
-		return fmt.Errorf("authentication timeout - no response received within 5 minutes")
 	}
 
-	token, err := cfg.Exchange(context.Background(), authCode)
+	token, err := cfg.Exchange(traffic.Context(), authCode)
 	if err != nil {
 		return fmt.Errorf("exchanging authorization code: %w", err)
 	}
 
-	c.token = token
-	c.httpClient = cfg.Client(context.Background(), token)
+	c.useToken(cfg, token)
 
 	// Debug: Print token info (without exposing the actual token)
 	fmt.Printf("Token received. Expires: %v\n", token.Expiry)
@@ -128,22 +211,195 @@ func (c *Client) authenticate() error {
 	return nil
 }
 
+// oauthConfig is the OAuth client of the configured Google app
+func oauthConfig(cfg *config.TUBOConfig) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		RedirectURL:  cfg.RedirectURI,
+		Scopes:       cfg.Scopes,
+		Endpoint:     google.Endpoint,
+	}
+}
+
+// CheckAuthorization refreshes the saved token of the configured account without ever
+// signing in, which confirms client_id, client_secret and the sign-in without spending quota.
+// It returns auth.ErrNoToken when the account hasn't signed in yet.
+func CheckAuthorization(cfg *config.TUBOConfig) error {
+	name := TokenName(cfg.Account)
+	saved, err := auth.LoadToken(name)
+	if err != nil {
+		return err
+	}
+	if saved == nil {
+		return auth.ErrNoToken
+	}
+	if saved.RefreshToken == "" {
+		return fmt.Errorf("the saved authorization has no refresh token, sign in again")
+	}
+
+	// An expired token makes the source ask Google for a new one
+	stale := *saved
+	stale.Expiry = time.Now().Add(-time.Minute)
+	source := auth.NewSavingTokenSource(name, oauthConfig(cfg).TokenSource(traffic.Context(), &stale))
+	if _, err := source.Token(); err != nil {
+		return fmt.Errorf("refreshing the saved authorization: %w", err)
+	}
+	return nil
+}
+
+// errDeviceCodeUnavailable means Google refused to start the device code flow for the client
+var errDeviceCodeUnavailable = errors.New("requesting device code (tubo.device_code needs a \"TVs and Limited Input devices\" OAuth client)")
+
+// authenticateDevice signs in with the OAuth device authorization grant: the user enters a
+// short code on another device while the token endpoint is polled
+func (c *Client) authenticateDevice(cfg *oauth2.Config) (*oauth2.Token, error) {
+	response, err := cfg.DeviceAuth(traffic.Context())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errDeviceCodeUnavailable, err)
+	}
+
+	fmt.Println("\nYouTube Authentication Required" + c.accountLabel())
+	fmt.Println("=====================================")
+	fmt.Printf("On any device, open %s and enter the code:\n\n    %s\n\n", response.VerificationURI, response.UserCode)
+	fmt.Println("Waiting for you to allow access...")
+
+	token, err := cfg.DeviceAccessToken(traffic.Context(), response)
+	if err != nil {
+		return nil, fmt.Errorf("waiting for device authorization: %w", err)
+	}
+	return token, nil
+}
+
+// useToken saves a freshly issued token for later runs and builds the API client on it;
+// refreshed access tokens are saved as well
+func (c *Client) useToken(cfg *oauth2.Config, token *oauth2.Token) {
+	if err := auth.SaveToken(c.tokenName(), token); err != nil {
+		fmt.Printf("Warning: could not save YouTube authorization, you'll be asked to sign in again next time: %v\n", err)
+	}
+	source := auth.NewSavingTokenSource(c.tokenName(), cfg.TokenSource(traffic.Context(), token))
+
+	c.token = token
+	c.httpClient = oauth2.NewClient(traffic.Context(), source)
+}
+
+// tokenName names the saved token of the configured account
+func (c *Client) tokenName() string {
+	return TokenName(c.config.Account)
+}
+
+// TokenName names the saved token of an account, keeping accounts apart; empty is the default one
+func TokenName(account string) string {
+	if account == "" {
+		return tokenCacheName
+	}
+	return tokenCacheName + "-" + account
+}
+
+// accountLabel names the configured account in sign-in messages
+func (c *Client) accountLabel() string {
+	if c.config.Account == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (account %s)", c.config.Account)
+}
+
+// searchStrategy builds one search query for a track
+type searchStrategy struct {
+	Name  string
+	Query func(track models.Track) string
+}
+
+// Reduced strategies to save quota - only the most effective ones, tried in order.
+// Strategies returning an empty query don't apply to the track and are skipped.
+var searchStrategies = []searchStrategy{
+	{"artist-title", func(t models.Track) string { return knownArtistQuery(t, "%s %s") }},   // Standard: "Artist Title"
+	{"quoted", func(t models.Track) string { return knownArtistQuery(t, "\"%s\" \"%s\"") }}, // Quoted: "Artist" "Title"
+	{"title-only", titleOnlyQuery},                                                   // "Title" when only "Various Artists" is known
+	{"musicbrainz", func(t models.Track) string { return variantQuery(t, 0) }},       // Canonical MusicBrainz name
+	{"musicbrainz-alias", func(t models.Track) string { return variantQuery(t, 1) }}, // Next MusicBrainz alias
+	{"other-artist", otherArtistQuery},                                               // Compilations: next credited artist
+}
+
+// knownArtistQuery formats the artist and title, or nothing when the artist is a
+// "Various Artists" placeholder that would only mislead the search
+func knownArtistQuery(t models.Track, format string) string {
+	if !t.KnownArtist() {
+		return ""
+	}
+	return fmt.Sprintf(format, t.Artist, t.Title)
+}
+
+// titleOnlyQuery searches the quoted title of compilation tracks whose real artist is unknown
+func titleOnlyQuery(t models.Track) string {
+	if t.KnownArtist() {
+		return ""
+	}
+	return fmt.Sprintf("\"%s\"", t.Title)
+}
+
+// otherArtistQuery searches a compilation track under its second credited artist; compilations
+// often credit the featured or remixing artist whose channel has the upload
+func otherArtistQuery(t models.Track) string {
+	others := t.OtherArtists()
+	if !t.IsCompilation() || len(others) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s %s", others[0], t.Title)
+}
+
+// variantQuery searches the track's i-th variant, or nothing when it has fewer
+func variantQuery(t models.Track, i int) string {
+	if i >= len(t.Variants) {
+		return ""
+	}
+	return fmt.Sprintf("%s %s", t.Variants[i].Artist, t.Variants[i].Title)
+}
+
+// StrategyNames returns the search strategy names in the order they are tried
+func StrategyNames() []string {
+	names := make([]string, 0, len(searchStrategies))
+	for _, strategy := range searchStrategies {
+		names = append(names, strategy.Name)
+	}
+	return names
+}
+
+// SearchOutcome describes the result of searching for a track
+type SearchOutcome = models.SearchOutcome
+
 // SearchTrack searches for a track using optimized strategies (quota-friendly)
 func (c *Client) SearchTrack(track models.Track) (*models.Track, float64, error) {
-	// Reduced strategies to save quota - only the most effective ones
-	searchStrategies := []string{
-		fmt.Sprintf("%s %s", track.Artist, track.Title),         // Standard: "Artist Title"
-		fmt.Sprintf("\"%s\" \"%s\"", track.Artist, track.Title), // Quoted: "Artist" "Title"
-		// Removed other strategies to save quota
+	outcome, err := c.SearchTrackOutcome(track)
+	if err != nil {
+		return nil, 0, err
 	}
+	return outcome.Track, outcome.Score, nil
+}
 
+// SearchTrackOutcome searches for a track and reports which strategy won and how many searches it took
+func (c *Client) SearchTrackOutcome(track models.Track) (*SearchOutcome, error) {
 	var bestMatch *models.Track
 	var bestScore float64
 	var bestStrategy string
+	searchesUsed := 0
+	var scoreTime time.Duration
+
+	// Lower minimum threshold but prioritize quota savings
+	minThreshold := c.minScore(track)
+	goodScore := c.matching.GoodScore
+	if minThreshold > goodScore {
+		goodScore = minThreshold
+	}
 
-	for i, query := range searchStrategies {
-		c.logToFile("Strategy %d: \"%s\"", i+1, query)
+	for i, strategy := range searchStrategies {
+		query := strategy.Query(track)
+		if query == "" {
+			continue
+		}
+		c.logToFile("Strategy %d (%s): \"%s\"", i+1, strategy.Name, query)
 
+		searchesUsed++
 		searchResults, err := c.search(query, "video")
 		if err != nil {
 			c.logToFile("Search error: %v", err)
@@ -155,8 +411,19 @@ func (c *Client) SearchTrack(track models.Track) (*models.Track, float64, error)
 			continue
 		}
 
+		// Fetch metadata for all candidates in a single request when scoring needs it
+		var details map[string]videoDetails
+		if c.needsEnrichment(track) {
+			details, err = c.fetchCandidateDetails(searchResults.Items)
+			if err != nil {
+				c.logToFile("Could not fetch candidate details, scoring without them: %v", err)
+			}
+		}
+
 		// Find best match in this search
-		match, score := c.findBestMatch(track, searchResults.Items)
+		scoreStart := time.Now()
+		match, score := c.findBestMatch(track, searchResults.Items, details)
+		scoreTime += time.Since(scoreStart)
 		if match != nil {
 			c.logToFile("Best result: \"%s\" by \"%s\" (score: %.2f)",
 				c.cleanVideoTitle(match.Title), match.Artist, score)
@@ -168,28 +435,32 @@ func (c *Client) SearchTrack(track models.Track) (*models.Track, float64, error)
 		if score > bestScore {
 			bestScore = score
 			bestMatch = match
-			bestStrategy = fmt.Sprintf("Strategy %d", i+1)
+			bestStrategy = strategy.Name
 		}
 
 		// If we found a good match, stop searching to save quota
-		if score >= 0.75 { // Increased threshold to stop earlier
+		if score >= goodScore {
 			c.logToFile("Good match found, stopping search to save quota")
 			break
 		}
 	}
 
-	// Lower minimum threshold but prioritize quota savings
-	minThreshold := 0.5
 	if bestScore < minThreshold {
 		c.logToFile("Best score %.2f below threshold %.2f", bestScore, minThreshold)
-		return nil, 0, nil
+		return &SearchOutcome{SearchesUsed: searchesUsed, ScoreTime: scoreTime}, nil
 	}
 
 	if bestMatch != nil {
 		c.logToFile("Selected match using %s (score: %.2f)", bestStrategy, bestScore)
 	}
 
-	return bestMatch, bestScore, nil
+	return &SearchOutcome{
+		Track:        bestMatch,
+		Score:        bestScore,
+		Strategy:     bestStrategy,
+		SearchesUsed: searchesUsed,
+		ScoreTime:    scoreTime,
+	}, nil
 }
 
 // CreatePlaylist creates a new playlist on YouTube
@@ -205,11 +476,11 @@ func (c *Client) CreatePlaylist(name, description string) (*models.Playlist, err
 	}
 
 	// Debug: Print request details
-	fmt.Printf("Creating playlist: \"%s\"\n", name)
-	fmt.Printf("Request body: %+v\n", request)
+	ui.Printf("Creating playlist: \"%s\"\n", name)
+	ui.Printf("Request body: %+v\n", request)
 
 	response := &youtubePlaylistResponse{}
-	if err := c.makeRequest("POST", baseURL+"/playlists?part=snippet,status", request, response); err != nil {
+	if err := c.makeRequest("POST", baseURL+"/playlists?part=snippet,status&fields="+url.QueryEscape(playlistFields), request, response); err != nil {
 		return nil, err
 	}
 
@@ -221,30 +492,206 @@ func (c *Client) CreatePlaylist(name, description string) (*models.Playlist, err
 	}, nil
 }
 
-// AddTracksToPlaylist adds tracks to an existing playlist
-func (c *Client) AddTracksToPlaylist(playlistID string, trackIDs []string) error {
+// SetPlaylistPrivacy changes who can see a playlist: "public", "unlisted" or "private" (50 quota units)
+func (c *Client) SetPlaylistPrivacy(playlistID, privacy string) error {
+	request := struct {
+		ID     string                `json:"id"`
+		Status youtubePlaylistStatus `json:"status"`
+	}{
+		ID:     playlistID,
+		Status: youtubePlaylistStatus{PrivacyStatus: privacy},
+	}
+
+	return c.makeRequest("PUT", baseURL+"/playlists?part=status", request, nil)
+}
+
+// DeletePlaylist deletes a playlist and its items (50 quota units)
+func (c *Client) DeletePlaylist(playlistID string) error {
+	params := url.Values{}
+	params.Set("id", playlistID)
+
+	return c.makeRequest("DELETE", baseURL+"/playlists?"+params.Encode(), nil, nil)
+}
+
+// AddTracksToPlaylist adds tracks to an existing playlist and returns the created playlist item IDs
+func (c *Client) AddTracksToPlaylist(playlistID string, trackIDs []string) ([]string, error) {
+	itemIDs := make([]string, 0, len(trackIDs))
+
 	for i, trackID := range trackIDs {
 		c.logToFile("Adding track %d/%d to playlist (Video ID: %s)", i+1, len(trackIDs), trackID)
 
-		request := youtubePlaylistItemRequest{
-			Snippet: youtubePlaylistItemSnippet{
-				PlaylistID: playlistID,
-				ResourceID: youtubeResourceID{
-					Kind:    "youtube#video",
-					VideoID: trackID,
-				},
-			},
+		itemID, err := c.insertPlaylistItem(playlistID, trackID, nil)
+		if err != nil {
+			if isPlaylistNotAccessible(err) {
+				return itemIDs, fmt.Errorf("adding track %s: %w: %v", trackID, ErrPlaylistNotAccessible, err)
+			}
+			return itemIDs, fmt.Errorf("adding track %s: %w", trackID, err)
+		}
+		itemIDs = append(itemIDs, itemID)
+
+		// Space out inserts to avoid rate limiting
+		c.throttle.wait()
+	}
+
+	return itemIDs, nil
+}
+
+// InsertTrackAt inserts a video at a 0-based position of a playlist and returns the playlist item ID
+func (c *Client) InsertTrackAt(playlistID, videoID string, position int) (string, error) {
+	c.logToFile("Inserting video %s at position %d of playlist %s", videoID, position, playlistID)
+	return c.insertPlaylistItem(playlistID, videoID, &position)
+}
+
+// GetPlaylistItemPosition returns the playlist and 0-based position of a playlist item
+func (c *Client) GetPlaylistItemPosition(itemID string) (string, int, error) {
+	params := url.Values{}
+	params.Set("part", "snippet")
+	params.Set("id", itemID)
+	params.Set("fields", itemPositionFields)
+
+	response := &youtubePlaylistItemListResponse{}
+	if err := c.makeRequest("GET", baseURL+"/playlistItems?"+params.Encode(), nil, response); err != nil {
+		return "", 0, err
+	}
+	if len(response.Items) == 0 {
+		return "", 0, fmt.Errorf("playlist item %s not found", itemID)
+	}
+
+	snippet := response.Items[0].Snippet
+	position := 0
+	if snippet.Position != nil {
+		position = *snippet.Position
+	}
+	return snippet.PlaylistID, position, nil
+}
+
+// DeletePlaylistItem removes an item from a playlist
+func (c *Client) DeletePlaylistItem(itemID string) error {
+	params := url.Values{}
+	params.Set("id", itemID)
+
+	return c.makeRequest("DELETE", baseURL+"/playlistItems?"+params.Encode(), nil, nil)
+}
+
+// PlaylistItem is an entry of a YouTube playlist
+type PlaylistItem struct {
+	ID       string
+	VideoID  string
+	Position int
+}
+
+// ListPlaylistItems returns all items of a playlist (1 quota unit per 50 items)
+func (c *Client) ListPlaylistItems(playlistID string) ([]PlaylistItem, error) {
+	var items []PlaylistItem
+	pageToken := ""
+
+	for {
+		params := url.Values{}
+		params.Set("part", "snippet")
+		params.Set("playlistId", playlistID)
+		params.Set("maxResults", "50")
+		params.Set("fields", itemListFields)
+		if pageToken != "" {
+			params.Set("pageToken", pageToken)
 		}
 
-		if err := c.makeRequest("POST", baseURL+"/playlistItems?part=snippet", request, nil); err != nil {
-			return fmt.Errorf("adding track %s: %w", trackID, err)
+		response := &youtubePlaylistItemListResponse{}
+		if err := c.makeRequest("GET", baseURL+"/playlistItems?"+params.Encode(), nil, response); err != nil {
+			return nil, err
 		}
 
-		// Add a small delay to avoid rate limiting
-		time.Sleep(100 * time.Millisecond)
+		for _, item := range response.Items {
+			position := 0
+			if item.Snippet.Position != nil {
+				position = *item.Snippet.Position
+			}
+			items = append(items, PlaylistItem{
+				ID:       item.ID,
+				VideoID:  item.Snippet.ResourceID.VideoID,
+				Position: position,
+			})
+		}
+
+		if response.NextPageToken == "" {
+			break
+		}
+		pageToken = response.NextPageToken
 	}
 
-	return nil
+	return items, nil
+}
+
+// FindUnavailableVideos returns the IDs of videos that were deleted or made private
+// (1 quota unit per 50 videos)
+func (c *Client) FindUnavailableVideos(videoIDs []string) (map[string]bool, error) {
+	unavailable := make(map[string]bool)
+
+	for start := 0; start < len(videoIDs); start += maxVideoIDsPerRequest {
+		end := start + maxVideoIDsPerRequest
+		if end > len(videoIDs) {
+			end = len(videoIDs)
+		}
+		batch := videoIDs[start:end]
+
+		params := url.Values{}
+		params.Set("part", "status")
+		params.Set("id", strings.Join(batch, ","))
+		params.Set("fields", videoStatusFields)
+
+		response := &youtubeVideoListResponse{}
+		if err := c.makeRequest("GET", baseURL+"/videos?"+params.Encode(), nil, response); err != nil {
+			return nil, err
+		}
+
+		// Deleted videos are simply missing from the response
+		found := make(map[string]bool)
+		for _, video := range response.Items {
+			found[video.ID] = true
+			if video.Status.PrivacyStatus == "private" ||
+				video.Status.UploadStatus == "deleted" ||
+				video.Status.UploadStatus == "rejected" {
+				unavailable[video.ID] = true
+			}
+		}
+		for _, id := range batch {
+			if !found[id] {
+				unavailable[id] = true
+			}
+		}
+	}
+
+	return unavailable, nil
+}
+
+// insertPlaylistItem adds a single video to a playlist, optionally at a given position
+func (c *Client) insertPlaylistItem(playlistID, videoID string, position *int) (string, error) {
+	request := youtubePlaylistItemRequest{
+		Snippet: youtubePlaylistItemSnippet{
+			PlaylistID: playlistID,
+			Position:   position,
+			ResourceID: youtubeResourceID{
+				Kind:    "youtube#video",
+				VideoID: videoID,
+			},
+		},
+	}
+
+	response := &youtubePlaylistItemResponse{}
+	for attempt := 0; ; attempt++ {
+		err := c.makeRequest("POST", baseURL+"/playlistItems?part=snippet&fields="+playlistItemFields, request, response)
+		if err == nil {
+			c.throttle.success()
+			return response.ID, nil
+		}
+		if !isRateLimited(err) || attempt >= maxMutationRetries {
+			return "", err
+		}
+
+		// Back off and retry when YouTube reports conflicts or too many requests
+		c.throttle.backoff()
+		c.logToFile("Rate limited, retrying in %s (attempt %d/%d)", c.throttle.current, attempt+1, maxMutationRetries)
+		c.throttle.wait()
+	}
 }
 
 // search performs a search query on YouTube
@@ -256,6 +703,7 @@ func (c *Client) search(query, searchType string) (*youtubeSearchResponse, error
 	params.Set("maxResults", "15")      // Increased from 10 to 15
 	params.Set("videoCategoryId", "10") // Music category
 	params.Set("order", "relevance")
+	params.Set("fields", searchFields)
 
 	searchURL := baseURL + "/search?" + params.Encode()
 
@@ -268,12 +716,25 @@ func (c *Client) search(query, searchType string) (*youtubeSearchResponse, error
 }
 
 // findBestMatch uses improved similarity scoring to find the best matching track
-func (c *Client) findBestMatch(original models.Track, candidates []youtubeSearchItem) (*models.Track, float64) {
+func (c *Client) findBestMatch(original models.Track, candidates []youtubeSearchItem, details map[string]videoDetails) (*models.Track, float64) {
 	var bestMatch *models.Track
 	var bestScore float64
 
 	for i, candidate := range candidates {
 		score := c.calculateSimilarity(original, candidate)
+		for _, variant := range original.Alternatives() {
+			if variantScore := c.calculateSimilarity(original.WithVariant(variant), candidate); variantScore > score {
+				score = variantScore
+			}
+		}
+		d, ok := details[candidate.ID.VideoID]
+		if ok {
+			score += c.detailsAdjustment(original, d)
+		}
+		score += c.customAdjustment(original, candidate, d)
+		if score > 1.0 {
+			score = 1.0
+		}
 
 		if i < 3 { // Show top 3 candidates in log
 			cleanTitle := c.cleanVideoTitle(candidate.Snippet.Title)
@@ -284,9 +745,10 @@ func (c *Client) findBestMatch(original models.Track, candidates []youtubeSearch
 		if score > bestScore {
 			bestScore = score
 			bestMatch = &models.Track{
-				ID:     candidate.ID.VideoID,
-				Title:  c.cleanVideoTitle(candidate.Snippet.Title),
-				Artist: c.cleanChannelTitle(candidate.Snippet.ChannelTitle),
+				ID:       candidate.ID.VideoID,
+				Title:    c.cleanVideoTitle(candidate.Snippet.Title),
+				Artist:   c.cleanChannelTitle(candidate.Snippet.ChannelTitle),
+				Duration: details[candidate.ID.VideoID].Duration,
 			}
 		}
 	}
@@ -310,12 +772,16 @@ func (c *Client) calculateSimilarity(original models.Track, candidate youtubeSea
 		strings.ToLower(original.Artist),
 		strings.ToLower(cleanChannel),
 	)
+	if !original.KnownArtist() {
+		// Only "Various Artists" is known, so the title decides alone
+		artistSim = titleSim
+	}
 
 	// Bonus points for various matching patterns
 	var bonusScore float64
 
 	// Check if original artist appears in video title
-	if strings.Contains(strings.ToLower(candidate.Snippet.Title), strings.ToLower(original.Artist)) {
+	if original.KnownArtist() && strings.Contains(strings.ToLower(candidate.Snippet.Title), strings.ToLower(original.Artist)) {
 		bonusScore += 0.15
 	}
 
@@ -332,19 +798,30 @@ func (c *Client) calculateSimilarity(original models.Track, candidate youtubeSea
 		strings.Contains(channelTitleLower, "official") ||
 		strings.Contains(channelTitleLower, "records") ||
 		strings.HasSuffix(channelTitleLower, "vevo") {
-		bonusScore += 0.05
+		bonusScore += c.matching.OfficialBonus
 	}
 
-	// Penalty for covers, live versions, remixes (unless original also mentions them)
+	// Penalty for covers, live versions, remixes (unless original also mentions them); only
+	// the largest applies
 	originalTitleLower := strings.ToLower(original.Title)
-	if (strings.Contains(videoTitleLower, "cover") && !strings.Contains(originalTitleLower, "cover")) ||
-		(strings.Contains(videoTitleLower, "live") && !strings.Contains(originalTitleLower, "live")) ||
-		(strings.Contains(videoTitleLower, "remix") && !strings.Contains(originalTitleLower, "remix")) {
-		bonusScore -= 0.10
+	var penalty float64
+	for _, version := range []struct {
+		word    string
+		penalty float64
+	}{
+		{"cover", c.matching.CoverPenalty},
+		{"live", c.matching.LivePenalty},
+		{"remix", c.matching.RemixPenalty},
+	} {
+		if strings.Contains(videoTitleLower, version.word) && !strings.Contains(originalTitleLower, version.word) &&
+			version.penalty > penalty {
+			penalty = version.penalty
+		}
 	}
+	bonusScore -= penalty
 
 	// Weighted average with bonuses
-	finalScore := (titleSim * 0.7) + (artistSim * 0.3) + bonusScore
+	finalScore := (titleSim * c.matching.TitleWeight) + (artistSim * c.matching.ArtistWeight) + bonusScore
 
 	// Cap at 1.0
 	if finalScore > 1.0 {
@@ -440,14 +917,13 @@ func (c *Client) makeRequest(method, requestURL string, body interface{}, result
 		return fmt.Errorf("creating request: %w", err)
 	}
 
-	// Use OAuth for all requests
-	req.Header.Set("Authorization", "Bearer "+c.token.AccessToken)
+	// The OAuth HTTP client adds the (refreshed) access token to every request
 	req.Header.Set("Content-Type", "application/json")
 
 	// Debug: Print request details (without exposing token)
-	fmt.Printf("Making %s request to: %s\n", method, requestURL)
+	ui.Printf("Making %s request to: %s\n", method, requestURL)
 	if body != nil {
-		fmt.Printf("Request body: %s\n", string(reqBody))
+		ui.Printf("Request body: %s\n", string(reqBody))
 	}
 
 	resp, err := c.httpClient.Do(req)
@@ -462,14 +938,27 @@ func (c *Client) makeRequest(method, requestURL string, body interface{}, result
 		respBodyBytes, _ = io.ReadAll(resp.Body)
 	}
 
-	fmt.Printf("Response status: %d\n", resp.StatusCode)
+	ui.Printf("Response status: %d\n", resp.StatusCode)
 	if len(respBodyBytes) > 0 {
-		fmt.Printf("Response body: %s\n", string(respBodyBytes))
+		ui.Printf("Response body: %s\n", string(respBodyBytes))
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("API request failed with status %d. URL: %s, Response: %s",
-			resp.StatusCode, requestURL, string(respBodyBytes))
+		apiErr := &APIError{
+			StatusCode: resp.StatusCode,
+			URL:        requestURL,
+			Body:       string(respBodyBytes),
+		}
+
+		var errResp youtubeErrorResponse
+		if json.Unmarshal(respBodyBytes, &errResp) == nil {
+			apiErr.Message = errResp.Error.Message
+			if len(errResp.Error.Errors) > 0 {
+				apiErr.Reason = errResp.Error.Errors[0].Reason
+			}
+		}
+
+		return apiErr
 	}
 
 	if result != nil && len(respBodyBytes) > 0 {
@@ -483,6 +972,19 @@ func (c *Client) makeRequest(method, requestURL string, body interface{}, result
 
 // Helper functions
 
+// isPlaylistNotAccessible reports whether an API error means the playlist is gone or locked
+func isPlaylistNotAccessible(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.Reason {
+	case "playlistItemsNotAccessible", "playlistNotFound", "playlistForbidden":
+		return true
+	}
+	return false
+}
+
 // stringSimilarity calculates string similarity using Levenshtein distance
 func stringSimilarity(s1, s2 string) float64 {
 	if s1 == s2 {
@@ -595,10 +1097,60 @@ type youtubePlaylistItemRequest struct {
 
 type youtubePlaylistItemSnippet struct {
 	PlaylistID string            `json:"playlistId"`
+	Position   *int              `json:"position,omitempty"`
 	ResourceID youtubeResourceID `json:"resourceId"`
 }
 
+type youtubePlaylistItemResponse struct {
+	ID      string                     `json:"id"`
+	Snippet youtubePlaylistItemSnippet `json:"snippet"`
+}
+
+type youtubePlaylistItemListResponse struct {
+	Items         []youtubePlaylistItemResponse `json:"items"`
+	NextPageToken string                        `json:"nextPageToken"`
+}
+
+type youtubeVideoListResponse struct {
+	Items []youtubeVideo `json:"items"`
+}
+
+type youtubeVideo struct {
+	ID             string                     `json:"id"`
+	Status         youtubeVideoStatus         `json:"status"`
+	ContentDetails youtubeVideoContentDetails `json:"contentDetails"`
+}
+
+type youtubeVideoContentDetails struct {
+	Duration          string `json:"duration"` // ISO 8601, e.g. PT3M45S
+	RegionRestriction struct {
+		Allowed []string `json:"allowed"`
+		Blocked []string `json:"blocked"`
+	} `json:"regionRestriction"`
+}
+
+type youtubeVideoStatus struct {
+	PrivacyStatus string `json:"privacyStatus"`
+	UploadStatus  string `json:"uploadStatus"`
+}
+
 type youtubeResourceID struct {
 	Kind    string `json:"kind"`
 	VideoID string `json:"videoId"`
 }
+
+type youtubeErrorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Errors  []struct {
+			Reason  string `json:"reason"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	} `json:"error"`
+}
+
+// PlaylistURL returns a link to a YouTube playlist
+func (c *Client) PlaylistURL(playlistID string) string {
+	return fmt.Sprintf("https://www.youtube.com/playlist?list=%s", playlistID)
+}
@@ -0,0 +1,144 @@
+package tubo
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/traffic"
+)
+
+// maxVideoIDsPerRequest is the most IDs videos.list accepts in one call
+const maxVideoIDsPerRequest = 50
+
+// videoDetails holds the candidate metadata used to refine match scores
+type videoDetails struct {
+	Duration time.Duration
+	Allowed  []string // Regions the video is restricted to, if any
+	Blocked  []string // Regions the video is blocked in
+}
+
+// isoDurationPattern matches the ISO 8601 durations returned by the API, e.g. PT1H2M3S
+var isoDurationPattern = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// needsEnrichment reports whether candidate metadata would change the score for this track
+func (c *Client) needsEnrichment(track models.Track) bool {
+	if !c.config.EnrichCandidates || traffic.IsLight() {
+		return false // Skipped in bandwidth-light mode
+	}
+	return track.Duration > 0 || c.config.RegionCode != ""
+}
+
+// fetchCandidateDetails fetches metadata for all candidates of a search with as few
+// videos.list calls as possible (1 quota unit per 50 videos), reusing earlier results
+func (c *Client) fetchCandidateDetails(candidates []youtubeSearchItem) (map[string]videoDetails, error) {
+	var missing []string
+	for _, candidate := range candidates {
+		id := candidate.ID.VideoID
+		if _, ok := c.details[id]; !ok && id != "" {
+			missing = append(missing, id)
+		}
+	}
+
+	for start := 0; start < len(missing); start += maxVideoIDsPerRequest {
+		end := start + maxVideoIDsPerRequest
+		if end > len(missing) {
+			end = len(missing)
+		}
+
+		params := url.Values{}
+		params.Set("part", "contentDetails")
+		params.Set("id", strings.Join(missing[start:end], ","))
+		params.Set("fields", videoDetailsFields)
+
+		response := &youtubeVideoListResponse{}
+		if err := c.makeRequest("GET", baseURL+"/videos?"+params.Encode(), nil, response); err != nil {
+			return nil, fmt.Errorf("fetching video details: %w", err)
+		}
+
+		for _, video := range response.Items {
+			c.details[video.ID] = videoDetails{
+				Duration: parseISODuration(video.ContentDetails.Duration),
+				Allowed:  video.ContentDetails.RegionRestriction.Allowed,
+				Blocked:  video.ContentDetails.RegionRestriction.Blocked,
+			}
+		}
+		c.logToFile("Fetched details for %d candidates", end-start)
+	}
+
+	details := make(map[string]videoDetails, len(candidates))
+	for _, candidate := range candidates {
+		if d, ok := c.details[candidate.ID.VideoID]; ok {
+			details[candidate.ID.VideoID] = d
+		}
+	}
+
+	return details, nil
+}
+
+// detailsAdjustment returns the score change from duration and region information
+func (c *Client) detailsAdjustment(original models.Track, details videoDetails) float64 {
+	var adjustment float64
+
+	// Reward close durations, penalize clearly different ones (extended mixes, compilations).
+	// Compilation tracks are often edited for the album, so a different length is expected.
+	if original.Duration > 0 && details.Duration > 0 {
+		diff := original.Duration - details.Duration
+		if diff < 0 {
+			diff = -diff
+		}
+		switch {
+		case diff <= 10*time.Second:
+			adjustment += 0.10
+		case diff > 60*time.Second && !original.IsCompilation():
+			adjustment -= 0.15
+		}
+	}
+
+	// A video that can't be played in the user's region is almost useless
+	if region := strings.ToUpper(c.config.RegionCode); region != "" {
+		if containsRegion(details.Blocked, region) ||
+			(len(details.Allowed) > 0 && !containsRegion(details.Allowed, region)) {
+			adjustment -= 0.50
+		}
+	}
+
+	return adjustment
+}
+
+// containsRegion checks if a region code is in the list
+func containsRegion(regions []string, region string) bool {
+	for _, r := range regions {
+		if strings.EqualFold(r, region) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseISODuration converts an ISO 8601 duration such as PT3M45S, returning 0 if it can't be parsed
+func parseISODuration(value string) time.Duration {
+	parts := isoDurationPattern.FindStringSubmatch(value)
+	if parts == nil {
+		return 0
+	}
+
+	units := []time.Duration{24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var total time.Duration
+	for i, unit := range units {
+		if parts[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(parts[i+1])
+		if err != nil {
+			return 0
+		}
+		total += time.Duration(n) * unit
+	}
+
+	return total
+}
@@ -0,0 +1,22 @@
+package tubo
+
+import "github.com/Verryx-02/PlaylistPorter/internal/models"
+
+// minScore returns the score a match for the track must reach, following the popularity policy
+func (c *Client) minScore(track models.Track) float64 {
+	policy := c.config.Popularity
+	if policy == nil || track.Popularity == nil {
+		return c.matching.MinScore
+	}
+
+	popularity := *track.Popularity
+	switch {
+	case policy.PopularAbove > 0 && popularity >= policy.PopularAbove && policy.PopularMinScore > 0:
+		c.logToFile("Popular track (popularity %d), requiring score %.2f", popularity, policy.PopularMinScore)
+		return policy.PopularMinScore
+	case popularity < policy.ObscureBelow && policy.ObscureMinScore > 0:
+		c.logToFile("Obscure track (popularity %d), accepting score %.2f", popularity, policy.ObscureMinScore)
+		return policy.ObscureMinScore
+	}
+	return c.matching.MinScore
+}
@@ -0,0 +1,153 @@
+package tubo
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Position notes are a block at the end of a playlist description listing the source track
+// of every position, so the playlist can be traced back to its source without a local state:
+//
+//	[PlaylistPorter positions v1 items=12]
+//	1: 4uLU6hMCjMI75M1A2tKUQC 0VjIjW4GlUZAMYd2vXMi3b - 7qiZfU4dY1lWllzX7mPBI3
+//	[/PlaylistPorter positions]
+//
+// Each line starts with the 1-based position of its first ID; "-" marks an item PlaylistPorter
+// didn't add. When the block doesn't fit the description, the last positions are left out
+// and a "… N more" line says so.
+const (
+	positionsHeader  = "[PlaylistPorter positions v1 items=%d]"
+	positionsPrefix  = "[PlaylistPorter positions v1"
+	positionsEnd     = "[/PlaylistPorter positions]"
+	positionsPerLine = 10
+	positionUnknown  = "-"
+
+	// DescriptionLimit is the most bytes YouTube accepts in a playlist description
+	DescriptionLimit = 5000
+)
+
+// WithPositions returns the description with its position notes replaced by a block for
+// trackIDs, the source track ID of each playlist position ("" when unknown). The block is
+// shortened to keep the description within DescriptionLimit.
+func WithPositions(description string, trackIDs []string) string {
+	text := StripPositions(description)
+	if text != "" {
+		text += "\n\n"
+	}
+	return text + formatPositions(trackIDs, DescriptionLimit-len(text))
+}
+
+// StripPositions removes the position notes from a description
+func StripPositions(description string) string {
+	start := strings.Index(description, positionsPrefix)
+	if start < 0 {
+		return description
+	}
+	rest := ""
+	if end := strings.Index(description[start:], positionsEnd); end >= 0 {
+		rest = description[start+end+len(positionsEnd):]
+	}
+	return strings.TrimSpace(description[:start] + rest)
+}
+
+// ParsePositions reads the position notes of a description and returns the source track ID
+// of each position ("" when unknown), the number of items the block describes and whether
+// the description has a block. Positions left out of a shortened block are missing.
+func ParsePositions(description string) ([]string, int, bool) {
+	start := strings.Index(description, positionsPrefix)
+	if start < 0 {
+		return nil, 0, false
+	}
+	block := description[start:]
+	if end := strings.Index(block, positionsEnd); end >= 0 {
+		block = block[:end]
+	}
+
+	lines := strings.Split(block, "\n")
+	items := 0
+	fmt.Sscanf(lines[0], positionsHeader, &items)
+
+	var trackIDs []string
+	for _, line := range lines[1:] {
+		first, ids, found := strings.Cut(strings.TrimSpace(line), ":")
+		position, err := strconv.Atoi(first)
+		if !found || err != nil || position < 1 {
+			continue
+		}
+		for i, id := range strings.Fields(ids) {
+			index := position - 1 + i
+			for len(trackIDs) <= index {
+				trackIDs = append(trackIDs, "")
+			}
+			if id != positionUnknown {
+				trackIDs[index] = id
+			}
+		}
+	}
+	return trackIDs, items, true
+}
+
+// formatPositions writes the block for trackIDs in at most room bytes
+func formatPositions(trackIDs []string, room int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, positionsHeader+"\n", len(trackIDs))
+	footer := positionsEnd
+	// Reserve room for the footer and a "… N more" line
+	room -= len(footer) + len(fmt.Sprintf("… %d more\n", len(trackIDs)))
+
+	for start := 0; start < len(trackIDs); start += positionsPerLine {
+		end := start + positionsPerLine
+		if end > len(trackIDs) {
+			end = len(trackIDs)
+		}
+		ids := make([]string, end-start)
+		for i, id := range trackIDs[start:end] {
+			if id == "" {
+				id = positionUnknown
+			}
+			ids[i] = id
+		}
+		line := fmt.Sprintf("%d: %s\n", start+1, strings.Join(ids, " "))
+		if b.Len()+len(line) > room {
+			fmt.Fprintf(&b, "… %d more\n", len(trackIDs)-start)
+			break
+		}
+		b.WriteString(line)
+	}
+
+	b.WriteString(footer)
+	return b.String()
+}
+
+// GetPlaylistSnippet returns the title and description of a playlist (1 quota unit)
+func (c *Client) GetPlaylistSnippet(playlistID string) (string, string, error) {
+	params := url.Values{}
+	params.Set("part", "snippet")
+	params.Set("id", playlistID)
+	params.Set("fields", sourcePlaylistFields)
+
+	response := &youtubeSourcePlaylistResponse{}
+	if err := c.makeRequest("GET", baseURL+"/playlists?"+params.Encode(), nil, response); err != nil {
+		return "", "", err
+	}
+	if len(response.Items) == 0 {
+		return "", "", fmt.Errorf("%w: playlist %s not found or private", ErrPlaylistNotAccessible, playlistID)
+	}
+	snippet := response.Items[0].Snippet
+	return snippet.Title, snippet.Description, nil
+}
+
+// SetPlaylistDescription replaces the description of a playlist, keeping its title (50 quota units)
+func (c *Client) SetPlaylistDescription(playlistID, title, description string) error {
+	request := struct {
+		ID      string                 `json:"id"`
+		Snippet youtubePlaylistSnippet `json:"snippet"`
+	}{
+		ID:      playlistID,
+		Snippet: youtubePlaylistSnippet{Title: title, Description: description},
+	}
+
+	return c.makeRequest("PUT", baseURL+"/playlists?part=snippet", request, nil)
+}
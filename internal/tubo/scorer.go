@@ -0,0 +1,63 @@
+package tubo
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+)
+
+// Candidate is a search result offered to custom scorers
+type Candidate struct {
+	VideoID      string
+	Title        string // Raw video title
+	ChannelTitle string
+	Description  string
+	Duration     time.Duration // Zero unless candidate details were fetched
+}
+
+// Scorer contributes a custom signal to candidate scores. Score returns an
+// adjustment (usually between -0.5 and 0.5) added to the built-in score.
+type Scorer interface {
+	Name() string
+	Score(original models.Track, candidate Candidate) float64
+}
+
+var (
+	scorersMu sync.RWMutex
+	scorers   []Scorer
+)
+
+// RegisterScorer adds a custom scorer; call it from an init function
+func RegisterScorer(s Scorer) {
+	scorersMu.Lock()
+	defer scorersMu.Unlock()
+	scorers = append(scorers, s)
+}
+
+// registeredScorers returns the custom scorers in registration order
+func registeredScorers() []Scorer {
+	scorersMu.RLock()
+	defer scorersMu.RUnlock()
+	return append([]Scorer(nil), scorers...)
+}
+
+// customAdjustment sums the contributions of all registered scorers
+func (c *Client) customAdjustment(original models.Track, item youtubeSearchItem, details videoDetails) float64 {
+	candidate := Candidate{
+		VideoID:      item.ID.VideoID,
+		Title:        item.Snippet.Title,
+		ChannelTitle: item.Snippet.ChannelTitle,
+		Description:  item.Snippet.Description,
+		Duration:     details.Duration,
+	}
+
+	var total float64
+	for _, s := range registeredScorers() {
+		if adjustment := s.Score(original, candidate); adjustment != 0 {
+			c.logToFile("      %s: %+.2f", s.Name(), adjustment)
+			total += adjustment
+		}
+	}
+	return total
+}
@@ -0,0 +1,128 @@
+package tubo
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+)
+
+// IDPrefix marks YouTube source playlists in states, keeping them apart from Spotify IDs
+const IDPrefix = "youtube-"
+
+// Partial responses for reading a source playlist
+const (
+	sourcePlaylistFields = "items/snippet(title,description)"
+	sourceItemFields     = "nextPageToken,items(snippet(title,videoOwnerChannelTitle,resourceId/videoId))"
+)
+
+// GetPlaylist reads a YouTube playlist as a source, guessing artist and title of every video.
+// "Artist - Title" video titles are split; otherwise the uploading channel is taken as the artist.
+// Durations come from one videos.list call per 50 videos; id may carry IDPrefix.
+func (c *Client) GetPlaylist(id string) (*models.Playlist, error) {
+	playlistID := strings.TrimPrefix(id, IDPrefix)
+
+	params := url.Values{}
+	params.Set("part", "snippet")
+	params.Set("id", playlistID)
+	params.Set("fields", sourcePlaylistFields)
+
+	response := &youtubeSourcePlaylistResponse{}
+	if err := c.makeRequest("GET", baseURL+"/playlists?"+params.Encode(), nil, response); err != nil {
+		return nil, err
+	}
+	if len(response.Items) == 0 {
+		return nil, fmt.Errorf("%w: playlist %s not found or private", ErrPlaylistNotAccessible, playlistID)
+	}
+
+	playlist := &models.Playlist{
+		ID:          IDPrefix + playlistID,
+		Name:        response.Items[0].Snippet.Title,
+		Description: response.Items[0].Snippet.Description,
+	}
+
+	var candidates []youtubeSearchItem
+	pageToken := ""
+	for {
+		params := url.Values{}
+		params.Set("part", "snippet")
+		params.Set("playlistId", playlistID)
+		params.Set("maxResults", "50")
+		params.Set("fields", sourceItemFields)
+		if pageToken != "" {
+			params.Set("pageToken", pageToken)
+		}
+
+		items := &youtubeSourceItemListResponse{}
+		if err := c.makeRequest("GET", baseURL+"/playlistItems?"+params.Encode(), nil, items); err != nil {
+			return nil, fmt.Errorf("fetching playlist items: %w", err)
+		}
+
+		for _, item := range items.Items {
+			// Deleted and private videos stay in playlists without an owner
+			if item.Snippet.VideoOwnerChannelTitle == "" {
+				continue
+			}
+			candidate := youtubeSearchItem{}
+			candidate.ID.VideoID = item.Snippet.ResourceID.VideoID
+			candidate.Snippet.Title = item.Snippet.Title
+			candidate.Snippet.ChannelTitle = item.Snippet.VideoOwnerChannelTitle
+			candidates = append(candidates, candidate)
+		}
+
+		if items.NextPageToken == "" {
+			break
+		}
+		pageToken = items.NextPageToken
+	}
+
+	details, err := c.fetchCandidateDetails(candidates)
+	if err != nil {
+		c.logToFile("Could not fetch video durations, matching without them: %v", err)
+	}
+
+	for _, candidate := range candidates {
+		artist, title := c.splitVideoTitle(candidate.Snippet.Title, candidate.Snippet.ChannelTitle)
+		playlist.Tracks = append(playlist.Tracks, models.Track{
+			ID:        IDPrefix + candidate.ID.VideoID,
+			Title:     title,
+			Artist:    artist,
+			Duration:  details[candidate.ID.VideoID].Duration,
+			SourceURL: VideoURL(candidate.ID.VideoID),
+		})
+	}
+	playlist.TotalTracks = len(playlist.Tracks)
+
+	return playlist, nil
+}
+
+// splitVideoTitle guesses artist and title from a video title and its channel
+func (c *Client) splitVideoTitle(videoTitle, channel string) (string, string) {
+	title := c.cleanVideoTitle(videoTitle)
+	for _, separator := range []string{" - ", " – ", " — "} {
+		if artist, rest, found := strings.Cut(title, separator); found && artist != "" && rest != "" {
+			return strings.TrimSpace(artist), strings.TrimSpace(rest)
+		}
+	}
+	return c.cleanChannelTitle(channel), title
+}
+
+// YouTube API response structures for source playlists
+
+type youtubeSourcePlaylistResponse struct {
+	Items []struct {
+		Snippet youtubePlaylistSnippet `json:"snippet"`
+	} `json:"items"`
+}
+
+type youtubeSourceItemListResponse struct {
+	Items []struct {
+		Snippet struct {
+			Title                  string            `json:"title"`
+			VideoOwnerChannelTitle string            `json:"videoOwnerChannelTitle"`
+			ResourceID             youtubeResourceID `json:"resourceId"`
+		} `json:"snippet"`
+	} `json:"items"`
+	NextPageToken string `json:"nextPageToken"`
+}
@@ -0,0 +1,71 @@
+package tubo
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultMutationDelay = 100 * time.Millisecond
+	maxMutationDelay     = 30 * time.Second
+	maxMutationRetries   = 5
+)
+
+// mutationThrottle spaces out playlist mutations and backs off when YouTube pushes back
+type mutationThrottle struct {
+	base    time.Duration // Configured delay between mutations
+	jitter  time.Duration // Random extra delay, up to this value
+	current time.Duration // Adaptive delay, grows on 409/429 and decays on success
+}
+
+// newMutationThrottle creates a throttle from configured milliseconds; a nil delay means the
+// default and 0 no delay
+func newMutationThrottle(delayMS *int, jitterMS int) *mutationThrottle {
+	base := defaultMutationDelay
+	if delayMS != nil {
+		base = time.Duration(max(*delayMS, 0)) * time.Millisecond
+	}
+
+	return &mutationThrottle{
+		base:    base,
+		jitter:  time.Duration(jitterMS) * time.Millisecond,
+		current: base,
+	}
+}
+
+// wait sleeps for the current delay plus jitter
+func (t *mutationThrottle) wait() {
+	delay := t.current
+	if t.jitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(t.jitter) + 1))
+	}
+	time.Sleep(delay)
+}
+
+// success lets the adaptive delay decay back toward the configured base
+func (t *mutationThrottle) success() {
+	t.current = t.current * 3 / 4
+	if t.current < t.base {
+		t.current = t.base
+	}
+}
+
+// backoff doubles the adaptive delay after a rate-limit response, starting from the default
+// delay when no delay is configured
+func (t *mutationThrottle) backoff() {
+	t.current = max(t.current*2, defaultMutationDelay)
+	if t.current > maxMutationDelay {
+		t.current = maxMutationDelay
+	}
+}
+
+// isRateLimited reports whether an error is a 409 or 429 response worth retrying
+func isRateLimited(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusConflict || apiErr.StatusCode == http.StatusTooManyRequests
+}
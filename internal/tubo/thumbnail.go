@@ -0,0 +1,75 @@
+package tubo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+const uploadURL = "https://www.googleapis.com/upload/youtube/v3"
+
+// SetPlaylistThumbnail uploads a JPEG as the playlist's cover image (playlistImages.insert)
+func (c *Client) SetPlaylistThumbnail(playlistID string, jpegData []byte) error {
+	metadata, err := json.Marshal(map[string]interface{}{
+		"snippet": map[string]string{
+			"playlistId": playlistID,
+			"type":       "hero",
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("marshaling image metadata: %w", err)
+	}
+
+	// Metadata and image travel in one multipart/related upload
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	part, err := writer.CreatePart(textproto.MIMEHeader{"Content-Type": {"application/json; charset=UTF-8"}})
+	if err != nil {
+		return fmt.Errorf("building upload: %w", err)
+	}
+	part.Write(metadata)
+
+	part, err = writer.CreatePart(textproto.MIMEHeader{"Content-Type": {"image/jpeg"}})
+	if err != nil {
+		return fmt.Errorf("building upload: %w", err)
+	}
+	part.Write(jpegData)
+	writer.Close()
+
+	requestURL := uploadURL + "/playlistImages?part=snippet&uploadType=multipart"
+	req, err := http.NewRequest("POST", requestURL, &body)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "multipart/related; boundary="+writer.Boundary())
+
+	ui.Printf("Uploading playlist image (%d bytes)\n", len(jpegData))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		apiErr := &APIError{StatusCode: resp.StatusCode, URL: requestURL, Body: string(respBody)}
+		var errResp youtubeErrorResponse
+		if json.Unmarshal(respBody, &errResp) == nil {
+			apiErr.Message = errResp.Error.Message
+			if len(errResp.Error.Errors) > 0 {
+				apiErr.Reason = errResp.Error.Errors[0].Reason
+			}
+		}
+		return apiErr
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package tubo
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/shortlink"
+)
+
+// videoIDPattern matches YouTube's 11-character video IDs
+var videoIDPattern = regexp.MustCompile(`^[0-9A-Za-z_-]{11}$`)
+
+// VideoIDFromURL extracts the video ID from a YouTube or YouTube Music link,
+// resolving youtu.be share links first. A bare video ID is returned unchanged.
+func VideoIDFromURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if videoIDPattern.MatchString(raw) {
+		return raw, nil
+	}
+
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid YouTube URL: %w", err)
+	}
+
+	if shortlink.IsShortLink(u.Hostname()) {
+		resolved, err := shortlink.Resolve(u.String())
+		if err != nil {
+			return "", err
+		}
+		if u, err = url.Parse(resolved); err != nil {
+			return "", fmt.Errorf("invalid YouTube URL: %w", err)
+		}
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	switch host {
+	case "youtube.com", "m.youtube.com", "music.youtube.com":
+	default:
+		return "", fmt.Errorf("not a YouTube link: %s", u.Hostname())
+	}
+
+	if id := u.Query().Get("v"); videoIDPattern.MatchString(id) {
+		return id, nil
+	}
+
+	// /shorts/ID, /embed/ID and /live/ID
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) == 2 && videoIDPattern.MatchString(parts[1]) {
+		return parts[1], nil
+	}
+
+	return "", fmt.Errorf("video ID not found in URL")
+}
+
+// playlistIDPattern matches YouTube playlist IDs (PL..., OLAK5uy_..., etc.)
+var playlistIDPattern = regexp.MustCompile(`^[0-9A-Za-z_-]{12,64}$`)
+
+// IsYouTubeURL reports whether a link points to YouTube or YouTube Music
+func IsYouTubeURL(link string) bool {
+	link = strings.ToLower(link)
+	return strings.Contains(link, "youtube.com/") || strings.Contains(link, "youtu.be/")
+}
+
+// VideoURL links to a video on YouTube
+func VideoURL(videoID string) string {
+	return "https://www.youtube.com/watch?v=" + videoID
+}
+
+// IsYouTubeID reports whether a state ID belongs to a YouTube source playlist
+func IsYouTubeID(id string) bool {
+	return strings.HasPrefix(id, IDPrefix)
+}
+
+// PlaylistIDFromURL extracts the prefixed playlist ID from a YouTube or YouTube Music playlist link
+func PlaylistIDFromURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid YouTube URL: %w", err)
+	}
+
+	id := u.Query().Get("list")
+	if id == "LL" || id == "WL" {
+		return "", fmt.Errorf("Liked videos and Watch later can't be read through the API")
+	}
+	if !playlistIDPattern.MatchString(id) {
+		return "", fmt.Errorf("not a YouTube playlist link (expected https://www.youtube.com/playlist?list=...)")
+	}
+	return IDPrefix + id, nil
+}
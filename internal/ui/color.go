@@ -0,0 +1,53 @@
+package ui
+
+import "os"
+
+// LowConfidenceScore is the match score below which a match is shown as uncertain
+const LowConfidenceScore = 0.75
+
+// ANSI color codes
+const (
+	ansiReset  = "\033[0m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+)
+
+// color is on for terminals unless NO_COLOR is set (https://no-color.org)
+var color = os.Getenv("NO_COLOR") == "" && IsTerminal(os.Stdout)
+
+// SetColor enables or disables colored output
+func SetColor(enabled bool) {
+	color = enabled
+}
+
+// colorize wraps text in an ANSI color when colors are enabled
+func colorize(code, text string) string {
+	if !color || plain {
+		return text
+	}
+	return code + text + ansiReset
+}
+
+// Green marks successful matches
+func Green(text string) string {
+	return colorize(ansiGreen, text)
+}
+
+// Red marks failed matches
+func Red(text string) string {
+	return colorize(ansiRed, text)
+}
+
+// Yellow marks low-confidence matches and warnings
+func Yellow(text string) string {
+	return colorize(ansiYellow, text)
+}
+
+// ForScore colors a matched track green, or yellow when its score is low
+func ForScore(score float64, text string) string {
+	if score < LowConfidenceScore {
+		return Yellow(text)
+	}
+	return Green(text)
+}
@@ -0,0 +1,179 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+var (
+	out   io.Writer = os.Stdout
+	plain bool      // Screen-reader friendly output: no emoji, progress animations or rulers
+	quiet bool      // Only session summaries are printed
+)
+
+// SetOutput redirects everything printed by this package
+func SetOutput(w io.Writer) {
+	out = w
+}
+
+// SetPlain enables or disables plain output
+func SetPlain(enabled bool) {
+	plain = enabled
+}
+
+// IsPlain reports whether plain output is enabled
+func IsPlain() bool {
+	return plain
+}
+
+// SetQuiet suppresses all output except session summaries
+func SetQuiet(enabled bool) {
+	quiet = enabled
+}
+
+// IsTerminal reports whether f is an interactive terminal
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Printf writes formatted output to stdout, simplified in plain mode and dropped in quiet mode
+func Printf(format string, args ...interface{}) {
+	if quiet {
+		return
+	}
+	write(fmt.Sprintf(format, args...))
+}
+
+// Println writes a line to stdout, simplified in plain mode and dropped in quiet mode
+func Println(args ...interface{}) {
+	if quiet {
+		return
+	}
+	write(fmt.Sprintln(args...))
+}
+
+// Summaryf writes part of a session summary, which is printed even in quiet mode
+func Summaryf(format string, args ...interface{}) {
+	write(fmt.Sprintf(format, args...))
+}
+
+// Promptf writes a question waiting for an answer, and what it is about; like a summary it is
+// printed even in quiet mode, since a run would otherwise wait on input nobody was asked for
+func Promptf(format string, args ...interface{}) {
+	write(fmt.Sprintf(format, args...))
+}
+
+// write sends text to the output, applying the plain mode rules
+func write(text string) {
+	if plain {
+		text = toPlain(text)
+	}
+	if text != "" {
+		io.WriteString(out, text)
+	}
+}
+
+// toPlain rewrites decorated terminal output as plain sentences
+func toPlain(text string) string {
+	// Carriage-return progress lines are animations; only their final line is worth reading
+	if strings.HasPrefix(text, "\r") {
+		if !strings.HasSuffix(text, "\n") {
+			return ""
+		}
+		text = strings.TrimRight(strings.TrimPrefix(text, "\r"), " \n") + "\n"
+	}
+
+	lines := strings.Split(text, "\n")
+	kept := make([]string, 0, len(lines))
+	for i, line := range lines {
+		complete := i < len(lines)-1
+		if complete && isRuler(line) {
+			continue
+		}
+		kept = append(kept, plainLine(line))
+	}
+
+	return strings.Join(kept, "\n")
+}
+
+// isRuler reports whether a line only underlines or frames other text
+func isRuler(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	if len([]rune(trimmed)) < 3 {
+		return false
+	}
+	for _, r := range trimmed {
+		if r != '=' && r != '-' && !isBoxDrawing(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// plainLine removes emoji and box drawing from a line, keeping its indentation
+func plainLine(line string) string {
+	content := strings.TrimLeft(line, " ")
+	indent := line[:len(line)-len(content)]
+
+	var b strings.Builder
+	for _, r := range content {
+		switch {
+		case isEmoji(r) || isBoxDrawing(r):
+			continue
+		case r == '→':
+			b.WriteString("->")
+		case r == '•':
+			b.WriteString("-")
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	cleaned := strings.Join(strings.FieldsFunc(b.String(), func(r rune) bool { return r == ' ' }), " ")
+	if cleaned == "" {
+		if strings.TrimSpace(content) != "" {
+			return "" // Line held only decorations
+		}
+		return line
+	}
+
+	// Keep a separating space for text continuing a line printed earlier
+	if indent == "" && strings.HasPrefix(content, " ") {
+		indent = " "
+	}
+	if strings.HasSuffix(content, " ") {
+		cleaned += " "
+	}
+
+	return indent + cleaned
+}
+
+// isEmoji reports whether r is a pictograph or one of its modifiers
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF: // Pictographs, emoticons, transport, symbols
+		return true
+	case r >= 0x2600 && r <= 0x27BF: // Miscellaneous symbols and dingbats (✅ ❌ ✋)
+		return true
+	case r >= 0x2B00 && r <= 0x2BFF: // Arrows and stars (⭐)
+		return true
+	case r >= 0x2300 && r <= 0x23FF: // Technical symbols (⏳ ⏸ ⏭)
+		return true
+	case r == 0xFE0F || r == 0x200D || r == 0x20E3: // Variation selector, joiner, keycap
+		return true
+	case r == 0x2139 || r == 0x203C || r == 0x2049: // ℹ ‼ ⁉
+		return true
+	}
+	return false
+}
+
+// isBoxDrawing reports whether r is a box drawing or block element character
+func isBoxDrawing(r rune) bool {
+	return r >= 0x2500 && r <= 0x259F
+}
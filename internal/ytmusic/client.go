@@ -0,0 +1,335 @@
+// Package ytmusic searches the YouTube Music catalog through the internal API used by its web app.
+// Searches there don't count against the YouTube Data API quota.
+package ytmusic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/config"
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/processor"
+	"github.com/Verryx-02/PlaylistPorter/internal/traffic"
+)
+
+const (
+	searchURL     = "https://music.youtube.com/youtubei/v1/search?prettyPrint=false"
+	clientName    = "WEB_REMIX"
+	clientVersion = "1.20240612.01.00"
+	userAgent     = "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/125.0 Safari/537.36"
+
+	// songsFilter restricts results to songs, leaving out videos, albums and artists
+	songsFilter = "EgWKAQIIAWoMEA4QChADEAQQCRAF"
+
+	durationSlop = 10.0 // Seconds of difference still counted as the same recording
+)
+
+// Client searches YouTube Music
+type Client struct {
+	config     *config.TUBOConfig
+	httpClient *http.Client
+	processor  *processor.Processor
+	logger     *log.Logger
+	matching   config.MatchingParams
+}
+
+// NewClient creates a YouTube Music search client; no credentials are needed
+func NewClient(cfg *config.TUBOConfig) *Client {
+	return &Client{
+		config:     cfg,
+		httpClient: traffic.NewClient(30 * time.Second),
+		processor:  processor.New(),
+		matching:   config.DefaultMatching,
+	}
+}
+
+// SetMatching replaces the default score thresholds, see the matching section of the config.
+// Candidates are scored by the processor, so the weights, bonus and penalties don't apply.
+func (c *Client) SetMatching(params config.MatchingParams) {
+	c.matching = params
+}
+
+// SetLogger sets the logger for detailed file logging
+func (c *Client) SetLogger(logger *log.Logger) {
+	c.logger = logger
+}
+
+func (c *Client) logToFile(format string, args ...interface{}) {
+	if c.logger != nil {
+		c.logger.Printf(format, args...)
+	}
+}
+
+// SearchTrackOutcome searches YouTube Music songs for a track. SearchesUsed stays 0 because
+// no Data API quota is spent; the queries made are logged instead.
+func (c *Client) SearchTrackOutcome(track models.Track) (*models.SearchOutcome, error) {
+	original := track
+	c.processor.NormalizeTrack(&original)
+
+	// Only the title is searched when the artist is a "Various Artists" placeholder
+	queries := []string{fmt.Sprintf("\"%s\"", track.Title)}
+	if track.KnownArtist() {
+		queries = []string{fmt.Sprintf("%s %s", track.Artist, track.Title)}
+	}
+	if track.ISRC != "" {
+		queries = append([]string{track.ISRC}, queries...) // ISRCs often find the exact recording
+	}
+	for _, variant := range track.Variants {
+		queries = append(queries, fmt.Sprintf("%s %s", variant.Artist, variant.Title))
+	}
+	if others := track.OtherArtists(); track.IsCompilation() && len(others) > 0 {
+		queries = append(queries, fmt.Sprintf("%s %s", others[0], track.Title))
+	}
+	var variants []models.Track
+	for _, variant := range track.Alternatives() {
+		alternative := track.WithVariant(variant)
+		c.processor.NormalizeTrack(&alternative)
+		variants = append(variants, alternative)
+	}
+
+	var best *models.Track
+	bestScore := 0.0
+	var lastErr error
+	var scoreTime time.Duration
+	for _, query := range queries {
+		c.logToFile("YouTube Music search: \"%s\"", query)
+
+		songs, err := c.search(query)
+		if err != nil {
+			c.logToFile("Search error: %v", err)
+			lastErr = err
+			continue
+		}
+
+		scoreStart := time.Now()
+		for _, song := range songs {
+			candidate := song
+			c.processor.NormalizeTrack(&candidate)
+			score := c.score(original, candidate)
+			for _, alternative := range variants {
+				if variantScore := c.score(alternative, candidate); variantScore > score {
+					score = variantScore
+				}
+			}
+			if score > bestScore {
+				bestScore = score
+				matched := song
+				best = &matched
+			}
+		}
+
+		scoreTime += time.Since(scoreStart)
+
+		if bestScore >= c.matching.GoodScore {
+			break
+		}
+	}
+
+	if best == nil && lastErr != nil {
+		return nil, lastErr
+	}
+
+	outcome := &models.SearchOutcome{ScoreTime: scoreTime}
+	if best != nil && bestScore >= c.matching.MinScore {
+		outcome.Track = best
+		outcome.Score = bestScore
+		outcome.Strategy = "ytmusic"
+	}
+	return outcome, nil
+}
+
+// score compares a candidate with the original track, with a bonus when the durations agree
+func (c *Client) score(original, candidate models.Track) float64 {
+	score := c.processor.CalculateMatchScore(original, candidate)
+	if original.Duration > 0 && candidate.Duration > 0 {
+		diff := (original.Duration - candidate.Duration).Seconds()
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff <= durationSlop {
+			score += 0.1
+		}
+	}
+	if score > 1 {
+		score = 1
+	}
+	return score
+}
+
+// search runs one songs search and returns the results as tracks whose ID is the video ID
+func (c *Client) search(query string) ([]models.Track, error) {
+	gl := c.config.RegionCode
+	if gl == "" {
+		gl = "US"
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"context": map[string]interface{}{
+			"client": map[string]string{
+				"clientName":    clientName,
+				"clientVersion": clientVersion,
+				"hl":            "en",
+				"gl":            gl,
+			},
+		},
+		"query":  query,
+		"params": songsFilter,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request body: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", searchURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Origin", "https://music.youtube.com")
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("YouTube Music search failed with status %d: %s", resp.StatusCode, respBody)
+	}
+
+	response := &searchResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+
+	var songs []models.Track
+	for _, tab := range response.Contents.TabbedSearchResultsRenderer.Tabs {
+		for _, section := range tab.TabRenderer.Content.SectionListRenderer.Contents {
+			for _, item := range section.MusicShelfRenderer.Contents {
+				if song, ok := item.MusicResponsiveListItemRenderer.track(); ok {
+					songs = append(songs, song)
+				}
+			}
+		}
+	}
+	return songs, nil
+}
+
+// track reads title, artists, album and duration from the columns of a result row,
+// whose second column reads "Artist • Album • 3:45"
+func (r listItemRenderer) track() (models.Track, bool) {
+	videoID := r.PlaylistItemData.VideoID
+	if videoID == "" || len(r.FlexColumns) < 2 {
+		return models.Track{}, false
+	}
+
+	song := models.Track{
+		ID:    videoID,
+		Title: r.FlexColumns[0].text(),
+	}
+
+	var groups [][]string
+	current := []string{}
+	for _, run := range r.FlexColumns[1].MusicResponsiveListItemFlexColumnRenderer.Text.Runs {
+		if strings.TrimSpace(run.Text) == "•" {
+			groups = append(groups, current)
+			current = []string{}
+			continue
+		}
+		current = append(current, run.Text)
+	}
+	groups = append(groups, current)
+
+	// Unfiltered layouts start with the result type
+	if len(groups) > 0 && len(groups[0]) == 1 && groups[0][0] == "Song" {
+		groups = groups[1:]
+	}
+	if len(groups) > 0 {
+		if duration, ok := parseDuration(strings.Join(groups[len(groups)-1], "")); ok {
+			song.Duration = duration
+			groups = groups[:len(groups)-1]
+		}
+	}
+	if len(groups) > 0 {
+		song.Artist = strings.TrimSpace(strings.Join(groups[0], ""))
+	}
+	if len(groups) > 1 {
+		song.Album = strings.TrimSpace(strings.Join(groups[1], ""))
+	}
+
+	return song, song.Title != ""
+}
+
+// parseDuration converts "3:45" or "1:02:03"
+func parseDuration(value string) (time.Duration, bool) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, false
+	}
+	var total int
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return 0, false
+		}
+		total = total*60 + n
+	}
+	return time.Duration(total) * time.Second, true
+}
+
+// YouTube Music internal API response structures (only the fields used)
+
+type searchResponse struct {
+	Contents struct {
+		TabbedSearchResultsRenderer struct {
+			Tabs []struct {
+				TabRenderer struct {
+					Content struct {
+						SectionListRenderer struct {
+							Contents []struct {
+								MusicShelfRenderer struct {
+									Contents []struct {
+										MusicResponsiveListItemRenderer listItemRenderer `json:"musicResponsiveListItemRenderer"`
+									} `json:"contents"`
+								} `json:"musicShelfRenderer"`
+							} `json:"contents"`
+						} `json:"sectionListRenderer"`
+					} `json:"content"`
+				} `json:"tabRenderer"`
+			} `json:"tabs"`
+		} `json:"tabbedSearchResultsRenderer"`
+	} `json:"contents"`
+}
+
+type listItemRenderer struct {
+	FlexColumns      []flexColumn `json:"flexColumns"`
+	PlaylistItemData struct {
+		VideoID string `json:"videoId"`
+	} `json:"playlistItemData"`
+}
+
+type flexColumn struct {
+	MusicResponsiveListItemFlexColumnRenderer struct {
+		Text struct {
+			Runs []struct {
+				Text string `json:"text"`
+			} `json:"runs"`
+		} `json:"text"`
+	} `json:"musicResponsiveListItemFlexColumnRenderer"`
+}
+
+// text joins the runs of a column
+func (f flexColumn) text() string {
+	var parts []string
+	for _, run := range f.MusicResponsiveListItemFlexColumnRenderer.Text.Runs {
+		parts = append(parts, run.Text)
+	}
+	return strings.Join(parts, "")
+}
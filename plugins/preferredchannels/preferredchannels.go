@@ -0,0 +1,49 @@
+// Package preferredchannels is an example scoring plugin: it favors videos from the
+// YouTube channels listed in PLAYLISTPORTER_PREFERRED_CHANNELS (comma-separated).
+//
+// Build the command with -tags preferredchannels to compile it in.
+package preferredchannels
+
+import (
+	"os"
+	"strings"
+
+	"github.com/Verryx-02/PlaylistPorter/porter"
+)
+
+// bonus is added to candidates from a preferred channel
+const bonus = 0.15
+
+func init() {
+	var channels []string
+	for _, channel := range strings.Split(os.Getenv("PLAYLISTPORTER_PREFERRED_CHANNELS"), ",") {
+		if channel = strings.ToLower(strings.TrimSpace(channel)); channel != "" {
+			channels = append(channels, channel)
+		}
+	}
+
+	if len(channels) > 0 {
+		porter.RegisterScorer(scorer{channels: channels})
+	}
+}
+
+// scorer rewards candidates uploaded by a preferred channel
+type scorer struct {
+	channels []string
+}
+
+// Name identifies the scorer in logs
+func (s scorer) Name() string {
+	return "preferred-channels"
+}
+
+// Score returns the bonus when the candidate's channel is preferred
+func (s scorer) Score(original porter.Track, candidate porter.Candidate) float64 {
+	channel := strings.ToLower(candidate.ChannelTitle)
+	for _, preferred := range s.channels {
+		if channel == preferred {
+			return bonus
+		}
+	}
+	return 0
+}
@@ -0,0 +1,276 @@
+// Package porter ports Spotify playlists to YouTube.
+//
+// It is the API of PlaylistPorter for programs that want to embed playlist porting (bots,
+// web apps) instead of running the playlistporter command.
+//
+// The API is not stable yet. Config, Track, MatchResult, State and the provider
+// interfaces are aliases of internal types, so they gain, rename or reshape fields
+// whenever the command does (e.g. Track carrying album and track artists). Pin a
+// module version and read the changes before upgrading.
+//
+// A minimal program:
+//
+//	cfg, err := porter.LoadConfig(porter.ConfigPath())
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	p := porter.New(cfg, porter.Options{MaxTracks: 50, StatesDir: "/var/lib/mybot/states"})
+//	if err := p.Port("https://open.spotify.com/playlist/..."); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// A Porter keeps its checkpoints in Options.StatesDir (the command's states directory if
+// empty), so sessions resume across runs, and answers questions such as whether to recreate
+// a deleted YouTube playlist with Options.Confirm instead of reading stdin. The first
+// YouTube call opens the OAuth flow in a browser.
+//
+// Some settings still apply to the whole process, not to one Porter: progress messages go
+// to the writer set with SetOutput (stdout by default), Options.Light turns on light mode
+// for every client, RegisterScorer affects every search, and FailurePause asks on the
+// terminal. Porters sharing a process share these.
+package porter
+
+import (
+	"io"
+	"time"
+
+	"github.com/Verryx-02/PlaylistPorter/internal/config"
+	"github.com/Verryx-02/PlaylistPorter/internal/models"
+	"github.com/Verryx-02/PlaylistPorter/internal/orchestrator"
+	"github.com/Verryx-02/PlaylistPorter/internal/paths"
+	"github.com/Verryx-02/PlaylistPorter/internal/processor"
+	"github.com/Verryx-02/PlaylistPorter/internal/state"
+	"github.com/Verryx-02/PlaylistPorter/internal/traffic"
+	"github.com/Verryx-02/PlaylistPorter/internal/tubo"
+	"github.com/Verryx-02/PlaylistPorter/internal/ui"
+)
+
+// Configuration types
+type (
+	Config     = config.Config
+	SPTConfig  = config.SPTConfig
+	TUBOConfig = config.TUBOConfig
+	SplitRule  = config.SplitRule
+)
+
+// Data types
+type (
+	Track       = models.Track
+	Playlist    = models.Playlist
+	MatchResult = models.MatchResult
+)
+
+// Service providers: implement them to read from or port to services PlaylistPorter doesn't know,
+// or to replace the real clients with fakes in tests
+type (
+	Source         = orchestrator.Source
+	Destination    = orchestrator.Destination
+	SourceIDParser = orchestrator.SourceIDParser
+	SearchOutcome  = models.SearchOutcome
+)
+
+// Custom scoring
+type (
+	Scorer    = tubo.Scorer
+	Candidate = tubo.Candidate
+)
+
+// RegisterScorer adds a custom signal to the candidate scores of every search.
+// Call it from an init function, e.g. in a file compiled in with a build tag.
+func RegisterScorer(s Scorer) {
+	tubo.RegisterScorer(s)
+}
+
+// State types
+type (
+	State        = state.PortingState
+	StateManager = state.Manager
+	SearchHit    = state.SearchHit
+)
+
+// Workflow phases
+const (
+	PhaseAll    = orchestrator.PhaseAll
+	PhaseMatch  = orchestrator.PhaseMatch
+	PhaseUpload = orchestrator.PhaseUpload
+)
+
+// Port modes
+const (
+	ModeFollow   = state.ModeFollow
+	ModeSnapshot = state.ModeSnapshot
+	ModeArchive  = state.ModeArchive
+)
+
+// Destination services
+const (
+	DestYouTube    = orchestrator.DestYouTube
+	DestSoundCloud = orchestrator.DestSoundCloud
+	DestSpotify    = orchestrator.DestSpotify
+	DestJellyfin   = orchestrator.DestJellyfin
+	DestFolder     = orchestrator.DestFolder
+)
+
+// Failure policies: what a track that was searched but not matched does to the batch
+const (
+	FailureContinue = orchestrator.FailureContinue
+	FailurePause    = orchestrator.FailurePause
+	FailureAbort    = orchestrator.FailureAbort
+)
+
+// LoadConfig reads a YAML configuration file, applying PLAYLISTPORTER_* environment overrides
+func LoadConfig(path string) (*Config, error) {
+	return config.Load(path)
+}
+
+// LoadConfigFromEnv builds the configuration from PLAYLISTPORTER_* environment variables only
+func LoadConfigFromEnv() (*Config, error) {
+	return config.LoadFromEnv()
+}
+
+// OpenStates opens a directory of saved states (see StatesDir for the one the command uses)
+func OpenStates(dir string) (*StateManager, error) {
+	return state.NewManager(dir)
+}
+
+// ConfigPath returns the config file the command reads by default
+func ConfigPath() string {
+	return paths.Config()
+}
+
+// StatesDir returns the directory where Porters without Options.StatesDir keep their saved states
+func StatesDir() string {
+	return paths.States()
+}
+
+// SetStatesDir makes Porters without Options.StatesDir keep their saved states in dir; empty
+// restores the default
+func SetStatesDir(dir string) {
+	paths.SetStates(dir)
+}
+
+// SetOutput redirects the progress messages of all Porters, normally printed to stdout
+func SetOutput(w io.Writer) {
+	ui.SetOutput(w)
+}
+
+// MatchScore compares two tracks after normalizing their metadata, from 0.0 to 1.0
+func MatchScore(a, b Track) float64 {
+	p := processor.New()
+	p.NormalizeTrack(&a)
+	p.NormalizeTrack(&b)
+	return p.CalculateMatchScore(a, b)
+}
+
+// Options tune a Porter; the zero value ports 50 tracks per session
+type Options struct {
+	MaxTracks        int           // Tracks searched per session (default 50)
+	Sync             bool          // Check completed playlists for new tracks
+	Phase            string        // PhaseAll (default), PhaseMatch or PhaseUpload
+	Mode             string        // Port mode for new states; "" picks the default for the playlist
+	ArchiveWeekly    bool          // Archive mode: one YouTube playlist per week
+	Split            bool          // Route matches using the config's split rules
+	HoldOnRegression bool          // Don't upload sync batches that match much worse than usual
+	LogFile          string        // Detailed log path; "" disables the log
+	MaxDuration      time.Duration // Per-run time budget; 0 is unlimited
+	Destination      string        // DestYouTube (default), DestSoundCloud, DestJellyfin, DestFolder or DestSpotify
+	Light            bool          // Bandwidth-light mode; applies to the whole process
+	Account          string        // YouTube account to write with; "" uses Config.TUBO.Account
+	SessionNote      string        // Stored with each session and shown in the session history
+	StrictISRC       bool          // Only keep matches corroborated by the source ISRC (YouTube only)
+	OnFailure        string        // FailureContinue (default), FailurePause (asks on the terminal) or FailureAbort
+	StatesDir        string        // Saved states directory; "" uses StatesDir()
+
+	// Confirm answers yes/no questions of a run, e.g. whether to recreate a YouTube playlist
+	// that was deleted. Nil answers no.
+	Confirm func(question string) bool
+
+	// Custom providers replacing the built-in services (optional). A custom destination is
+	// recorded in states under the Destination name ("custom" if empty).
+	Source            Source
+	CustomDestination Destination
+}
+
+// Porter runs porting sessions with a fixed configuration
+type Porter struct {
+	cfg  *Config
+	opts Options
+}
+
+// New creates a Porter
+func New(cfg *Config, opts Options) *Porter {
+	if opts.MaxTracks < 1 {
+		opts.MaxTracks = 50
+	}
+	if opts.Phase == "" {
+		opts.Phase = PhaseAll
+	}
+	return &Porter{cfg: cfg, opts: opts}
+}
+
+// newOrchestrator creates the orchestrator for one run (it closes its log when the run ends)
+func (p *Porter) newOrchestrator() *orchestrator.Orchestrator {
+	orch := orchestrator.New(p.cfg, p.opts.LogFile != "", p.opts.LogFile, p.opts.MaxTracks, p.opts.Sync)
+	orch.SetPhase(p.opts.Phase)
+	orch.SetMode(p.opts.Mode)
+	orch.SetArchiveWeekly(p.opts.ArchiveWeekly)
+	orch.SetSplit(p.opts.Split)
+	orch.SetHoldOnRegression(p.opts.HoldOnRegression)
+	orch.SetMaxDuration(p.opts.MaxDuration)
+	orch.SetDestination(p.opts.Destination)
+	orch.SetAccount(p.opts.Account)
+	orch.SetSessionNote(p.opts.SessionNote)
+	orch.SetStrictISRC(p.opts.StrictISRC)
+	orch.SetFailurePolicy(p.opts.OnFailure)
+	orch.SetStatesDir(p.opts.StatesDir)
+	if p.opts.Confirm != nil {
+		orch.SetConfirm(p.opts.Confirm)
+	} else {
+		orch.SetConfirm(func(string) bool { return false })
+	}
+	if p.opts.Source != nil {
+		orch.UseSource(p.opts.Source)
+	}
+	if p.opts.CustomDestination != nil {
+		name := p.opts.Destination
+		if name == "" {
+			name = "custom"
+		}
+		orch.UseDestination(name, p.opts.CustomDestination)
+	}
+	if p.opts.Light {
+		traffic.SetLight(true)
+	}
+	return orch
+}
+
+// Port runs the next session for a Spotify playlist URL, resuming from its saved state
+func (p *Porter) Port(sptURL string) error {
+	return p.newOrchestrator().PortPlaylist(sptURL)
+}
+
+// Merge ports several Spotify playlists into one YouTube playlist; name may be empty
+func (p *Porter) Merge(sptURLs []string, name string) error {
+	return p.newOrchestrator().MergePlaylists(sptURLs, name)
+}
+
+// PortAll ports every playlist in the signed-in user's Spotify library, sharing
+// MaxTracks between them round-robin
+func (p *Porter) PortAll() error {
+	return p.newOrchestrator().PortAllPlaylists()
+}
+
+// Verify replaces matched videos that were deleted or made private on YouTube
+func (p *Porter) Verify(sptURL string) error {
+	return p.newOrchestrator().VerifyPlaylist(sptURL)
+}
+
+// RetryFailed searches again for tracks that failed to match
+func (p *Porter) RetryFailed(sptURL string) error {
+	return p.newOrchestrator().RetryFailed(sptURL)
+}
+
+// RecreateTarget builds a new YouTube playlist from the stored matches
+func (p *Porter) RecreateTarget(sptURL string) error {
+	return p.newOrchestrator().RecreateTarget(sptURL)
+}